@@ -3,13 +3,20 @@ package main
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/xNatthapol/hubster/internal/config"
 	"github.com/xNatthapol/hubster/internal/database"
+	"github.com/xNatthapol/hubster/internal/events"
 	"github.com/xNatthapol/hubster/internal/handlers"
+	"github.com/xNatthapol/hubster/internal/mail"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/push"
 	"github.com/xNatthapol/hubster/internal/repositories"
 	"github.com/xNatthapol/hubster/internal/services"
 	"github.com/xNatthapol/hubster/internal/utils"
+	"github.com/xNatthapol/hubster/internal/webhook"
 
 	_ "github.com/xNatthapol/hubster/docs"
 
@@ -48,9 +55,15 @@ func main() {
 		log.Fatalf("FATAL: Failed to initialize database: %v", err)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eventBus := events.NewPostgresBus(db, database.DSN(cfg))
+	eventBus.Start(ctx)
+
 	var gcsUploader *utils.GCSUploader
 	if cfg.GCSBucketName != "" && cfg.GCSServiceAccountKeyPath != "" {
-		uploader, err := utils.NewGCSUploader(context.Background(), cfg.GCSBucketName, cfg.GCSServiceAccountKeyPath)
+		uploader, err := utils.NewGCSUploader(ctx, cfg.GCSBucketName, cfg.GCSServiceAccountKeyPath)
 		if err != nil {
 			log.Printf("WARNING: Failed to initialize GCS Uploader: %v. Image uploads disabled.", err)
 			gcsUploader = nil
@@ -67,25 +80,162 @@ func main() {
 		gcsUploader = nil
 	}
 
+	var fcmDispatcher, apnsDispatcher push.Dispatcher
+	if cfg.FCMServiceAccountKeyPath != "" {
+		dispatcher, err := push.NewFCMDispatcher(ctx, cfg.FCMProjectID, cfg.FCMServiceAccountKeyPath)
+		if err != nil {
+			log.Printf("WARNING: Failed to initialize FCM dispatcher: %v. FCM push disabled.", err)
+		} else {
+			fcmDispatcher = dispatcher
+		}
+	}
+	if cfg.APNsAuthKeyPath != "" {
+		dispatcher, err := push.NewAPNsDispatcher(push.APNsConfig{
+			AuthKeyPath: cfg.APNsAuthKeyPath,
+			KeyID:       cfg.APNsKeyID,
+			TeamID:      cfg.APNsTeamID,
+			Topic:       cfg.APNsTopic,
+			UseSandbox:  cfg.APNsUseSandbox,
+		})
+		if err != nil {
+			log.Printf("WARNING: Failed to initialize APNs dispatcher: %v. APNs push disabled.", err)
+		} else {
+			apnsDispatcher = dispatcher
+		}
+	}
+	var pushDispatcher push.Dispatcher
+	if multi := push.NewMultiDispatcher(map[models.PushProvider]push.Dispatcher{
+		models.PushProviderFCM:  fcmDispatcher,
+		models.PushProviderAPNs: apnsDispatcher,
+	}); multi.Enabled() {
+		pushDispatcher = multi
+	}
+
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		log.Printf("WARNING: Invalid TIME_ZONE '%s': %v. Falling back to UTC.", cfg.TimeZone, err)
+		location = time.UTC
+	}
+
+	var mailer mail.Mailer
+	if cfg.SMTPHost != "" {
+		smtpMailer, err := mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		if err != nil {
+			log.Printf("WARNING: Failed to initialize SMTP mailer: %v. Email notifications disabled.", err)
+		} else {
+			mailer = smtpMailer
+		}
+	}
+	mailRenderer, err := mail.NewRenderer(location)
+	if err != nil {
+		log.Fatalf("FATAL: Failed to parse email templates: %v", err)
+	}
+	defaultLocale := mail.ParseLocale(cfg.DefaultLocale, mail.LocaleThai)
+
 	userRepo := repositories.NewUserRepository(db)
 	subscriptionServiceRepo := repositories.NewSubscriptionServiceRepository(db)
 	hostedSubRepo := repositories.NewHostedSubscriptionRepository(db)
 	membershipRepo := repositories.NewSubscriptionMembershipRepository(db)
 	joinRequestRepo := repositories.NewJoinRequestRepository(db) // Add this
 	paymentRecordRepo := repositories.NewPaymentRecordRepository(db)
+	paymentRecordMessageRepo := repositories.NewPaymentRecordMessageRepository(db)
+	membershipLedgerRepo := repositories.NewMembershipLedgerRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	deviceTokenRepo := repositories.NewDeviceTokenRepository(db)
+	notificationPrefRepo := repositories.NewNotificationPreferenceRepository(db)
+	paymentReminderRepo := repositories.NewPaymentReminderRepository(db)
+	outboxRepo := repositories.NewOutboxRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	auditLogRepo := repositories.NewAuditLogRepository(db)
+	journalRepo := repositories.NewJournalRepository(db)
+	refundRepo := repositories.NewRefundRepository(db)
+	autoApprovalRepo := repositories.NewAutoApprovalRepository(db)
+	hostDashboardRepo := repositories.NewHostDashboardRepository(db)
+	calendarFeedRepo := repositories.NewCalendarFeedRepository(db)
+	inviteRepo := repositories.NewSubscriptionInviteRepository(db)
+	waitlistRepo := repositories.NewWaitlistRepository(db)
+	screeningQuestionRepo := repositories.NewScreeningQuestionRepository(db)
+	transactor := repositories.NewTransactor(db)
+	outboxPublisher := services.NewOutboxPublisher(outboxRepo)
 
-	authService := services.NewAuthService(userRepo, cfg)
-	userService := services.NewUserService(userRepo)
+	auditService := services.NewAuditService(auditLogRepo, hostedSubRepo, userRepo)
+	accountingService := services.NewAccountingService(journalRepo, hostedSubRepo, transactor, location)
+	authService := services.NewAuthService(userRepo, transactor, auditService, cfg)
+	userService := services.NewUserService(userRepo, transactor, auditService)
 	uploadService := services.NewUploadService(gcsUploader)
-	subscriptionCatalogService := services.NewSubscriptionCatalogService(subscriptionServiceRepo)
+	pushService := services.NewPushService(deviceTokenRepo, pushDispatcher, transactor, auditService)
+	notificationPrefService := services.NewNotificationPreferenceService(notificationPrefRepo, transactor, auditService, defaultLocale)
+	emailService := services.NewEmailService(mailer, mailRenderer, userRepo, notificationPrefService)
+	notificationService := services.NewNotificationService(notificationRepo, transactor, outboxPublisher)
+	subscriptionCatalogService := services.NewSubscriptionCatalogService(subscriptionServiceRepo, transactor, auditService)
 	hostedSubService := services.NewHostedSubscriptionService(
 		hostedSubRepo,
 		joinRequestRepo,
 		membershipRepo,
 		subscriptionServiceRepo,
 		userRepo,
+		waitlistRepo,
+		screeningQuestionRepo,
+		paymentRecordRepo,
+		transactor,
+		outboxPublisher,
+		auditService,
+	)
+	proofInspector := services.NewProofImageInspector(gcsUploader)
+	paymentService := services.NewPaymentService(paymentRecordRepo, paymentRecordMessageRepo, membershipLedgerRepo, membershipRepo, hostedSubRepo, transactor, outboxPublisher, auditService, accountingService, proofInspector, autoApprovalRepo)
+	refundService := services.NewRefundService(
+		refundRepo,
+		membershipRepo,
+		paymentRecordRepo,
+		membershipLedgerRepo,
+		accountingService,
+		transactor,
+		outboxPublisher,
+		auditService,
+	)
+	paymentReminderService := services.NewPaymentReminderService(paymentReminderRepo, hostedSubRepo, transactor, auditService)
+	webhookService := services.NewWebhookService(webhookRepo, transactor, auditService)
+	promptPayService := services.NewPromptPayService(hostedSubRepo, membershipRepo, transactor, auditService)
+	autoApprovalService := services.NewAutoApprovalService(autoApprovalRepo, hostedSubRepo, transactor, auditService)
+	hostDashboardService := services.NewHostDashboardService(hostDashboardRepo, location)
+	memberDuesService := services.NewMemberDuesService(membershipRepo, paymentRecordRepo, location)
+	exportService := services.NewExportService(hostedSubRepo, membershipRepo, paymentRecordRepo, membershipLedgerRepo, userRepo, location)
+	calendarService := services.NewCalendarService(calendarFeedRepo, membershipRepo, hostedSubRepo, transactor, auditService, location)
+	inviteService := services.NewInviteService(inviteRepo, hostedSubRepo, joinRequestRepo, membershipRepo, waitlistRepo, transactor, outboxPublisher, auditService)
+	waitlistService := services.NewWaitlistService(waitlistRepo, hostedSubRepo, joinRequestRepo, membershipRepo, screeningQuestionRepo, transactor, outboxPublisher, auditService)
+
+	if journaled, err := accountingService.BackfillApprovedPayments(ctx); err != nil {
+		log.Printf("ERROR: Failed to journal previously approved payments: %v", err)
+	} else if journaled > 0 {
+		log.Printf("INFO: Journaled %d previously approved payments", journaled)
+	}
+
+	outboxRelay := services.NewOutboxRelay(outboxRepo, transactor,
+		services.NewNotificationEventSubscriber(notificationService, hostedSubRepo, joinRequestRepo, paymentRecordRepo, refundRepo, userRepo),
+		services.NewNotificationChannelSubscriber(services.NewPushNotificationChannel(pushService), notificationRepo),
+		services.NewNotificationChannelSubscriber(
+			services.NewEmailNotificationChannel(emailService, hostedSubRepo, membershipRepo, paymentRecordRepo),
+			notificationRepo,
+		),
+		services.NewRealtimeOutboxSubscriber(eventBus),
+		services.NewWebhookEventSubscriber(webhookRepo),
+		services.NewWaitlistEventSubscriber(waitlistService),
 	)
-	paymentService := services.NewPaymentService(paymentRecordRepo, membershipRepo, hostedSubRepo)
+	outboxRelay.Start(ctx)
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepo, webhook.NewClient())
+	webhookDispatcher.Start(ctx)
+	paymentReminderJob := services.NewPaymentReminderJob(membershipRepo, paymentReminderRepo, notificationService, transactor, location)
+	paymentReminderJob.Start(ctx)
+	hostDigestJob := services.NewHostDigestJob(hostedSubRepo, joinRequestRepo, paymentRecordRepo, notificationPrefService, emailService, transactor, location)
+	hostDigestJob.Start(ctx)
+	waitlistJob := services.NewWaitlistJob(waitlistService)
+	waitlistJob.Start(ctx)
 
 	authHandler := handlers.NewAuthHandler(authService)
 	userHandler := handlers.NewUserHandler(userService, hostedSubService)
@@ -93,6 +243,23 @@ func main() {
 	subscriptionServiceHandler := handlers.NewSubscriptionServiceHandler(subscriptionCatalogService)
 	hostedSubHandler := handlers.NewHostedSubscriptionHandler(hostedSubService)
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	eventStreamHandler := handlers.NewEventStreamHandler(eventBus)
+	deviceHandler := handlers.NewDeviceHandler(pushService)
+	notificationPrefHandler := handlers.NewNotificationPreferenceHandler(notificationPrefService)
+	paymentReminderHandler := handlers.NewPaymentReminderHandler(paymentReminderService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditService)
+	accountingHandler := handlers.NewAccountingHandler(accountingService)
+	refundHandler := handlers.NewRefundHandler(refundService)
+	promptPayHandler := handlers.NewPromptPayHandler(promptPayService)
+	autoApprovalHandler := handlers.NewAutoApprovalHandler(autoApprovalService)
+	hostDashboardHandler := handlers.NewHostDashboardHandler(hostDashboardService)
+	memberDuesHandler := handlers.NewMemberDuesHandler(memberDuesService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	inviteHandler := handlers.NewInviteHandler(inviteService, cfg.InviteBaseURL)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
 
 	app := fiber.New(fiber.Config{
 		AppName: "Hubster App",
@@ -104,6 +271,7 @@ func main() {
 		AllowMethods: "GET, POST, PUT, PATCH, DELETE, OPTIONS",
 	}))
 	app.Use(logger.New())
+	app.Use(middleware.RequestMetadata())
 
 	handlers.SetupRoutes(
 		app,
@@ -113,6 +281,23 @@ func main() {
 		subscriptionServiceHandler,
 		hostedSubHandler,
 		paymentHandler,
+		notificationHandler,
+		eventStreamHandler,
+		deviceHandler,
+		notificationPrefHandler,
+		paymentReminderHandler,
+		webhookHandler,
+		auditLogHandler,
+		accountingHandler,
+		refundHandler,
+		promptPayHandler,
+		autoApprovalHandler,
+		hostDashboardHandler,
+		memberDuesHandler,
+		exportHandler,
+		calendarHandler,
+		inviteHandler,
+		waitlistHandler,
 		cfg)
 
 	log.Printf("INFO: Starting server on port %s", cfg.ServerPort)
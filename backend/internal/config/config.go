@@ -27,6 +27,20 @@ type Config struct {
 	CORSAllowedOrigins       string        `mapstructure:"CORS_ALLOWED_ORIGINS"`
 	GCSBucketName            string        `mapstructure:"GCS_BUCKET_NAME"`
 	GCSServiceAccountKeyPath string        `mapstructure:"GCS_SERVICE_ACCOUNT_KEY_PATH"`
+	FCMProjectID             string        `mapstructure:"FCM_PROJECT_ID"`
+	FCMServiceAccountKeyPath string        `mapstructure:"FCM_SERVICE_ACCOUNT_KEY_PATH"`
+	APNsAuthKeyPath          string        `mapstructure:"APNS_AUTH_KEY_PATH"`
+	APNsKeyID                string        `mapstructure:"APNS_KEY_ID"`
+	APNsTeamID               string        `mapstructure:"APNS_TEAM_ID"`
+	APNsTopic                string        `mapstructure:"APNS_TOPIC"`
+	APNsUseSandbox           bool          `mapstructure:"APNS_USE_SANDBOX"`
+	SMTPHost                 string        `mapstructure:"SMTP_HOST"`
+	SMTPPort                 string        `mapstructure:"SMTP_PORT"`
+	SMTPUsername             string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword             string        `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom                 string        `mapstructure:"SMTP_FROM"`
+	DefaultLocale            string        `mapstructure:"DEFAULT_LOCALE"`
+	InviteBaseURL            string        `mapstructure:"INVITE_BASE_URL"`
 }
 
 var AppConfig *Config
@@ -50,6 +64,11 @@ func LoadConfig(path string) (*Config, error) {
 	viper.SetDefault("JWT_SECRET", insecureDefaultJwtSecret)
 	viper.SetDefault("JWT_EXPIRES_IN_MINUTES", "60m")
 	viper.SetDefault("CORS_ALLOWED_ORIGINS", "*")
+	viper.SetDefault("APNS_USE_SANDBOX", false)
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("SMTP_FROM", "Hubster <no-reply@hubster.local>")
+	viper.SetDefault("DEFAULT_LOCALE", "th")
+	viper.SetDefault("INVITE_BASE_URL", "")
 
 	if err := viper.ReadInConfig(); err == nil {
 		log.Println("INFO: Config file loaded successfully.")
@@ -73,6 +92,14 @@ func LoadConfig(path string) (*Config, error) {
 		log.Println("WARNING: GCS_BUCKET_NAME or GCS_SERVICE_ACCOUNT_KEY_PATH not configured. Image upload functionality will be disabled.")
 	}
 
+	if cfg.FCMServiceAccountKeyPath == "" && cfg.APNsAuthKeyPath == "" {
+		log.Println("WARNING: FCM_SERVICE_ACCOUNT_KEY_PATH and APNS_AUTH_KEY_PATH not configured. Push notifications will be disabled.")
+	}
+
+	if cfg.SMTPHost == "" {
+		log.Println("WARNING: SMTP_HOST not configured. Email notifications will be disabled.")
+	}
+
 	AppConfig = &cfg
 	log.Printf("INFO: Configuration loaded successfully.")
 
@@ -13,11 +13,60 @@ import (
 
 var DB *gorm.DB
 
-func ConnectDB(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+// auditLogAppendOnlySQL makes Postgres reject any update or delete of audit log entries,
+// so the log stays trustworthy even if application code tries to rewrite it.
+const auditLogAppendOnlySQL = `
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+CREATE TRIGGER audit_logs_append_only
+	BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+`
+
+// memberSubscriptionIndexSQL turns the unique index on memberships created before memberships were soft-deleted
+// into a partial one, so a member who left can rejoin. AutoMigrate keeps an index that already exists by name.
+const memberSubscriptionIndexSQL = `
+DO $$
+BEGIN
+	IF EXISTS (
+		SELECT 1 FROM pg_indexes
+		WHERE tablename = 'subscription_memberships'
+			AND indexname = 'idx_member_subscription'
+			AND indexdef NOT LIKE '%WHERE%'
+	) THEN
+		DROP INDEX idx_member_subscription;
+		CREATE UNIQUE INDEX idx_member_subscription
+			ON subscription_memberships (member_user_id, hosted_subscription_id)
+			WHERE deleted_at IS NULL;
+	END IF;
+END $$;
+`
+
+// proofImageHashBandsSQL fills in the hash bands of proof images hashed before the bands were stored, split the
+// same way as phash.Bands.
+const proofImageHashBandsSQL = `
+UPDATE payment_records SET
+	proof_image_hash_band0 = proof_image_hash & 8191,
+	proof_image_hash_band1 = (proof_image_hash >> 13) & 8191,
+	proof_image_hash_band2 = (proof_image_hash >> 26) & 8191,
+	proof_image_hash_band3 = (proof_image_hash >> 39) & 8191,
+	proof_image_hash_band4 = (proof_image_hash >> 52) & 4095
+WHERE proof_image_hash IS NOT NULL AND proof_image_hash_band0 IS NULL;
+`
+
+// DSN builds the Postgres connection string from the configuration.
+func DSN(cfg *config.Config) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
 		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, cfg.DBSSLMode, cfg.TimeZone)
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+func ConnectDB(cfg *config.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(DSN(cfg)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 
@@ -36,10 +85,43 @@ func ConnectDB(cfg *config.Config) (*gorm.DB, error) {
 		&models.SubscriptionMembership{},
 		&models.JoinRequest{},
 		&models.PaymentRecord{},
+		&models.PaymentRecordMessage{},
+		&models.MembershipLedgerEntry{},
+		&models.PaymentCycleAllocation{},
+		&models.Notification{},
+		&models.DeviceToken{},
+		&models.NotificationPreference{},
+		&models.PaymentReminderRule{},
+		&models.PaymentReminderDispatch{},
+		&models.OutboxEvent{},
+		&models.ProcessedOutboxEvent{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{},
+		&models.JournalEntry{},
+		&models.JournalLine{},
+		&models.Refund{},
+		&models.AutoApprovalRule{},
+		&models.AutoApproval{},
+		&models.AuditLog{},
+		&models.CalendarFeed{},
+		&models.SubscriptionInvite{},
+		&models.WaitlistEntry{},
+		&models.ScreeningQuestion{},
+		&models.JoinRequestAnswer{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
+	if err := db.Exec(memberSubscriptionIndexSQL).Error; err != nil {
+		return nil, fmt.Errorf("failed to migrate membership index: %w", err)
+	}
+	if err := db.Exec(auditLogAppendOnlySQL).Error; err != nil {
+		return nil, fmt.Errorf("failed to protect audit log: %w", err)
+	}
+	if err := db.Exec(proofImageHashBandsSQL).Error; err != nil {
+		return nil, fmt.Errorf("failed to backfill proof image hash bands: %w", err)
+	}
 	log.Println("Database migrated successfully")
 
 	// Assign to global variable
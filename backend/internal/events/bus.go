@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+const subscriberBufferSize = 32
+
+// Publisher publishes domain events.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Bus publishes domain events and lets callers subscribe to the events addressed to a user.
+type Bus interface {
+	Publisher
+	Subscribe(userID uint) (<-chan Event, func())
+}
+
+// LocalBus fans events out to subscribers inside the current process.
+type LocalBus struct {
+	mu          sync.RWMutex
+	subscribers map[uint]map[chan Event]struct{}
+}
+
+// NewLocalBus creates an in-process event bus.
+func NewLocalBus() *LocalBus {
+	return &LocalBus{subscribers: make(map[uint]map[chan Event]struct{})}
+}
+
+// Publish delivers the event to every local subscriber of its recipients.
+func (b *LocalBus) Publish(_ context.Context, event Event) error {
+	b.deliver(event)
+	return nil
+}
+
+// Subscribe registers a subscriber for the user's events. The returned function must be called to unsubscribe.
+func (b *LocalBus) Subscribe(userID uint) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan Event]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[userID], ch)
+			if len(b.subscribers[userID]) == 0 {
+				delete(b.subscribers, userID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+func (b *LocalBus) deliver(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, userID := range event.RecipientUserIDs {
+		for ch := range b.subscribers[userID] {
+			select {
+			case ch <- event:
+			default:
+				log.Printf("Warning: Dropping event %s (%s) for slow subscriber of user %d", event.ID, event.Type, userID)
+			}
+		}
+	}
+}
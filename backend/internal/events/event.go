@@ -0,0 +1,97 @@
+package events
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type identifies the kind of domain event.
+type Type string
+
+const (
+	JoinRequestCreated    Type = "join_request.created"
+	JoinRequestApproved   Type = "join_request.approved"
+	JoinRequestDeclined   Type = "join_request.declined"
+	PaymentProofSubmitted Type = "payment_proof.submitted"
+	PaymentProofApproved  Type = "payment_proof.approved"
+	PaymentProofDeclined  Type = "payment_proof.declined"
+	PaymentProofDisputed  Type = "payment_proof.disputed"
+	PaymentProofReverted  Type = "payment_proof.approval_reverted"
+	PaymentMessagePosted  Type = "payment_record.message_posted"
+	MemberJoined          Type = "membership.joined" // Joined through an invite, without a join request to approve
+	MemberLeft            Type = "membership.left"
+	MemberRemoved         Type = "membership.removed"
+	SlotsChanged          Type = "hosted_subscription.slots_changed"
+	WaitlistSlotOffered   Type = "waitlist.slot_offered"
+	RefundIssued          Type = "refund.issued"
+	RefundAcknowledged    Type = "refund.acknowledged"
+	NotificationCreated   Type = "notification.created"
+)
+
+// WebhookTypes are the event types webhook endpoints can subscribe to. Slot changes and notifications are only
+// used inside the application.
+var WebhookTypes = []Type{
+	JoinRequestCreated,
+	JoinRequestApproved,
+	JoinRequestDeclined,
+	PaymentProofSubmitted,
+	PaymentProofApproved,
+	PaymentProofDeclined,
+	PaymentProofDisputed,
+	PaymentProofReverted,
+	PaymentMessagePosted,
+	MemberJoined,
+	MemberLeft,
+	MemberRemoved,
+	WaitlistSlotOffered,
+	RefundIssued,
+	RefundAcknowledged,
+}
+
+// IsWebhookType reports whether webhook endpoints can subscribe to events of type t.
+func IsWebhookType(t string) bool {
+	return slices.Contains(WebhookTypes, Type(t))
+}
+
+// Event describes a state change that interested users should hear about.
+type Event struct {
+	ID                   string    `json:"id"`
+	Type                 Type      `json:"type"`
+	OccurredAt           time.Time `json:"occurred_at"`
+	ActorUserID          uint      `json:"actor_user_id,omitempty"`
+	RecipientUserIDs     []uint    `json:"recipient_user_ids"`
+	HostedSubscriptionID uint      `json:"hosted_subscription_id,omitempty"`
+	JoinRequestID        uint      `json:"join_request_id,omitempty"`
+	MembershipID         uint      `json:"membership_id,omitempty"`
+	PaymentRecordID      uint      `json:"payment_record_id,omitempty"`
+	RefundID             uint      `json:"refund_id,omitempty"`
+	WaitlistEntryID      uint      `json:"waitlist_entry_id,omitempty"`
+	NotificationID       uint      `json:"notification_id,omitempty"`
+	Status               string    `json:"status,omitempty"`
+}
+
+// New creates an event of the given type addressed to the given users.
+func New(eventType Type, actorUserID uint, recipientUserIDs ...uint) Event {
+	return Event{
+		ID:               uuid.NewString(),
+		Type:             eventType,
+		OccurredAt:       time.Now().UTC(),
+		ActorUserID:      actorUserID,
+		RecipientUserIDs: uniqueRecipients(recipientUserIDs),
+	}
+}
+
+func uniqueRecipients(userIDs []uint) []uint {
+	seen := make(map[uint]bool, len(userIDs))
+	recipients := make([]uint, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		recipients = append(recipients, id)
+	}
+	return recipients
+}
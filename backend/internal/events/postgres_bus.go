@@ -0,0 +1,109 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	postgresChannel = "hubster_events"
+	// Postgres rejects NOTIFY payloads of 8000 bytes or more.
+	maxNotifyPayloadBytes = 7999
+	maxReconnectBackoff   = 30 * time.Second
+)
+
+// PostgresBus shares events between backend instances through Postgres LISTEN/NOTIFY.
+// Every instance listens on the same channel and delivers received events to its local subscribers.
+type PostgresBus struct {
+	local *LocalBus
+	db    *gorm.DB
+	dsn   string
+}
+
+// NewPostgresBus creates an event bus that publishes with NOTIFY on db and listens on a dedicated connection to dsn.
+func NewPostgresBus(db *gorm.DB, dsn string) *PostgresBus {
+	return &PostgresBus{
+		local: NewLocalBus(),
+		db:    db,
+		dsn:   dsn,
+	}
+}
+
+// Publish sends the event to all backend instances, including this one.
+func (b *PostgresBus) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshalling event: %w", err)
+	}
+	if len(payload) > maxNotifyPayloadBytes {
+		b.local.deliver(event)
+		return fmt.Errorf("event %s payload too large for NOTIFY (%d bytes), delivered locally only", event.ID, len(payload))
+	}
+
+	if err := b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", postgresChannel, string(payload)).Error; err != nil {
+		b.local.deliver(event)
+		return fmt.Errorf("notifying event %s, delivered locally only: %w", event.ID, err)
+	}
+	return nil
+}
+
+// Subscribe registers a subscriber for the user's events on this instance.
+func (b *PostgresBus) Subscribe(userID uint) (<-chan Event, func()) {
+	return b.local.Subscribe(userID)
+}
+
+// Start listens for events in the background until ctx is cancelled, reconnecting when the connection drops.
+func (b *PostgresBus) Start(ctx context.Context) {
+	go func() {
+		backoff := time.Second
+		for {
+			connected, err := b.listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if connected {
+				backoff = time.Second
+			}
+			log.Printf("WARNING: Event listener stopped: %v. Reconnecting in %s.", err, backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxReconnectBackoff)
+		}
+	}()
+}
+
+func (b *PostgresBus) listen(ctx context.Context) (bool, error) {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return false, fmt.Errorf("connecting event listener: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+postgresChannel); err != nil {
+		return false, fmt.Errorf("listening on channel %s: %w", postgresChannel, err)
+	}
+	log.Printf("INFO: Listening for events on Postgres channel '%s'", postgresChannel)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, fmt.Errorf("waiting for notification: %w", err)
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("Warning: Ignoring malformed event payload on channel %s: %v", postgresChannel, err)
+			continue
+		}
+		b.local.deliver(event)
+	}
+}
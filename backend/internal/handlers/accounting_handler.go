@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// AccountingHandler handles earnings, spending and receivables reports.
+type AccountingHandler struct {
+	accountingService services.AccountingService
+}
+
+// NewAccountingHandler creates a new AccountingHandler.
+func NewAccountingHandler(accountingService services.AccountingService) *AccountingHandler {
+	return &AccountingHandler{accountingService: accountingService}
+}
+
+// GetMyEarnings handles a host viewing their earnings.
+// @Summary Get my earnings as a host
+// @Description Reports, per hosted subscription and month, the amount billed to members (earned) and the amount received from them (collected). Defaults to the last twelve months.
+// @Tags Accounting
+// @Produce json
+// @Param from query string false "Only entries at or after this time (RFC 3339)"
+// @Param to query string false "Only entries before this time (RFC 3339)"
+// @Security BearerAuth
+// @Success 200 {array} models.HostEarningsRow "Earnings per subscription and month, newest month first"
+// @Failure 400 {object} ErrorResponse "Invalid time range"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/earnings [get]
+func (h *AccountingHandler) GetMyEarnings(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	from, err := parseOptionalTimeQuery(c, "from")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid query parameter", Details: err.Error()})
+	}
+	to, err := parseOptionalTimeQuery(c, "to")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid query parameter", Details: err.Error()})
+	}
+
+	rows, err := h.accountingService.HostEarnings(c.Context(), userID, from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReportRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+		}
+		log.Printf("Error reporting earnings for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve earnings"})
+	}
+	if rows == nil {
+		rows = []models.HostEarningsRow{}
+	}
+	return c.Status(fiber.StatusOK).JSON(rows)
+}
+
+// GetMySpending handles a member viewing their spending.
+// @Summary Get my spending as a member
+// @Description Reports how much the authenticated member paid per subscription service in a calendar year, net of refunds.
+// @Tags Accounting
+// @Produce json
+// @Param year query int false "Calendar year (defaults to the current year)"
+// @Security BearerAuth
+// @Success 200 {array} models.MemberSpendRow "Spending per service, highest first"
+// @Failure 400 {object} ErrorResponse "Invalid year"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/spending [get]
+func (h *AccountingHandler) GetMySpending(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	year := c.QueryInt("year", 0)
+	if year < 0 || year > 9999 {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid year"})
+	}
+
+	rows, err := h.accountingService.MemberSpend(c.Context(), userID, year)
+	if err != nil {
+		log.Printf("Error reporting spending for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve spending"})
+	}
+	if rows == nil {
+		rows = []models.MemberSpendRow{}
+	}
+	return c.Status(fiber.StatusOK).JSON(rows)
+}
+
+// GetMyReceivables handles a host viewing what their members owe.
+// @Summary Get my outstanding receivables as a host
+// @Description Lists the memberships of the authenticated host's subscriptions with an amount still owed, largest first.
+// @Tags Accounting
+// @Produce json
+// @Param hosted_subscription_id query int false "Only this hosted subscription"
+// @Security BearerAuth
+// @Success 200 {array} models.ReceivableRow "Outstanding amounts per membership"
+// @Failure 400 {object} ErrorResponse "Invalid query parameter"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of the subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/receivables [get]
+func (h *AccountingHandler) GetMyReceivables(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	hostedSubscriptionID, err := parseOptionalUintQuery(c, "hosted_subscription_id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid query parameter", Details: err.Error()})
+	}
+
+	rows, err := h.accountingService.Receivables(c.Context(), userID, hostedSubscriptionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSubscriptionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error reporting receivables for user %d: %v", userID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve receivables"})
+		}
+	}
+	if rows == nil {
+		rows = []models.ReceivableRow{}
+	}
+	return c.Status(fiber.StatusOK).JSON(rows)
+}
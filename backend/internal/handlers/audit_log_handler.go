@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// AuditLogHandler handles audit log queries.
+type AuditLogHandler struct {
+	auditService services.AuditService
+}
+
+// NewAuditLogHandler creates a new AuditLogHandler.
+func NewAuditLogHandler(auditService services.AuditService) *AuditLogHandler {
+	return &AuditLogHandler{auditService: auditService}
+}
+
+// ListHostedSubscriptionAuditLog handles listing the audit log of one of the host's subscriptions.
+// @Summary List the audit log of a hosted subscription
+// @Description Retrieves the recorded actions on a subscription owned by the authenticated host (join requests, memberships, payment reviews, settings), newest first.
+// @Tags AuditLogs
+// @Produce json
+// @Param subscriptionId path int true "Hosted Subscription ID"
+// @Param action query string false "Only entries with this action, e.g. payment_record.approve"
+// @Param entity_type query string false "Only entries about this entity type, e.g. payment_record"
+// @Param entity_id query int false "Only entries about this entity ID"
+// @Param actor_user_id query int false "Only entries made by this user"
+// @Param from query string false "Only entries at or after this time (RFC 3339)"
+// @Param to query string false "Only entries before this time (RFC 3339)"
+// @Param limit query int false "Maximum number of entries to return (default 50, max 200)"
+// @Param offset query int false "Number of entries to skip"
+// @Security BearerAuth
+// @Success 200 {array} models.AuditLog "A list of audit log entries"
+// @Failure 400 {object} ErrorResponse "Invalid ID or filter format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Hosted subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/audit-logs [get]
+func (h *AuditLogHandler) ListHostedSubscriptionAuditLog(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+	filter, err := parseAuditLogFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid filter", Details: err.Error()})
+	}
+
+	entries, err := h.auditService.ListForHostedSubscription(c.Context(), userID, uint(subscriptionID), filter, c.QueryInt("limit", 0), c.QueryInt("offset", 0))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSubscriptionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error listing audit log of subscription %d for host %d: %v", subscriptionID, userID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve audit log"})
+		}
+	}
+	if entries == nil {
+		entries = []models.AuditLog{}
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+// ListAllAuditLogs handles listing the audit log across all users.
+// @Summary List the global audit log
+// @Description Retrieves recorded actions across all users, newest first. Admins only.
+// @Tags AuditLogs
+// @Produce json
+// @Param hosted_subscription_id query int false "Only entries about this hosted subscription"
+// @Param action query string false "Only entries with this action, e.g. payment_record.approve"
+// @Param entity_type query string false "Only entries about this entity type, e.g. payment_record"
+// @Param entity_id query int false "Only entries about this entity ID"
+// @Param actor_user_id query int false "Only entries made by this user"
+// @Param from query string false "Only entries at or after this time (RFC 3339)"
+// @Param to query string false "Only entries before this time (RFC 3339)"
+// @Param limit query int false "Maximum number of entries to return (default 50, max 200)"
+// @Param offset query int false "Number of entries to skip"
+// @Security BearerAuth
+// @Success 200 {array} models.AuditLog "A list of audit log entries"
+// @Failure 400 {object} ErrorResponse "Invalid filter format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not an admin)"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/audit-logs [get]
+func (h *AuditLogHandler) ListAllAuditLogs(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	filter, err := parseAuditLogFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid filter", Details: err.Error()})
+	}
+	if filter.HostedSubscriptionID, err = parseOptionalUintQuery(c, "hosted_subscription_id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid filter", Details: err.Error()})
+	}
+
+	entries, err := h.auditService.ListAll(c.Context(), userID, filter, c.QueryInt("limit", 0), c.QueryInt("offset", 0))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrUserNotFound):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: services.ErrForbidden.Error()})
+		default:
+			log.Printf("Error listing global audit log for user %d: %v", userID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve audit log"})
+		}
+	}
+	if entries == nil {
+		entries = []models.AuditLog{}
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+func parseAuditLogFilter(c *fiber.Ctx) (models.AuditLogFilter, error) {
+	filter := models.AuditLogFilter{
+		Action:     models.AuditAction(c.Query("action")),
+		EntityType: c.Query("entity_type"),
+	}
+
+	var err error
+	if filter.EntityID, err = parseOptionalUintQuery(c, "entity_id"); err != nil {
+		return filter, err
+	}
+	if filter.ActorUserID, err = parseOptionalUintQuery(c, "actor_user_id"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = parseOptionalTimeQuery(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseOptionalTimeQuery(c, "to"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func parseOptionalUintQuery(c *fiber.Ctx, key string) (*uint, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a positive integer", key)
+	}
+	id := uint(value)
+	return &id, nil
+}
+
+func parseOptionalTimeQuery(c *fiber.Ctx, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", key)
+	}
+	return &value, nil
+}
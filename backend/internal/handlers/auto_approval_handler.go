@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// AutoApprovalHandler handles requests related to hosted subscriptions' auto-approval rules and their log.
+type AutoApprovalHandler struct {
+	autoApprovalService services.AutoApprovalService
+	validate            *validator.Validate
+}
+
+// NewAutoApprovalHandler creates a new AutoApprovalHandler.
+func NewAutoApprovalHandler(autoApprovalService services.AutoApprovalService) *AutoApprovalHandler {
+	return &AutoApprovalHandler{
+		autoApprovalService: autoApprovalService,
+		validate:            validator.New(),
+	}
+}
+
+// ListAutoApprovalRules handles a host viewing the auto-approval rules of their subscription.
+// @Summary List auto-approval rules
+// @Description Returns the auto-approval rules of a subscription owned by the authenticated host, in the order they are evaluated when a payment proof is submitted.
+// @Tags AutoApproval
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Security BearerAuth
+// @Success 200 {array} models.AutoApprovalRule "Auto-approval rules"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/auto-approval-rules [get]
+func (h *AutoApprovalHandler) ListAutoApprovalRules(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	rules, err := h.autoApprovalService.ListRules(c.Context(), hostUserID, uint(subscriptionID))
+	if err != nil {
+		return h.handleAutoApprovalError(c, err, "Failed to retrieve auto-approval rules")
+	}
+	if rules == nil {
+		rules = []models.AutoApprovalRule{}
+	}
+	return c.Status(fiber.StatusOK).JSON(rules)
+}
+
+// CreateAutoApprovalRule handles a host adding an auto-approval rule to their subscription.
+// @Summary Create an auto-approval rule
+// @Description Adds a rule that approves submitted payment proofs meeting all its conditions: the exact amount expected, a transaction reference not used by any other payment, a verified bank slip, or a minimum number of the member's earlier payments approved on time. A rule needs at least one condition. Proofs flagged by the slip or image checks are never approved automatically.
+// @Tags AutoApproval
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param rule body models.AutoApprovalRuleRequest true "Rule name, conditions and enabled state"
+// @Security BearerAuth
+// @Success 201 {object} models.AutoApprovalRule "Auto-approval rule created"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format, validation error or rule without conditions"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 409 {object} ErrorResponse "Maximum number of auto-approval rules reached"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/auto-approval-rules [post]
+func (h *AutoApprovalHandler) CreateAutoApprovalRule(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	req := new(models.AutoApprovalRuleRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	rule, err := h.autoApprovalService.CreateRule(c.Context(), hostUserID, uint(subscriptionID), req)
+	if err != nil {
+		return h.handleAutoApprovalError(c, err, "Failed to create auto-approval rule")
+	}
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+// UpdateAutoApprovalRule handles a host changing one of their subscription's auto-approval rules.
+// @Summary Update an auto-approval rule
+// @Description Replaces the name, conditions and enabled state of an auto-approval rule. Payments it already approved are unaffected.
+// @Tags AutoApproval
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param ruleId path int true "ID of the auto-approval rule"
+// @Param rule body models.AutoApprovalRuleRequest true "Rule name, conditions and enabled state"
+// @Security BearerAuth
+// @Success 200 {object} models.AutoApprovalRule "Auto-approval rule updated"
+// @Failure 400 {object} ErrorResponse "Invalid ID format, validation error or rule without conditions"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription or rule not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/auto-approval-rules/{ruleId} [put]
+func (h *AutoApprovalHandler) UpdateAutoApprovalRule(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+	ruleID, err := strconv.ParseUint(c.Params("ruleId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid auto-approval rule ID format"})
+	}
+
+	req := new(models.AutoApprovalRuleRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	rule, err := h.autoApprovalService.UpdateRule(c.Context(), hostUserID, uint(subscriptionID), uint(ruleID), req)
+	if err != nil {
+		return h.handleAutoApprovalError(c, err, "Failed to update auto-approval rule")
+	}
+	return c.Status(fiber.StatusOK).JSON(rule)
+}
+
+// DeleteAutoApprovalRule handles a host removing one of their subscription's auto-approval rules.
+// @Summary Delete an auto-approval rule
+// @Description Removes an auto-approval rule. The auto-approval log keeps the name and conditions of the payments it approved.
+// @Tags AutoApproval
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param ruleId path int true "ID of the auto-approval rule"
+// @Security BearerAuth
+// @Success 200 {object} object "message: Auto-approval rule deleted successfully"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription or rule not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/auto-approval-rules/{ruleId} [delete]
+func (h *AutoApprovalHandler) DeleteAutoApprovalRule(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+	ruleID, err := strconv.ParseUint(c.Params("ruleId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid auto-approval rule ID format"})
+	}
+
+	if err := h.autoApprovalService.DeleteRule(c.Context(), hostUserID, uint(subscriptionID), uint(ruleID)); err != nil {
+		return h.handleAutoApprovalError(c, err, "Failed to delete auto-approval rule")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Auto-approval rule deleted successfully"})
+}
+
+// ListAutoApprovals handles a host viewing which payments their subscription's rules approved.
+// @Summary List auto-approved payments
+// @Description Retrieves a page of the payment records approved by the subscription's auto-approval rules, newest first, with the rule and conditions that approved each and whether the host reverted it.
+// @Tags AutoApproval
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param limit query int false "Maximum number of entries to return (default 20, max 100)"
+// @Param offset query int false "Number of entries to skip"
+// @Security BearerAuth
+// @Success 200 {array} models.AutoApproval "Auto-approval log"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/auto-approvals [get]
+func (h *AutoApprovalHandler) ListAutoApprovals(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+	limit := c.QueryInt("limit", 0)
+	offset := c.QueryInt("offset", 0)
+
+	approvals, err := h.autoApprovalService.ListApprovals(c.Context(), hostUserID, uint(subscriptionID), limit, offset)
+	if err != nil {
+		return h.handleAutoApprovalError(c, err, "Failed to retrieve auto-approvals")
+	}
+	if approvals == nil {
+		approvals = []models.AutoApproval{}
+	}
+	return c.Status(fiber.StatusOK).JSON(approvals)
+}
+
+func (h *AutoApprovalHandler) handleAutoApprovalError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrSubscriptionNotFound), errors.Is(err, services.ErrAutoApprovalRuleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrAutoApprovalRuleNoConditions):
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrTooManyAutoApprovalRules):
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling auto-approval request %s %s: %v", c.Method(), c.Path(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: fallback})
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// newBulkReviewResponse reports each item of a bulk review with the status and error its single-item endpoint
+// would have answered. Unexpected errors are logged and reported with the fallback message.
+func newBulkReviewResponse(c *fiber.Ctx, results []services.BulkItemResult, errorStatus func(error) int, fallback string) models.BulkReviewResponse {
+	resp := models.BulkReviewResponse{Results: make([]models.BulkReviewItemResult, 0, len(results))}
+	for _, result := range results {
+		item := models.BulkReviewItemResult{ID: result.ID, Succeeded: result.Err == nil, StatusCode: fiber.StatusOK, Status: result.Status}
+		if result.Err != nil {
+			item.StatusCode = errorStatus(result.Err)
+			item.Error = result.Err.Error()
+			if item.StatusCode == fiber.StatusInternalServerError {
+				log.Printf("Error handling bulk review item %d in %s %s: %v", result.ID, c.Method(), c.Path(), result.Err)
+				item.Error = fallback
+			}
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	return resp
+}
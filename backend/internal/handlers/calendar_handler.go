@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// CalendarHandler handles users' calendar feeds and the renewal dates of hosted subscriptions.
+type CalendarHandler struct {
+	calendarService services.CalendarService
+	validate        *validator.Validate
+}
+
+// NewCalendarHandler creates a new CalendarHandler.
+func NewCalendarHandler(calendarService services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+		validate:        validator.New(),
+	}
+}
+
+// GetMyCalendarFeed handles retrieving the status of the current user's calendar feed.
+// @Summary Get my calendar feed
+// @Description Returns whether the authenticated user has a calendar feed and when it was last fetched. The feed URL is only shown when a token is issued.
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.CalendarFeedResponse "Calendar feed status"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/calendar-feed [get]
+func (h *CalendarHandler) GetMyCalendarFeed(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	feed, err := h.calendarService.GetFeed(c.Context(), userID)
+	if err != nil {
+		return h.handleCalendarError(c, err, "Failed to retrieve calendar feed")
+	}
+	return c.Status(fiber.StatusOK).JSON(feed)
+}
+
+// IssueMyCalendarFeedToken handles creating the current user's calendar feed or rotating its token.
+// @Summary Issue a calendar feed URL
+// @Description Creates the authenticated user's iCalendar feed of payment due dates and renewal dates, or rotates its secret token so the previous URL stops working. The returned URL is shown only once; subscribe to it from a calendar app.
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.CalendarFeedResponse "Calendar feed with its new URL"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/calendar-feed [post]
+func (h *CalendarHandler) IssueMyCalendarFeedToken(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	token, feed, err := h.calendarService.IssueFeedToken(c.Context(), userID)
+	if err != nil {
+		return h.handleCalendarError(c, err, "Failed to issue calendar feed")
+	}
+	feed.URL = c.BaseURL() + "/api/calendar/" + token + ".ics"
+	return c.Status(fiber.StatusOK).JSON(feed)
+}
+
+// DeleteMyCalendarFeed handles turning the current user's calendar feed off.
+// @Summary Delete my calendar feed
+// @Description Turns the authenticated user's calendar feed off; its URL stops working.
+// @Tags Calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object "message: Calendar feed deleted successfully"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Calendar feed not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/calendar-feed [delete]
+func (h *CalendarHandler) DeleteMyCalendarFeed(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	if err := h.calendarService.DeleteFeed(c.Context(), userID); err != nil {
+		return h.handleCalendarError(c, err, "Failed to delete calendar feed")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Calendar feed deleted successfully"})
+}
+
+// GetCalendarFeed handles a calendar app fetching a feed. The secret token in the path authenticates the request.
+// @Summary Fetch a calendar feed
+// @Description Returns an iCalendar feed with an all-day event on the next payment date of each of the feed owner's memberships and a recurring event on the renewal date of each subscription they host. Events keep the same UID when their date moves.
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Secret feed token"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 404 {object} ErrorResponse "Calendar feed not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /calendar/{token}.ics [get]
+func (h *CalendarHandler) GetCalendarFeed(c *fiber.Ctx) error {
+	feed, err := h.calendarService.RenderFeed(c.Context(), c.Params("token"))
+	if err != nil {
+		return h.handleCalendarError(c, err, "Failed to render calendar feed")
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return c.Status(fiber.StatusOK).Send(feed)
+}
+
+// GetRenewalDate handles a host viewing the renewal date of their subscription.
+// @Summary Get the renewal date
+// @Description Returns the date the plan of a subscription owned by the authenticated host renews on. It repeats every billing cycle in the host's calendar feed.
+// @Tags Calendar
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Security BearerAuth
+// @Success 200 {object} models.RenewalDateResponse "Renewal date"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/renewal-date [get]
+func (h *CalendarHandler) GetRenewalDate(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	renewal, err := h.calendarService.GetRenewalDate(c.Context(), hostUserID, uint(subscriptionID))
+	if err != nil {
+		return h.handleCalendarError(c, err, "Failed to retrieve renewal date")
+	}
+	return c.Status(fiber.StatusOK).JSON(renewal)
+}
+
+// UpdateRenewalDate handles a host setting the renewal date of their subscription.
+// @Summary Update the renewal date
+// @Description Sets the date (YYYY-MM-DD) the plan of a subscription owned by the authenticated host renews on. An empty date clears it.
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param renewal body models.UpdateRenewalDateRequest true "New renewal date"
+// @Security BearerAuth
+// @Success 200 {object} models.RenewalDateResponse "Updated renewal date"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/renewal-date [put]
+func (h *CalendarHandler) UpdateRenewalDate(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	req := new(models.UpdateRenewalDateRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	renewal, err := h.calendarService.UpdateRenewalDate(c.Context(), hostUserID, uint(subscriptionID), req)
+	if err != nil {
+		return h.handleCalendarError(c, err, "Failed to update renewal date")
+	}
+	return c.Status(fiber.StatusOK).JSON(renewal)
+}
+
+func (h *CalendarHandler) handleCalendarError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrCalendarFeedNotFound), errors.Is(err, services.ErrSubscriptionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidRenewalDate):
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling calendar request: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: fallback})
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// DeviceHandler handles push notification device registration.
+type DeviceHandler struct {
+	pushService services.PushService
+	validate    *validator.Validate
+}
+
+// NewDeviceHandler creates a new DeviceHandler.
+func NewDeviceHandler(pushService services.PushService) *DeviceHandler {
+	return &DeviceHandler{
+		pushService: pushService,
+		validate:    validator.New(),
+	}
+}
+
+// RegisterDevice handles registering the current device for push notifications.
+// @Summary Register a device for push notifications
+// @Description Registers (or refreshes) an FCM or APNs token for the authenticated user. Provider defaults to fcm.
+// @Tags Devices
+// @Accept json
+// @Produce json
+// @Param device body models.RegisterDeviceRequest true "Device token details"
+// @Security BearerAuth
+// @Success 201 {object} models.DeviceToken "Device registered successfully"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/devices [post]
+func (h *DeviceHandler) RegisterDevice(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	req := new(models.RegisterDeviceRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	device, err := h.pushService.RegisterDevice(c.Context(), userID, req)
+	if err != nil {
+		log.Printf("Error registering device for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to register device"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(device)
+}
+
+// ListMyDevices handles listing the current user's registered devices.
+// @Summary List my registered devices
+// @Description Retrieves the push notification devices registered by the authenticated user.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.DeviceToken "A list of registered devices"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/devices [get]
+func (h *DeviceHandler) ListMyDevices(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	devices, err := h.pushService.ListDevices(c.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing devices for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve devices"})
+	}
+	if devices == nil {
+		devices = []models.DeviceToken{}
+	}
+	return c.Status(fiber.StatusOK).JSON(devices)
+}
+
+// UnregisterDevice handles removing one of the current user's devices.
+// @Summary Unregister a device
+// @Description Removes a push notification device of the authenticated user, e.g. on logout.
+// @Tags Devices
+// @Produce json
+// @Param id path int true "Device ID"
+// @Security BearerAuth
+// @Success 200 {object} object "message: Device unregistered successfully"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the owner of the device)"
+// @Failure 404 {object} ErrorResponse "Device not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/devices/{id} [delete]
+func (h *DeviceHandler) UnregisterDevice(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	deviceIDStr := c.Params("id")
+	deviceID, err := strconv.ParseUint(deviceIDStr, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid device ID format"})
+	}
+
+	err = h.pushService.UnregisterDevice(c.Context(), userID, uint(deviceID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDeviceNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error unregistering device %d for user %d: %v", deviceID, userID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to unregister device"})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Device unregistered successfully"})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"github.com/xNatthapol/hubster/internal/events"
+	"github.com/xNatthapol/hubster/internal/middleware"
+)
+
+const eventStreamKeepAliveInterval = 25 * time.Second
+
+// EventStreamHandler streams domain events to connected clients using Server-Sent Events.
+type EventStreamHandler struct {
+	bus events.Bus
+}
+
+// NewEventStreamHandler creates a new EventStreamHandler.
+func NewEventStreamHandler(bus events.Bus) *EventStreamHandler {
+	return &EventStreamHandler{bus: bus}
+}
+
+// StreamMyEvents handles a long-lived Server-Sent Events connection for the current user.
+// @Summary Stream my real-time events
+// @Description Opens a Server-Sent Events stream that pushes domain events (join requests, payment proofs, members leaving) addressed to the authenticated user. Each message's event name is the event type and its data is the JSON-encoded event.
+// @Tags Events
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Success 200 {string} string "Event stream"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /users/me/events [get]
+func (h *EventStreamHandler) StreamMyEvents(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	eventsCh, unsubscribe := h.bus.Subscribe(userID)
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		keepAlive := time.NewTicker(eventStreamKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		fmt.Fprint(w, "retry: 5000\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-eventsCh:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					log.Printf("Error marshalling event %s for user %d: %v", event.ID, userID, err)
+					continue
+				}
+				fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+
+			// A failed flush means the client has disconnected.
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return nil
+}
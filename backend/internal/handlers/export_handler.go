@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// exportablePaymentRecordStatuses are the statuses a payment record export can be filtered by.
+var exportablePaymentRecordStatuses = map[models.PaymentRecordStatus]bool{
+	models.PaymentRecordStatusProofSubmitted:    true,
+	models.PaymentRecordStatusApproved:          true,
+	models.PaymentRecordStatusDeclined:          true,
+	models.PaymentRecordStatusRequiresAttention: true,
+	models.PaymentRecordStatusDisputed:          true,
+	models.PaymentRecordStatusSuperseded:        true,
+}
+
+// ExportHandler handles CSV exports and PDF statements of payment history.
+type ExportHandler struct {
+	exportService services.ExportService
+}
+
+// NewExportHandler creates a new ExportHandler.
+func NewExportHandler(exportService services.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// ExportPaymentRecords handles a host exporting a subscription's payment records.
+// @Summary Export a subscription's payment records as CSV
+// @Description Streams every payment record of a subscription owned by the authenticated host as CSV, oldest first, optionally limited to a submission date range and to some statuses.
+// @Tags Exports
+// @Produce text/csv
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param from query string false "Only records submitted at or after this time (RFC 3339)"
+// @Param to query string false "Only records submitted before this time (RFC 3339)"
+// @Param status query string false "Comma-separated statuses to include (e.g. Approved,Declined)"
+// @Security BearerAuth
+// @Success 200 {file} binary "CSV file"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID, time range or status"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/payment-records/export.csv [get]
+func (h *ExportHandler) ExportPaymentRecords(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+	filter := models.PaymentRecordExportFilter{HostedSubscriptionID: uint(subscriptionID)}
+	if filter.From, err = parseOptionalTimeQuery(c, "from"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid query parameter", Details: err.Error()})
+	}
+	if filter.To, err = parseOptionalTimeQuery(c, "to"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid query parameter", Details: err.Error()})
+	}
+	if raw := c.Query("status"); raw != "" {
+		for _, value := range strings.Split(raw, ",") {
+			status := models.PaymentRecordStatus(strings.TrimSpace(value))
+			if !exportablePaymentRecordStatuses[status] {
+				return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid query parameter", Details: "unknown status " + string(status)})
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	file, err := h.exportService.PaymentRecordsCSV(c.Context(), hostUserID, filter)
+	if err != nil {
+		return h.handleExportError(c, err, "Failed to export payment records")
+	}
+	return sendExportFile(c, file)
+}
+
+// GetSubscriptionStatement handles a host downloading a subscription's monthly statement.
+// @Summary Get a subscription's monthly statement as PDF
+// @Description Renders the monthly statement of a subscription owned by the authenticated host: every member's charges, payments and refunds in the month, with each member's opening and closing balance and the month's totals.
+// @Tags Exports
+// @Produce application/pdf
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param month path string true "Statement month (YYYY-MM)"
+// @Security BearerAuth
+// @Success 200 {file} binary "PDF statement"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID or month"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/statements/{month} [get]
+func (h *ExportHandler) GetSubscriptionStatement(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	file, err := h.exportService.SubscriptionStatementPDF(c.Context(), hostUserID, uint(subscriptionID), c.Params("month"))
+	if err != nil {
+		return h.handleExportError(c, err, "Failed to generate statement")
+	}
+	return sendExportFile(c, file)
+}
+
+// GetMembershipStatement handles downloading one membership's monthly statement.
+// @Summary Get a membership's monthly statement as PDF
+// @Description Renders the monthly statement of one membership: the member's charges, payments and refunds in the month with their opening and closing balance. Available to the member and the host, also after the member has left.
+// @Tags Exports
+// @Produce application/pdf
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Param month path string true "Statement month (YYYY-MM)"
+// @Security BearerAuth
+// @Success 200 {file} binary "PDF statement"
+// @Failure 400 {object} ErrorResponse "Invalid membership ID or month"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (neither the member nor the host)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /memberships/{membershipId}/statements/{month} [get]
+func (h *ExportHandler) GetMembershipStatement(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	membershipID, err := strconv.ParseUint(c.Params("membershipId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid membership ID format"})
+	}
+
+	file, err := h.exportService.MembershipStatementPDF(c.Context(), userID, uint(membershipID), c.Params("month"))
+	if err != nil {
+		return h.handleExportError(c, err, "Failed to generate statement")
+	}
+	return sendExportFile(c, file)
+}
+
+func (h *ExportHandler) handleExportError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrSubscriptionNotFound), errors.Is(err, services.ErrMembershipNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidStatementMonth), errors.Is(err, services.ErrInvalidReportRange):
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling export request %s %s: %v", c.Method(), c.Path(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: fallback})
+	}
+}
+
+// sendExportFile streams an export to the client as a download. The response has started by the time the file
+// is written, so a failure part way only cuts the download short and is logged.
+func sendExportFile(c *fiber.Ctx, file *services.ExportFile) error {
+	path := c.Path()
+	c.Attachment(file.Filename)
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := file.Write(context.Background(), w); err != nil {
+			log.Printf("Error streaming export %s for %s: %v", file.Filename, path, err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("Error streaming export %s for %s: %v", file.Filename, path, err)
+		}
+	})
+	return nil
+}
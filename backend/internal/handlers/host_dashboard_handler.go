@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// HostDashboardHandler handles the host dashboard.
+type HostDashboardHandler struct {
+	dashboardService services.HostDashboardService
+}
+
+// NewHostDashboardHandler creates a new HostDashboardHandler.
+func NewHostDashboardHandler(dashboardService services.HostDashboardService) *HostDashboardHandler {
+	return &HostDashboardHandler{dashboardService: dashboardService}
+}
+
+// GetMyHostDashboard handles a host viewing the summary of all their subscriptions.
+// @Summary Get my host dashboard
+// @Description Summarizes every subscription of the authenticated host in one response: members, pending join requests, payment proofs awaiting review, disputed payments, overdue members and outstanding amounts, with the revenue expected and collected for the cycles due in the current month. Also lists the items waiting on the host, oldest first.
+// @Tags HostDashboard
+// @Produce json
+// @Param limit query int false "Maximum number of action items (default 50, max 200)"
+// @Security BearerAuth
+// @Success 200 {object} models.HostDashboardResponse "Host dashboard"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/host-dashboard [get]
+func (h *HostDashboardHandler) GetMyHostDashboard(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	dashboard, err := h.dashboardService.GetDashboard(c.Context(), userID, c.QueryInt("limit", 0))
+	if err != nil {
+		log.Printf("Error building host dashboard for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve host dashboard"})
+	}
+	return c.Status(fiber.StatusOK).JSON(dashboard)
+}
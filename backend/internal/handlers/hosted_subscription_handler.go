@@ -55,6 +55,9 @@ func (h *HostedSubscriptionHandler) CreateHostedSubscription(c *fiber.Ctx) error
 		if errors.Is(err, services.ErrServiceNotFound) {
 			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error(), Details: "Invalid subscription_service_id provided."})
 		}
+		if errors.Is(err, services.ErrInvalidPromptPayID) || errors.Is(err, services.ErrInvalidRenewalDate) {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+		}
 		log.Printf("Error creating hosted subscription for user %d: %v", hostUserID, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to create hosted subscription"})
 	}
@@ -137,7 +140,7 @@ func (h *HostedSubscriptionHandler) ExploreAllHostedSubscriptions(c *fiber.Ctx)
 
 // GetHostedSubscriptionDetails handles requests for a specific hosted subscription.
 // @Summary Get details of a specific hosted subscription
-// @Description Retrieves details of a specific hosted subscription by its ID.
+// @Description Retrieves details of a specific hosted subscription by its ID. Private subscriptions are only found by their host, members and pending requesters.
 // @Tags HostedSubscriptions
 // @Produce json
 // @Param id path int true "Hosted Subscription ID"
@@ -158,6 +161,9 @@ func (h *HostedSubscriptionHandler) GetHostedSubscriptionDetails(c *fiber.Ctx) e
 	}
 	subscription, err := h.service.GetHostedSubscriptionDetailsByID(c.Context(), uint(id), authenticatedUserID)
 	if err != nil {
+		if errors.Is(err, services.ErrSubscriptionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		}
 		log.Printf("Error getting subscription details for ID %d: %v", id, err)
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve subscription details"})
 	}
@@ -166,15 +172,17 @@ func (h *HostedSubscriptionHandler) GetHostedSubscriptionDetails(c *fiber.Ctx) e
 
 // CreateJoinRequest handles a user's request to join a hosted subscription.
 // @Summary Request to join a subscription
-// @Description Allows an authenticated user to send a request to join a specific hosted subscription. (No request body needed)
+// @Description Allows an authenticated user to send a request to join a specific hosted subscription. The body is optional: it carries a message to the host and the answers to the subscription's screening questions, every required one of which must be answered.
 // @Tags HostedSubscriptions
+// @Accept json
 // @Produce json
 // @Param id path int true "Hosted Subscription ID to join"
+// @Param request body models.CreateJoinRequestRequest false "Message and screening answers"
 // @Security BearerAuth
 // @Success 201 {object} models.JoinRequest "Join request created successfully"
-// @Failure 400 {object} ErrorResponse "Invalid input or request (e.g., subscription full, already member)"
+// @Failure 400 {object} ErrorResponse "Invalid input or request (e.g., validation error or missing screening answers)"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 403 {object} ErrorResponse "Forbidden (e.g., host trying to join own subscription)"
+// @Failure 403 {object} ErrorResponse "Forbidden (e.g., host trying to join own subscription, or a private subscription that needs an invite)"
 // @Failure 404 {object} ErrorResponse "Hosted subscription not found"
 // @Failure 409 {object} ErrorResponse "Conflict (e.g., already sent a pending request)"
 // @Failure 500 {object} ErrorResponse "Internal server error"
@@ -191,13 +199,26 @@ func (h *HostedSubscriptionHandler) CreateJoinRequest(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid hosted subscription ID format"})
 	}
 
-	joinRequest, err := h.service.CreateJoinRequest(c.Context(), requesterUserID, uint(hostedSubscriptionID))
+	req := new(models.CreateJoinRequestRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+		}
+		if err := h.validate.Struct(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+		}
+	}
+
+	joinRequest, err := h.service.CreateJoinRequest(c.Context(), requesterUserID, uint(hostedSubscriptionID), req)
 	if err != nil {
 		switch {
 		case errors.Is(err, services.ErrSubscriptionNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrInvalidScreeningAnswer):
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
 		case errors.Is(err, services.ErrSubscriptionFull),
-			errors.Is(err, services.ErrHostCannotJoinOwn):
+			errors.Is(err, services.ErrHostCannotJoinOwn),
+			errors.Is(err, services.ErrInviteRequired):
 			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
 		case errors.Is(err, services.ErrAlreadyMember),
 			errors.Is(err, services.ErrAlreadyRequestedToJoin):
@@ -213,13 +234,13 @@ func (h *HostedSubscriptionHandler) CreateJoinRequest(c *fiber.Ctx) error {
 
 // ListJoinRequestsForSubscription handles hosts viewing join requests for their subscription.
 // @Summary List join requests for a specific hosted subscription
-// @Description Retrieves join requests for a subscription owned by the authenticated host. Can filter by status.
+// @Description Retrieves join requests for a subscription owned by the authenticated host, each with the requester's message, screening answers, review stats and most recent payments. Can filter by status.
 // @Tags JoinRequests
 // @Produce json
 // @Param subscriptionId path int true "ID of the Hosted Subscription"
 // @Param status query string false "Filter by request status (e.g., Pending, Approved, Declined)" Enums(Pending,Approved,Declined,Cancelled)
 // @Security BearerAuth
-// @Success 200 {array} models.JoinRequest "A list of join requests"
+// @Success 200 {array} models.JoinRequestDetailsResponse "A list of join requests"
 // @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
@@ -261,7 +282,7 @@ func (h *HostedSubscriptionHandler) ListJoinRequestsForSubscription(c *fiber.Ctx
 		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve join requests"})
 	}
 	if requests == nil {
-		requests = []models.JoinRequest{}
+		requests = []models.JoinRequestDetailsResponse{}
 	}
 	return c.Status(fiber.StatusOK).JSON(requests)
 }
@@ -295,19 +316,12 @@ func (h *HostedSubscriptionHandler) ApproveJoinRequest(c *fiber.Ctx) error {
 
 	membership, err := h.service.ApproveJoinRequest(c.Context(), hostUserID, uint(requestID))
 	if err != nil {
-		switch {
-		case errors.Is(err, services.ErrJoinRequestNotFound), errors.Is(err, services.ErrSubscriptionNotFound):
-			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
-		case errors.Is(err, services.ErrCannotManageRequest), errors.Is(err, services.ErrSubscriptionFull):
-			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
-		case errors.Is(err, services.ErrJoinRequestNotPending):
-			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
-		case errors.Is(err, services.ErrAlreadyMember):
-			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Error: err.Error()})
-		default:
+		status := joinRequestErrorStatus(err)
+		if status == fiber.StatusInternalServerError {
 			log.Printf("Error approving join request %d by host %d: %v", requestID, hostUserID, err)
-			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to approve join request"})
+			return c.Status(status).JSON(ErrorResponse{Error: "Failed to approve join request"})
 		}
+		return c.Status(status).JSON(ErrorResponse{Error: err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(membership)
 }
@@ -339,20 +353,281 @@ func (h *HostedSubscriptionHandler) DeclineJoinRequest(c *fiber.Ctx) error {
 	}
 
 	err = h.service.DeclineJoinRequest(c.Context(), hostUserID, uint(requestID))
+	if err != nil {
+		status := joinRequestErrorStatus(err)
+		if status == fiber.StatusInternalServerError {
+			log.Printf("Error declining join request %d by host %d: %v", requestID, hostUserID, err)
+			return c.Status(status).JSON(ErrorResponse{Error: "Failed to decline join request"})
+		}
+		return c.Status(status).JSON(ErrorResponse{Error: err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Join request declined successfully"})
+}
+
+// BulkApproveJoinRequests handles a host approving several join requests at once.
+// @Summary Approve several join requests
+// @Description Approves each join request as the single approve endpoint would, each on its own and in the order given, and reports the outcome per request. Once a subscription is full, its remaining requests fail. Repeated IDs are reviewed once.
+// @Tags JoinRequests
+// @Accept json
+// @Produce json
+// @Param review_details body models.BulkJoinRequestReviewRequest true "Join requests to approve"
+// @Security BearerAuth
+// @Success 200 {object} models.BulkReviewResponse "Outcome per join request"
+// @Failure 400 {object} ErrorResponse "Invalid request body or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /join-requests/bulk-approve [post]
+func (h *HostedSubscriptionHandler) BulkApproveJoinRequests(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	req := new(models.BulkJoinRequestReviewRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	results := h.service.BulkApproveJoinRequests(c.Context(), hostUserID, req.JoinRequestIDs)
+	return c.Status(fiber.StatusOK).JSON(newBulkReviewResponse(c, results, joinRequestErrorStatus, "Failed to approve join request"))
+}
+
+// BulkDeclineJoinRequests handles a host declining several join requests at once.
+// @Summary Decline several join requests
+// @Description Declines each join request as the single decline endpoint would, each on its own, and reports the outcome per request. Repeated IDs are reviewed once.
+// @Tags JoinRequests
+// @Accept json
+// @Produce json
+// @Param review_details body models.BulkJoinRequestReviewRequest true "Join requests to decline"
+// @Security BearerAuth
+// @Success 200 {object} models.BulkReviewResponse "Outcome per join request"
+// @Failure 400 {object} ErrorResponse "Invalid request body or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /join-requests/bulk-decline [post]
+func (h *HostedSubscriptionHandler) BulkDeclineJoinRequests(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	req := new(models.BulkJoinRequestReviewRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	results := h.service.BulkDeclineJoinRequests(c.Context(), hostUserID, req.JoinRequestIDs)
+	return c.Status(fiber.StatusOK).JSON(newBulkReviewResponse(c, results, joinRequestErrorStatus, "Failed to decline join request"))
+}
+
+// joinRequestErrorStatus maps a join request review error to the HTTP status it is reported with.
+func joinRequestErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrJoinRequestNotFound), errors.Is(err, services.ErrSubscriptionNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, services.ErrCannotManageRequest), errors.Is(err, services.ErrSubscriptionFull):
+		return fiber.StatusForbidden
+	case errors.Is(err, services.ErrJoinRequestNotPending):
+		return fiber.StatusBadRequest
+	case errors.Is(err, services.ErrAlreadyMember):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// UpdateVisibility handles a host changing who can find and join their subscription.
+// @Summary Update the visibility of a hosted subscription
+// @Description Public subscriptions are listed in explore and anyone can request to join. Unlisted ones are left out of explore, but anyone with the link can still request to join. Private ones are only visible to their participants and can only be joined with an invite. Existing members, join requests and invites are unaffected.
+// @Tags HostedSubscriptions
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param visibility body models.UpdateVisibilityRequest true "New visibility"
+// @Security BearerAuth
+// @Success 200 {object} models.HostedSubscriptionResponse "Updated hosted subscription"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/visibility [put]
+func (h *HostedSubscriptionHandler) UpdateVisibility(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	req := new(models.UpdateVisibilityRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	subscription, err := h.service.UpdateVisibility(c.Context(), hostUserID, uint(subscriptionID), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSubscriptionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error updating visibility of subscription %d: %v", subscriptionID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to update visibility"})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(subscription)
+}
+
+// UpdateTotalSlots handles a host changing how many people share their subscription.
+// @Summary Update the total slots of a hosted subscription
+// @Description Sets how many people, host included, share a subscription owned by the authenticated host. It cannot be fewer than the host and current members. Slots added are offered to the subscription's waitlist in order.
+// @Tags HostedSubscriptions
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param slots body models.UpdateTotalSlotsRequest true "New total slots"
+// @Security BearerAuth
+// @Success 200 {object} models.HostedSubscriptionResponse "Updated hosted subscription"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format, validation error or fewer slots than members and waitlist reservations"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/slots [put]
+func (h *HostedSubscriptionHandler) UpdateTotalSlots(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	req := new(models.UpdateTotalSlotsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	subscription, err := h.service.UpdateTotalSlots(c.Context(), hostUserID, uint(subscriptionID), req)
 	if err != nil {
 		switch {
-		case errors.Is(err, services.ErrJoinRequestNotFound), errors.Is(err, services.ErrSubscriptionNotFound):
+		case errors.Is(err, services.ErrSubscriptionNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
-		case errors.Is(err, services.ErrCannotManageRequest):
+		case errors.Is(err, services.ErrForbidden):
 			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
-		case errors.Is(err, services.ErrJoinRequestNotPending):
+		case errors.Is(err, services.ErrTotalSlotsBelowMembers):
 			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
 		default:
-			log.Printf("Error declining join request %d by host %d: %v", requestID, hostUserID, err)
-			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to decline join request"})
+			log.Printf("Error updating total slots of subscription %d: %v", subscriptionID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to update total slots"})
 		}
 	}
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Join request declined successfully"})
+	return c.Status(fiber.StatusOK).JSON(subscription)
+}
+
+// GetScreeningQuestions handles a user viewing the questions asked of everyone requesting to join a subscription.
+// @Summary Get the screening questions of a hosted subscription
+// @Description Retrieves the screening questions of a subscription in the order they are asked, with whether each must be answered. Join requests send their answers by question ID.
+// @Tags HostedSubscriptions
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Security BearerAuth
+// @Success 200 {array} models.ScreeningQuestion "Screening questions"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/screening-questions [get]
+func (h *HostedSubscriptionHandler) GetScreeningQuestions(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	questions, err := h.service.GetScreeningQuestions(c.Context(), userID, uint(subscriptionID))
+	if err != nil {
+		if errors.Is(err, services.ErrSubscriptionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		}
+		log.Printf("Error getting screening questions of subscription %d: %v", subscriptionID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve screening questions"})
+	}
+	if questions == nil {
+		questions = []models.ScreeningQuestion{}
+	}
+	return c.Status(fiber.StatusOK).JSON(questions)
+}
+
+// UpdateScreeningQuestions handles a host setting the questions asked of everyone requesting to join their subscription.
+// @Summary Update the screening questions of a hosted subscription
+// @Description Replaces the screening questions of a subscription owned by the authenticated host with up to 10 questions, asked in the order given. Questions are required unless required is false. Join requests already sent keep the questions they answered.
+// @Tags HostedSubscriptions
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param questions body models.UpdateScreeningQuestionsRequest true "New screening questions"
+// @Security BearerAuth
+// @Success 200 {array} models.ScreeningQuestion "Updated screening questions"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/screening-questions [put]
+func (h *HostedSubscriptionHandler) UpdateScreeningQuestions(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	req := new(models.UpdateScreeningQuestionsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	questions, err := h.service.UpdateScreeningQuestions(c.Context(), hostUserID, uint(subscriptionID), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSubscriptionNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error updating screening questions of subscription %d: %v", subscriptionID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to update screening questions"})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(questions)
 }
 
 // ListSubscriptionMembers handles a host viewing members of their specific subscription.
@@ -398,3 +673,89 @@ func (h *HostedSubscriptionHandler) ListSubscriptionMembers(c *fiber.Ctx) error
 	}
 	return c.Status(fiber.StatusOK).JSON(memberships)
 }
+
+// RemoveMember handles a host removing a member from their subscription.
+// @Summary Remove a member from a hosted subscription
+// @Description Allows the host to end a member's membership, freeing the slot. The member is notified, and the host can still refund the unused part of the member's payment through the membership's refunds.
+// @Tags HostedSubscriptions
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Security BearerAuth
+// @Success 200 {object} object "message: Member removed successfully"
+// @Failure 400 {object} ErrorResponse "Invalid subscription or membership ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/members/{membershipId} [delete]
+func (h *HostedSubscriptionHandler) RemoveMember(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	hostedSubscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+	membershipID, err := strconv.ParseUint(c.Params("membershipId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid membership ID format"})
+	}
+
+	err = h.service.RemoveMember(c.Context(), hostUserID, uint(hostedSubscriptionID), uint(membershipID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMembershipNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error removing membership %d by host %d: %v", membershipID, hostUserID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to remove member"})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Member removed successfully"})
+}
+
+// LeaveSubscription handles a member leaving a subscription they have joined.
+// @Summary Leave a subscription
+// @Description Allows an authenticated member to give up their slot in a hosted subscription.
+// @Tags MyMemberships
+// @Produce json
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Security BearerAuth
+// @Success 200 {object} object "message: Left subscription successfully"
+// @Failure 400 {object} ErrorResponse "Invalid membership ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the member of this slot)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /memberships/{membershipId} [delete]
+func (h *HostedSubscriptionHandler) LeaveSubscription(c *fiber.Ctx) error {
+	memberUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	membershipIDStr := c.Params("membershipId")
+	membershipID, err := strconv.ParseUint(membershipIDStr, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid membership ID format"})
+	}
+
+	err = h.service.LeaveSubscription(c.Context(), memberUserID, uint(membershipID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMembershipNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrNotMember):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error leaving membership %d by user %d: %v", membershipID, memberUserID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to leave subscription"})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Left subscription successfully"})
+}
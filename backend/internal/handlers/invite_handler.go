@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// InviteHandler handles invites to hosted subscriptions.
+type InviteHandler struct {
+	inviteService services.InviteService
+	inviteBaseURL string
+	validate      *validator.Validate
+}
+
+// NewInviteHandler creates a new InviteHandler. Invite links are inviteBaseURL followed by the invite code;
+// when it is empty they point at the invite preview endpoint of this API.
+func NewInviteHandler(inviteService services.InviteService, inviteBaseURL string) *InviteHandler {
+	return &InviteHandler{
+		inviteService: inviteService,
+		inviteBaseURL: strings.TrimSuffix(inviteBaseURL, "/"),
+		validate:      validator.New(),
+	}
+}
+
+// CreateInvite handles a host creating an invite to their subscription.
+// @Summary Create an invite
+// @Description Creates a shareable invite code and link to a subscription owned by the authenticated host. Anyone with the invite can see and join the subscription, even a private one. Invites approve automatically unless auto_approve is false, and can expire or be limited to a number of uses.
+// @Tags Invites
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param invite body models.CreateSubscriptionInviteRequest true "Invite options"
+// @Security BearerAuth
+// @Success 201 {object} models.SubscriptionInviteResponse "Invite created"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format, validation error or expiry in the past"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/invites [post]
+func (h *InviteHandler) CreateInvite(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	req := new(models.CreateSubscriptionInviteRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	invite, err := h.inviteService.CreateInvite(c.Context(), hostUserID, uint(subscriptionID), req)
+	if err != nil {
+		return h.handleInviteError(c, err, "Failed to create invite")
+	}
+	invite.URL = h.inviteURL(c, invite.Code)
+	return c.Status(fiber.StatusCreated).JSON(invite)
+}
+
+// ListInvites handles a host viewing the invites to their subscription.
+// @Summary List invites
+// @Description Retrieves all invites to a subscription owned by the authenticated host, newest first, with how often each was used and whether it can still be used.
+// @Tags Invites
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Security BearerAuth
+// @Success 200 {array} models.SubscriptionInviteResponse "Invites"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/invites [get]
+func (h *InviteHandler) ListInvites(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	invites, err := h.inviteService.ListInvites(c.Context(), hostUserID, uint(subscriptionID))
+	if err != nil {
+		return h.handleInviteError(c, err, "Failed to retrieve invites")
+	}
+	for i := range invites {
+		invites[i].URL = h.inviteURL(c, invites[i].Code)
+	}
+	return c.Status(fiber.StatusOK).JSON(invites)
+}
+
+// RevokeInvite handles a host revoking an invite to their subscription.
+// @Summary Revoke an invite
+// @Description Stops an invite to a subscription owned by the authenticated host from being used again. People who already joined with it stay members.
+// @Tags Invites
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param inviteId path int true "ID of the invite"
+// @Security BearerAuth
+// @Success 200 {object} models.SubscriptionInviteResponse "Revoked invite"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription or invite not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/invites/{inviteId} [delete]
+func (h *InviteHandler) RevokeInvite(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+	inviteID, err := strconv.ParseUint(c.Params("inviteId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid invite ID format"})
+	}
+
+	invite, err := h.inviteService.RevokeInvite(c.Context(), hostUserID, uint(subscriptionID), uint(inviteID))
+	if err != nil {
+		return h.handleInviteError(c, err, "Failed to revoke invite")
+	}
+	invite.URL = h.inviteURL(c, invite.Code)
+	return c.Status(fiber.StatusOK).JSON(invite)
+}
+
+// PreviewInvite handles someone opening an invite they were sent.
+// @Summary Preview an invite
+// @Description Shows the subscription an invite is for, even a private one, and whether accepting it joins straight away or sends a join request to the host.
+// @Tags Invites
+// @Produce json
+// @Param code path string true "Invite code"
+// @Security BearerAuth
+// @Success 200 {object} models.InvitePreviewResponse "Invite and its subscription"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Invite not found"
+// @Failure 410 {object} ErrorResponse "Invite expired, revoked or used up"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /invites/{code} [get]
+func (h *InviteHandler) PreviewInvite(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	preview, err := h.inviteService.PreviewInvite(c.Context(), userID, c.Params("code"))
+	if err != nil {
+		return h.handleInviteError(c, err, "Failed to retrieve invite")
+	}
+	return c.Status(fiber.StatusOK).JSON(preview)
+}
+
+// AcceptInvite handles someone joining a subscription with an invite.
+// @Summary Accept an invite
+// @Description Joins the invite's subscription as the authenticated user. An auto-approving invite creates the membership straight away and records the join request as approved; any other invite sends a join request to the host. Each acceptance takes one use of the invite.
+// @Tags Invites
+// @Produce json
+// @Param code path string true "Invite code"
+// @Security BearerAuth
+// @Success 201 {object} models.AcceptInviteResponse "Membership created or join request sent"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (subscription full or host joining own subscription)"
+// @Failure 404 {object} ErrorResponse "Invite not found"
+// @Failure 409 {object} ErrorResponse "Already a member or already requested to join"
+// @Failure 410 {object} ErrorResponse "Invite expired, revoked or used up"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /invites/{code}/accept [post]
+func (h *InviteHandler) AcceptInvite(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	result, err := h.inviteService.AcceptInvite(c.Context(), userID, c.Params("code"))
+	if err != nil {
+		return h.handleInviteError(c, err, "Failed to accept invite")
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (h *InviteHandler) inviteURL(c *fiber.Ctx, code string) string {
+	if h.inviteBaseURL != "" {
+		return h.inviteBaseURL + "/" + code
+	}
+	return c.BaseURL() + "/api/invites/" + code
+}
+
+func (h *InviteHandler) handleInviteError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrSubscriptionNotFound), errors.Is(err, services.ErrInviteNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrSubscriptionFull),
+		errors.Is(err, services.ErrHostCannotJoinOwn):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidInviteExpiry):
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrAlreadyMember), errors.Is(err, services.ErrAlreadyRequestedToJoin):
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInviteUnavailable):
+		return c.Status(fiber.StatusGone).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling invite request: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: fallback})
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// MemberDuesHandler handles the summary of what a member owes.
+type MemberDuesHandler struct {
+	duesService services.MemberDuesService
+}
+
+// NewMemberDuesHandler creates a new MemberDuesHandler.
+func NewMemberDuesHandler(duesService services.MemberDuesService) *MemberDuesHandler {
+	return &MemberDuesHandler{duesService: duesService}
+}
+
+// GetMyDues handles a member viewing what they owe across all their memberships.
+// @Summary Get what I owe as a member
+// @Description Sorts the next payment of each of the authenticated member's memberships into overdue, due this month and upcoming, each with the amount due, the amount on proofs awaiting review and the host's payment QR code (a dynamic PromptPay QR when the host set it up). Also projects the member's spend over the next 12 months at current prices.
+// @Tags MyMemberships
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.MemberDuesSummaryResponse "What the member owes"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/dues [get]
+func (h *MemberDuesHandler) GetMyDues(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	summary, err := h.duesService.GetSummary(c.Context(), userID)
+	if err != nil {
+		log.Printf("Error summarizing dues for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve what you owe"})
+	}
+	return c.Status(fiber.StatusOK).JSON(summary)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// NotificationHandler handles requests related to the current user's notifications.
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// ListMyNotifications handles listing the current user's notifications.
+// @Summary List my notifications
+// @Description Retrieves the authenticated user's notifications, newest first.
+// @Tags Notifications
+// @Produce json
+// @Param unread_only query bool false "Only return unread notifications"
+// @Param limit query int false "Maximum number of notifications to return (default 20, max 100)"
+// @Param offset query int false "Number of notifications to skip"
+// @Security BearerAuth
+// @Success 200 {array} models.Notification "A list of notifications"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/notifications [get]
+func (h *NotificationHandler) ListMyNotifications(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	unreadOnly := c.QueryBool("unread_only", false)
+	limit := c.QueryInt("limit", 0)
+	offset := c.QueryInt("offset", 0)
+
+	notifications, err := h.notificationService.ListNotifications(c.Context(), userID, unreadOnly, limit, offset)
+	if err != nil {
+		log.Printf("Error listing notifications for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve notifications"})
+	}
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+	return c.Status(fiber.StatusOK).JSON(notifications)
+}
+
+// GetMyUnreadNotificationCount handles fetching the current user's unread notification count.
+// @Summary Get my unread notification count
+// @Description Returns the number of unread notifications for the authenticated user.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UnreadNotificationCountResponse "Unread notification count"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/notifications/unread-count [get]
+func (h *NotificationHandler) GetMyUnreadNotificationCount(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	count, err := h.notificationService.GetUnreadCount(c.Context(), userID)
+	if err != nil {
+		log.Printf("Error counting unread notifications for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve unread notification count"})
+	}
+	return c.Status(fiber.StatusOK).JSON(models.UnreadNotificationCountResponse{UnreadCount: count})
+}
+
+// MarkNotificationAsRead handles marking a single notification as read.
+// @Summary Mark a notification as read
+// @Description Marks one of the authenticated user's notifications as read.
+// @Tags Notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Security BearerAuth
+// @Success 200 {object} models.Notification "Notification marked as read"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the recipient)"
+// @Failure 404 {object} ErrorResponse "Notification not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkNotificationAsRead(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	notificationIDStr := c.Params("id")
+	notificationID, err := strconv.ParseUint(notificationIDStr, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid notification ID format"})
+	}
+
+	notification, err := h.notificationService.MarkAsRead(c.Context(), userID, uint(notificationID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotificationNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error marking notification %d as read for user %d: %v", notificationID, userID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to mark notification as read"})
+		}
+	}
+	return c.Status(fiber.StatusOK).JSON(notification)
+}
+
+// MarkAllNotificationsAsRead handles marking all of the current user's notifications as read.
+// @Summary Mark all notifications as read
+// @Description Marks every unread notification of the authenticated user as read.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object "message: All notifications marked as read"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/notifications/read-all [patch]
+func (h *NotificationHandler) MarkAllNotificationsAsRead(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	if err := h.notificationService.MarkAllAsRead(c.Context(), userID); err != nil {
+		log.Printf("Error marking all notifications as read for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to mark notifications as read"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "All notifications marked as read"})
+}
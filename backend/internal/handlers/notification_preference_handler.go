@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// NotificationPreferenceHandler handles requests related to the current user's notification preferences.
+type NotificationPreferenceHandler struct {
+	prefService services.NotificationPreferenceService
+	validate    *validator.Validate
+}
+
+// NewNotificationPreferenceHandler creates a new NotificationPreferenceHandler.
+func NewNotificationPreferenceHandler(prefService services.NotificationPreferenceService) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		prefService: prefService,
+		validate:    validator.New(),
+	}
+}
+
+// GetMyNotificationPreferences handles fetching the current user's notification preferences.
+// @Summary Get my notification preferences
+// @Description Returns the authenticated user's email opt-outs and preferred email language. Defaults apply until preferences are saved.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.NotificationPreference "Notification preferences"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/notification-preferences [get]
+func (h *NotificationPreferenceHandler) GetMyNotificationPreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	pref, err := h.prefService.GetPreferences(c.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching notification preferences for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve notification preferences"})
+	}
+	return c.Status(fiber.StatusOK).JSON(pref)
+}
+
+// UpdateMyNotificationPreferences handles changing the current user's notification preferences.
+// @Summary Update my notification preferences
+// @Description Opts the authenticated user in or out of email categories and sets the email language (en or th). Omitted fields are unchanged.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param preferences body models.UpdateNotificationPreferenceRequest true "Preference changes"
+// @Security BearerAuth
+// @Success 200 {object} models.NotificationPreference "Updated notification preferences"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/notification-preferences [patch]
+func (h *NotificationPreferenceHandler) UpdateMyNotificationPreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	req := new(models.UpdateNotificationPreferenceRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	pref, err := h.prefService.UpdatePreferences(c.Context(), userID, req)
+	if err != nil {
+		log.Printf("Error updating notification preferences for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to update notification preferences"})
+	}
+	return c.Status(fiber.StatusOK).JSON(pref)
+}
@@ -28,7 +28,7 @@ func NewPaymentHandler(paymentService services.PaymentService) *PaymentHandler {
 
 // SubmitPaymentProof handles a member submitting their payment proof for a membership.
 // @Summary Submit payment proof for a subscription membership
-// @Description Allows an authenticated member to submit proof of payment for a specific subscription membership they are part of.
+// @Description Allows an authenticated member to submit proof of payment for a specific subscription membership they are part of. Set cycle_count to prepay several cycles; the amount must then cover every cycle.
 // @Tags Payments
 // @Accept json
 // @Produce json
@@ -69,6 +69,8 @@ func (h *PaymentHandler) SubmitPaymentProof(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
 		case errors.Is(err, services.ErrNotMember):
 			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrPrepaymentAmountTooLow):
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
 		default:
 			log.Printf("Error submitting payment proof for membership %d by user %d: %v", membershipID, memberUserID, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to submit payment proof"})
@@ -84,7 +86,7 @@ func (h *PaymentHandler) SubmitPaymentProof(c *fiber.Ctx) error {
 // @Tags Payments
 // @Produce json
 // @Param subscriptionId path int true "ID of the Hosted Subscription"
-// @Param status query string false "Filter by payment record status (e.g., ProofSubmitted)" Enums(ProofSubmitted,Approved,Declined,RequiresAttention)
+// @Param status query string false "Filter by payment record status (e.g., ProofSubmitted)" Enums(ProofSubmitted,Approved,Declined,Disputed,Superseded,RequiresAttention)
 // @Security BearerAuth
 // @Success 200 {array} models.PaymentRecordResponse "A list of payment records"
 // @Failure 400 {object} ErrorResponse "Invalid ID or status format"
@@ -156,16 +158,19 @@ func (h *PaymentHandler) GetPaymentRecord(c *fiber.Ctx) error {
 
 // ApprovePaymentProof handles a host approving a payment proof.
 // @Summary Approve a payment proof
-// @Description Allows a host to approve a submitted payment proof.
+// @Description Allows a host to approve a submitted payment proof. When the slip in the proof carries a different transaction reference than the member gave, or backs another payment record, the approval is refused until the host acknowledges the warning.
 // @Tags Payments
+// @Accept json
 // @Produce json
 // @Param id path int true "Payment Record ID"
+// @Param approve_details body models.ApprovePaymentProofRequest false "Acknowledgement of slip warnings"
 // @Security BearerAuth
 // @Success 200 {object} models.PaymentRecordResponse "Payment proof approved"
 // @Failure 400 {object} ErrorResponse "Invalid ID or record not modifiable"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 403 {object} ErrorResponse "Forbidden"
 // @Failure 404 {object} ErrorResponse "Payment record not found"
+// @Failure 409 {object} ErrorResponse "Slip warnings not acknowledged"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /payment-records/{id}/approve [patch]
 func (h *PaymentHandler) ApprovePaymentProof(c *fiber.Ctx) error {
@@ -180,22 +185,64 @@ func (h *PaymentHandler) ApprovePaymentProof(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid payment record ID"})
 	}
 
-	paymentRecord, err := h.paymentService.ApprovePaymentProof(c.Context(), hostUserID, uint(prID))
+	req := new(models.ApprovePaymentProofRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+		}
+	}
+
+	paymentRecord, err := h.paymentService.ApprovePaymentProof(c.Context(), hostUserID, uint(prID), req.AcknowledgeSlipWarnings)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to approve payment proof"})
+		return h.handlePaymentRecordError(c, err, "Failed to approve payment proof")
+	}
+	return c.Status(fiber.StatusOK).JSON(paymentRecord)
+}
+
+// RevertAutoApproval handles a host taking back an approval made by one of their auto-approval rules.
+// @Summary Revert an auto-approval
+// @Description Reverses the payment and cycle charges an auto-approval posted, restores the member's due date and puts the payment proof back up for review. Refused once anything else has been posted to the membership's ledger since.
+// @Tags Payments
+// @Produce json
+// @Param id path int true "Payment Record ID"
+// @Security BearerAuth
+// @Success 200 {object} models.PaymentRecordResponse "Auto-approval reverted; the proof awaits review"
+// @Failure 400 {object} ErrorResponse "Invalid payment record ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 404 {object} ErrorResponse "Payment record not found"
+// @Failure 409 {object} ErrorResponse "Not auto-approved, or the membership's balance has changed since"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /payment-records/{id}/revert-auto-approval [post]
+func (h *PaymentHandler) RevertAutoApproval(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	prID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid payment record ID"})
+	}
+
+	paymentRecord, err := h.paymentService.RevertAutoApproval(c.Context(), hostUserID, uint(prID))
+	if err != nil {
+		return h.handlePaymentRecordError(c, err, "Failed to revert auto-approval")
 	}
 	return c.Status(fiber.StatusOK).JSON(paymentRecord)
 }
 
 // DeclinePaymentProof handles a host declining a payment proof.
 // @Summary Decline a payment proof
-// @Description Allows a host to decline a submitted payment proof, with optional notes.
+// @Description Allows a host to decline a submitted or disputed payment proof, with an optional reason shown to the member.
 // @Tags Payments
+// @Accept json
 // @Produce json
 // @Param id path int true "Payment Record ID"
+// @Param decline_details body models.DeclinePaymentProofRequest false "Reason for declining"
 // @Security BearerAuth
 // @Success 200 {object} models.PaymentRecordResponse "Payment proof declined"
-// @Failure 400 {object} ErrorResponse "Invalid ID, record not modifiable, or notes missing if required"
+// @Failure 400 {object} ErrorResponse "Invalid ID, validation error, or record not modifiable"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 403 {object} ErrorResponse "Forbidden"
 // @Failure 404 {object} ErrorResponse "Payment record not found"
@@ -213,13 +260,244 @@ func (h *PaymentHandler) DeclinePaymentProof(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid payment record ID"})
 	}
 
-	paymentRecord, err := h.paymentService.DeclinePaymentProof(c.Context(), hostUserID, uint(prID))
+	req := new(models.DeclinePaymentProofRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	paymentRecord, err := h.paymentService.DeclinePaymentProof(c.Context(), hostUserID, uint(prID), req.Reason)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to decline payment proof"})
+		return h.handlePaymentRecordError(c, err, "Failed to decline payment proof")
 	}
 	return c.Status(fiber.StatusOK).JSON(paymentRecord)
 }
 
+// BulkApprovePaymentProofs handles a host approving several payment proofs at once.
+// @Summary Approve several payment proofs
+// @Description Approves each payment proof as the single approve endpoint would, each on its own, and reports the outcome per record. A record that cannot be approved leaves the others approved. Repeated IDs are reviewed once.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param approve_details body models.BulkApprovePaymentProofsRequest true "Payment records to approve"
+// @Security BearerAuth
+// @Success 200 {object} models.BulkReviewResponse "Outcome per payment record"
+// @Failure 400 {object} ErrorResponse "Invalid request body or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /payment-records/bulk-approve [post]
+func (h *PaymentHandler) BulkApprovePaymentProofs(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	req := new(models.BulkApprovePaymentProofsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	results := h.paymentService.BulkApprovePaymentProofs(c.Context(), hostUserID, req.PaymentRecordIDs, req.AcknowledgeSlipWarnings)
+	return c.Status(fiber.StatusOK).JSON(newBulkReviewResponse(c, results, paymentRecordErrorStatus, "Failed to approve payment proof"))
+}
+
+// BulkDeclinePaymentProofs handles a host declining several payment proofs at once.
+// @Summary Decline several payment proofs
+// @Description Declines each payment proof with the same optional reason, as the single decline endpoint would, each on its own, and reports the outcome per record. Repeated IDs are reviewed once.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param decline_details body models.BulkDeclinePaymentProofsRequest true "Payment records to decline"
+// @Security BearerAuth
+// @Success 200 {object} models.BulkReviewResponse "Outcome per payment record"
+// @Failure 400 {object} ErrorResponse "Invalid request body or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /payment-records/bulk-decline [post]
+func (h *PaymentHandler) BulkDeclinePaymentProofs(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	req := new(models.BulkDeclinePaymentProofsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	results := h.paymentService.BulkDeclinePaymentProofs(c.Context(), hostUserID, req.PaymentRecordIDs, req.Reason)
+	return c.Status(fiber.StatusOK).JSON(newBulkReviewResponse(c, results, paymentRecordErrorStatus, "Failed to decline payment proof"))
+}
+
+// DisputePaymentRecord handles a member disputing a declined payment.
+// @Summary Dispute a declined payment
+// @Description Allows the member to contest a declined payment with a comment and optional evidence URLs. The record goes back to the host for review.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment Record ID"
+// @Param dispute_details body models.DisputePaymentRecordRequest true "Dispute comment and evidence"
+// @Security BearerAuth
+// @Success 200 {object} models.PaymentRecordResponse "Payment record disputed"
+// @Failure 400 {object} ErrorResponse "Invalid input or record not declined"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the member of this payment)"
+// @Failure 404 {object} ErrorResponse "Payment record not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /payment-records/{id}/dispute [patch]
+func (h *PaymentHandler) DisputePaymentRecord(c *fiber.Ctx) error {
+	memberUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	prID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid payment record ID"})
+	}
+
+	req := new(models.DisputePaymentRecordRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	paymentRecord, err := h.paymentService.DisputePaymentRecord(c.Context(), memberUserID, uint(prID), req)
+	if err != nil {
+		return h.handlePaymentRecordError(c, err, "Failed to dispute payment record")
+	}
+	return c.Status(fiber.StatusOK).JSON(paymentRecord)
+}
+
+// ResubmitPaymentProof handles a member submitting a corrected proof for a declined payment.
+// @Summary Resubmit a payment proof
+// @Description Allows the member to submit a corrected proof for the cycle of a declined or disputed payment record. The new record supersedes the old one.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the declined Payment Record"
+// @Param payment_details body models.ResubmitPaymentProofRequest true "Details of the corrected payment proof"
+// @Security BearerAuth
+// @Success 201 {object} models.PaymentRecord "Payment proof resubmitted"
+// @Failure 400 {object} ErrorResponse "Invalid input or record cannot be resubmitted"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the member of this payment)"
+// @Failure 404 {object} ErrorResponse "Payment record not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /payment-records/{id}/resubmit [post]
+func (h *PaymentHandler) ResubmitPaymentProof(c *fiber.Ctx) error {
+	memberUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	prID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid payment record ID"})
+	}
+
+	req := new(models.ResubmitPaymentProofRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	paymentRecord, err := h.paymentService.ResubmitPaymentProof(c.Context(), memberUserID, uint(prID), req)
+	if err != nil {
+		return h.handlePaymentRecordError(c, err, "Failed to resubmit payment proof")
+	}
+	return c.Status(fiber.StatusCreated).JSON(paymentRecord)
+}
+
+// ListPaymentRecordMessages handles fetching the message thread of a payment record.
+// @Summary List payment record messages
+// @Description Retrieves the message thread between host and member about a payment record, oldest first, including messages on the records it superseded.
+// @Tags Payments
+// @Produce json
+// @Param id path int true "Payment Record ID"
+// @Security BearerAuth
+// @Success 200 {array} models.PaymentRecordMessageResponse "The message thread"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (neither host nor member)"
+// @Failure 404 {object} ErrorResponse "Payment record not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /payment-records/{id}/messages [get]
+func (h *PaymentHandler) ListPaymentRecordMessages(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	prID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid payment record ID"})
+	}
+
+	messages, err := h.paymentService.ListPaymentRecordMessages(c.Context(), userID, uint(prID))
+	if err != nil {
+		return h.handlePaymentRecordError(c, err, "Failed to retrieve payment record messages")
+	}
+	if messages == nil {
+		messages = []models.PaymentRecordMessageResponse{}
+	}
+	return c.Status(fiber.StatusOK).JSON(messages)
+}
+
+// PostPaymentRecordMessage handles the host or member adding a message to a payment record's thread.
+// @Summary Post a payment record message
+// @Description Adds a message, with optional attachment URLs, to the thread of a payment record. Only the host and the paying member can post.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment Record ID"
+// @Param message body models.CreatePaymentRecordMessageRequest true "Message to post"
+// @Security BearerAuth
+// @Success 201 {object} models.PaymentRecordMessageResponse "Message posted"
+// @Failure 400 {object} ErrorResponse "Invalid input"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (neither host nor member)"
+// @Failure 404 {object} ErrorResponse "Payment record not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /payment-records/{id}/messages [post]
+func (h *PaymentHandler) PostPaymentRecordMessage(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	prID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid payment record ID"})
+	}
+
+	req := new(models.CreatePaymentRecordMessageRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	message, err := h.paymentService.PostPaymentRecordMessage(c.Context(), userID, uint(prID), req)
+	if err != nil {
+		return h.handlePaymentRecordError(c, err, "Failed to post payment record message")
+	}
+	return c.Status(fiber.StatusCreated).JSON(message)
+}
+
 // ListMyPaymentRecordsForMembership handles a member viewing their payment history for a specific membership.
 // @Summary List my payment records for a membership
 // @Description Retrieves the payment history for a specific subscription membership the user is part of.
@@ -263,3 +541,79 @@ func (h *PaymentHandler) ListMyPaymentRecordsForMembership(c *fiber.Ctx) error {
 	}
 	return c.Status(fiber.StatusOK).JSON(records)
 }
+
+func (h *PaymentHandler) handlePaymentRecordError(c *fiber.Ctx, err error, fallback string) error {
+	status := paymentRecordErrorStatus(err)
+	if status == fiber.StatusInternalServerError {
+		log.Printf("Error handling payment record request %s %s: %v", c.Method(), c.Path(), err)
+		return c.Status(status).JSON(ErrorResponse{Error: fallback})
+	}
+	return c.Status(status).JSON(ErrorResponse{Error: err.Error()})
+}
+
+// paymentRecordErrorStatus maps a PaymentService error to the HTTP status it is reported with.
+func paymentRecordErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPaymentRecordNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, services.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, services.ErrPaymentRecordNotModifiable),
+		errors.Is(err, services.ErrPaymentRecordNotDisputable),
+		errors.Is(err, services.ErrPaymentRecordNotResubmittable),
+		errors.Is(err, services.ErrPrepaymentAmountTooLow):
+		return fiber.StatusBadRequest
+	case errors.Is(err, services.ErrSlipWarningsNotAcknowledged),
+		errors.Is(err, services.ErrPaymentNotAutoApproved),
+		errors.Is(err, services.ErrAutoApprovalNotRevertible),
+		errors.Is(err, services.ErrPaymentRecordAlreadyReviewed):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// ListMembershipLedger handles viewing the balance ledger of a membership.
+// @Summary List a membership's ledger
+// @Description Retrieves the charges and payments on a membership's balance, newest first. A negative balance is owed by the member; a positive balance is credit applied to their next cycle. Accessible by the member and the host.
+// @Tags Payments
+// @Produce json
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Param limit query int false "Maximum number of entries to return (default 50, max 200)"
+// @Param offset query int false "Number of entries to skip"
+// @Security BearerAuth
+// @Success 200 {array} models.MembershipLedgerEntry "Ledger entries"
+// @Failure 400 {object} ErrorResponse "Invalid membership ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (neither the member nor the host)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /memberships/{membershipId}/ledger [get]
+func (h *PaymentHandler) ListMembershipLedger(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	membershipID, err := strconv.ParseUint(c.Params("membershipId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid membership ID format"})
+	}
+
+	entries, err := h.paymentService.ListMembershipLedger(c.Context(), userID, uint(membershipID), c.QueryInt("limit", 0), c.QueryInt("offset", 0))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMembershipNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error listing ledger of membership %d for user %d: %v", membershipID, userID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve membership ledger"})
+		}
+	}
+	if entries == nil {
+		entries = []models.MembershipLedgerEntry{}
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// PaymentReminderHandler handles requests related to hosted subscriptions' payment reminder schedules.
+type PaymentReminderHandler struct {
+	reminderService services.PaymentReminderService
+	validate        *validator.Validate
+}
+
+// NewPaymentReminderHandler creates a new PaymentReminderHandler.
+func NewPaymentReminderHandler(reminderService services.PaymentReminderService) *PaymentReminderHandler {
+	return &PaymentReminderHandler{
+		reminderService: reminderService,
+		validate:        validator.New(),
+	}
+}
+
+// GetReminderSchedule handles a host viewing the payment reminder schedule of their subscription.
+// @Summary Get the payment reminder schedule
+// @Description Returns the reminder offsets (in days relative to each member's next payment date) of a subscription owned by the authenticated host. Subscriptions without a custom schedule use 3 days before, on the day and 2 days overdue.
+// @Tags PaymentReminders
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Security BearerAuth
+// @Success 200 {object} models.ReminderScheduleResponse "Reminder schedule"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/reminder-schedule [get]
+func (h *PaymentReminderHandler) GetReminderSchedule(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionIDStr := c.Params("subscriptionId")
+	subscriptionID, err := strconv.ParseUint(subscriptionIDStr, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	schedule, err := h.reminderService.GetSchedule(c.Context(), hostUserID, uint(subscriptionID))
+	if err != nil {
+		return h.handleScheduleError(c, err, hostUserID, subscriptionID, "Failed to retrieve reminder schedule")
+	}
+	return c.Status(fiber.StatusOK).JSON(schedule)
+}
+
+// UpdateReminderSchedule handles a host replacing the payment reminder schedule of their subscription.
+// @Summary Update the payment reminder schedule
+// @Description Replaces the reminder offsets of a subscription owned by the authenticated host. Offsets are days relative to the due date (-14 to 14; negative is before, positive is overdue). An empty list turns reminders off.
+// @Tags PaymentReminders
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param schedule body models.UpdateReminderScheduleRequest true "New reminder schedule"
+// @Security BearerAuth
+// @Success 200 {object} models.ReminderScheduleResponse "Updated reminder schedule"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format or validation error"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/reminder-schedule [put]
+func (h *PaymentReminderHandler) UpdateReminderSchedule(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionIDStr := c.Params("subscriptionId")
+	subscriptionID, err := strconv.ParseUint(subscriptionIDStr, 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	req := new(models.UpdateReminderScheduleRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	schedule, err := h.reminderService.UpdateSchedule(c.Context(), hostUserID, uint(subscriptionID), req)
+	if err != nil {
+		return h.handleScheduleError(c, err, hostUserID, subscriptionID, "Failed to update reminder schedule")
+	}
+	return c.Status(fiber.StatusOK).JSON(schedule)
+}
+
+func (h *PaymentReminderHandler) handleScheduleError(c *fiber.Ctx, err error, hostUserID uint, subscriptionID uint64, message string) error {
+	switch {
+	case errors.Is(err, services.ErrSubscriptionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling reminder schedule for host %d, sub %d: %v", hostUserID, subscriptionID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: message})
+	}
+}
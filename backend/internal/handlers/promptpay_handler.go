@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/qrcode"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// paymentQRModuleSize is the width in pixels of one QR module in rendered PNGs.
+const paymentQRModuleSize = 8
+
+// PromptPayHandler handles PromptPay settings and per-member payment QR codes.
+type PromptPayHandler struct {
+	promptPayService services.PromptPayService
+	validate         *validator.Validate
+}
+
+// NewPromptPayHandler creates a new PromptPayHandler.
+func NewPromptPayHandler(promptPayService services.PromptPayService) *PromptPayHandler {
+	return &PromptPayHandler{
+		promptPayService: promptPayService,
+		validate:         validator.New(),
+	}
+}
+
+// GetPromptPaySettings handles a host viewing the PromptPay settings of their subscription.
+// @Summary Get the PromptPay settings
+// @Description Returns the PromptPay ID that payment QR codes of a subscription owned by the authenticated host pay to.
+// @Tags PromptPay
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Security BearerAuth
+// @Success 200 {object} models.PromptPaySettingsResponse "PromptPay settings"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/promptpay [get]
+func (h *PromptPayHandler) GetPromptPaySettings(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	settings, err := h.promptPayService.GetSettings(c.Context(), hostUserID, uint(subscriptionID))
+	if err != nil {
+		return h.handlePromptPayError(c, err, "Failed to retrieve PromptPay settings")
+	}
+	return c.Status(fiber.StatusOK).JSON(settings)
+}
+
+// UpdatePromptPaySettings handles a host setting the PromptPay ID of their subscription.
+// @Summary Update the PromptPay settings
+// @Description Sets the PromptPay ID (10-digit mobile number, 13-digit national or tax ID, or 15-digit e-wallet ID) that payment QR codes of a subscription owned by the authenticated host pay to. An empty ID turns PromptPay off.
+// @Tags PromptPay
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Param settings body models.UpdatePromptPayRequest true "New PromptPay settings"
+// @Security BearerAuth
+// @Success 200 {object} models.PromptPaySettingsResponse "Updated PromptPay settings"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format, validation error or invalid PromptPay ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/promptpay [put]
+func (h *PromptPayHandler) UpdatePromptPaySettings(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	req := new(models.UpdatePromptPayRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	settings, err := h.promptPayService.UpdateSettings(c.Context(), hostUserID, uint(subscriptionID), req)
+	if err != nil {
+		return h.handlePromptPayError(c, err, "Failed to update PromptPay settings")
+	}
+	return c.Status(fiber.StatusOK).JSON(settings)
+}
+
+// GetPaymentQR handles retrieving the PromptPay payload for a membership's due cycle.
+// @Summary Get the PromptPay payload for a membership's due cycle
+// @Description Returns a dynamic PromptPay payload with the exact amount the member owes on their due cycle and a reference identifying the membership and cycle. Available to the member and the host.
+// @Tags PromptPay
+// @Produce json
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Security BearerAuth
+// @Success 200 {object} models.PaymentQRResponse "PromptPay payload"
+// @Failure 400 {object} ErrorResponse "Invalid membership ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the member or host)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 409 {object} ErrorResponse "PromptPay not set up or nothing due"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /memberships/{membershipId}/payment-qr [get]
+func (h *PromptPayHandler) GetPaymentQR(c *fiber.Ctx) error {
+	qr, err := h.getPaymentQR(c)
+	if err != nil || qr == nil {
+		return err
+	}
+	return c.Status(fiber.StatusOK).JSON(qr)
+}
+
+// GetPaymentQRImage handles rendering the PromptPay QR code for a membership's due cycle.
+// @Summary Render the PromptPay QR code for a membership's due cycle
+// @Description Renders the membership's dynamic PromptPay payload as a PNG image for the member to scan with their banking app. Available to the member and the host.
+// @Tags PromptPay
+// @Produce png
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Security BearerAuth
+// @Success 200 {file} binary "PNG image of the QR code"
+// @Failure 400 {object} ErrorResponse "Invalid membership ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the member or host)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 409 {object} ErrorResponse "PromptPay not set up or nothing due"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /memberships/{membershipId}/payment-qr.png [get]
+func (h *PromptPayHandler) GetPaymentQRImage(c *fiber.Ctx) error {
+	qr, err := h.getPaymentQR(c)
+	if err != nil || qr == nil {
+		return err
+	}
+
+	code, err := qrcode.Encode([]byte(qr.Payload))
+	if err != nil {
+		log.Printf("Error encoding payment QR for membership %d: %v", qr.SubscriptionMembershipID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to render payment QR code"})
+	}
+	image, err := code.PNG(paymentQRModuleSize)
+	if err != nil {
+		log.Printf("Error rendering payment QR for membership %d: %v", qr.SubscriptionMembershipID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to render payment QR code"})
+	}
+	c.Set(fiber.HeaderContentType, "image/png")
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).Send(image)
+}
+
+// getPaymentQR resolves the payment QR of the membership in the path. When it returns a nil QR,
+// the error response has already been written.
+func (h *PromptPayHandler) getPaymentQR(c *fiber.Ctx) (*models.PaymentQRResponse, error) {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return nil, c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	membershipID, err := strconv.ParseUint(c.Params("membershipId"), 10, 32)
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid membership ID format"})
+	}
+
+	qr, err := h.promptPayService.GetMembershipPaymentQR(c.Context(), userID, uint(membershipID))
+	if err != nil {
+		return nil, h.handlePromptPayError(c, err, "Failed to generate payment QR code")
+	}
+	return qr, nil
+}
+
+func (h *PromptPayHandler) handlePromptPayError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrSubscriptionNotFound), errors.Is(err, services.ErrMembershipNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidPromptPayID):
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrPromptPayNotConfigured), errors.Is(err, services.ErrNoAmountDue):
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling PromptPay request: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: fallback})
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// RefundHandler handles refunds from hosts to members.
+type RefundHandler struct {
+	refundService services.RefundService
+	validate      *validator.Validate
+}
+
+// NewRefundHandler creates a new RefundHandler.
+func NewRefundHandler(refundService services.RefundService) *RefundHandler {
+	return &RefundHandler{
+		refundService: refundService,
+		validate:      validator.New(),
+	}
+}
+
+// GetRefundSuggestion handles computing the suggested refund for a membership.
+// @Summary Get the suggested refund for a membership
+// @Description Computes the value of the unused days of the period the member has paid through, counted from when they left (or today), plus their credit balance, less earlier refunds. Accessible by the member and the host, also after the member has left.
+// @Tags Refunds
+// @Produce json
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Security BearerAuth
+// @Success 200 {object} models.RefundSuggestion "Suggested refund"
+// @Failure 400 {object} ErrorResponse "Invalid membership ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (neither the member nor the host)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /memberships/{membershipId}/refund-suggestion [get]
+func (h *RefundHandler) GetRefundSuggestion(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	membershipID, err := strconv.ParseUint(c.Params("membershipId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid membership ID format"})
+	}
+
+	suggestion, err := h.refundService.SuggestRefund(c.Context(), userID, uint(membershipID))
+	if err != nil {
+		return h.handleRefundError(c, err, "Failed to compute refund suggestion")
+	}
+	return c.Status(fiber.StatusOK).JSON(suggestion)
+}
+
+// CreateRefund handles a host recording a refund to a member.
+// @Summary Record a refund to a member
+// @Description Allows the host to record money returned to a member for an approved payment, with proof of the transfer. The refund is taken from the member's credit balance first, then from the host's earnings.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Param refund_details body models.CreateRefundRequest true "Refund details and proof"
+// @Security BearerAuth
+// @Success 201 {object} models.Refund "Refund recorded"
+// @Failure 400 {object} ErrorResponse "Invalid input, payment not refundable, or amount too high"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /memberships/{membershipId}/refunds [post]
+func (h *RefundHandler) CreateRefund(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	membershipID, err := strconv.ParseUint(c.Params("membershipId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid membership ID format"})
+	}
+
+	req := new(models.CreateRefundRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	refund, err := h.refundService.CreateRefund(c.Context(), hostUserID, uint(membershipID), req)
+	if err != nil {
+		return h.handleRefundError(c, err, "Failed to record refund")
+	}
+	return c.Status(fiber.StatusCreated).JSON(refund)
+}
+
+// ListRefunds handles listing the refunds of a membership.
+// @Summary List refunds of a membership
+// @Description Retrieves the refunds recorded for a membership, newest first. Accessible by the member and the host.
+// @Tags Refunds
+// @Produce json
+// @Param membershipId path int true "ID of the Subscription Membership"
+// @Security BearerAuth
+// @Success 200 {array} models.Refund "Refunds of the membership"
+// @Failure 400 {object} ErrorResponse "Invalid membership ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (neither the member nor the host)"
+// @Failure 404 {object} ErrorResponse "Membership not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /memberships/{membershipId}/refunds [get]
+func (h *RefundHandler) ListRefunds(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	membershipID, err := strconv.ParseUint(c.Params("membershipId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid membership ID format"})
+	}
+
+	refunds, err := h.refundService.ListRefunds(c.Context(), userID, uint(membershipID))
+	if err != nil {
+		return h.handleRefundError(c, err, "Failed to retrieve refunds")
+	}
+	if refunds == nil {
+		refunds = []models.Refund{}
+	}
+	return c.Status(fiber.StatusOK).JSON(refunds)
+}
+
+// AcknowledgeRefund handles a member confirming they received a refund.
+// @Summary Acknowledge a refund
+// @Description Allows the member to confirm that they received a refund from the host.
+// @Tags Refunds
+// @Produce json
+// @Param id path int true "Refund ID"
+// @Security BearerAuth
+// @Success 200 {object} models.Refund "Refund acknowledged"
+// @Failure 400 {object} ErrorResponse "Invalid ID or refund already acknowledged"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the refunded member)"
+// @Failure 404 {object} ErrorResponse "Refund not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /refunds/{id}/acknowledge [patch]
+func (h *RefundHandler) AcknowledgeRefund(c *fiber.Ctx) error {
+	memberUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	refundID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid refund ID"})
+	}
+
+	refund, err := h.refundService.AcknowledgeRefund(c.Context(), memberUserID, uint(refundID))
+	if err != nil {
+		return h.handleRefundError(c, err, "Failed to acknowledge refund")
+	}
+	return c.Status(fiber.StatusOK).JSON(refund)
+}
+
+func (h *RefundHandler) handleRefundError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrMembershipNotFound), errors.Is(err, services.ErrRefundNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrPaymentNotRefundable),
+		errors.Is(err, services.ErrRefundExceedsPayment),
+		errors.Is(err, services.ErrRefundAlreadyAcknowledged):
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling refund request %s %s: %v", c.Method(), c.Path(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: fallback})
+	}
+}
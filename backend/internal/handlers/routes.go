@@ -20,6 +20,23 @@ func SetupRoutes(
 	subscriptionServiceHandler *SubscriptionServiceHandler,
 	hostedSubHandler *HostedSubscriptionHandler,
 	paymentHandler *PaymentHandler,
+	notificationHandler *NotificationHandler,
+	eventStreamHandler *EventStreamHandler,
+	deviceHandler *DeviceHandler,
+	notificationPrefHandler *NotificationPreferenceHandler,
+	paymentReminderHandler *PaymentReminderHandler,
+	webhookHandler *WebhookHandler,
+	auditLogHandler *AuditLogHandler,
+	accountingHandler *AccountingHandler,
+	refundHandler *RefundHandler,
+	promptPayHandler *PromptPayHandler,
+	autoApprovalHandler *AutoApprovalHandler,
+	hostDashboardHandler *HostDashboardHandler,
+	memberDuesHandler *MemberDuesHandler,
+	exportHandler *ExportHandler,
+	calendarHandler *CalendarHandler,
+	inviteHandler *InviteHandler,
+	waitlistHandler *WaitlistHandler,
 	cfg *config.Config,
 ) {
 
@@ -41,6 +58,36 @@ func SetupRoutes(
 	currentUserGroup.Get("/hosted-subscriptions", hostedSubHandler.ListUserHostedSubscriptions)
 	currentUserGroup.Get("/join-requests", userHandler.ListMyJoinRequests)
 	currentUserGroup.Get("/memberships", userHandler.ListMyMemberships)
+	currentUserGroup.Get("/dues", memberDuesHandler.GetMyDues)
+	currentUserGroup.Get("/notifications", notificationHandler.ListMyNotifications)
+	currentUserGroup.Get("/notifications/unread-count", notificationHandler.GetMyUnreadNotificationCount)
+	currentUserGroup.Patch("/notifications/read-all", notificationHandler.MarkAllNotificationsAsRead)
+	currentUserGroup.Patch("/notifications/:id/read", notificationHandler.MarkNotificationAsRead)
+	currentUserGroup.Get("/notification-preferences", notificationPrefHandler.GetMyNotificationPreferences)
+	currentUserGroup.Patch("/notification-preferences", notificationPrefHandler.UpdateMyNotificationPreferences)
+	currentUserGroup.Get("/events", eventStreamHandler.StreamMyEvents)
+	currentUserGroup.Post("/devices", deviceHandler.RegisterDevice)
+	currentUserGroup.Get("/devices", deviceHandler.ListMyDevices)
+	currentUserGroup.Delete("/devices/:id", deviceHandler.UnregisterDevice)
+	currentUserGroup.Post("/webhooks", webhookHandler.CreateWebhookEndpoint)
+	currentUserGroup.Get("/webhooks", webhookHandler.ListMyWebhookEndpoints)
+	currentUserGroup.Get("/webhooks/:id", webhookHandler.GetWebhookEndpoint)
+	currentUserGroup.Patch("/webhooks/:id", webhookHandler.UpdateWebhookEndpoint)
+	currentUserGroup.Delete("/webhooks/:id", webhookHandler.DeleteWebhookEndpoint)
+	currentUserGroup.Get("/webhooks/:id/deliveries", webhookHandler.ListWebhookDeliveries)
+	currentUserGroup.Get("/webhooks/:id/deliveries/:deliveryId", webhookHandler.GetWebhookDelivery)
+	currentUserGroup.Post("/webhooks/:id/deliveries/:deliveryId/redeliver", webhookHandler.RedeliverWebhook)
+	currentUserGroup.Get("/earnings", accountingHandler.GetMyEarnings)
+	currentUserGroup.Get("/spending", accountingHandler.GetMySpending)
+	currentUserGroup.Get("/receivables", accountingHandler.GetMyReceivables)
+	currentUserGroup.Get("/host-dashboard", hostDashboardHandler.GetMyHostDashboard)
+	currentUserGroup.Get("/calendar-feed", calendarHandler.GetMyCalendarFeed)
+	currentUserGroup.Post("/calendar-feed", calendarHandler.IssueMyCalendarFeedToken)
+	currentUserGroup.Delete("/calendar-feed", calendarHandler.DeleteMyCalendarFeed)
+	currentUserGroup.Get("/waitlist", waitlistHandler.ListMyWaitlistEntries)
+
+	// Calendar feed route, authenticated by the secret token in the path
+	api.Get("/calendar/:token.ics", calendarHandler.GetCalendarFeed)
 
 	// Subscription Services Catalog routes
 	serviceCatalogGroup := api.Group("/subscription-services")
@@ -55,23 +102,82 @@ func SetupRoutes(
 	hostedSubscriptionsGroup.Post("/:id/join-requests", hostedSubHandler.CreateJoinRequest)
 	hostedSubscriptionsGroup.Get("/:subscriptionId/join-requests", hostedSubHandler.ListJoinRequestsForSubscription)
 	hostedSubscriptionsGroup.Get("/:subscriptionId/members", hostedSubHandler.ListSubscriptionMembers)
+	hostedSubscriptionsGroup.Delete("/:subscriptionId/members/:membershipId", hostedSubHandler.RemoveMember)
 	hostedSubscriptionsGroup.Get("/:subscriptionId/payment-records", paymentHandler.ListPaymentRecordsForHostedSubscription)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/reminder-schedule", paymentReminderHandler.GetReminderSchedule)
+	hostedSubscriptionsGroup.Put("/:subscriptionId/reminder-schedule", paymentReminderHandler.UpdateReminderSchedule)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/promptpay", promptPayHandler.GetPromptPaySettings)
+	hostedSubscriptionsGroup.Put("/:subscriptionId/promptpay", promptPayHandler.UpdatePromptPaySettings)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/renewal-date", calendarHandler.GetRenewalDate)
+	hostedSubscriptionsGroup.Put("/:subscriptionId/renewal-date", calendarHandler.UpdateRenewalDate)
+	hostedSubscriptionsGroup.Put("/:subscriptionId/visibility", hostedSubHandler.UpdateVisibility)
+	hostedSubscriptionsGroup.Put("/:subscriptionId/slots", hostedSubHandler.UpdateTotalSlots)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/screening-questions", hostedSubHandler.GetScreeningQuestions)
+	hostedSubscriptionsGroup.Put("/:subscriptionId/screening-questions", hostedSubHandler.UpdateScreeningQuestions)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/waitlist", waitlistHandler.ListWaitlist)
+	hostedSubscriptionsGroup.Post("/:subscriptionId/waitlist", waitlistHandler.JoinWaitlist)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/invites", inviteHandler.ListInvites)
+	hostedSubscriptionsGroup.Post("/:subscriptionId/invites", inviteHandler.CreateInvite)
+	hostedSubscriptionsGroup.Delete("/:subscriptionId/invites/:inviteId", inviteHandler.RevokeInvite)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/auto-approval-rules", autoApprovalHandler.ListAutoApprovalRules)
+	hostedSubscriptionsGroup.Post("/:subscriptionId/auto-approval-rules", autoApprovalHandler.CreateAutoApprovalRule)
+	hostedSubscriptionsGroup.Put("/:subscriptionId/auto-approval-rules/:ruleId", autoApprovalHandler.UpdateAutoApprovalRule)
+	hostedSubscriptionsGroup.Delete("/:subscriptionId/auto-approval-rules/:ruleId", autoApprovalHandler.DeleteAutoApprovalRule)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/auto-approvals", autoApprovalHandler.ListAutoApprovals)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/audit-logs", auditLogHandler.ListHostedSubscriptionAuditLog)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/payment-records/export.csv", exportHandler.ExportPaymentRecords)
+	hostedSubscriptionsGroup.Get("/:subscriptionId/statements/:month", exportHandler.GetSubscriptionStatement)
 
 	// Join Requests management routes
 	joinRequestsGroup := api.Group("/join-requests", middleware.Protected(cfg))
+	joinRequestsGroup.Post("/bulk-approve", hostedSubHandler.BulkApproveJoinRequests)
+	joinRequestsGroup.Post("/bulk-decline", hostedSubHandler.BulkDeclineJoinRequests)
 	joinRequestsGroup.Patch("/:requestId/approve", hostedSubHandler.ApproveJoinRequest)
 	joinRequestsGroup.Patch("/:requestId/decline", hostedSubHandler.DeclineJoinRequest)
 
+	// Invite routes
+	invitesGroup := api.Group("/invites", middleware.Protected(cfg))
+	invitesGroup.Get("/:code", inviteHandler.PreviewInvite)
+	invitesGroup.Post("/:code/accept", inviteHandler.AcceptInvite)
+
+	// Waitlist routes
+	waitlistGroup := api.Group("/waitlist", middleware.Protected(cfg))
+	waitlistGroup.Delete("/:entryId", waitlistHandler.LeaveWaitlist)
+	waitlistGroup.Post("/:entryId/claim", waitlistHandler.ClaimSlot)
+
 	// Subscription Memberships routes
 	membershipsGroup := api.Group("/memberships", middleware.Protected(cfg))
+	membershipsGroup.Delete("/:membershipId", hostedSubHandler.LeaveSubscription)
 	membershipsGroup.Post("/:membershipId/payment-records", paymentHandler.SubmitPaymentProof)
 	membershipsGroup.Get("/:membershipId/payment-records", paymentHandler.ListMyPaymentRecordsForMembership)
+	membershipsGroup.Get("/:membershipId/ledger", paymentHandler.ListMembershipLedger)
+	membershipsGroup.Get("/:membershipId/statements/:month", exportHandler.GetMembershipStatement)
+	membershipsGroup.Get("/:membershipId/refund-suggestion", refundHandler.GetRefundSuggestion)
+	membershipsGroup.Get("/:membershipId/refunds", refundHandler.ListRefunds)
+	membershipsGroup.Post("/:membershipId/refunds", refundHandler.CreateRefund)
+	membershipsGroup.Get("/:membershipId/payment-qr", promptPayHandler.GetPaymentQR)
+	membershipsGroup.Get("/:membershipId/payment-qr.png", promptPayHandler.GetPaymentQRImage)
 
 	// Payment Records routes
 	paymentRecordsGroup := api.Group("/payment-records", middleware.Protected(cfg))
+	paymentRecordsGroup.Post("/bulk-approve", paymentHandler.BulkApprovePaymentProofs)
+	paymentRecordsGroup.Post("/bulk-decline", paymentHandler.BulkDeclinePaymentProofs)
 	paymentRecordsGroup.Get("/:id", paymentHandler.GetPaymentRecord)
 	paymentRecordsGroup.Patch("/:id/approve", paymentHandler.ApprovePaymentProof)
+	paymentRecordsGroup.Post("/:id/revert-auto-approval", paymentHandler.RevertAutoApproval)
 	paymentRecordsGroup.Patch("/:id/decline", paymentHandler.DeclinePaymentProof)
+	paymentRecordsGroup.Patch("/:id/dispute", paymentHandler.DisputePaymentRecord)
+	paymentRecordsGroup.Post("/:id/resubmit", paymentHandler.ResubmitPaymentProof)
+	paymentRecordsGroup.Get("/:id/messages", paymentHandler.ListPaymentRecordMessages)
+	paymentRecordsGroup.Post("/:id/messages", paymentHandler.PostPaymentRecordMessage)
+
+	// Refunds routes
+	refundsGroup := api.Group("/refunds", middleware.Protected(cfg))
+	refundsGroup.Patch("/:id/acknowledge", refundHandler.AcknowledgeRefund)
+
+	// Admin routes
+	adminGroup := api.Group("/admin", middleware.Protected(cfg))
+	adminGroup.Get("/audit-logs", auditLogHandler.ListAllAuditLogs)
 
 	// Image Upload route
 	uploadsGroup := api.Group("/uploads", middleware.Protected(cfg))
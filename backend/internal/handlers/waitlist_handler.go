@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// WaitlistHandler handles the waitlists of full hosted subscriptions.
+type WaitlistHandler struct {
+	waitlistService services.WaitlistService
+	validate        *validator.Validate
+}
+
+// NewWaitlistHandler creates a new WaitlistHandler.
+func NewWaitlistHandler(waitlistService services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: waitlistService,
+		validate:        validator.New(),
+	}
+}
+
+// JoinWaitlist handles a user queueing for a full subscription.
+// @Summary Join the waitlist of a full subscription
+// @Description Puts the authenticated user at the end of the waitlist of a subscription with no free slot. When a slot frees up it is offered to the first in line, who has 24 hours to claim it before it is offered to the next.
+// @Tags Waitlist
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Security BearerAuth
+// @Success 201 {object} models.WaitlistEntryResponse "Waitlist entry with the user's position"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (host of this subscription or private subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 409 {object} ErrorResponse "Already a member, already requested to join, already on the waitlist or subscription has a free slot"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/waitlist [post]
+func (h *WaitlistHandler) JoinWaitlist(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	entry, err := h.waitlistService.JoinWaitlist(c.Context(), userID, uint(subscriptionID))
+	if err != nil {
+		return h.handleWaitlistError(c, err, "Failed to join waitlist")
+	}
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+// ListWaitlist handles a host viewing the waitlist of their subscription.
+// @Summary List the waitlist of a hosted subscription
+// @Description Retrieves the users waiting for or offered a slot of a subscription owned by the authenticated host, first in line first.
+// @Tags Waitlist
+// @Produce json
+// @Param subscriptionId path int true "ID of the Hosted Subscription"
+// @Security BearerAuth
+// @Success 200 {array} models.WaitlistEntryResponse "Waitlist"
+// @Failure 400 {object} ErrorResponse "Invalid subscription ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the host of this subscription)"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /hosted-subscriptions/{subscriptionId}/waitlist [get]
+func (h *WaitlistHandler) ListWaitlist(c *fiber.Ctx) error {
+	hostUserID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	subscriptionID, err := strconv.ParseUint(c.Params("subscriptionId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid subscription ID format"})
+	}
+
+	entries, err := h.waitlistService.ListWaitlist(c.Context(), hostUserID, uint(subscriptionID))
+	if err != nil {
+		return h.handleWaitlistError(c, err, "Failed to list waitlist")
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+// ListMyWaitlistEntries handles a user viewing the waitlists they are on.
+// @Summary List my waitlist entries
+// @Description Retrieves the waitlists the authenticated user is waiting on, with their position in each, and the slots offered to them that they can still claim.
+// @Tags Waitlist
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.WaitlistEntryResponse "Waitlist entries"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/waitlist [get]
+func (h *WaitlistHandler) ListMyWaitlistEntries(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	entries, err := h.waitlistService.ListMyWaitlistEntries(c.Context(), userID)
+	if err != nil {
+		return h.handleWaitlistError(c, err, "Failed to list waitlist entries")
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+// LeaveWaitlist handles a user leaving a waitlist.
+// @Summary Leave a waitlist
+// @Description Takes the authenticated user off a waitlist. A slot offered to them is offered to the next in line.
+// @Tags Waitlist
+// @Param entryId path int true "ID of the Waitlist Entry"
+// @Security BearerAuth
+// @Success 204 "Left the waitlist"
+// @Failure 400 {object} ErrorResponse "Invalid waitlist entry ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Waitlist entry not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /waitlist/{entryId} [delete]
+func (h *WaitlistHandler) LeaveWaitlist(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	entryID, err := strconv.ParseUint(c.Params("entryId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid waitlist entry ID format"})
+	}
+
+	if err := h.waitlistService.LeaveWaitlist(c.Context(), userID, uint(entryID)); err != nil {
+		return h.handleWaitlistError(c, err, "Failed to leave waitlist")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ClaimSlot handles a user claiming the slot offered to them.
+// @Summary Claim an offered slot
+// @Description Claims the slot offered to the authenticated user from a waitlist, sending the host a join request. The body is optional: it carries a message to the host and the answers to the subscription's screening questions, every required one of which must be answered. The slot stays held for the user until the host decides; if the host declines, it is offered to the next in line.
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param entryId path int true "ID of the Waitlist Entry"
+// @Param request body models.CreateJoinRequestRequest false "Message and screening answers"
+// @Security BearerAuth
+// @Success 201 {object} models.JoinRequest "Join request sent to the host"
+// @Failure 400 {object} ErrorResponse "Invalid waitlist entry ID format, validation error or missing screening answers"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Waitlist entry not found"
+// @Failure 409 {object} ErrorResponse "No slot offered or already requested to join"
+// @Failure 410 {object} ErrorResponse "The slot offer has expired"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /waitlist/{entryId}/claim [post]
+func (h *WaitlistHandler) ClaimSlot(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized: Invalid user context"})
+	}
+
+	entryID, err := strconv.ParseUint(c.Params("entryId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid waitlist entry ID format"})
+	}
+
+	req := new(models.CreateJoinRequestRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+		}
+		if err := h.validate.Struct(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+		}
+	}
+
+	joinRequest, err := h.waitlistService.ClaimSlot(c.Context(), userID, uint(entryID), req)
+	if err != nil {
+		return h.handleWaitlistError(c, err, "Failed to claim slot")
+	}
+	return c.Status(fiber.StatusCreated).JSON(joinRequest)
+}
+
+func (h *WaitlistHandler) handleWaitlistError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrSubscriptionNotFound), errors.Is(err, services.ErrWaitlistEntryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidScreeningAnswer):
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrHostCannotJoinOwn),
+		errors.Is(err, services.ErrInviteRequired):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrAlreadyMember), errors.Is(err, services.ErrAlreadyRequestedToJoin),
+		errors.Is(err, services.ErrAlreadyOnWaitlist), errors.Is(err, services.ErrSubscriptionNotFull),
+		errors.Is(err, services.ErrNoSlotOffered):
+		return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrSlotOfferExpired):
+		return c.Status(fiber.StatusGone).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling waitlist request: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: fallback})
+	}
+}
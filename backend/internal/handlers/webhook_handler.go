@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/services"
+)
+
+// WebhookHandler handles webhook endpoint management and delivery inspection.
+type WebhookHandler struct {
+	webhookService services.WebhookService
+	validate       *validator.Validate
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validate:       validator.New(),
+	}
+}
+
+// CreateWebhookEndpoint handles registering a new webhook endpoint.
+// @Summary Register a webhook endpoint
+// @Description Registers a URL that receives signed POST requests for the selected event types. The signing secret is only returned in this response.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param endpoint body models.CreateWebhookEndpointRequest true "Webhook endpoint details"
+// @Security BearerAuth
+// @Success 201 {object} models.WebhookEndpointResponse "Webhook endpoint registered successfully"
+// @Failure 400 {object} ErrorResponse "Validation error, unknown event type, invalid URL or URL that does not point to a public address"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Maximum number of webhook endpoints reached"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/webhooks [post]
+func (h *WebhookHandler) CreateWebhookEndpoint(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	req := new(models.CreateWebhookEndpointRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(c.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrWebhookURLNotAllowed),
+			errors.Is(err, services.ErrInvalidWebhookEventType):
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+		case errors.Is(err, services.ErrTooManyWebhookEndpoints):
+			return c.Status(fiber.StatusConflict).JSON(ErrorResponse{Error: err.Error()})
+		default:
+			log.Printf("Error creating webhook endpoint for user %d: %v", userID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to create webhook endpoint"})
+		}
+	}
+	return c.Status(fiber.StatusCreated).JSON(endpoint)
+}
+
+// ListMyWebhookEndpoints handles listing the current user's webhook endpoints.
+// @Summary List my webhook endpoints
+// @Description Retrieves the webhook endpoints registered by the authenticated user.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.WebhookEndpointResponse "A list of webhook endpoints"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/webhooks [get]
+func (h *WebhookHandler) ListMyWebhookEndpoints(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	endpoints, err := h.webhookService.ListEndpoints(c.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing webhook endpoints for user %d: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Failed to retrieve webhook endpoints"})
+	}
+	if endpoints == nil {
+		endpoints = []models.WebhookEndpointResponse{}
+	}
+	return c.Status(fiber.StatusOK).JSON(endpoints)
+}
+
+// GetWebhookEndpoint handles retrieving one of the current user's webhook endpoints.
+// @Summary Get a webhook endpoint
+// @Description Retrieves a webhook endpoint of the authenticated user.
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook endpoint ID"
+// @Security BearerAuth
+// @Success 200 {object} models.WebhookEndpointResponse "Webhook endpoint details"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the owner of the endpoint)"
+// @Failure 404 {object} ErrorResponse "Webhook endpoint not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhookEndpoint(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	endpointID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid webhook endpoint ID format"})
+	}
+
+	endpoint, err := h.webhookService.GetEndpoint(c.Context(), userID, uint(endpointID))
+	if err != nil {
+		return h.handleWebhookError(c, err, "Failed to retrieve webhook endpoint")
+	}
+	return c.Status(fiber.StatusOK).JSON(endpoint)
+}
+
+// UpdateWebhookEndpoint handles changing one of the current user's webhook endpoints.
+// @Summary Update a webhook endpoint
+// @Description Changes the URL, description, event types or active state of a webhook endpoint. Omitted fields are unchanged.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook endpoint ID"
+// @Param endpoint body models.UpdateWebhookEndpointRequest true "Fields to update"
+// @Security BearerAuth
+// @Success 200 {object} models.WebhookEndpointResponse "Webhook endpoint updated successfully"
+// @Failure 400 {object} ErrorResponse "Invalid ID format, validation error, unknown event type, invalid URL or URL that does not point to a public address"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the owner of the endpoint)"
+// @Failure 404 {object} ErrorResponse "Webhook endpoint not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/webhooks/{id} [patch]
+func (h *WebhookHandler) UpdateWebhookEndpoint(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	endpointID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid webhook endpoint ID format"})
+	}
+
+	req := new(models.UpdateWebhookEndpointRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Cannot parse JSON request body"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Validation failed", Details: err.Error()})
+	}
+
+	endpoint, err := h.webhookService.UpdateEndpoint(c.Context(), userID, uint(endpointID), req)
+	if err != nil {
+		return h.handleWebhookError(c, err, "Failed to update webhook endpoint")
+	}
+	return c.Status(fiber.StatusOK).JSON(endpoint)
+}
+
+// DeleteWebhookEndpoint handles removing one of the current user's webhook endpoints.
+// @Summary Delete a webhook endpoint
+// @Description Removes a webhook endpoint of the authenticated user together with its delivery history.
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook endpoint ID"
+// @Security BearerAuth
+// @Success 200 {object} object "message: Webhook endpoint deleted successfully"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the owner of the endpoint)"
+// @Failure 404 {object} ErrorResponse "Webhook endpoint not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhookEndpoint(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	endpointID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid webhook endpoint ID format"})
+	}
+
+	if err := h.webhookService.DeleteEndpoint(c.Context(), userID, uint(endpointID)); err != nil {
+		return h.handleWebhookError(c, err, "Failed to delete webhook endpoint")
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Webhook endpoint deleted successfully"})
+}
+
+// ListWebhookDeliveries handles listing the deliveries made to a webhook endpoint.
+// @Summary List webhook deliveries
+// @Description Retrieves a page of deliveries made to a webhook endpoint of the authenticated user, newest first.
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook endpoint ID"
+// @Param limit query int false "Maximum number of deliveries to return (default 20, max 100)"
+// @Param offset query int false "Number of deliveries to skip"
+// @Security BearerAuth
+// @Success 200 {array} models.WebhookDelivery "A list of webhook deliveries"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the owner of the endpoint)"
+// @Failure 404 {object} ErrorResponse "Webhook endpoint not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	endpointID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid webhook endpoint ID format"})
+	}
+	limit := c.QueryInt("limit", 0)
+	offset := c.QueryInt("offset", 0)
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Context(), userID, uint(endpointID), limit, offset)
+	if err != nil {
+		return h.handleWebhookError(c, err, "Failed to retrieve webhook deliveries")
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	return c.Status(fiber.StatusOK).JSON(deliveries)
+}
+
+// GetWebhookDelivery handles retrieving a webhook delivery with all of its attempts.
+// @Summary Get a webhook delivery
+// @Description Retrieves a delivery made to a webhook endpoint of the authenticated user, including the response of every attempt.
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook endpoint ID"
+// @Param deliveryId path int true "Webhook delivery ID"
+// @Security BearerAuth
+// @Success 200 {object} models.WebhookDelivery "Webhook delivery details"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the owner of the endpoint)"
+// @Failure 404 {object} ErrorResponse "Webhook endpoint or delivery not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) GetWebhookDelivery(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	endpointID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid webhook endpoint ID format"})
+	}
+	deliveryID, err := strconv.ParseUint(c.Params("deliveryId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid webhook delivery ID format"})
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.Context(), userID, uint(endpointID), uint(deliveryID))
+	if err != nil {
+		return h.handleWebhookError(c, err, "Failed to retrieve webhook delivery")
+	}
+	return c.Status(fiber.StatusOK).JSON(delivery)
+}
+
+// RedeliverWebhook handles queueing a webhook delivery to be sent again.
+// @Summary Redeliver a webhook
+// @Description Queues a delivery to be sent again right away with a fresh set of retries, e.g. after fixing the receiving server.
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook endpoint ID"
+// @Param deliveryId path int true "Webhook delivery ID"
+// @Security BearerAuth
+// @Success 202 {object} models.WebhookDelivery "Redelivery queued"
+// @Failure 400 {object} ErrorResponse "Invalid ID format"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden (not the owner of the endpoint)"
+// @Failure 404 {object} ErrorResponse "Webhook endpoint or delivery not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /users/me/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhook(c *fiber.Ctx) error {
+	userID, ok := c.Locals(middleware.UserIDKey).(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrorResponse{Error: "Unauthorized"})
+	}
+
+	endpointID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid webhook endpoint ID format"})
+	}
+	deliveryID, err := strconv.ParseUint(c.Params("deliveryId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "Invalid webhook delivery ID format"})
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Context(), userID, uint(endpointID), uint(deliveryID))
+	if err != nil {
+		return h.handleWebhookError(c, err, "Failed to queue webhook redelivery")
+	}
+	return c.Status(fiber.StatusAccepted).JSON(delivery)
+}
+
+func (h *WebhookHandler) handleWebhookError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrWebhookEndpointNotFound), errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrWebhookURLNotAllowed),
+		errors.Is(err, services.ErrInvalidWebhookEventType):
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+	default:
+		log.Printf("Error handling webhook request %s %s: %v", c.Method(), c.Path(), err)
+		return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: fallback})
+	}
+}
@@ -0,0 +1,110 @@
+// Package ical writes iCalendar (RFC 5545) feeds of all-day events, for calendar apps to subscribe to.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineOctets is the longest a content line may be before it is folded onto a continuation line.
+const maxLineOctets = 75
+
+// Calendar is a feed of events. RefreshInterval tells subscribing apps how often to fetch it again.
+type Calendar struct {
+	ProductID       string
+	Name            string
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is an all-day event. Apps match events across fetches by UID, so an event whose date changes moves
+// instead of being duplicated. RecurrenceRule, when set, repeats the event (e.g. "FREQ=MONTHLY").
+type Event struct {
+	UID            string
+	Summary        string
+	Description    string
+	Date           time.Time // Only the year, month and day are used
+	RecurrenceRule string
+	LastModified   time.Time
+}
+
+// Write encodes the calendar to w with CRLF line endings, stamping every event with now.
+func Write(w io.Writer, cal Calendar, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", cal.ProductID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escapeText(cal.Name))
+	}
+	if cal.RefreshInterval > 0 {
+		interval := formatDuration(cal.RefreshInterval)
+		line("REFRESH-INTERVAL;VALUE=DURATION", interval)
+		line("X-PUBLISHED-TTL", interval)
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, event := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escapeText(event.UID))
+		line("DTSTAMP", stamp)
+		if !event.LastModified.IsZero() {
+			line("LAST-MODIFIED", event.LastModified.UTC().Format("20060102T150405Z"))
+		}
+		line("DTSTART;VALUE=DATE", event.Date.Format("20060102"))
+		line("DTEND;VALUE=DATE", event.Date.AddDate(0, 0, 1).Format("20060102"))
+		if event.RecurrenceRule != "" {
+			line("RRULE", event.RecurrenceRule)
+		}
+		line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION", escapeText(event.Description))
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// writeFolded writes a content line, folding it after every 75 octets without splitting a UTF-8 character.
+func writeFolded(w *bufio.Writer, content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && content[cut]&0xc0 == 0x80 {
+			cut--
+		}
+		w.WriteString(content[:cut])
+		w.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxLineOctets - 1 // The leading space of a continuation line counts towards its length
+	}
+	w.WriteString(content)
+	w.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// formatDuration formats d as an RFC 5545 duration in whole hours and minutes, e.g. PT6H.
+func formatDuration(d time.Duration) string {
+	hours := int(d / time.Hour)
+	minutes := int((d % time.Hour) / time.Minute)
+	if minutes == 0 {
+		return fmt.Sprintf("PT%dH", hours)
+	}
+	return fmt.Sprintf("PT%dH%dM", hours, minutes)
+}
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"time"
+)
+
+// JoinApprovedData is the data for the join approval email.
+type JoinApprovedData struct {
+	RecipientName    string
+	HostName         string
+	SubscriptionName string
+	Amount           float64
+	DueDate          *time.Time
+}
+
+// PaymentReminderData is the data for the payment reminder email.
+type PaymentReminderData struct {
+	RecipientName    string
+	SubscriptionName string
+	Amount           float64
+	DueDate          time.Time
+	Overdue          bool
+}
+
+// PaymentApprovedData is the data for the payment approved email.
+type PaymentApprovedData struct {
+	RecipientName    string
+	SubscriptionName string
+	CycleLabel       string
+	Amount           float64
+	NextDueDate      *time.Time
+}
+
+// PaymentDeclinedData is the data for the payment declined email.
+type PaymentDeclinedData struct {
+	RecipientName    string
+	SubscriptionName string
+	CycleLabel       string
+	Reason           string
+}
+
+// HostDigestSubscription summarizes one hosted subscription in the weekly host digest.
+type HostDigestSubscription struct {
+	Title                string
+	MembersCount         int
+	TotalSlots           int
+	PendingJoinRequests  int
+	PendingPaymentProofs int
+	CollectedAmount      float64
+}
+
+// HostDigestData is the data for the weekly host digest email.
+type HostDigestData struct {
+	RecipientName  string
+	WeekStart      time.Time
+	Subscriptions  []HostDigestSubscription
+	TotalCollected float64
+}
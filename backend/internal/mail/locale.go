@@ -0,0 +1,143 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Locale identifies the language of an email.
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleThai    Locale = "th"
+)
+
+// ParseLocale returns the supported locale matching s, or fallback when s is not supported.
+func ParseLocale(s string, fallback Locale) Locale {
+	switch Locale(strings.ToLower(strings.TrimSpace(s))) {
+	case LocaleEnglish:
+		return LocaleEnglish
+	case LocaleThai:
+		return LocaleThai
+	default:
+		return fallback
+	}
+}
+
+var thaiMonthAbbreviations = [...]string{
+	"ม.ค.", "ก.พ.", "มี.ค.", "เม.ย.", "พ.ค.", "มิ.ย.",
+	"ก.ค.", "ส.ค.", "ก.ย.", "ต.ค.", "พ.ย.", "ธ.ค.",
+}
+
+// FormatDate formats t in loc for the locale. Thai dates use the Buddhist Era year.
+func FormatDate(t time.Time, loc *time.Location, locale Locale) string {
+	t = t.In(loc)
+	if locale == LocaleThai {
+		return fmt.Sprintf("%d %s %d", t.Day(), thaiMonthAbbreviations[t.Month()-1], t.Year()+543)
+	}
+	return t.Format("2 Jan 2006")
+}
+
+// FormatAmount formats a Thai Baht amount for the locale.
+func FormatAmount(amount float64, locale Locale) string {
+	formatted := GroupThousands(fmt.Sprintf("%.2f", amount))
+	if locale == LocaleThai {
+		return formatted + " บาท"
+	}
+	return "฿" + formatted
+}
+
+// GroupThousands inserts thousands separators into a formatted decimal number, e.g. "-1234.50" becomes "-1,234.50".
+func GroupThousands(s string) string {
+	integer, fraction, _ := strings.Cut(s, ".")
+	negative := strings.HasPrefix(integer, "-")
+	integer = strings.TrimPrefix(integer, "-")
+
+	var b strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	result := b.String() + "." + fraction
+	if negative {
+		return "-" + result
+	}
+	return result
+}
+
+// translations holds the localized strings used by the email templates, keyed by locale and message key.
+var translations = map[Locale]map[string]string{
+	LocaleEnglish: {
+		"greeting":                 "Hi %s,",
+		"footer":                   "You are receiving this email because of your Hubster account. You can turn these emails off in your notification preferences.",
+		"join_approved.subject":    "You're in! Welcome to %s",
+		"join_approved.body":       "%s approved your request to join %s.",
+		"join_approved.next_steps": "Your first payment of %s is due on %s. Open Hubster to see the host's payment details.",
+		"payment_reminder.subject": "Payment reminder for %s",
+		"payment_reminder.body":    "Your payment of %s for %s is due on %s.",
+		"payment_reminder.overdue": "Your payment of %s for %s was due on %s and is now overdue.",
+		"payment_reminder.action":  "Please pay the host and submit your payment proof in Hubster.",
+		"payment_approved.subject": "Payment approved for %s",
+		"payment_approved.body":    "Your payment of %s for %s (%s) was approved.",
+		"payment_approved.next":    "Your next payment is due on %s.",
+		"payment_declined.subject": "Payment declined for %s",
+		"payment_declined.body":    "Your payment proof for %s (%s) was declined by the host.",
+		"payment_declined.reason":  "Reason: %s",
+		"payment_declined.action":  "Please check the payment details and submit a new proof in Hubster.",
+		"host_digest.subject":      "Your weekly Hubster summary",
+		"host_digest.intro":        "Here is what happened in your hosted subscriptions during the week of %s.",
+		"host_digest.subscription": "Subscription",
+		"host_digest.members":      "Members",
+		"host_digest.pending_jr":   "Pending join requests",
+		"host_digest.pending_pr":   "Proofs to review",
+		"host_digest.collected":    "Collected this week",
+		"host_digest.total":        "Total collected this week: %s",
+		"host_digest.action":       "Open Hubster to review pending requests and payment proofs.",
+	},
+	LocaleThai: {
+		"greeting":                 "สวัสดีคุณ %s",
+		"footer":                   "คุณได้รับอีเมลนี้เนื่องจากบัญชี Hubster ของคุณ คุณสามารถปิดอีเมลเหล่านี้ได้ในการตั้งค่าการแจ้งเตือน",
+		"join_approved.subject":    "ยินดีต้อนรับสู่ %s",
+		"join_approved.body":       "%s อนุมัติคำขอเข้าร่วม %s ของคุณแล้ว",
+		"join_approved.next_steps": "การชำระเงินครั้งแรกจำนวน %s ครบกำหนดวันที่ %s เปิด Hubster เพื่อดูรายละเอียดการชำระเงินของโฮสต์",
+		"payment_reminder.subject": "แจ้งเตือนการชำระเงินสำหรับ %s",
+		"payment_reminder.body":    "การชำระเงินจำนวน %s สำหรับ %s ครบกำหนดวันที่ %s",
+		"payment_reminder.overdue": "การชำระเงินจำนวน %s สำหรับ %s ครบกำหนดเมื่อวันที่ %s และเกินกำหนดแล้ว",
+		"payment_reminder.action":  "กรุณาชำระเงินให้โฮสต์และส่งหลักฐานการชำระเงินใน Hubster",
+		"payment_approved.subject": "การชำระเงินสำหรับ %s ได้รับการอนุมัติ",
+		"payment_approved.body":    "การชำระเงินจำนวน %s สำหรับ %s (%s) ได้รับการอนุมัติแล้ว",
+		"payment_approved.next":    "การชำระเงินครั้งถัดไปครบกำหนดวันที่ %s",
+		"payment_declined.subject": "การชำระเงินสำหรับ %s ถูกปฏิเสธ",
+		"payment_declined.body":    "หลักฐานการชำระเงินสำหรับ %s (%s) ถูกโฮสต์ปฏิเสธ",
+		"payment_declined.reason":  "เหตุผล: %s",
+		"payment_declined.action":  "กรุณาตรวจสอบรายละเอียดการชำระเงินและส่งหลักฐานใหม่ใน Hubster",
+		"host_digest.subject":      "สรุปประจำสัปดาห์จาก Hubster",
+		"host_digest.intro":        "สรุปความเคลื่อนไหวของกลุ่มที่คุณเป็นโฮสต์ในสัปดาห์ของวันที่ %s",
+		"host_digest.subscription": "กลุ่ม",
+		"host_digest.members":      "สมาชิก",
+		"host_digest.pending_jr":   "คำขอเข้าร่วมที่รอ",
+		"host_digest.pending_pr":   "หลักฐานที่รอตรวจสอบ",
+		"host_digest.collected":    "ยอดที่ได้รับสัปดาห์นี้",
+		"host_digest.total":        "ยอดรวมที่ได้รับสัปดาห์นี้: %s",
+		"host_digest.action":       "เปิด Hubster เพื่อตรวจสอบคำขอเข้าร่วมและหลักฐานการชำระเงินที่รออยู่",
+	},
+}
+
+// Translate returns the localized string for key formatted with args, falling back to English.
+func Translate(locale Locale, key string, args ...any) string {
+	format, ok := translations[locale][key]
+	if !ok {
+		format, ok = translations[LocaleEnglish][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
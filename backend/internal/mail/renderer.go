@@ -0,0 +1,126 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Template names an email template. Every template has an HTML and a plain-text variant.
+type Template string
+
+const (
+	TemplateJoinApproved    Template = "join_approved"
+	TemplatePaymentReminder Template = "payment_reminder"
+	TemplatePaymentApproved Template = "payment_approved"
+	TemplatePaymentDeclined Template = "payment_declined"
+	TemplateHostDigest      Template = "host_digest"
+)
+
+var allTemplates = []Template{
+	TemplateJoinApproved,
+	TemplatePaymentReminder,
+	TemplatePaymentApproved,
+	TemplatePaymentDeclined,
+	TemplateHostDigest,
+}
+
+// Renderer renders the embedded email templates for a locale.
+type Renderer struct {
+	html     map[Template]*htmltemplate.Template
+	text     map[Template]*texttemplate.Template
+	location *time.Location
+}
+
+// NewRenderer parses the embedded templates. Dates are rendered in the given location.
+func NewRenderer(location *time.Location) (*Renderer, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	r := &Renderer{
+		html:     make(map[Template]*htmltemplate.Template, len(allTemplates)),
+		text:     make(map[Template]*texttemplate.Template, len(allTemplates)),
+		location: location,
+	}
+
+	// Placeholders so the templates parse; Render swaps in functions bound to the recipient's locale.
+	funcs := r.funcs(LocaleEnglish)
+	for _, name := range allTemplates {
+		htmlTmpl, err := htmltemplate.New("layout.html.tmpl").Funcs(htmltemplate.FuncMap(funcs)).
+			ParseFS(templateFS, "templates/layout.html.tmpl", fmt.Sprintf("templates/%s.html.tmpl", name))
+		if err != nil {
+			return nil, fmt.Errorf("parsing HTML template '%s': %w", name, err)
+		}
+		textTmpl, err := texttemplate.New("layout.txt.tmpl").Funcs(funcs).
+			ParseFS(templateFS, "templates/layout.txt.tmpl", fmt.Sprintf("templates/%s.txt.tmpl", name))
+		if err != nil {
+			return nil, fmt.Errorf("parsing text template '%s': %w", name, err)
+		}
+		r.html[name] = htmlTmpl
+		r.text[name] = textTmpl
+	}
+	return r, nil
+}
+
+// Render renders the subject and both bodies of a template. The returned message has no recipient set.
+// The data passed in must expose a RecipientName field, which the layouts use for the greeting.
+func (r *Renderer) Render(name Template, locale Locale, data any) (Message, error) {
+	htmlTmpl, ok := r.html[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template '%s'", name)
+	}
+	funcs := r.funcs(locale)
+
+	textTmpl, err := r.text[name].Clone()
+	if err != nil {
+		return Message{}, fmt.Errorf("cloning text template '%s': %w", name, err)
+	}
+	textTmpl.Funcs(funcs)
+
+	htmlClone, err := htmlTmpl.Clone()
+	if err != nil {
+		return Message{}, fmt.Errorf("cloning HTML template '%s': %w", name, err)
+	}
+	htmlClone.Funcs(htmltemplate.FuncMap(funcs))
+
+	var subject, text, html bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("rendering subject of '%s': %w", name, err)
+	}
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("rendering text body of '%s': %w", name, err)
+	}
+	if err := htmlClone.Execute(&html, data); err != nil {
+		return Message{}, fmt.Errorf("rendering HTML body of '%s': %w", name, err)
+	}
+
+	return Message{
+		Subject:  strings.TrimSpace(subject.String()),
+		HTMLBody: html.String(),
+		TextBody: text.String(),
+	}, nil
+}
+
+func (r *Renderer) funcs(locale Locale) texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"t": func(key string, args ...any) string {
+			return Translate(locale, key, args...)
+		},
+		"date": func(t time.Time) string {
+			return FormatDate(t, r.location, locale)
+		},
+		"amount": func(amount float64) string {
+			return FormatAmount(amount, locale)
+		},
+		"lang": func() string {
+			return string(locale)
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	ClientIPKey  = "clientIP"
+	UserAgentKey = "userAgent"
+)
+
+// RequestMetadata stores the client's IP address and user agent in context locals, so services
+// can attribute the changes they make (e.g. in the audit log) through the request context.
+func RequestMetadata() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(ClientIPKey, c.IP())
+		// Header values point into fasthttp's reusable buffers, so keep a copy.
+		c.Locals(UserAgentKey, strings.Clone(c.Get(fiber.HeaderUserAgent)))
+		return c.Next()
+	}
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditAction names a state-changing action, as "<entity>.<verb>".
+type AuditAction string
+
+const (
+	AuditUserSignUp                AuditAction = "user.sign_up"
+	AuditUserUpdateProfile         AuditAction = "user.update_profile"
+	AuditSubscriptionServiceCreate AuditAction = "subscription_service.create"
+	AuditHostedSubscriptionCreate  AuditAction = "hosted_subscription.create"
+	AuditReminderScheduleUpdate    AuditAction = "hosted_subscription.update_reminder_schedule"
+	AuditPromptPayUpdate           AuditAction = "hosted_subscription.update_promptpay"
+	AuditRenewalDateUpdate         AuditAction = "hosted_subscription.update_renewal_date"
+	AuditVisibilityUpdate          AuditAction = "hosted_subscription.update_visibility"
+	AuditScreeningQuestionsUpdate  AuditAction = "hosted_subscription.update_screening_questions"
+	AuditTotalSlotsUpdate          AuditAction = "hosted_subscription.update_total_slots"
+	AuditInviteCreate              AuditAction = "subscription_invite.create"
+	AuditInviteRevoke              AuditAction = "subscription_invite.revoke"
+	AuditInviteAccept              AuditAction = "subscription_invite.accept"
+	AuditWaitlistJoin              AuditAction = "waitlist_entry.join"
+	AuditWaitlistLeave             AuditAction = "waitlist_entry.leave"
+	AuditWaitlistClaim             AuditAction = "waitlist_entry.claim"
+	AuditAutoApprovalRuleCreate    AuditAction = "auto_approval_rule.create"
+	AuditAutoApprovalRuleUpdate    AuditAction = "auto_approval_rule.update"
+	AuditAutoApprovalRuleDelete    AuditAction = "auto_approval_rule.delete"
+	AuditJoinRequestCreate         AuditAction = "join_request.create"
+	AuditJoinRequestApprove        AuditAction = "join_request.approve"
+	AuditJoinRequestDecline        AuditAction = "join_request.decline"
+	AuditMembershipCreate          AuditAction = "membership.create"
+	AuditMembershipLeave           AuditAction = "membership.leave"
+	AuditMembershipRemove          AuditAction = "membership.remove"
+	AuditPaymentRecordSubmit       AuditAction = "payment_record.submit"
+	AuditPaymentRecordApprove      AuditAction = "payment_record.approve"
+	AuditPaymentRecordAutoApprove  AuditAction = "payment_record.auto_approve"
+	AuditPaymentRecordRevertAuto   AuditAction = "payment_record.revert_auto_approval"
+	AuditPaymentRecordDecline      AuditAction = "payment_record.decline"
+	AuditPaymentRecordDispute      AuditAction = "payment_record.dispute"
+	AuditPaymentRecordResubmit     AuditAction = "payment_record.resubmit"
+	AuditPaymentRecordPostMessage  AuditAction = "payment_record.post_message"
+	AuditRefundCreate              AuditAction = "refund.create"
+	AuditRefundAcknowledge         AuditAction = "refund.acknowledge"
+	AuditNotificationPrefsUpdate   AuditAction = "notification_preference.update"
+	AuditDeviceRegister            AuditAction = "device.register"
+	AuditDeviceUnregister          AuditAction = "device.unregister"
+	AuditWebhookEndpointCreate     AuditAction = "webhook_endpoint.create"
+	AuditWebhookEndpointUpdate     AuditAction = "webhook_endpoint.update"
+	AuditWebhookEndpointDelete     AuditAction = "webhook_endpoint.delete"
+	AuditWebhookDeliveryRedeliver  AuditAction = "webhook_delivery.redeliver"
+	AuditCalendarFeedIssue         AuditAction = "calendar_feed.issue_token"
+	AuditCalendarFeedDelete        AuditAction = "calendar_feed.delete"
+)
+
+// AuditChange is the value of one field before and after an action. Before is null for creations
+// and After is null for deletions.
+// @name AuditChange
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditChanges maps field names to their change. It is stored as a JSON document.
+type AuditChanges map[string]AuditChange
+
+// Value implements driver.Valuer.
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (c *AuditChanges) Scan(value any) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*c = AuditChanges{}
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into AuditChanges", value)
+	}
+	return json.Unmarshal(raw, c)
+}
+
+// AuditLog is an append-only record of who changed what, when and from where.
+// @name AuditLog
+type AuditLog struct {
+	ID                   uint         `gorm:"primarykey" json:"id"`
+	CreatedAt            time.Time    `gorm:"index" json:"createdAt"`
+	ActorUserID          *uint        `gorm:"index" json:"actor_user_id,omitempty"` // Nil for actions taken by the system
+	Action               AuditAction  `gorm:"type:varchar(100);not null;index" json:"action"`
+	EntityType           string       `gorm:"type:varchar(50);not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID             uint         `gorm:"not null;index:idx_audit_logs_entity" json:"entity_id"`
+	HostedSubscriptionID *uint        `gorm:"index" json:"hosted_subscription_id,omitempty"`
+	Changes              AuditChanges `gorm:"type:text;not null" json:"changes" swaggertype:"object"`
+	IPAddress            string       `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	UserAgent            string       `gorm:"type:varchar(255)" json:"user_agent,omitempty"`
+}
+
+// AuditLogFilter narrows an audit log query. Zero values match everything.
+type AuditLogFilter struct {
+	HostedSubscriptionID *uint
+	ActorUserID          *uint
+	EntityType           string
+	EntityID             *uint
+	Action               AuditAction
+	From                 *time.Time
+	To                   *time.Time
+}
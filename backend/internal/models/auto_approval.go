@@ -0,0 +1,81 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AutoApprovalRule lets a hosted subscription approve payment proofs on submission without the host.
+// A proof is approved by the first enabled rule whose conditions it all meets; a rule needs at least one condition.
+// Proofs flagged by the slip or image checks, and corrected proofs replacing a declined one, are left for the host.
+// @name AutoApprovalRule
+type AutoApprovalRule struct {
+	ID                     uint               `gorm:"primarykey" json:"id"`
+	CreatedAt              time.Time          `json:"createdAt"`
+	UpdatedAt              time.Time          `json:"updatedAt"`
+	HostedSubscriptionID   uint               `gorm:"not null;index" json:"hosted_subscription_id"`
+	HostedSubscription     HostedSubscription `gorm:"foreignKey:HostedSubscriptionID" json:"-"`
+	Name                   string             `gorm:"type:varchar(100);not null" json:"name"`
+	Enabled                bool               `gorm:"not null" json:"enabled"`
+	RequireExactAmount     bool               `gorm:"not null" json:"require_exact_amount"`     // Amount paid equals the amount expected
+	RequireUniqueReference bool               `gorm:"not null" json:"require_unique_reference"` // Transaction reference given and not used by any other payment
+	RequireVerifiedSlip    bool               `gorm:"not null" json:"require_verified_slip"`    // The slip QR code was read and matches the reference
+	MinOnTimeApprovals     int                `gorm:"not null;default:0" json:"min_on_time_approvals"`
+}
+
+// HasConditions reports whether the rule checks anything at all.
+func (r *AutoApprovalRule) HasConditions() bool {
+	return r.RequireExactAmount || r.RequireUniqueReference || r.RequireVerifiedSlip || r.MinOnTimeApprovals > 0
+}
+
+// Conditions describes the rule's conditions, e.g. "exact amount, 3 on-time approvals".
+func (r *AutoApprovalRule) Conditions() string {
+	var conditions []string
+	if r.RequireExactAmount {
+		conditions = append(conditions, "exact amount")
+	}
+	if r.RequireUniqueReference {
+		conditions = append(conditions, "unique reference")
+	}
+	if r.RequireVerifiedSlip {
+		conditions = append(conditions, "verified slip")
+	}
+	if r.MinOnTimeApprovals > 0 {
+		conditions = append(conditions, fmt.Sprintf("%d on-time approvals", r.MinOnTimeApprovals))
+	}
+	return strings.Join(conditions, ", ")
+}
+
+// AutoApproval logs a payment record approved by a rule, along with the membership's billing before the approval
+// so the host can revert it.
+// @name AutoApproval
+type AutoApproval struct {
+	ID                       uint       `gorm:"primarykey" json:"id"`
+	CreatedAt                time.Time  `json:"createdAt"`
+	PaymentRecordID          uint       `gorm:"not null;uniqueIndex" json:"payment_record_id"`
+	SubscriptionMembershipID uint       `gorm:"not null" json:"subscription_membership_id"`
+	HostedSubscriptionID     uint       `gorm:"not null;index" json:"hosted_subscription_id"`
+	RuleID                   uint       `gorm:"not null" json:"rule_id"`
+	RuleName                 string     `gorm:"type:varchar(100);not null" json:"rule_name"`
+	RuleConditions           string     `gorm:"type:varchar(255)" json:"rule_conditions"` // The rule's conditions when it approved the record
+	AmountPaid               float64    `gorm:"not null" json:"amount_paid"`
+	RevertedAt               *time.Time `json:"reverted_at,omitempty"`
+	RevertedByUserID         *uint      `json:"reverted_by_user_id,omitempty"`
+
+	PreviousBalance         float64    `gorm:"not null" json:"-"`
+	PreviousNextPaymentDate *time.Time `json:"-"`
+	PreviousBilledThrough   *time.Time `json:"-"`
+	LastLedgerEntryID       uint       `gorm:"not null" json:"-"` // The membership's last ledger entry once the approval was applied
+}
+
+// AutoApprovalRuleRequest defines the request body for creating or replacing an auto-approval rule.
+// @name AutoApprovalRuleRequest
+type AutoApprovalRuleRequest struct {
+	Name                   string `json:"name" validate:"required,min=1,max=100"`
+	Enabled                bool   `json:"enabled"`
+	RequireExactAmount     bool   `json:"require_exact_amount"`
+	RequireUniqueReference bool   `json:"require_unique_reference"`
+	RequireVerifiedSlip    bool   `json:"require_verified_slip"`
+	MinOnTimeApprovals     int    `json:"min_on_time_approvals" validate:"min=0,max=100"`
+}
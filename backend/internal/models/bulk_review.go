@@ -0,0 +1,40 @@
+package models
+
+// BulkApprovePaymentProofsRequest defines the request body for a host approving several payment proofs at once.
+// @name BulkApprovePaymentProofsRequest
+type BulkApprovePaymentProofsRequest struct {
+	PaymentRecordIDs        []uint `json:"payment_record_ids" validate:"required,min=1,max=100,dive,gt=0"`
+	AcknowledgeSlipWarnings bool   `json:"acknowledge_slip_warnings,omitempty"` // Applies to every proof in the batch
+}
+
+// BulkDeclinePaymentProofsRequest defines the request body for a host declining several payment proofs at once.
+// @name BulkDeclinePaymentProofsRequest
+type BulkDeclinePaymentProofsRequest struct {
+	PaymentRecordIDs []uint `json:"payment_record_ids" validate:"required,min=1,max=100,dive,gt=0"`
+	Reason           string `json:"reason,omitempty" validate:"max=1000"` // Given for every proof in the batch
+}
+
+// BulkJoinRequestReviewRequest defines the request body for a host approving or declining several join requests at once.
+// @name BulkJoinRequestReviewRequest
+type BulkJoinRequestReviewRequest struct {
+	JoinRequestIDs []uint `json:"join_request_ids" validate:"required,min=1,max=100,dive,gt=0"`
+}
+
+// BulkReviewItemResult is the outcome of one item of a bulk review.
+// @name BulkReviewItemResult
+type BulkReviewItemResult struct {
+	ID         uint   `json:"id"`
+	Succeeded  bool   `json:"succeeded"`
+	StatusCode int    `json:"status_code"`      // What the single-item endpoint would have answered
+	Status     string `json:"status,omitempty"` // The item's status after a successful review
+	Error      string `json:"error,omitempty"`
+}
+
+// BulkReviewResponse reports a bulk review item by item. Each item is reviewed on its own, so a failed item
+// leaves the others reviewed.
+// @name BulkReviewResponse
+type BulkReviewResponse struct {
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []BulkReviewItemResult `json:"results"`
+}
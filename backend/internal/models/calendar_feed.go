@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// CalendarFeed is a user's secret iCalendar feed of payment due dates and renewal dates. Only a SHA-256 hash
+// of the feed token is stored, so the feed URL is shown once, when the token is issued.
+type CalendarFeed struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	UserID        uint       `gorm:"not null;uniqueIndex" json:"user_id"`
+	User          User       `gorm:"foreignKey:UserID" json:"-"`
+	TokenHash     string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+}
+
+// CalendarFeedResponse describes a user's calendar feed. URL is only set right after a token is issued.
+// @name CalendarFeedResponse
+type CalendarFeedResponse struct {
+	Enabled       bool       `json:"enabled"`
+	URL           string     `json:"url,omitempty"`
+	IssuedAt      *time.Time `json:"issued_at,omitempty"`
+	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+}
+
+// UpdateRenewalDateRequest defines the request body for a host setting the date their own plan renews on.
+// An empty date clears it.
+// @name UpdateRenewalDateRequest
+type UpdateRenewalDateRequest struct {
+	RenewalDate string `json:"renewal_date" validate:"omitempty,datetime=2006-01-02"` // YYYY-MM-DD
+}
+
+// RenewalDateResponse is the renewal date of a hosted subscription.
+// @name RenewalDateResponse
+type RenewalDateResponse struct {
+	HostedSubscriptionID uint             `json:"hosted_subscription_id"`
+	BillingCycle         BillingCycleType `json:"billing_cycle"`
+	RenewalDate          *time.Time       `json:"renewal_date"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+// DevicePlatform defines the platform a device token was issued on.
+type DevicePlatform string
+
+const (
+	DevicePlatformAndroid DevicePlatform = "android"
+	DevicePlatformIOS     DevicePlatform = "ios"
+	DevicePlatformWeb     DevicePlatform = "web"
+)
+
+// PushProvider defines the push service that issued a device token.
+type PushProvider string
+
+const (
+	PushProviderFCM  PushProvider = "fcm"
+	PushProviderAPNs PushProvider = "apns"
+)
+
+// DeviceToken is a push notification token registered by a user's device.
+// @name DeviceToken
+type DeviceToken struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`
+	User       User           `gorm:"foreignKey:UserID" json:"-"`
+	Token      string         `gorm:"type:varchar(512);uniqueIndex;not null" json:"token"`
+	Platform   DevicePlatform `gorm:"type:varchar(20);not null" json:"platform"`
+	Provider   PushProvider   `gorm:"type:varchar(20);not null" json:"provider"`
+	LastSeenAt time.Time      `gorm:"not null" json:"last_seen_at"`
+}
+
+// RegisterDeviceRequest defines the request body for registering a device for push notifications.
+// @name RegisterDeviceRequest
+type RegisterDeviceRequest struct {
+	Token    string         `json:"token" validate:"required,max=512"`
+	Platform DevicePlatform `json:"platform" validate:"required,oneof=android ios web"`
+	Provider PushProvider   `json:"provider,omitempty" validate:"omitempty,oneof=fcm apns"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// PaymentRecordExportFilter narrows a payment record export of one hosted subscription. Zero values match everything.
+type PaymentRecordExportFilter struct {
+	HostedSubscriptionID uint
+	From                 *time.Time // Submitted at or after
+	To                   *time.Time // Submitted before
+	Statuses             []PaymentRecordStatus
+}
+
+// PaymentRecordExportRow is one payment record of an export, with its member.
+type PaymentRecordExportRow struct {
+	ID                     uint
+	SubmittedAt            time.Time
+	PaymentCycleIdentifier string
+	CycleCount             int
+	MemberName             string
+	MemberEmail            string
+	AmountExpected         float64
+	AmountPaid             float64
+	PaymentMethod          string
+	TransactionReference   string
+	Status                 PaymentRecordStatus
+	ReviewedAt             *time.Time
+	DeclineReason          string
+	SlipCheckStatus        SlipCheckStatus
+}
+
+// StatementLine is one membership ledger entry on a statement, with its member.
+type StatementLine struct {
+	ID                       uint
+	CreatedAt                time.Time
+	SubscriptionMembershipID uint
+	MemberName               string
+	Kind                     MembershipLedgerEntryKind
+	Amount                   float64
+	BalanceAfter             float64
+	Description              string
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+)
+
+// HostDashboardActionType defines what a host is asked to do by a dashboard action item.
+type HostDashboardActionType string
+
+const (
+	HostDashboardActionJoinRequest     HostDashboardActionType = "join_request"     // A join request waits for approval
+	HostDashboardActionPaymentProof    HostDashboardActionType = "payment_proof"    // A payment proof waits for review
+	HostDashboardActionDisputedPayment HostDashboardActionType = "disputed_payment" // A member disputed a declined payment
+	HostDashboardActionOverdueMember   HostDashboardActionType = "overdue_member"   // A member is past their payment date
+)
+
+// HostDashboardSubscription summarizes one hosted subscription on the host dashboard.
+// Expected and collected revenue cover the payment cycles due in the current month.
+// @name HostDashboardSubscription
+type HostDashboardSubscription struct {
+	HostedSubscriptionID uint    `json:"hosted_subscription_id"`
+	SubscriptionTitle    string  `json:"subscription_title"`
+	TotalSlots           int     `json:"total_slots"`
+	MembersCount         int     `json:"members_count"`
+	PendingJoinRequests  int     `json:"pending_join_requests"`
+	PendingPaymentProofs int     `json:"pending_payment_proofs"`
+	DisputedPayments     int     `json:"disputed_payments"`
+	OverdueMembers       int     `json:"overdue_members"`
+	OutstandingAmount    float64 `json:"outstanding_amount"` // Owed on cycles already charged
+	ExpectedRevenue      float64 `json:"expected_revenue"`
+	CollectedRevenue     float64 `json:"collected_revenue"`
+}
+
+// HostDashboardTotals sums the host dashboard over all of the host's subscriptions.
+// @name HostDashboardTotals
+type HostDashboardTotals struct {
+	Subscriptions        int     `json:"subscriptions"`
+	MembersCount         int     `json:"members_count"`
+	PendingJoinRequests  int     `json:"pending_join_requests"`
+	PendingPaymentProofs int     `json:"pending_payment_proofs"`
+	DisputedPayments     int     `json:"disputed_payments"`
+	OverdueMembers       int     `json:"overdue_members"`
+	OutstandingAmount    float64 `json:"outstanding_amount"`
+	ExpectedRevenue      float64 `json:"expected_revenue"`
+	CollectedRevenue     float64 `json:"collected_revenue"`
+}
+
+// HostDashboardActionItem is something waiting on the host, oldest first.
+// EntityID is the join request, payment record or membership the item is about, depending on Type.
+// @name HostDashboardActionItem
+type HostDashboardActionItem struct {
+	Type                 HostDashboardActionType `json:"type"`
+	EntityID             uint                    `json:"entity_id"`
+	HostedSubscriptionID uint                    `json:"hosted_subscription_id"`
+	SubscriptionTitle    string                  `json:"subscription_title"`
+	UserName             string                  `json:"user_name"`        // The requester or member
+	Amount               *float64                `json:"amount,omitempty"` // Paid on a proof, or owed by an overdue member
+	Since                time.Time               `json:"since"`            // When the request, submission or dispute was made, or the payment fell due
+}
+
+// HostDashboardResponse is the host dashboard: per-subscription summaries, their totals and the open action items.
+// @name HostDashboardResponse
+type HostDashboardResponse struct {
+	CycleStart    time.Time                   `json:"cycle_start"` // The current month, in the server's time zone
+	CycleEnd      time.Time                   `json:"cycle_end"`
+	Totals        HostDashboardTotals         `json:"totals"`
+	Subscriptions []HostDashboardSubscription `json:"subscriptions"`
+	ActionItems   []HostDashboardActionItem   `json:"action_items"`
+}
@@ -12,53 +12,78 @@ const (
 	BillingAnnually BillingCycleType = "Annually"
 )
 
+// SubscriptionVisibility defines who can find and join a hosted subscription.
+type SubscriptionVisibility string
+
+const (
+	VisibilityPublic   SubscriptionVisibility = "Public"   // Listed in explore; anyone can request to join
+	VisibilityUnlisted SubscriptionVisibility = "Unlisted" // Not listed; anyone who has the link can request to join
+	VisibilityPrivate  SubscriptionVisibility = "Private"  // Not listed; only people with an invite can see and join it
+)
+
 // HostedSubscription represents a subscription plan offered for sharing by a host.
 // @name HostedSubscription
 type HostedSubscription struct {
-	ID                    uint                     `gorm:"primarykey" json:"id"`
-	CreatedAt             time.Time                `json:"createdAt"`
-	UpdatedAt             time.Time                `json:"updatedAt"`
-	HostUserID            uint                     `gorm:"not null" json:"host_user_id"`
-	User                  User                     `gorm:"foreignKey:HostUserID" json:"-"`
-	SubscriptionServiceID uint                     `gorm:"not null" json:"subscription_service_id"`
-	SubscriptionService   SubscriptionService      `gorm:"foreignKey:SubscriptionServiceID" json:"-"`
-	SubscriptionTitle     string                   `gorm:"type:varchar(255);not null" json:"subscription_title"`
-	PlanDetails           string                   `gorm:"type:text" json:"plan_details,omitempty"`
-	TotalSlots            int                      `gorm:"not null" json:"total_slots"`
-	CostPerCycle          float64                  `gorm:"not null" json:"cost_per_cycle"`
-	BillingCycle          BillingCycleType         `gorm:"type:varchar(20);not null" json:"billing_cycle"`
-	PaymentQRCodeURL      string                   `gorm:"type:text" json:"payment_qr_code_url,omitempty"`
-	Description           string                   `gorm:"type:text" json:"description,omitempty"`
-	Memberships           []SubscriptionMembership `gorm:"foreignKey:HostedSubscriptionID" json:"-"`
+	ID                     uint                     `gorm:"primarykey" json:"id"`
+	CreatedAt              time.Time                `json:"createdAt"`
+	UpdatedAt              time.Time                `json:"updatedAt"`
+	HostUserID             uint                     `gorm:"not null" json:"host_user_id"`
+	User                   User                     `gorm:"foreignKey:HostUserID" json:"-"`
+	SubscriptionServiceID  uint                     `gorm:"not null" json:"subscription_service_id"`
+	SubscriptionService    SubscriptionService      `gorm:"foreignKey:SubscriptionServiceID" json:"-"`
+	SubscriptionTitle      string                   `gorm:"type:varchar(255);not null" json:"subscription_title"`
+	PlanDetails            string                   `gorm:"type:text" json:"plan_details,omitempty"`
+	TotalSlots             int                      `gorm:"not null" json:"total_slots"`
+	CostPerCycle           float64                  `gorm:"not null" json:"cost_per_cycle"`
+	BillingCycle           BillingCycleType         `gorm:"type:varchar(20);not null" json:"billing_cycle"`
+	PaymentQRCodeURL       string                   `gorm:"type:text" json:"payment_qr_code_url,omitempty"`
+	PromptPayID            string                   `gorm:"type:varchar(20)" json:"-"` // Mobile number or national ID that dynamic payment QR codes pay to
+	Description            string                   `gorm:"type:text" json:"description,omitempty"`
+	CustomReminderSchedule bool                     `gorm:"not null;default:false" json:"-"`
+	RenewalDate            *time.Time               `gorm:"type:date" json:"renewal_date,omitempty"` // A date the host's own plan renews on; it repeats every billing cycle
+	Visibility             SubscriptionVisibility   `gorm:"type:varchar(20);not null;default:'Public';index" json:"visibility"`
+	Memberships            []SubscriptionMembership `gorm:"foreignKey:HostedSubscriptionID" json:"-"`
 }
 
 // CreateHostedSubscriptionRequest defines the request body for creating a new hosted subscription.
 // @name CreateHostedSubscriptionRequest
 type CreateHostedSubscriptionRequest struct {
-	SubscriptionServiceID uint             `json:"subscription_service_id" validate:"required,gt=0"`
-	SubscriptionTitle     string           `json:"subscription_title" validate:"required,min=3,max=100"`
-	PlanDetails           string           `json:"plan_details,omitempty" validate:"max=255"`
-	TotalSlots            int              `json:"total_slots" validate:"required,min=1,max=20"`
-	CostPerCycle          float64          `json:"cost_per_cycle" validate:"required,gt=0"`
-	BillingCycle          BillingCycleType `json:"billing_cycle" validate:"required,oneof=Monthly Annually"`
-	PaymentQRCodeURL      string           `json:"payment_qr_code_url" validate:"omitempty,url"`
-	Description           string           `json:"description,omitempty" validate:"max=1000"`
+	SubscriptionServiceID uint                   `json:"subscription_service_id" validate:"required,gt=0"`
+	SubscriptionTitle     string                 `json:"subscription_title" validate:"required,min=3,max=100"`
+	PlanDetails           string                 `json:"plan_details,omitempty" validate:"max=255"`
+	TotalSlots            int                    `json:"total_slots" validate:"required,min=1,max=20"`
+	CostPerCycle          float64                `json:"cost_per_cycle" validate:"required,gt=0"`
+	BillingCycle          BillingCycleType       `json:"billing_cycle" validate:"required,oneof=Monthly Annually"`
+	PaymentQRCodeURL      string                 `json:"payment_qr_code_url" validate:"omitempty,url"`
+	PromptPayID           string                 `json:"promptpay_id,omitempty" validate:"omitempty,max=20"`
+	Description           string                 `json:"description,omitempty" validate:"max=1000"`
+	RenewalDate           string                 `json:"renewal_date,omitempty" validate:"omitempty,datetime=2006-01-02"`         // YYYY-MM-DD
+	Visibility            SubscriptionVisibility `json:"visibility,omitempty" validate:"omitempty,oneof=Public Unlisted Private"` // Defaults to Public
+}
+
+// UpdateVisibilityRequest defines the request body for a host changing who can find and join their subscription.
+// @name UpdateVisibilityRequest
+type UpdateVisibilityRequest struct {
+	Visibility SubscriptionVisibility `json:"visibility" validate:"required,oneof=Public Unlisted Private"`
 }
 
 // HostedSubscriptionResponse is the DTO for returning hosted subscription details.
 // @name HostedSubscriptionResponse
 type HostedSubscriptionResponse struct {
-	ID                uint             `json:"id"`
-	Host              *UserResponse    `json:"host,omitempty"`
-	SubscriptionTitle string           `json:"subscription_title"`
-	PlanDetails       string           `json:"plan_details,omitempty"`
-	TotalSlots        int              `json:"total_slots"`
-	CostPerCycle      float64          `json:"cost_per_cycle"`
-	BillingCycle      BillingCycleType `json:"billing_cycle"`
-	PaymentQRCodeURL  string           `json:"payment_qr_code_url,omitempty"`
-	Description       string           `json:"description,omitempty"`
-	CreatedAt         time.Time        `json:"createdAt"`
-	UpdatedAt         time.Time        `json:"updatedAt"`
+	ID                uint                   `json:"id"`
+	Host              *UserResponse          `json:"host,omitempty"`
+	SubscriptionTitle string                 `json:"subscription_title"`
+	PlanDetails       string                 `json:"plan_details,omitempty"`
+	TotalSlots        int                    `json:"total_slots"`
+	CostPerCycle      float64                `json:"cost_per_cycle"`
+	BillingCycle      BillingCycleType       `json:"billing_cycle"`
+	PaymentQRCodeURL  string                 `json:"payment_qr_code_url,omitempty"`
+	PromptPayEnabled  bool                   `json:"promptpay_enabled"`
+	RenewalDate       *time.Time             `json:"renewal_date,omitempty"`
+	Visibility        SubscriptionVisibility `json:"visibility"`
+	Description       string                 `json:"description,omitempty"`
+	CreatedAt         time.Time              `json:"createdAt"`
+	UpdatedAt         time.Time              `json:"updatedAt"`
 
 	// Enriched / Calculated data
 	SubscriptionServiceName string   `json:"subscription_service_name"`
@@ -26,6 +26,9 @@ type JoinRequest struct {
 	HostedSubscriptionID uint               `gorm:"not null;uniqueIndex:idx_requester_subscription" json:"hosted_subscription_id"`
 	HostedSubscription   HostedSubscription `gorm:"foreignKey:HostedSubscriptionID" json:"-"`
 
-	RequestDate time.Time         `gorm:"not null" json:"request_date"`
-	Status      JoinRequestStatus `gorm:"type:varchar(20);not null;default:'Pending'" json:"status"`
+	RequestDate time.Time           `gorm:"not null" json:"request_date"`
+	Status      JoinRequestStatus   `gorm:"type:varchar(20);not null;default:'Pending'" json:"status"`
+	InviteID    *uint               `gorm:"index" json:"invite_id,omitempty"` // The invite the requester used, if any
+	Message     string              `gorm:"type:text" json:"message,omitempty"`
+	Answers     []JoinRequestAnswer `gorm:"foreignKey:JoinRequestID" json:"answers,omitempty"`
 }
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+// LedgerAccount names an account of the double-entry ledger. Every journal line also carries the host,
+// member and subscription it belongs to, so one chart of accounts serves every host and member.
+type LedgerAccount string
+
+const (
+	LedgerAccountReceivable LedgerAccount = "receivable" // Owed by a member to a host; a credit balance is member prepayment
+	LedgerAccountEarnings   LedgerAccount = "earnings"   // A host's revenue from billed cycles
+	LedgerAccountCash       LedgerAccount = "cash"       // Money a host has received
+)
+
+// JournalEntryKind defines the business event a journal entry records.
+type JournalEntryKind string
+
+const (
+	JournalEntryCycleCharge JournalEntryKind = "CycleCharge"
+	JournalEntryPayment     JournalEntryKind = "Payment"
+	JournalEntryRefund      JournalEntryKind = "Refund"
+	JournalEntryReversal    JournalEntryKind = "Reversal" // Cancels an earlier entry, e.g. of a reverted approval
+)
+
+// JournalEntry is one balanced transaction of the double-entry ledger: its lines' debits equal their credits.
+// @name JournalEntry
+type JournalEntry struct {
+	ID              uint             `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	OccurredAt      time.Time        `gorm:"not null;index" json:"occurred_at"`
+	Kind            JournalEntryKind `gorm:"type:varchar(30);not null" json:"kind"`
+	Description     string           `gorm:"type:varchar(255)" json:"description"`
+	PaymentRecordID *uint            `gorm:"index" json:"payment_record_id,omitempty"` // The payment whose approval or refund produced the entry
+	Lines           []JournalLine    `gorm:"foreignKey:JournalEntryID" json:"lines"`
+}
+
+// JournalLine debits or credits one account within a journal entry.
+// @name JournalLine
+type JournalLine struct {
+	ID                       uint          `gorm:"primarykey" json:"id"`
+	JournalEntryID           uint          `gorm:"not null;index" json:"journal_entry_id"`
+	OccurredAt               time.Time     `gorm:"not null;index" json:"occurred_at"` // Copied from the entry for reporting
+	Account                  LedgerAccount `gorm:"type:varchar(30);not null;index" json:"account"`
+	HostUserID               uint          `gorm:"not null;index" json:"host_user_id"`
+	MemberUserID             uint          `gorm:"not null;index" json:"member_user_id"`
+	HostedSubscriptionID     uint          `gorm:"not null;index" json:"hosted_subscription_id"`
+	SubscriptionServiceID    uint          `gorm:"not null" json:"subscription_service_id"`
+	SubscriptionMembershipID uint          `gorm:"not null;index" json:"subscription_membership_id"`
+	Debit                    float64       `gorm:"not null;default:0" json:"debit"`
+	Credit                   float64       `gorm:"not null;default:0" json:"credit"`
+}
+
+// HostEarningsRow is a host's billed and collected amounts for one subscription in one month.
+// @name HostEarningsRow
+type HostEarningsRow struct {
+	HostedSubscriptionID uint    `json:"hosted_subscription_id"`
+	SubscriptionTitle    string  `json:"subscription_title"`
+	Month                string  `json:"month"` // YYYY-MM in the server's time zone
+	Earned               float64 `json:"earned"`
+	Collected            float64 `json:"collected"`
+}
+
+// MemberSpendRow is what a member paid for one subscription service in one year.
+// @name MemberSpendRow
+type MemberSpendRow struct {
+	SubscriptionServiceID uint    `json:"subscription_service_id"`
+	ServiceName           string  `json:"service_name"`
+	Year                  int     `json:"year"`
+	Spent                 float64 `json:"spent"`
+}
+
+// ReceivableRow is the amount a member still owes a host for one membership.
+// @name ReceivableRow
+type ReceivableRow struct {
+	SubscriptionMembershipID uint    `json:"subscription_membership_id"`
+	HostedSubscriptionID     uint    `json:"hosted_subscription_id"`
+	SubscriptionTitle        string  `json:"subscription_title"`
+	MemberUserID             uint    `json:"member_user_id"`
+	MemberName               string  `json:"member_name"`
+	Outstanding              float64 `json:"outstanding"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// MemberDueItem is the next payment of one of a member's memberships, with what the host accepts payment by.
+// @name MemberDueItem
+type MemberDueItem struct {
+	SubscriptionMembershipID uint               `json:"subscription_membership_id"`
+	HostedSubscriptionID     uint               `json:"hosted_subscription_id"`
+	SubscriptionTitle        string             `json:"subscription_title"`
+	ServiceProviderName      string             `json:"service_provider_name"`
+	ServiceProviderLogoURL   string             `json:"service_provider_logo_url,omitempty"`
+	HostName                 string             `json:"host_name"`
+	DueDate                  *time.Time         `json:"due_date,omitempty"`
+	Amount                   float64            `json:"amount"` // Outstanding balance, or the member's share less any credit
+	PaymentStatus            PaymentStatusType  `json:"payment_status"`
+	AmountUnderReview        float64            `json:"amount_under_review"` // Paid on proofs the host has not reviewed yet
+	PaymentQRCodeURL         string             `json:"payment_qr_code_url,omitempty"`
+	PromptPay                *PaymentQRResponse `json:"promptpay,omitempty"` // Dynamic QR for the amount, when the host set up PromptPay
+}
+
+// MemberProjectedMonth is what a member is projected to pay in one month at current prices.
+// @name MemberProjectedMonth
+type MemberProjectedMonth struct {
+	Month  string  `json:"month"` // YYYY-MM in the server's time zone
+	Amount float64 `json:"amount"`
+}
+
+// MemberDuesSummaryResponse is what a member owes across all of their memberships. Overdue payments fell due
+// before today; payments due this month fall due by the end of the current month; upcoming ones after that.
+// @name MemberDuesSummaryResponse
+type MemberDuesSummaryResponse struct {
+	MonthStart        time.Time              `json:"month_start"` // The current month, in the server's time zone
+	MonthEnd          time.Time              `json:"month_end"`
+	TotalOverdue      float64                `json:"total_overdue"`
+	TotalDueThisMonth float64                `json:"total_due_this_month"` // Includes the overdue total
+	TotalUnderReview  float64                `json:"total_under_review"`
+	TotalCredit       float64                `json:"total_credit"`
+	Overdue           []MemberDueItem        `json:"overdue"`
+	DueThisMonth      []MemberDueItem        `json:"due_this_month"`
+	Upcoming          []MemberDueItem        `json:"upcoming"`
+	ProjectedSpend    []MemberProjectedMonth `json:"projected_spend"` // The next 12 months, starting with the current one
+	ProjectedTotal    float64                `json:"projected_total"`
+}
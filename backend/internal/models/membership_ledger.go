@@ -0,0 +1,50 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// MembershipLedgerEntryKind defines what moved money on a membership's ledger.
+type MembershipLedgerEntryKind string
+
+const (
+	MembershipLedgerCharge   MembershipLedgerEntryKind = "Charge"   // A payment cycle became billable
+	MembershipLedgerPayment  MembershipLedgerEntryKind = "Payment"  // An approved payment
+	MembershipLedgerRefund   MembershipLedgerEntryKind = "Refund"   // Credit returned to the member
+	MembershipLedgerReversal MembershipLedgerEntryKind = "Reversal" // A reverted approval's payment and charges taken back
+)
+
+// MembershipLedgerEntry is one movement on a membership's balance. Charges are negative and payments positive,
+// so the running balance is the member's credit when positive and their outstanding amount when negative.
+// @name MembershipLedgerEntry
+type MembershipLedgerEntry struct {
+	ID                       uint                      `gorm:"primarykey" json:"id"`
+	CreatedAt                time.Time                 `json:"createdAt"`
+	SubscriptionMembershipID uint                      `gorm:"not null;index" json:"subscription_membership_id"`
+	PaymentRecordID          *uint                     `gorm:"index" json:"payment_record_id,omitempty"`
+	Kind                     MembershipLedgerEntryKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Amount                   float64                   `gorm:"not null" json:"amount"`
+	BalanceAfter             float64                   `gorm:"not null" json:"balance_after"`
+	CycleDueDate             *time.Time                `json:"cycle_due_date,omitempty"` // The due date of the charged cycle
+	Description              string                    `gorm:"type:varchar(255)" json:"description"`
+}
+
+// PaymentCycleAllocation records how much of an approved payment went to one payment cycle.
+// A payment that prepays several cycles has one allocation per cycle.
+// @name PaymentCycleAllocation
+type PaymentCycleAllocation struct {
+	ID                       uint      `gorm:"primarykey" json:"id"`
+	CreatedAt                time.Time `json:"createdAt"`
+	PaymentRecordID          uint      `gorm:"not null;index" json:"payment_record_id"`
+	SubscriptionMembershipID uint      `gorm:"not null;index" json:"subscription_membership_id"`
+	CycleDueDate             time.Time `gorm:"not null" json:"cycle_due_date"`
+	Amount                   float64   `gorm:"not null" json:"amount"`
+	FullyPaid                bool      `gorm:"not null" json:"fully_paid"` // False when the cycle still has an outstanding amount
+}
+
+// RoundMoney rounds an amount to two decimal places, so splitting a cost between slots does not leave
+// fractional remainders on the ledger.
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationType defines the kind of event a notification describes.
+type NotificationType string
+
+const (
+	NotificationJoinRequestReceived  NotificationType = "JoinRequestReceived"
+	NotificationJoinRequestApproved  NotificationType = "JoinRequestApproved"
+	NotificationJoinRequestDeclined  NotificationType = "JoinRequestDeclined"
+	NotificationPaymentProofReceived NotificationType = "PaymentProofReceived"
+	NotificationPaymentProofApproved NotificationType = "PaymentProofApproved"
+	NotificationPaymentProofDeclined NotificationType = "PaymentProofDeclined"
+	NotificationPaymentProofReverted NotificationType = "PaymentProofReverted"
+	NotificationMemberJoined         NotificationType = "MemberJoined"
+	NotificationMemberLeft           NotificationType = "MemberLeft"
+	NotificationMemberRemoved        NotificationType = "MemberRemoved"
+	NotificationWaitlistSlotOffered  NotificationType = "WaitlistSlotOffered"
+	NotificationPaymentDue           NotificationType = "PaymentDue"
+	NotificationPaymentDisputed      NotificationType = "PaymentDisputed"
+	NotificationPaymentMessage       NotificationType = "PaymentMessage"
+	NotificationRefundIssued         NotificationType = "RefundIssued"
+	NotificationRefundAcknowledged   NotificationType = "RefundAcknowledged"
+)
+
+// Notification is an in-app message addressed to a single user.
+// @name Notification
+type Notification struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	UserID               uint             `gorm:"not null;index:idx_notifications_user_read" json:"user_id"`
+	User                 User             `gorm:"foreignKey:UserID" json:"-"`
+	Type                 NotificationType `gorm:"type:varchar(50);not null" json:"type"`
+	Title                string           `gorm:"type:varchar(255);not null" json:"title"`
+	Message              string           `gorm:"type:text" json:"message"`
+	HostedSubscriptionID *uint            `json:"hosted_subscription_id,omitempty"`
+	JoinRequestID        *uint            `json:"join_request_id,omitempty"`
+	PaymentRecordID      *uint            `json:"payment_record_id,omitempty"`
+	IsRead               bool             `gorm:"not null;default:false;index:idx_notifications_user_read" json:"is_read"`
+	ReadAt               *time.Time       `json:"read_at,omitempty"`
+}
+
+// UnreadNotificationCountResponse is the DTO for the unread notification badge.
+// @name UnreadNotificationCountResponse
+type UnreadNotificationCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationPreference stores a user's email opt-outs and preferred language.
+// Users without a stored row get the defaults: every email enabled in the server's default locale.
+// @name NotificationPreference
+type NotificationPreference struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	UserID                uint       `gorm:"not null;uniqueIndex" json:"user_id"`
+	User                  User       `gorm:"foreignKey:UserID" json:"-"`
+	Locale                string     `gorm:"type:varchar(10);not null" json:"locale"`
+	EmailJoinApprovals    bool       `gorm:"not null" json:"email_join_approvals"`
+	EmailPaymentReminders bool       `gorm:"not null" json:"email_payment_reminders"`
+	EmailPaymentOutcomes  bool       `gorm:"not null" json:"email_payment_outcomes"`
+	EmailWeeklyDigest     bool       `gorm:"not null" json:"email_weekly_digest"`
+	LastDigestSentAt      *time.Time `json:"-"`
+}
+
+// UpdateNotificationPreferenceRequest defines the request body for changing notification preferences.
+// Omitted fields are left unchanged.
+// @name UpdateNotificationPreferenceRequest
+type UpdateNotificationPreferenceRequest struct {
+	Locale                *string `json:"locale,omitempty" validate:"omitempty,oneof=en th"`
+	EmailJoinApprovals    *bool   `json:"email_join_approvals,omitempty"`
+	EmailPaymentReminders *bool   `json:"email_payment_reminders,omitempty"`
+	EmailPaymentOutcomes  *bool   `json:"email_payment_outcomes,omitempty"`
+	EmailWeeklyDigest     *bool   `json:"email_weekly_digest,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change that produced it.
+// The outbox relay publishes it to the in-process subscribers afterwards.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	EventID       string     `gorm:"type:varchar(36);uniqueIndex;not null" json:"event_id"`
+	EventType     string     `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_pending" json:"next_attempt_at"`
+	PublishedAt   *time.Time `gorm:"index:idx_outbox_pending" json:"published_at,omitempty"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+}
+
+// ProcessedOutboxEvent records that a subscriber has handled an outbox event, so redelivered events are skipped.
+type ProcessedOutboxEvent struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	EventID    string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_processed_event_subscriber" json:"event_id"`
+	Subscriber string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_processed_event_subscriber" json:"subscriber"`
+}
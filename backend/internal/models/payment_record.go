@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/xNatthapol/hubster/internal/phash"
 )
 
 // PaymentRecordStatus defines the status of a payment record/submission.
@@ -12,8 +14,45 @@ const (
 	PaymentRecordStatusApproved          PaymentRecordStatus = "Approved"
 	PaymentRecordStatusDeclined          PaymentRecordStatus = "Declined"
 	PaymentRecordStatusRequiresAttention PaymentRecordStatus = "RequiresAttention"
+	PaymentRecordStatusDisputed          PaymentRecordStatus = "Disputed"   // Declined, and the member asked the host to reconsider
+	PaymentRecordStatusSuperseded        PaymentRecordStatus = "Superseded" // Replaced by a corrected proof for the same cycle
+)
+
+// SlipCheckStatus is the outcome of reading the bank slip QR code in a payment proof image.
+type SlipCheckStatus string
+
+const (
+	SlipCheckVerified   SlipCheckStatus = "Verified"   // Slip read; its reference matches the one given, or filled it in
+	SlipCheckMismatch   SlipCheckStatus = "Mismatch"   // Slip read, but the member gave a different transaction reference
+	SlipCheckDuplicate  SlipCheckStatus = "Duplicate"  // The same slip backs another payment record
+	SlipCheckNoSlip     SlipCheckStatus = "NoSlip"     // No slip QR code found in the proof image
+	SlipCheckUnreadable SlipCheckStatus = "Unreadable" // The proof image could not be read
 )
 
+// NeedsAcknowledgement reports whether a host must explicitly acknowledge the slip check before approving.
+func (s SlipCheckStatus) NeedsAcknowledgement() bool {
+	return s == SlipCheckMismatch || s == SlipCheckDuplicate
+}
+
+// BlocksAutoApproval reports whether the checks on the proof flagged something a host has to look at. A proof image
+// that could not be read or hashed was never checked against earlier proofs, so it is left for the host as well.
+func (pr *PaymentRecord) BlocksAutoApproval() bool {
+	return pr.SlipCheckStatus.NeedsAcknowledgement() || pr.SimilarProofRecordID != nil ||
+		pr.SlipCheckStatus == SlipCheckUnreadable || pr.ProofImageHash == nil
+}
+
+// SetProofImageHash stores the perceptual hash of the proof image along with the bands it is looked up by.
+func (pr *PaymentRecord) SetProofImageHash(hash uint64) {
+	stored := int64(hash)
+	pr.ProofImageHash = &stored
+	bands := make([]int16, phash.BandCount)
+	for i, band := range phash.Bands(hash) {
+		bands[i] = int16(band)
+	}
+	pr.ProofImageHashBand0, pr.ProofImageHashBand1, pr.ProofImageHashBand2 = &bands[0], &bands[1], &bands[2]
+	pr.ProofImageHashBand3, pr.ProofImageHashBand4 = &bands[3], &bands[4]
+}
+
 // PaymentRecord stores information about a payment made by a member for a subscription slot.
 // @name PaymentRecord
 type PaymentRecord struct {
@@ -21,48 +60,109 @@ type PaymentRecord struct {
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 
-	SubscriptionMembershipID uint                   `gorm:"not null" json:"subscription_membership_id"`
-	SubscriptionMembership   SubscriptionMembership `gorm:"foreignKey:SubscriptionMembershipID" json:"-"`
-	PaymentCycleIdentifier   string                 `gorm:"type:varchar(100);not null" json:"payment_cycle_identifier"`
-	AmountExpected           float64                `gorm:"not null" json:"amount_expected"`
-	AmountPaid               float64                `gorm:"not null" json:"amount_paid"`
-	PaymentMethod            string                 `gorm:"type:varchar(100)" json:"payment_method,omitempty"`
-	TransactionReference     string                 `gorm:"type:varchar(255)" json:"transaction_reference,omitempty"`
-	ProofImageURL            string                 `gorm:"type:text;not null" json:"proof_image_url"`
-	SubmittedAt              time.Time              `gorm:"not null" json:"submitted_at"`
-	Status                   PaymentRecordStatus    `gorm:"type:varchar(50);not null" json:"status"`
-	ReviewedByUserID         *uint                  `json:"reviewed_by_user_id,omitempty"`
-	ReviewedAt               *time.Time             `json:"reviewed_at,omitempty"`
+	SubscriptionMembershipID uint                     `gorm:"not null" json:"subscription_membership_id"`
+	SubscriptionMembership   SubscriptionMembership   `gorm:"foreignKey:SubscriptionMembershipID" json:"-"`
+	PaymentCycleIdentifier   string                   `gorm:"type:varchar(100);not null" json:"payment_cycle_identifier"`
+	CycleCount               int                      `gorm:"not null;default:1" json:"cycle_count"` // Number of cycles the payment covers
+	AmountExpected           float64                  `gorm:"not null" json:"amount_expected"`
+	AmountPaid               float64                  `gorm:"not null" json:"amount_paid"`
+	PaymentMethod            string                   `gorm:"type:varchar(100)" json:"payment_method,omitempty"`
+	TransactionReference     string                   `gorm:"type:varchar(255)" json:"transaction_reference,omitempty"`
+	ProofImageURL            string                   `gorm:"type:text;not null" json:"proof_image_url"`
+	SubmittedAt              time.Time                `gorm:"not null" json:"submitted_at"`
+	Status                   PaymentRecordStatus      `gorm:"type:varchar(50);not null" json:"status"`
+	ReviewedByUserID         *uint                    `json:"reviewed_by_user_id,omitempty"`
+	ReviewedAt               *time.Time               `json:"reviewed_at,omitempty"`
+	DeclineReason            string                   `gorm:"type:text" json:"decline_reason,omitempty"`
+	DisputedAt               *time.Time               `json:"disputed_at,omitempty"`
+	SupersedesRecordID       *uint                    `gorm:"index" json:"supersedes_record_id,omitempty"`
+	SupersededByRecordID     *uint                    `json:"superseded_by_record_id,omitempty"`
+	SlipCheckStatus          SlipCheckStatus          `gorm:"type:varchar(20)" json:"slip_check_status,omitempty"`
+	SlipSendingBankCode      string                   `gorm:"type:varchar(10)" json:"slip_sending_bank_code,omitempty"`
+	SlipTransactionRef       string                   `gorm:"type:varchar(50);index" json:"slip_transaction_ref,omitempty"`
+	SlipDuplicateOfRecordID  *uint                    `json:"slip_duplicate_of_record_id,omitempty"` // Earliest other record backed by the same slip
+	ProofImageHash           *int64                   `json:"-"`                                     // Perceptual hash of the proof image, stored as its bit pattern
+	ProofImageHashBand0      *int16                   `gorm:"index" json:"-"`                        // The hash split into phash.Bands, indexed to find similar proofs
+	ProofImageHashBand1      *int16                   `gorm:"index" json:"-"`
+	ProofImageHashBand2      *int16                   `gorm:"index" json:"-"`
+	ProofImageHashBand3      *int16                   `gorm:"index" json:"-"`
+	ProofImageHashBand4      *int16                   `gorm:"index" json:"-"`
+	SimilarProofRecordID     *uint                    `json:"similar_proof_record_id,omitempty"` // Earliest other record whose proof image looks the same
+	AutoApprovalRuleID       *uint                    `json:"auto_approval_rule_id,omitempty"`   // The host's rule that approved the record on submission
+	Allocations              []PaymentCycleAllocation `gorm:"foreignKey:PaymentRecordID" json:"allocations,omitempty"`
 }
 
 // CreatePaymentRecordRequest defines the request body for a member submitting payment proof.
 // @name CreatePaymentRecordRequest
 type CreatePaymentRecordRequest struct {
 	PaymentCycleIdentifier string  `json:"payment_cycle_identifier" validate:"required,min=3,max=100"`
+	CycleCount             int     `json:"cycle_count,omitempty" validate:"omitempty,min=1,max=12"` // Cycles prepaid; defaults to 1
 	AmountPaid             float64 `json:"amount_paid" validate:"required,gt=0"`
 	ProofImageURL          string  `json:"proof_image_url" validate:"required,url"`
 	PaymentMethod          string  `json:"payment_method,omitempty" validate:"max=100"`
 	TransactionReference   string  `json:"transaction_reference,omitempty" validate:"max=255"`
 }
 
+// DeclinePaymentProofRequest defines the request body for a host declining a payment proof.
+// @name DeclinePaymentProofRequest
+type DeclinePaymentProofRequest struct {
+	Reason string `json:"reason,omitempty" validate:"max=1000"`
+}
+
+// ApprovePaymentProofRequest defines the optional request body for a host approving a payment proof.
+// @name ApprovePaymentProofRequest
+type ApprovePaymentProofRequest struct {
+	AcknowledgeSlipWarnings bool `json:"acknowledge_slip_warnings,omitempty"` // Approve even though the slip reference mismatches or is a duplicate
+}
+
+// DisputePaymentRecordRequest defines the request body for a member disputing a declined payment.
+// @name DisputePaymentRecordRequest
+type DisputePaymentRecordRequest struct {
+	Comment      string   `json:"comment" validate:"required,min=3,max=2000"`
+	EvidenceURLs []string `json:"evidence_urls,omitempty" validate:"max=5,dive,url"`
+}
+
+// ResubmitPaymentProofRequest defines the request body for a corrected proof replacing a declined one.
+// The payment cycle is taken from the declined record.
+// @name ResubmitPaymentProofRequest
+type ResubmitPaymentProofRequest struct {
+	AmountPaid           float64 `json:"amount_paid" validate:"required,gt=0"`
+	ProofImageURL        string  `json:"proof_image_url" validate:"required,url"`
+	PaymentMethod        string  `json:"payment_method,omitempty" validate:"max=100"`
+	TransactionReference string  `json:"transaction_reference,omitempty" validate:"max=255"`
+}
+
 // PaymentRecordResponse is a DTO for returning payment record details enriched with related info.
 // @name PaymentRecordResponse
 type PaymentRecordResponse struct {
-	ID                       uint                `json:"id"`
-	CreatedAt                time.Time           `json:"createdAt"`
-	UpdatedAt                time.Time           `json:"updatedAt"`
-	SubscriptionMembershipID uint                `json:"subscription_membership_id"`
-	PaymentCycleIdentifier   string              `json:"payment_cycle_identifier"`
-	AmountExpected           float64             `json:"amount_expected"`
-	AmountPaid               float64             `json:"amount_paid"`
-	PaymentMethod            string              `json:"payment_method,omitempty"`
-	TransactionReference     string              `json:"transaction_reference,omitempty"`
-	ProofImageURL            string              `json:"proof_image_url"`
-	SubmittedAt              time.Time           `json:"submitted_at"`
-	Status                   PaymentRecordStatus `json:"status"`
-	ReviewedByUserID         *uint               `json:"reviewed_by_user_id,omitempty"`
-	ReviewedAt               *time.Time          `json:"reviewed_at,omitempty"`
-	MemberName               string              `json:"member_name"`
-	MemberProfilePictureURL  *string             `json:"member_profile_picture_url,omitempty"`
-	SubscriptionTitle        string              `json:"subscription_title"`
+	ID                       uint                     `json:"id"`
+	CreatedAt                time.Time                `json:"createdAt"`
+	UpdatedAt                time.Time                `json:"updatedAt"`
+	SubscriptionMembershipID uint                     `json:"subscription_membership_id"`
+	PaymentCycleIdentifier   string                   `json:"payment_cycle_identifier"`
+	CycleCount               int                      `json:"cycle_count"`
+	AmountExpected           float64                  `json:"amount_expected"`
+	AmountPaid               float64                  `json:"amount_paid"`
+	PaymentMethod            string                   `json:"payment_method,omitempty"`
+	TransactionReference     string                   `json:"transaction_reference,omitempty"`
+	ProofImageURL            string                   `json:"proof_image_url"`
+	SubmittedAt              time.Time                `json:"submitted_at"`
+	Status                   PaymentRecordStatus      `json:"status"`
+	ReviewedByUserID         *uint                    `json:"reviewed_by_user_id,omitempty"`
+	ReviewedAt               *time.Time               `json:"reviewed_at,omitempty"`
+	DeclineReason            string                   `json:"decline_reason,omitempty"`
+	DisputedAt               *time.Time               `json:"disputed_at,omitempty"`
+	SupersedesRecordID       *uint                    `json:"supersedes_record_id,omitempty"`
+	SupersededByRecordID     *uint                    `json:"superseded_by_record_id,omitempty"`
+	SlipCheckStatus          SlipCheckStatus          `json:"slip_check_status,omitempty"`
+	SlipSendingBankCode      string                   `json:"slip_sending_bank_code,omitempty"`
+	SlipTransactionRef       string                   `json:"slip_transaction_ref,omitempty"`
+	SlipDuplicateOfRecordID  *uint                    `json:"slip_duplicate_of_record_id,omitempty"`
+	ProofImageReused         bool                     `json:"proof_image_reused"` // The proof image closely matches an earlier proof
+	SimilarProofRecordID     *uint                    `json:"similar_proof_record_id,omitempty"`
+	AutoApprovalRuleID       *uint                    `json:"auto_approval_rule_id,omitempty"`
+	Allocations              []PaymentCycleAllocation `json:"allocations"`
+	MemberName               string                   `json:"member_name"`
+	MemberProfilePictureURL  *string                  `json:"member_profile_picture_url,omitempty"`
+	SubscriptionTitle        string                   `json:"subscription_title"`
 }
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// PaymentRecordMessageKind defines what a message in a payment record's thread represents.
+type PaymentRecordMessageKind string
+
+const (
+	PaymentRecordMessageComment PaymentRecordMessageKind = "Comment"
+	PaymentRecordMessageDecline PaymentRecordMessageKind = "Decline" // The host's reason for declining
+	PaymentRecordMessageDispute PaymentRecordMessageKind = "Dispute" // The member's dispute of a decline
+)
+
+// PaymentRecordMessage is one message in the conversation between host and member about a payment record.
+type PaymentRecordMessage struct {
+	ID              uint                     `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time                `json:"createdAt"`
+	PaymentRecordID uint                     `gorm:"not null;index" json:"payment_record_id"`
+	AuthorUserID    uint                     `gorm:"not null" json:"author_user_id"`
+	Author          User                     `gorm:"foreignKey:AuthorUserID" json:"-"`
+	Kind            PaymentRecordMessageKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Body            string                   `gorm:"type:text;not null" json:"body"`
+	AttachmentURLs  StringList               `gorm:"type:text;not null" json:"attachment_urls"`
+}
+
+// CreatePaymentRecordMessageRequest defines the request body for posting to a payment record's thread.
+// @name CreatePaymentRecordMessageRequest
+type CreatePaymentRecordMessageRequest struct {
+	Body           string   `json:"body" validate:"required,min=1,max=2000"`
+	AttachmentURLs []string `json:"attachment_urls,omitempty" validate:"max=5,dive,url"`
+}
+
+// PaymentRecordMessageResponse is the DTO for a message in a payment record's thread.
+// @name PaymentRecordMessageResponse
+type PaymentRecordMessageResponse struct {
+	ID                      uint                     `json:"id"`
+	CreatedAt               time.Time                `json:"createdAt"`
+	PaymentRecordID         uint                     `json:"payment_record_id"`
+	AuthorUserID            uint                     `json:"author_user_id"`
+	AuthorName              string                   `json:"author_name"`
+	AuthorProfilePictureURL *string                  `json:"author_profile_picture_url,omitempty"`
+	AuthorIsHost            bool                     `json:"author_is_host"`
+	Kind                    PaymentRecordMessageKind `json:"kind"`
+	Body                    string                   `json:"body"`
+	AttachmentURLs          []string                 `json:"attachment_urls"`
+}
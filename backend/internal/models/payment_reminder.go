@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+// DefaultReminderOffsetDays is the reminder schedule used by hosted subscriptions that have not configured one:
+// three days before the due date, on the due date, and two days overdue.
+var DefaultReminderOffsetDays = []int{-3, 0, 2}
+
+// PaymentReminderRule is one entry of a hosted subscription's reminder schedule.
+// OffsetDays is relative to the member's NextPaymentDate: negative is before, zero is on the day, positive is overdue.
+type PaymentReminderRule struct {
+	ID                   uint               `gorm:"primarykey" json:"id"`
+	CreatedAt            time.Time          `json:"createdAt"`
+	HostedSubscriptionID uint               `gorm:"not null;uniqueIndex:idx_reminder_rule_subscription_offset" json:"hosted_subscription_id"`
+	HostedSubscription   HostedSubscription `gorm:"foreignKey:HostedSubscriptionID" json:"-"`
+	OffsetDays           int                `gorm:"not null;uniqueIndex:idx_reminder_rule_subscription_offset" json:"offset_days"`
+}
+
+// PaymentReminderDispatch records that a reminder was sent, so each rule fires at most once per payment cycle.
+type PaymentReminderDispatch struct {
+	ID                       uint                   `gorm:"primarykey" json:"id"`
+	CreatedAt                time.Time              `json:"createdAt"`
+	SubscriptionMembershipID uint                   `gorm:"not null;uniqueIndex:idx_reminder_dispatch_cycle" json:"subscription_membership_id"`
+	SubscriptionMembership   SubscriptionMembership `gorm:"foreignKey:SubscriptionMembershipID" json:"-"`
+	OffsetDays               int                    `gorm:"not null;uniqueIndex:idx_reminder_dispatch_cycle" json:"offset_days"`
+	DueDate                  time.Time              `gorm:"not null;uniqueIndex:idx_reminder_dispatch_cycle" json:"due_date"`
+}
+
+// UpdateReminderScheduleRequest defines the request body for replacing a hosted subscription's reminder schedule.
+// An empty list turns reminders off.
+// @name UpdateReminderScheduleRequest
+type UpdateReminderScheduleRequest struct {
+	OffsetDays []int `json:"offset_days" validate:"max=10,dive,min=-14,max=14"`
+}
+
+// ReminderScheduleResponse is the DTO for a hosted subscription's reminder schedule.
+// @name ReminderScheduleResponse
+type ReminderScheduleResponse struct {
+	HostedSubscriptionID uint  `json:"hosted_subscription_id"`
+	OffsetDays           []int `json:"offset_days"`
+	IsDefault            bool  `json:"is_default"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// UpdatePromptPayRequest defines the request body for a host setting the PromptPay ID of a subscription.
+// @name UpdatePromptPayRequest
+type UpdatePromptPayRequest struct {
+	PromptPayID string `json:"promptpay_id" validate:"max=20"` // Mobile number or national ID; empty turns PromptPay off
+}
+
+// PromptPaySettingsResponse is the DTO for returning the PromptPay settings of a hosted subscription.
+// @name PromptPaySettingsResponse
+type PromptPaySettingsResponse struct {
+	HostedSubscriptionID uint   `json:"hosted_subscription_id"`
+	PromptPayID          string `json:"promptpay_id"`
+	Enabled              bool   `json:"enabled"`
+}
+
+// PaymentQRResponse is the DTO for returning the PromptPay payload a member scans to pay their due cycle.
+// @name PaymentQRResponse
+type PaymentQRResponse struct {
+	SubscriptionMembershipID uint       `json:"subscription_membership_id"`
+	Amount                   float64    `json:"amount"`
+	DueDate                  *time.Time `json:"due_date,omitempty"`
+	Reference                string     `json:"reference"` // Carried in the QR so the host can tell payments apart
+	Payload                  string     `json:"payload"`
+}
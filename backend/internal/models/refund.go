@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// RefundStatus defines the status of a refund from a host to a member.
+type RefundStatus string
+
+const (
+	RefundStatusSent         RefundStatus = "Sent"         // The host sent the money and uploaded proof
+	RefundStatusAcknowledged RefundStatus = "Acknowledged" // The member confirmed receiving it
+)
+
+// Refund records money a host returned to a member for a payment, e.g. for the unused part of a cycle.
+// @name Refund
+type Refund struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	PaymentRecordID          uint         `gorm:"not null;index" json:"payment_record_id"`
+	SubscriptionMembershipID uint         `gorm:"not null;index" json:"subscription_membership_id"`
+	HostedSubscriptionID     uint         `gorm:"not null;index" json:"hosted_subscription_id"`
+	HostUserID               uint         `gorm:"not null;index" json:"host_user_id"`
+	MemberUserID             uint         `gorm:"not null;index" json:"member_user_id"`
+	Amount                   float64      `gorm:"not null" json:"amount"`
+	SuggestedAmount          float64      `gorm:"not null" json:"suggested_amount"` // What the system suggested when the refund was made
+	Reason                   string       `gorm:"type:text" json:"reason,omitempty"`
+	ProofImageURL            string       `gorm:"type:text;not null" json:"proof_image_url"`
+	TransactionReference     string       `gorm:"type:varchar(255)" json:"transaction_reference,omitempty"`
+	Status                   RefundStatus `gorm:"type:varchar(20);not null" json:"status"`
+	AcknowledgedAt           *time.Time   `json:"acknowledged_at,omitempty"`
+}
+
+// CreateRefundRequest defines the request body for a host recording a refund to a member.
+// @name CreateRefundRequest
+type CreateRefundRequest struct {
+	PaymentRecordID      uint    `json:"payment_record_id" validate:"required,gt=0"`
+	Amount               float64 `json:"amount" validate:"required,gt=0"`
+	Reason               string  `json:"reason,omitempty" validate:"max=1000"`
+	ProofImageURL        string  `json:"proof_image_url" validate:"required,url"`
+	TransactionReference string  `json:"transaction_reference,omitempty" validate:"max=255"`
+}
+
+// RefundSuggestion is the refund the system suggests for a membership: the value of the unused days of the
+// paid period plus any credit balance, less what was already refunded.
+// @name RefundSuggestion
+type RefundSuggestion struct {
+	SubscriptionMembershipID uint       `json:"subscription_membership_id"`
+	PaymentRecordID          *uint      `json:"payment_record_id,omitempty"` // The latest approved payment, to link the refund to
+	EndDate                  time.Time  `json:"end_date"`                    // When the membership ended, or now if it is still active
+	PaidThrough              *time.Time `json:"paid_through,omitempty"`
+	CycleDays                int        `json:"cycle_days"`
+	UnusedDays               int        `json:"unused_days"`
+	UnusedAmount             float64    `json:"unused_amount"`
+	CreditBalance            float64    `json:"credit_balance"`
+	AlreadyRefunded          float64    `json:"already_refunded"`
+	SuggestedAmount          float64    `json:"suggested_amount"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+)
+
+// ScreeningQuestion is a question a host asks everyone who requests to join a HostedSubscription.
+// @name ScreeningQuestion
+type ScreeningQuestion struct {
+	ID                   uint      `gorm:"primarykey" json:"id"`
+	CreatedAt            time.Time `json:"createdAt"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+	HostedSubscriptionID uint      `gorm:"not null;index" json:"hosted_subscription_id"`
+	Position             int       `gorm:"not null" json:"position"` // Order in which the question is asked, from 1
+	Question             string    `gorm:"type:varchar(500);not null" json:"question"`
+	Required             bool      `gorm:"not null" json:"required"`
+}
+
+// JoinRequestAnswer is a requester's answer to a screening question. It keeps the question as it was asked,
+// so the answer still reads right after the host rewords or removes the question.
+// @name JoinRequestAnswer
+type JoinRequestAnswer struct {
+	ID                  uint   `gorm:"primarykey" json:"id"`
+	JoinRequestID       uint   `gorm:"not null;index" json:"-"`
+	ScreeningQuestionID uint   `gorm:"not null" json:"screening_question_id"`
+	Question            string `gorm:"type:varchar(500);not null" json:"question"`
+	Answer              string `gorm:"type:text;not null" json:"answer"`
+}
+
+// ScreeningQuestionInput is one question of UpdateScreeningQuestionsRequest.
+// @name ScreeningQuestionInput
+type ScreeningQuestionInput struct {
+	Question string `json:"question" validate:"required,max=500"`
+	Required *bool  `json:"required,omitempty"` // Defaults to true
+}
+
+// UpdateScreeningQuestionsRequest replaces the screening questions of a hosted subscription, in the order given.
+// @name UpdateScreeningQuestionsRequest
+type UpdateScreeningQuestionsRequest struct {
+	Questions []ScreeningQuestionInput `json:"questions" validate:"max=10,dive"`
+}
+
+// ScreeningAnswerInput is the answer to one screening question in CreateJoinRequestRequest.
+// @name ScreeningAnswerInput
+type ScreeningAnswerInput struct {
+	QuestionID uint   `json:"question_id" validate:"required"`
+	Answer     string `json:"answer" validate:"required,max=2000"`
+}
+
+// CreateJoinRequestRequest is the optional body of a join request: a message to the host and answers to the
+// subscription's screening questions.
+// @name CreateJoinRequestRequest
+type CreateJoinRequestRequest struct {
+	Message string                 `json:"message,omitempty" validate:"omitempty,max=1000"`
+	Answers []ScreeningAnswerInput `json:"answers,omitempty" validate:"omitempty,max=10,dive"`
+}
+
+// RequesterReviewStats sums up how a requester has done as a member across all of Hubster, so a host can
+// judge a join request from a stranger.
+// @name RequesterReviewStats
+type RequesterReviewStats struct {
+	ActiveMemberships      int64   `json:"active_memberships"`
+	EndedMemberships       int64   `json:"ended_memberships"`
+	PaymentsApproved       int64   `json:"payments_approved"`
+	PaymentsOnTime         int64   `json:"payments_on_time"` // Approved payments submitted by the day they were due
+	PaymentsDeclined       int64   `json:"payments_declined"`
+	PaymentsDisputed       int64   `json:"payments_disputed"`
+	PaymentsAwaitingReview int64   `json:"payments_awaiting_review"`
+	TotalPaid              float64 `json:"total_paid"` // Sum of approved payments
+}
+
+// RequesterPaymentRow is one payment of a requester's history, without the proof or the host's details.
+// @name RequesterPaymentRow
+type RequesterPaymentRow struct {
+	ServiceName            string              `json:"service_name"`
+	PaymentCycleIdentifier string              `json:"payment_cycle_identifier"`
+	AmountExpected         float64             `json:"amount_expected"`
+	AmountPaid             float64             `json:"amount_paid"`
+	Status                 PaymentRecordStatus `json:"status"`
+	SubmittedAt            time.Time           `json:"submitted_at"`
+	ReviewedAt             *time.Time          `json:"reviewed_at,omitempty"`
+}
+
+// JoinRequestDetailsResponse is a join request as its host sees it: the request with the requester's message and
+// answers, their review stats and their most recent payments.
+// @name JoinRequestDetailsResponse
+type JoinRequestDetailsResponse struct {
+	JoinRequest
+	ReviewStats    RequesterReviewStats  `json:"review_stats"`
+	PaymentHistory []RequesterPaymentRow `json:"payment_history"`
+}
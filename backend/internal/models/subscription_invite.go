@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+)
+
+// SubscriptionInviteStatus describes whether an invite can still be used.
+type SubscriptionInviteStatus string
+
+const (
+	InviteStatusActive    SubscriptionInviteStatus = "Active"
+	InviteStatusExpired   SubscriptionInviteStatus = "Expired"
+	InviteStatusExhausted SubscriptionInviteStatus = "Exhausted" // All of its uses have been taken
+	InviteStatusRevoked   SubscriptionInviteStatus = "Revoked"
+)
+
+// SubscriptionInvite is a shareable code that lets people see and join a hosted subscription whatever its
+// visibility. With AutoApprove, accepting it makes the user a member straight away; otherwise it files a
+// join request for the host to approve.
+type SubscriptionInvite struct {
+	ID                   uint               `gorm:"primarykey" json:"id"`
+	CreatedAt            time.Time          `json:"createdAt"`
+	UpdatedAt            time.Time          `json:"updatedAt"`
+	HostedSubscriptionID uint               `gorm:"not null;index" json:"hosted_subscription_id"`
+	HostedSubscription   HostedSubscription `gorm:"foreignKey:HostedSubscriptionID" json:"-"`
+	CreatedByUserID      uint               `gorm:"not null" json:"created_by_user_id"`
+	Code                 string             `gorm:"type:varchar(32);not null;uniqueIndex" json:"code"`
+	AutoApprove          bool               `gorm:"not null" json:"auto_approve"`
+	ExpiresAt            *time.Time         `json:"expires_at,omitempty"`
+	MaxUses              *int               `json:"max_uses,omitempty"` // Nil for unlimited
+	UseCount             int                `gorm:"not null;default:0" json:"use_count"`
+	RevokedAt            *time.Time         `json:"revoked_at,omitempty"`
+}
+
+// Status reports whether the invite can still be used at now.
+func (i *SubscriptionInvite) Status(now time.Time) SubscriptionInviteStatus {
+	switch {
+	case i.RevokedAt != nil:
+		return InviteStatusRevoked
+	case i.ExpiresAt != nil && !now.Before(*i.ExpiresAt):
+		return InviteStatusExpired
+	case i.MaxUses != nil && i.UseCount >= *i.MaxUses:
+		return InviteStatusExhausted
+	default:
+		return InviteStatusActive
+	}
+}
+
+// CreateSubscriptionInviteRequest defines the request body for a host creating an invite.
+// @name CreateSubscriptionInviteRequest
+type CreateSubscriptionInviteRequest struct {
+	AutoApprove *bool      `json:"auto_approve,omitempty"` // Defaults to true
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	MaxUses     *int       `json:"max_uses,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+// SubscriptionInviteResponse is the DTO for returning an invite to its host.
+// @name SubscriptionInviteResponse
+type SubscriptionInviteResponse struct {
+	ID                   uint                     `json:"id"`
+	HostedSubscriptionID uint                     `json:"hosted_subscription_id"`
+	Code                 string                   `json:"code"`
+	URL                  string                   `json:"url"`
+	AutoApprove          bool                     `json:"auto_approve"`
+	ExpiresAt            *time.Time               `json:"expires_at,omitempty"`
+	MaxUses              *int                     `json:"max_uses,omitempty"`
+	UseCount             int                      `json:"use_count"`
+	Status               SubscriptionInviteStatus `json:"status"`
+	RevokedAt            *time.Time               `json:"revoked_at,omitempty"`
+	CreatedAt            time.Time                `json:"createdAt"`
+}
+
+// InvitePreviewResponse shows the person an invite was shared with what they are invited to.
+// @name InvitePreviewResponse
+type InvitePreviewResponse struct {
+	Code         string                     `json:"code"`
+	AutoApprove  bool                       `json:"auto_approve"`
+	ExpiresAt    *time.Time                 `json:"expires_at,omitempty"`
+	Subscription HostedSubscriptionResponse `json:"subscription"`
+}
+
+// AcceptInviteResponse is the outcome of accepting an invite: a membership when the invite approves
+// automatically, otherwise a pending join request. The join request is set in both cases.
+// @name AcceptInviteResponse
+type AcceptInviteResponse struct {
+	JoinRequest *JoinRequest            `json:"join_request"`
+	Membership  *SubscriptionMembership `json:"membership,omitempty"`
+}
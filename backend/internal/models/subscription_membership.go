@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // PaymentStatusType defines the payment status for a membership slot.
@@ -21,13 +23,16 @@ type SubscriptionMembership struct {
 	ID                   uint               `gorm:"primarykey" json:"id"`
 	CreatedAt            time.Time          `json:"createdAt"`
 	UpdatedAt            time.Time          `json:"updatedAt"`
-	MemberUserID         uint               `gorm:"not null;uniqueIndex:idx_member_subscription" json:"member_user_id"`
+	DeletedAt            gorm.DeletedAt     `gorm:"index" json:"-"`
+	MemberUserID         uint               `gorm:"not null;uniqueIndex:idx_member_subscription,where:deleted_at IS NULL" json:"member_user_id"`
 	User                 User               `gorm:"foreignKey:MemberUserID" json:"member_user"`
 	HostedSubscriptionID uint               `gorm:"not null;uniqueIndex:idx_member_subscription" json:"hosted_subscription_id"`
 	HostedSubscription   HostedSubscription `gorm:"foreignKey:HostedSubscriptionID" json:"-"`
 	JoinedDate           time.Time          `gorm:"not null" json:"joined_date"`
 	PaymentStatus        PaymentStatusType  `gorm:"type:varchar(50);default:'PaymentDue'" json:"payment_status"`
 	NextPaymentDate      *time.Time         `json:"next_payment_date,omitempty"`
+	Balance              float64            `gorm:"not null;default:0" json:"balance"` // Credit when positive, outstanding when negative
+	BilledThrough        *time.Time         `json:"billed_through,omitempty"`          // Due date of the latest cycle charged to the ledger
 	PaymentRecords       []PaymentRecord    `gorm:"foreignKey:SubscriptionMembershipID" json:"-"`
 }
 
@@ -43,6 +48,9 @@ type SubscriptionMembershipResponse struct {
 	JoinedDate              time.Time         `json:"joined_date"`
 	PaymentStatus           PaymentStatusType `json:"payment_status"`
 	NextPaymentDate         *time.Time        `json:"next_payment_date,omitempty"`
+	Balance                 float64           `json:"balance"`
+	OutstandingAmount       float64           `json:"outstanding_amount"`
+	CreditBalance           float64           `json:"credit_balance"`
 
 	// Details from the HostedSubscription
 	HostedSubscriptionTitle string  `json:"hosted_subscription_title"`
@@ -51,4 +59,21 @@ type SubscriptionMembershipResponse struct {
 	HostName                string  `json:"host_name"`
 	CostPerSlot             float64 `json:"cost_per_slot"`
 	PaymentQRCodeURL        string  `json:"payment_qr_code_url,omitempty"`
+	PromptPayEnabled        bool    `json:"promptpay_enabled"`
+}
+
+// OutstandingAmount is what the member still owes on cycles already charged.
+func (m *SubscriptionMembership) OutstandingAmount() float64 {
+	if m.Balance < 0 {
+		return -m.Balance
+	}
+	return 0
+}
+
+// CreditBalance is the amount the member has paid ahead, to be applied to their next cycle.
+func (m *SubscriptionMembership) CreditBalance() float64 {
+	if m.Balance > 0 {
+		return m.Balance
+	}
+	return 0
 }
@@ -15,6 +15,7 @@ type User struct {
 	FullName          string    `gorm:"type:varchar(255);not null" json:"full_name"`
 	ProfilePictureURL *string   `gorm:"type:text" json:"profile_picture_url,omitempty"`
 	PhoneNumber       *string   `gorm:"type:varchar(30)" json:"phone_number,omitempty"`
+	IsAdmin           bool      `gorm:"not null;default:false" json:"is_admin"` // Granted directly in the database
 }
 
 // UpdateUserRequest defines the structure for updating user profile
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+// WaitlistEntryStatus defines the state of a user's place on a waitlist.
+type WaitlistEntryStatus string
+
+const (
+	WaitlistStatusWaiting WaitlistEntryStatus = "Waiting" // Queued for a slot
+	WaitlistStatusOffered WaitlistEntryStatus = "Offered" // A free slot is held for the user until the offer expires
+	WaitlistStatusClaimed WaitlistEntryStatus = "Claimed" // The user took the slot and sent a join request
+	WaitlistStatusExpired WaitlistEntryStatus = "Expired" // The offer ran out before the user claimed it
+	WaitlistStatusLeft    WaitlistEntryStatus = "Left"    // The user left the waitlist
+)
+
+// WaitlistEntry is a user's place in the queue for a full hosted subscription. Freed slots are offered to
+// waiting users in the order they joined.
+// @name WaitlistEntry
+type WaitlistEntry struct {
+	ID                   uint                `gorm:"primarykey" json:"id"`
+	CreatedAt            time.Time           `json:"createdAt"`
+	UpdatedAt            time.Time           `json:"updatedAt"`
+	HostedSubscriptionID uint                `gorm:"not null;index:idx_waitlist_queue" json:"hosted_subscription_id"`
+	HostedSubscription   HostedSubscription  `gorm:"foreignKey:HostedSubscriptionID" json:"-"`
+	UserID               uint                `gorm:"not null;index" json:"user_id"`
+	User                 User                `gorm:"foreignKey:UserID" json:"-"`
+	Status               WaitlistEntryStatus `gorm:"type:varchar(20);not null;index:idx_waitlist_queue" json:"status"`
+	OfferedAt            *time.Time          `json:"offered_at,omitempty"`
+	OfferExpiresAt       *time.Time          `gorm:"index" json:"offer_expires_at,omitempty"`
+	JoinRequestID        *uint               `json:"join_request_id,omitempty"` // The join request sent when the slot was claimed
+}
+
+// WaitlistEntryResponse is the DTO for returning a waitlist entry with its place in the queue.
+// @name WaitlistEntryResponse
+type WaitlistEntryResponse struct {
+	ID                   uint                `json:"id"`
+	HostedSubscriptionID uint                `json:"hosted_subscription_id"`
+	SubscriptionTitle    string              `json:"subscription_title"`
+	User                 *UserResponse       `json:"user,omitempty"` // Set in the host's view of the waitlist
+	Status               WaitlistEntryStatus `json:"status"`
+	Position             int                 `json:"position,omitempty"` // 1 for the next in line; only set while waiting
+	OfferedAt            *time.Time          `json:"offered_at,omitempty"`
+	OfferExpiresAt       *time.Time          `json:"offer_expires_at,omitempty"`
+	JoinRequestID        *uint               `json:"join_request_id,omitempty"`
+	CreatedAt            time.Time           `json:"createdAt"`
+}
+
+// UpdateTotalSlotsRequest defines the request body for a host changing the number of slots of a subscription.
+// @name UpdateTotalSlotsRequest
+type UpdateTotalSlotsRequest struct {
+	TotalSlots int `json:"total_slots" validate:"required,min=1,max=20"`
+}
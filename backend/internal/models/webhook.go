@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+)
+
+// WebhookDeliveryStatus defines the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "Pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "Succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "Failed"
+)
+
+// WebhookEndpoint is a URL a user registered to receive domain events they are involved in.
+type WebhookEndpoint struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	User        User      `gorm:"foreignKey:UserID" json:"-"`
+	URL         string    `gorm:"type:text;not null" json:"url"`
+	Description string    `gorm:"type:varchar(255)" json:"description,omitempty"`
+	Secret      string    `gorm:"type:varchar(100);not null" json:"-"`
+	EventTypes  string    `gorm:"type:text;not null" json:"-"` // Comma-separated event types
+	IsActive    bool      `gorm:"not null" json:"is_active"`
+}
+
+// WebhookDelivery is one event queued for one endpoint, retried until it succeeds or runs out of attempts.
+type WebhookDelivery struct {
+	ID                uint                     `gorm:"primarykey" json:"id"`
+	CreatedAt         time.Time                `json:"createdAt"`
+	UpdatedAt         time.Time                `json:"updatedAt"`
+	WebhookEndpointID uint                     `gorm:"not null;uniqueIndex:idx_webhook_delivery_endpoint_event" json:"webhook_endpoint_id"`
+	WebhookEndpoint   WebhookEndpoint          `gorm:"foreignKey:WebhookEndpointID" json:"-"`
+	EventID           string                   `gorm:"type:varchar(36);not null;uniqueIndex:idx_webhook_delivery_endpoint_event" json:"event_id"`
+	EventType         string                   `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload           string                   `gorm:"type:text;not null" json:"payload"`
+	Status            WebhookDeliveryStatus    `gorm:"type:varchar(20);not null;index:idx_webhook_delivery_due" json:"status"`
+	Attempts          int                      `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt     time.Time                `gorm:"not null;index:idx_webhook_delivery_due" json:"next_attempt_at"`
+	LastAttemptAt     *time.Time               `json:"last_attempt_at,omitempty"`
+	LastStatusCode    int                      `json:"last_status_code,omitempty"`
+	LastError         string                   `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveryAttempts  []WebhookDeliveryAttempt `gorm:"foreignKey:WebhookDeliveryID" json:"delivery_attempts,omitempty"`
+}
+
+// WebhookDeliveryAttempt records the outcome of one HTTP request made for a delivery.
+type WebhookDeliveryAttempt struct {
+	ID                uint      `gorm:"primarykey" json:"id"`
+	CreatedAt         time.Time `json:"createdAt"`
+	WebhookDeliveryID uint      `gorm:"not null;index" json:"webhook_delivery_id"`
+	AttemptNumber     int       `gorm:"not null" json:"attempt_number"`
+	StatusCode        int       `json:"status_code,omitempty"`
+	ResponseBody      string    `gorm:"type:text" json:"response_body,omitempty"`
+	Error             string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs        int64     `json:"duration_ms"`
+}
+
+// CreateWebhookEndpointRequest defines the request body for registering a webhook endpoint.
+// @name CreateWebhookEndpointRequest
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	Description string   `json:"description,omitempty" validate:"max=255"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,dive,required"` // Any of events.WebhookTypes
+}
+
+// UpdateWebhookEndpointRequest defines the request body for changing a webhook endpoint. Omitted fields are unchanged.
+// @name UpdateWebhookEndpointRequest
+type UpdateWebhookEndpointRequest struct {
+	URL         *string  `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+	EventTypes  []string `json:"event_types,omitempty" validate:"omitempty,min=1,dive,required"` // Any of events.WebhookTypes
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+// WebhookEndpointResponse is the DTO for a webhook endpoint. The secret is only included when the endpoint is created.
+// @name WebhookEndpointResponse
+type WebhookEndpointResponse struct {
+	ID          uint      `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	EventTypes  []string  `json:"event_types"`
+	IsActive    bool      `json:"is_active"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
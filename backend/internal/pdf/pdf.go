@@ -0,0 +1,292 @@
+// Package pdf writes simple text documents as PDF 1.4, page by page, so long reports can be streamed to the
+// client as they are produced. It uses the standard Helvetica fonts every viewer ships with, which cover the
+// Windows-1252 character set; other characters, such as Thai, are printed as '?'.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font is one of the standard fonts a document can print with.
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+// ErrClosed is returned when writing to a document that has been closed.
+var ErrClosed = errors.New("pdf: document closed")
+
+// Fixed object numbers; pages are numbered from firstPageObject as they are written.
+const (
+	catalogObject   = 1
+	pagesObject     = 2
+	regularFont     = 3
+	boldFont        = 4
+	firstPageObject = 5
+)
+
+// Document writes a PDF to an underlying writer. Only the page being drawn is held in memory.
+// Drawing methods record the first error, which Close returns.
+type Document struct {
+	w       *bufio.Writer
+	written int64
+	offsets map[int]int64
+	nextObj int
+	pages   []int
+	title   string
+	page    *bytes.Buffer
+	err     error
+	closed  bool
+}
+
+// New starts a document titled title on w.
+func New(w io.Writer, title string) *Document {
+	d := &Document{
+		w:       bufio.NewWriter(w),
+		offsets: make(map[int]int64),
+		nextObj: firstPageObject,
+		title:   title,
+	}
+	d.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	d.object(catalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObject))
+	d.object(regularFont, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	d.object(boldFont, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	return d
+}
+
+// NewPage finishes the current page, if any, and starts a blank one. Coordinates are in points from the
+// bottom-left corner.
+func (d *Document) NewPage() {
+	if d.closed {
+		d.fail(ErrClosed)
+		return
+	}
+	d.flushPage()
+	d.page = new(bytes.Buffer)
+}
+
+// Text prints s with its baseline starting at (x, y).
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	if d.ensurePage() {
+		fmt.Fprintf(d.page, "BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font+1, size, x, y, escape(encode(s)))
+	}
+}
+
+// Line draws a straight line of the given width.
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	if d.ensurePage() {
+		fmt.Fprintf(d.page, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+	}
+}
+
+// Close finishes the last page and writes the page tree and cross-reference table. It does not close the
+// underlying writer.
+func (d *Document) Close() error {
+	if d.closed {
+		return d.err
+	}
+	if d.page == nil {
+		d.NewPage()
+	}
+	d.flushPage()
+	d.closed = true
+
+	kids := make([]string, len(d.pages))
+	for i, page := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	d.object(pagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.2f %.2f] >>",
+		strings.Join(kids, " "), len(d.pages), PageWidth, PageHeight))
+	info := d.reserve()
+	d.object(info, fmt.Sprintf("<< /Title (%s) /Producer (Hubster) >>", escape(encode(d.title))))
+
+	xref := d.written
+	d.printf("xref\n0 %d\n0000000000 65535 f \n", d.nextObj)
+	for n := 1; n < d.nextObj; n++ {
+		d.printf("%010d 00000 n \n", d.offsets[n])
+	}
+	d.printf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", d.nextObj, catalogObject, info, xref)
+	if d.err == nil {
+		d.fail(d.w.Flush())
+	}
+	return d.err
+}
+
+// TextWidth returns the width in points of s printed in font at size.
+func TextWidth(s string, font Font, size float64) float64 {
+	widths := &helveticaWidths
+	if font == Bold {
+		widths = &helveticaBoldWidths
+	}
+	var units int
+	for _, r := range s {
+		if r >= ' ' && r <= '~' {
+			units += widths[r-' ']
+		} else {
+			units += defaultWidth
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// Truncate shortens s with an ellipsis so it fits in maxWidth points.
+func Truncate(s string, font Font, size float64, maxWidth float64) string {
+	if TextWidth(s, font, size) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if candidate := string(runes) + "..."; TextWidth(candidate, font, size) <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func (d *Document) ensurePage() bool {
+	if d.closed {
+		d.fail(ErrClosed)
+		return false
+	}
+	if d.page == nil {
+		d.NewPage()
+	}
+	return d.err == nil
+}
+
+// flushPage writes the current page's compressed content stream and page object.
+func (d *Document) flushPage() {
+	if d.page == nil || d.err != nil {
+		return
+	}
+	var content bytes.Buffer
+	zw := zlib.NewWriter(&content)
+	if _, err := zw.Write(d.page.Bytes()); err != nil {
+		d.fail(err)
+		return
+	}
+	if err := zw.Close(); err != nil {
+		d.fail(err)
+		return
+	}
+
+	stream := d.reserve()
+	d.offsets[stream] = d.written
+	d.printf("%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", stream, content.Len())
+	d.write(content.Bytes())
+	d.printf("\nendstream\nendobj\n")
+
+	page := d.reserve()
+	d.object(page, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		pagesObject, regularFont, boldFont, stream))
+	d.pages = append(d.pages, page)
+	d.page = nil
+}
+
+func (d *Document) reserve() int {
+	n := d.nextObj
+	d.nextObj++
+	return n
+}
+
+func (d *Document) object(n int, body string) {
+	d.offsets[n] = d.written
+	d.printf("%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+func (d *Document) printf(format string, args ...any) {
+	d.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (d *Document) write(p []byte) {
+	if d.err != nil {
+		return
+	}
+	n, err := d.w.Write(p)
+	d.written += int64(n)
+	d.fail(err)
+}
+
+func (d *Document) fail(err error) {
+	if d.err == nil && err != nil {
+		d.err = err
+	}
+}
+
+// encode converts s to Windows-1252, replacing characters outside it with '?'.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		default:
+			if b, ok := windows1252[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// escape quotes b for use inside a PDF literal string.
+func escape(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for _, c := range b {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			out = append(out, '\\', c)
+		case c < ' ':
+			out = append(out, ' ')
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// windows1252 maps the characters Windows-1252 places in 0x80-0x9f.
+var windows1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88, '‰': 0x89,
+	'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95,
+	'–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// defaultWidth is used for characters outside printable ASCII, in thousandths of the font size.
+const defaultWidth = 556
+
+// Advance widths of printable ASCII from the Helvetica and Helvetica-Bold font metrics, starting at ' '.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,123 @@
+// Package phash computes perceptual hashes of images: 64-bit fingerprints that stay close when an image is
+// re-encoded, resized or slightly cropped, so re-uploads of the same picture can be found by Hamming distance.
+package phash
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// sampleSize is the side of the grayscale thumbnail the DCT runs on.
+	sampleSize = 32
+	// hashSize is the side of the block of lowest DCT frequencies kept in the hash.
+	hashSize = 8
+	// bandBits is the width of the bands Bands splits a hash into; the last band holds the 12 bits left over.
+	bandBits = 13
+)
+
+// BandCount is how many bands Bands splits a hash into. Hashes that differ in fewer bits than there are bands
+// agree on at least one whole band, so looking up each band exactly finds every hash within BandCount-1 bits.
+const BandCount = 5
+
+// dctCos[u][x] is cos((2x+1)uπ / 2N), shared by the row and column passes.
+var dctCos = func() (table [sampleSize][sampleSize]float64) {
+	for u := range table {
+		for x := range table[u] {
+			table[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * sampleSize))
+		}
+	}
+	return table
+}()
+
+// Compute returns the DCT perceptual hash of img: the image is shrunk to a 32x32 grayscale thumbnail, and each
+// bit tells whether one of the 8x8 lowest-frequency DCT coefficients is above their median.
+func Compute(img image.Image) uint64 {
+	thumb := thumbnail(img)
+
+	var rows [sampleSize][sampleSize]float64
+	for y := 0; y < sampleSize; y++ {
+		for u := 0; u < hashSize; u++ {
+			var sum float64
+			for x := 0; x < sampleSize; x++ {
+				sum += thumb[y][x] * dctCos[u][x]
+			}
+			rows[y][u] = sum
+		}
+	}
+	var coefficients [hashSize * hashSize]float64
+	for v := 0; v < hashSize; v++ {
+		for u := 0; u < hashSize; u++ {
+			var sum float64
+			for y := 0; y < sampleSize; y++ {
+				sum += rows[y][u] * dctCos[v][y]
+			}
+			coefficients[v*hashSize+u] = sum
+		}
+	}
+
+	// The DC coefficient is the average brightness; leaving it out of the median keeps the split balanced.
+	sorted := append([]float64(nil), coefficients[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, c := range coefficients {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// Distance returns the number of differing bits between two hashes; 0 is the same picture.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Bands splits a hash into BandCount runs of consecutive bits, lowest bits first.
+func Bands(hash uint64) [BandCount]uint16 {
+	var bands [BandCount]uint16
+	for i := range bands {
+		bands[i] = uint16(hash >> (i * bandBits) & (1<<bandBits - 1))
+	}
+	return bands
+}
+
+// thumbnail averages the luminance of img over a sampleSize x sampleSize grid.
+func thumbnail(img image.Image) [sampleSize][sampleSize]float64 {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	var sums [sampleSize][sampleSize]float64
+	var counts [sampleSize][sampleSize]int
+	if width == 0 || height == 0 {
+		return sums
+	}
+
+	luminance := func(x, y int) float64 {
+		return float64(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+	}
+	if ycc, ok := img.(*image.YCbCr); ok {
+		luminance = func(x, y int) float64 { return float64(ycc.Y[ycc.YOffset(x, y)]) }
+	}
+
+	for y := 0; y < height; y++ {
+		cellY := y * sampleSize / height
+		for x := 0; x < width; x++ {
+			cellX := x * sampleSize / width
+			sums[cellY][cellX] += luminance(bounds.Min.X+x, bounds.Min.Y+y)
+			counts[cellY][cellX]++
+		}
+	}
+	for y := range sums {
+		for x := range sums[y] {
+			if counts[y][x] > 0 {
+				sums[y][x] /= float64(counts[y][x])
+			}
+		}
+	}
+	return sums
+}
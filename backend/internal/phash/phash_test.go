@@ -0,0 +1,187 @@
+package phash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"testing"
+)
+
+// Proofs whose hashes are within nearDuplicate bits are treated as the same picture by the payment service;
+// unrelated pictures should be far outside that.
+const (
+	nearDuplicate = 4
+	unrelated     = 16
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, ^uint64(0), 64},
+		{0b1011, 0b0001, 2},
+		{1 << 63, 1, 2},
+		{0xF0F0F0F0F0F0F0F0, 0x0F0F0F0F0F0F0F0F, 64},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%#x, %#x) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBands(t *testing.T) {
+	hash := uint64(0xFEDCBA9876543210)
+	var joined uint64
+	for i, band := range Bands(hash) {
+		joined |= uint64(band) << (i * bandBits)
+	}
+	if joined != hash {
+		t.Errorf("bands of %#x join back into %#x", hash, joined)
+	}
+
+	// Flipping fewer bits than there are bands must leave at least one band untouched.
+	for _, flipped := range []uint64{0, 1 << 63, 1<<0 | 1<<13 | 1<<26 | 1<<39, 0xF << 60, 1<<12 | 1<<25 | 1<<38 | 1<<51} {
+		a, b := Bands(hash), Bands(hash^flipped)
+		shared := false
+		for i := range a {
+			shared = shared || a[i] == b[i]
+		}
+		if !shared {
+			t.Errorf("flipping %#x changed every band", flipped)
+		}
+	}
+	a, b := Bands(0), Bands(1|1<<13|1<<26|1<<39|1<<52)
+	for i := range a {
+		if a[i] == b[i] {
+			t.Errorf("flipping a bit in every band left band %d unchanged", i)
+		}
+	}
+}
+
+func TestComputeNearDuplicates(t *testing.T) {
+	original := scene(640, 480, 0)
+	want := Compute(original)
+
+	tests := []struct {
+		name string
+		img  image.Image
+	}{
+		{"same image", original},
+		{"half size", resize(original, 320, 240)},
+		{"brightened", adjust(original, func(v float64) float64 { return v + 12 })},
+		{"higher contrast", adjust(original, func(v float64) float64 { return (v-128)*1.15 + 128 })},
+		{"jpeg re-encoded", reencodeJPEG(t, original, 70)},
+		{"cropped edge", original.SubImage(image.Rect(3, 3, 637, 477))},
+	}
+	for _, tt := range tests {
+		if d := Distance(want, Compute(tt.img)); d > nearDuplicate {
+			t.Errorf("%s: distance %d, want at most %d", tt.name, d, nearDuplicate)
+		}
+	}
+}
+
+func TestComputeDifferentImages(t *testing.T) {
+	original := scene(640, 480, 0)
+	want := Compute(original)
+
+	tests := []struct {
+		name string
+		img  image.Image
+	}{
+		{"different scene", scene(640, 480, 1)},
+		{"mirrored", mirror(original)},
+		{"inverted", adjust(original, func(v float64) float64 { return 255 - v })},
+	}
+	for _, tt := range tests {
+		if d := Distance(want, Compute(tt.img)); d < unrelated {
+			t.Errorf("%s: distance %d, want at least %d", tt.name, d, unrelated)
+		}
+	}
+}
+
+// scene draws a deterministic picture with a gradient background and a few blocks whose placement depends on seed.
+func scene(width, height, seed int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := 60 + 100*float64(x)/float64(width) + 40*math.Sin(float64(y+seed*90)/40)
+			img.SetGray(x, y, color.Gray{Y: clamp(v)})
+		}
+	}
+	blocks := [][4]float64{{0.1, 0.15, 0.35, 0.4}, {0.55, 0.2, 0.9, 0.35}, {0.3, 0.6, 0.7, 0.85}}
+	for i, b := range blocks {
+		if seed != 0 {
+			b = [4]float64{1 - b[2], b[1] + 0.05*float64(seed), 1 - b[0], b[3] + 0.05*float64(seed)}
+		}
+		shade := uint8(20 + 100*(i%2))
+		if i == 2 {
+			shade = 230
+		}
+		for y := int(b[1] * float64(height)); y < int(b[3]*float64(height)); y++ {
+			for x := int(b[0] * float64(width)); x < int(b[2]*float64(width)); x++ {
+				img.SetGray(x, y, color.Gray{Y: shade})
+			}
+		}
+	}
+	return img
+}
+
+// resize scales img to width x height by averaging the source pixels each destination pixel covers.
+func resize(img *image.Gray, width, height int) *image.Gray {
+	b := img.Bounds()
+	out := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var sum, n int
+			for sy := y * b.Dy() / height; sy < (y+1)*b.Dy()/height; sy++ {
+				for sx := x * b.Dx() / width; sx < (x+1)*b.Dx()/width; sx++ {
+					sum += int(img.GrayAt(sx, sy).Y)
+					n++
+				}
+			}
+			out.SetGray(x, y, color.Gray{Y: uint8(sum / n)})
+		}
+	}
+	return out
+}
+
+func adjust(img *image.Gray, f func(float64) float64) *image.Gray {
+	out := image.NewGray(img.Bounds())
+	for i, v := range img.Pix {
+		out.Pix[i] = clamp(f(float64(v)))
+	}
+	return out
+}
+
+func mirror(img *image.Gray) *image.Gray {
+	b := img.Bounds()
+	out := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.SetGray(b.Max.X-1-x+b.Min.X, y, img.GrayAt(x, y))
+		}
+	}
+	return out
+}
+
+func reencodeJPEG(t *testing.T, img image.Image, quality int) image.Image {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	decoded, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatalf("jpeg.Decode: %v", err)
+	}
+	return decoded
+}
+
+func clamp(v float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(v))))
+}
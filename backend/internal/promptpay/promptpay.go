@@ -0,0 +1,139 @@
+// Package promptpay builds Thai PromptPay payment payloads following the EMVCo merchant-presented QR specification.
+package promptpay
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidID is returned when a PromptPay ID is not a Thai mobile number, national/tax ID or e-wallet ID.
+var ErrInvalidID = errors.New("promptpay ID must be a 10-digit mobile number, a 13-digit national or tax ID, or a 15-digit e-wallet ID")
+
+// ErrInvalidReference is returned when a reference label is too long or contains unsupported characters.
+var ErrInvalidReference = errors.New("promptpay reference must be at most 25 letters, digits or dashes")
+
+const (
+	applicationID      = "A000000677010111"
+	currencyTHB        = "764"
+	countryCode        = "TH"
+	maxReferenceLength = 25
+)
+
+// EMVCo tag IDs used in the payload.
+const (
+	tagPayloadFormat     = "00"
+	tagInitiationMethod  = "01"
+	tagMerchantPromptPay = "29"
+	tagCurrency          = "53"
+	tagAmount            = "54"
+	tagCountry           = "58"
+	tagAdditionalData    = "62"
+	tagCRC               = "63"
+
+	subTagApplicationID = "00"
+	subTagMobile        = "01"
+	subTagNationalID    = "02"
+	subTagEWallet       = "03"
+	subTagReference     = "05"
+)
+
+// NormalizeID strips separators from a PromptPay ID and checks that it is a supported kind.
+// Mobile numbers may be given in local form (0812345678) or with the country code (66812345678).
+func NormalizeID(id string) (string, error) {
+	var b strings.Builder
+	for _, r := range id {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-' || r == ' ' || r == '+':
+		default:
+			return "", ErrInvalidID
+		}
+	}
+	digits := b.String()
+	if len(digits) == 11 && strings.HasPrefix(digits, "66") {
+		digits = "0" + digits[2:]
+	}
+	switch {
+	case len(digits) == 10 && digits[0] == '0':
+	case len(digits) == 13, len(digits) == 15:
+	default:
+		return "", ErrInvalidID
+	}
+	return digits, nil
+}
+
+// Payload builds the PromptPay payload for id. A positive amount makes it a dynamic (single-use) code
+// that pre-fills the amount in the payer's banking app; reference, if set, is carried as the reference label.
+func Payload(id string, amount float64, reference string) (string, error) {
+	normalized, err := NormalizeID(id)
+	if err != nil {
+		return "", err
+	}
+	if err := validateReference(reference); err != nil {
+		return "", err
+	}
+
+	var account string
+	switch len(normalized) {
+	case 10:
+		// Mobile numbers are sent in international form without the leading zero, left-padded to 13 digits.
+		account = field(subTagMobile, fmt.Sprintf("%013s", "66"+normalized[1:]))
+	case 13:
+		account = field(subTagNationalID, normalized)
+	default:
+		account = field(subTagEWallet, normalized)
+	}
+
+	var b strings.Builder
+	b.WriteString(field(tagPayloadFormat, "01"))
+	if amount > 0 {
+		b.WriteString(field(tagInitiationMethod, "12"))
+	} else {
+		b.WriteString(field(tagInitiationMethod, "11"))
+	}
+	b.WriteString(field(tagMerchantPromptPay, field(subTagApplicationID, applicationID)+account))
+	b.WriteString(field(tagCountry, countryCode))
+	b.WriteString(field(tagCurrency, currencyTHB))
+	if amount > 0 {
+		b.WriteString(field(tagAmount, fmt.Sprintf("%.2f", amount)))
+	}
+	if reference != "" {
+		b.WriteString(field(tagAdditionalData, field(subTagReference, reference)))
+	}
+	b.WriteString(tagCRC + "04")
+	return b.String() + fmt.Sprintf("%04X", crc16(b.String())), nil
+}
+
+func field(tag, value string) string {
+	return fmt.Sprintf("%s%02d%s", tag, len(value), value)
+}
+
+func validateReference(reference string) error {
+	if len(reference) > maxReferenceLength {
+		return ErrInvalidReference
+	}
+	for _, r := range reference {
+		if !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r == '-') {
+			return ErrInvalidReference
+		}
+	}
+	return nil
+}
+
+// crc16 computes the CRC-16/CCITT-FALSE checksum (polynomial 0x1021, initial value 0xFFFF) required by EMVCo.
+func crc16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
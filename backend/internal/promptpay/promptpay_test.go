@@ -0,0 +1,84 @@
+package promptpay
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCRC16CheckValue(t *testing.T) {
+	// The standard check value of CRC-16/CCITT-FALSE.
+	if got := crc16("123456789"); got != 0x29B1 {
+		t.Errorf("crc16(%q) = %04X, want 29B1", "123456789", got)
+	}
+}
+
+// The expected payloads are the outputs published by the promptpay-qr reference implementation.
+func TestPayloadKnownVectors(t *testing.T) {
+	tests := []struct {
+		id     string
+		amount float64
+		want   string
+	}{
+		{"0801234567", 0, "00020101021129370016A000000677010111011300668012345675802TH530376463046197"},
+		{"1111111111111", 0, "00020101021129370016A000000677010111021311111111111115802TH530376463047B5A"},
+		{"012345678901234", 0, "00020101021129390016A00000067701011103150123456789012345802TH530376463049781"},
+		{"000-000-0000", 4.22, "00020101021229370016A000000677010111011300660000000005802TH530376454044.226304E469"},
+	}
+	for _, tt := range tests {
+		got, err := Payload(tt.id, tt.amount, "")
+		if err != nil {
+			t.Errorf("Payload(%q, %v): %v", tt.id, tt.amount, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Payload(%q, %v) = %s, want %s", tt.id, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestPayloadChecksumCoversReference(t *testing.T) {
+	got, err := Payload("0801234567", 750, "INV-42")
+	if err != nil {
+		t.Fatalf("Payload: %v", err)
+	}
+	body, checksum := got[:len(got)-4], got[len(got)-4:]
+	if want := "00020101021229370016A000000677010111011300668012345675802TH5303764" +
+		"5406750.00" + "62100506INV-42" + "6304"; body != want {
+		t.Errorf("payload body = %s, want %s", body, want)
+	}
+	if want := fmt.Sprintf("%04X", crc16(body)); checksum != want {
+		t.Errorf("checksum = %s, want %s", checksum, want)
+	}
+}
+
+func TestNormalizeID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+		err  error
+	}{
+		{"081-234-5678", "0812345678", nil},
+		{"+66 81 234 5678", "0812345678", nil},
+		{"1-1111-11111-11-1", "1111111111111", nil},
+		{"012345678901234", "012345678901234", nil},
+		{"812345678", "", ErrInvalidID},
+		{"1812345678", "", ErrInvalidID},
+		{"08123456789", "", ErrInvalidID},
+		{"0812345678x", "", ErrInvalidID},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeID(tt.id)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("NormalizeID(%q) = %q, %v; want %q, %v", tt.id, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestPayloadRejectsInvalidReference(t *testing.T) {
+	for _, reference := range []string{"has space", "ใบแจ้งหนี้", "12345678901234567890123456"} {
+		if _, err := Payload("0812345678", 100, reference); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("Payload with reference %q: got %v, want ErrInvalidReference", reference, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package promptpay
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrNotASlip is returned when a payload is not a Thai bank slip verification code.
+var ErrNotASlip = errors.New("payload is not a bank slip verification code")
+
+// slipAPIID identifies the Bank of Thailand slip verification payload inside tag 00.
+const slipAPIID = "000001"
+
+// Slip tag IDs. The payload is a list of EMVCo-style tag-length-value fields.
+const (
+	tagSlipData    = "00"
+	subTagSlipAPI  = "00"
+	subTagSlipBank = "01"
+	subTagSlipRef  = "02"
+)
+
+// Slip is the content of the mini QR code Thai banks print on transfer slips.
+type Slip struct {
+	SendingBankCode      string // Three-digit Bank of Thailand code of the payer's bank
+	TransactionReference string // Reference of the transfer, unique per sending bank
+}
+
+// ParseSlip extracts the sending bank and transaction reference from a slip verification payload.
+// The payload's own checksum is not verified: the reference only identifies the transfer, it does not
+// prove it, so duplicates are what matter.
+func ParseSlip(payload string) (*Slip, error) {
+	fields, err := parseTLV(payload)
+	if err != nil {
+		return nil, ErrNotASlip
+	}
+	data, ok := fields[tagSlipData]
+	if !ok {
+		return nil, ErrNotASlip
+	}
+	sub, err := parseTLV(data)
+	if err != nil || sub[subTagSlipAPI] != slipAPIID || sub[subTagSlipRef] == "" {
+		return nil, ErrNotASlip
+	}
+	return &Slip{SendingBankCode: sub[subTagSlipBank], TransactionReference: sub[subTagSlipRef]}, nil
+}
+
+// parseTLV splits a payload of two-digit tags and two-digit lengths into its fields.
+func parseTLV(payload string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for len(payload) > 0 {
+		if len(payload) < 4 {
+			return nil, ErrNotASlip
+		}
+		length, err := strconv.Atoi(payload[2:4])
+		if err != nil || len(payload) < 4+length {
+			return nil, ErrNotASlip
+		}
+		fields[payload[:2]] = payload[4 : 4+length]
+		payload = payload[4+length:]
+	}
+	return fields, nil
+}
@@ -0,0 +1,37 @@
+package promptpay
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSlip(t *testing.T) {
+	// Tag 00 holds the API ID 000001, bank code 014 and the reference; the country and checksum fields are ignored.
+	payload := "004500060000010103014022420231018ABCDEF0123456789" + "5102TH" + "91049C8A"
+	slip, err := ParseSlip(payload)
+	if err != nil {
+		t.Fatalf("ParseSlip: %v", err)
+	}
+	if slip.SendingBankCode != "014" || slip.TransactionReference != "20231018ABCDEF0123456789" {
+		t.Errorf("ParseSlip = %+v, want bank 014 and reference 20231018ABCDEF0123456789", slip)
+	}
+}
+
+func TestParseSlipRejectsOtherPayloads(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{"promptpay payment", "00020101021129370016A000000677010111011300668012345675802TH530376463046197"},
+		{"wrong API ID", "0028000600000201030140207REF1234"},
+		{"missing reference", "001700060000010103014"},
+		{"truncated", "004100060000010103014"},
+		{"not TLV", "hello"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		if _, err := ParseSlip(tt.payload); !errors.Is(err, ErrNotASlip) {
+			t.Errorf("%s: got %v, want ErrNotASlip", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProductionHost = "https://api.push.apple.com"
+	apnsSandboxHost    = "https://api.sandbox.push.apple.com"
+	apnsRequestTimeout = 10 * time.Second
+	// Apple rejects provider tokens older than one hour and throttles refreshes more frequent than every 20 minutes.
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsConfig holds the credentials for token-based APNs authentication.
+type APNsConfig struct {
+	AuthKeyPath string
+	KeyID       string
+	TeamID      string
+	Topic       string
+	UseSandbox  bool
+}
+
+// APNsDispatcher sends push notifications through the Apple Push Notification service.
+type APNsDispatcher struct {
+	key    *ecdsa.PrivateKey
+	keyID  string
+	teamID string
+	topic  string
+	host   string
+	client *http.Client
+
+	mu            sync.Mutex
+	token         string
+	tokenIssuedAt time.Time
+}
+
+// NewAPNsDispatcher creates an APNs dispatcher from a .p8 auth key.
+func NewAPNsDispatcher(cfg APNsConfig) (*APNsDispatcher, error) {
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, fmt.Errorf("APNs key ID, team ID and topic are required")
+	}
+	keyPEM, err := os.ReadFile(cfg.AuthKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading APNs auth key: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing APNs auth key: %w", err)
+	}
+
+	host := apnsProductionHost
+	if cfg.UseSandbox {
+		host = apnsSandboxHost
+	}
+
+	return &APNsDispatcher{
+		key:    key,
+		keyID:  cfg.KeyID,
+		teamID: cfg.TeamID,
+		topic:  cfg.Topic,
+		host:   host,
+		client: &http.Client{Timeout: apnsRequestTimeout},
+	}, nil
+}
+
+type apnsErrorResponse struct {
+	Reason string `json:"reason"`
+}
+
+// Send delivers a message to a single APNs device token.
+func (d *APNsDispatcher) Send(ctx context.Context, target Target, msg Message) error {
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range msg.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshalling APNs payload: %w", err)
+	}
+
+	providerToken, err := d.providerToken(false)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.host+"/3/device/"+target.Token, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating APNs request: %w", err)
+	}
+	req.Header.Set("authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", d.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return &RetryableError{Err: fmt.Errorf("sending APNs request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var apnsErr apnsErrorResponse
+	_ = json.Unmarshal(respBody, &apnsErr)
+	sendErr := fmt.Errorf("APNs responded %d: %s", resp.StatusCode, apnsErr.Reason)
+
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		apnsErr.Reason == "BadDeviceToken",
+		apnsErr.Reason == "DeviceTokenNotForTopic",
+		apnsErr.Reason == "Unregistered":
+		return fmt.Errorf("%w: %v", ErrInvalidToken, sendErr)
+	case apnsErr.Reason == "ExpiredProviderToken":
+		if _, err := d.providerToken(true); err != nil {
+			return err
+		}
+		return &RetryableError{Err: sendErr}
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return &RetryableError{Err: sendErr}
+	default:
+		return sendErr
+	}
+}
+
+// providerToken returns a cached signed JWT, issuing a new one when it is about to expire or when forced.
+func (d *APNsDispatcher) providerToken(forceRefresh bool) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !forceRefresh && d.token != "" && time.Since(d.tokenIssuedAt) < apnsTokenLifetime {
+		return d.token, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": d.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = d.keyID
+
+	signed, err := token.SignedString(d.key)
+	if err != nil {
+		return "", fmt.Errorf("signing APNs provider token: %w", err)
+	}
+	d.token = signed
+	d.tokenIssuedAt = now
+	return signed, nil
+}
@@ -0,0 +1,79 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xNatthapol/hubster/internal/models"
+)
+
+var (
+	// ErrInvalidToken means the push provider no longer accepts the device token and it should be removed.
+	ErrInvalidToken = errors.New("push token is invalid or unregistered")
+	// ErrProviderNotConfigured means no dispatcher is configured for the token's provider.
+	ErrProviderNotConfigured = errors.New("push provider is not configured")
+)
+
+// Message is the content of a push notification.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Target identifies the device a message is sent to.
+type Target struct {
+	Token    string
+	Platform models.DevicePlatform
+	Provider models.PushProvider
+}
+
+// Dispatcher sends push notifications to a single device.
+type Dispatcher interface {
+	Send(ctx context.Context, target Target, msg Message) error
+}
+
+// RetryableError wraps a transient delivery failure that may succeed if retried.
+type RetryableError struct {
+	Err error
+}
+
+func (e *RetryableError) Error() string { return fmt.Sprintf("retryable push failure: %v", e.Err) }
+func (e *RetryableError) Unwrap() error { return e.Err }
+
+// IsRetryable reports whether err is a transient delivery failure.
+func IsRetryable(err error) bool {
+	var retryable *RetryableError
+	return errors.As(err, &retryable)
+}
+
+// MultiDispatcher routes messages to the dispatcher configured for the target's provider.
+type MultiDispatcher struct {
+	dispatchers map[models.PushProvider]Dispatcher
+}
+
+// NewMultiDispatcher creates a dispatcher that routes by provider. Nil dispatchers are ignored.
+func NewMultiDispatcher(dispatchers map[models.PushProvider]Dispatcher) *MultiDispatcher {
+	configured := make(map[models.PushProvider]Dispatcher, len(dispatchers))
+	for provider, dispatcher := range dispatchers {
+		if dispatcher != nil {
+			configured[provider] = dispatcher
+		}
+	}
+	return &MultiDispatcher{dispatchers: configured}
+}
+
+// Send delivers the message through the dispatcher for the target's provider.
+func (m *MultiDispatcher) Send(ctx context.Context, target Target, msg Message) error {
+	dispatcher, ok := m.dispatchers[target.Provider]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProviderNotConfigured, target.Provider)
+	}
+	return dispatcher.Send(ctx, target, msg)
+}
+
+// Enabled reports whether at least one provider is configured.
+func (m *MultiDispatcher) Enabled() bool {
+	return len(m.dispatchers) > 0
+}
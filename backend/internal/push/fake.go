@@ -0,0 +1,73 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var errFakeUnavailable = errors.New("fake provider unavailable")
+
+// SentMessage is a message recorded by FakeDispatcher.
+type SentMessage struct {
+	Target  Target
+	Message Message
+}
+
+// FakeDispatcher records messages instead of sending them, for tests and local development.
+type FakeDispatcher struct {
+	mu            sync.Mutex
+	sent          []SentMessage
+	invalidTokens map[string]bool
+	failures      map[string]int
+}
+
+// NewFakeDispatcher creates a FakeDispatcher that rejects the given tokens with ErrInvalidToken.
+func NewFakeDispatcher(invalidTokens ...string) *FakeDispatcher {
+	invalid := make(map[string]bool, len(invalidTokens))
+	for _, token := range invalidTokens {
+		invalid[token] = true
+	}
+	return &FakeDispatcher{invalidTokens: invalid, failures: make(map[string]int)}
+}
+
+// FailTransiently makes the next times sends to the token fail with a RetryableError.
+func (f *FakeDispatcher) FailTransiently(token string, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[token] = times
+}
+
+// Send records the message, or fails with ErrInvalidToken for tokens marked invalid and with a RetryableError
+// while the token has transient failures left.
+func (f *FakeDispatcher) Send(_ context.Context, target Target, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.invalidTokens[target.Token] {
+		return ErrInvalidToken
+	}
+	if f.failures[target.Token] > 0 {
+		f.failures[target.Token]--
+		return &RetryableError{Err: errFakeUnavailable}
+	}
+	f.sent = append(f.sent, SentMessage{Target: target, Message: msg})
+	return nil
+}
+
+// Sent returns a copy of every recorded message.
+func (f *FakeDispatcher) Sent() []SentMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sent := make([]SentMessage, len(f.sent))
+	copy(sent, f.sent)
+	return sent
+}
+
+// Reset clears the recorded messages.
+func (f *FakeDispatcher) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = nil
+}
@@ -0,0 +1,127 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	fcmScope          = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmSendURLFormat  = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmRequestTimeout = 10 * time.Second
+)
+
+// FCMDispatcher sends push notifications through the Firebase Cloud Messaging HTTP v1 API.
+type FCMDispatcher struct {
+	sendURL string
+	client  *http.Client
+}
+
+// NewFCMDispatcher creates an FCM dispatcher authenticated with a service account key file.
+// If projectID is empty, the project of the service account is used.
+func NewFCMDispatcher(ctx context.Context, projectID, keyFilePath string) (*FCMDispatcher, error) {
+	keyJSON, err := os.ReadFile(keyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading FCM service account key: %w", err)
+	}
+	creds, err := google.CredentialsFromJSON(ctx, keyJSON, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("parsing FCM service account key: %w", err)
+	}
+	if projectID == "" {
+		projectID = creds.ProjectID
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("FCM project ID is required")
+	}
+
+	client := oauth2.NewClient(ctx, creds.TokenSource)
+	client.Timeout = fcmRequestTimeout
+
+	return &FCMDispatcher{
+		sendURL: fmt.Sprintf(fcmSendURLFormat, projectID),
+		client:  client,
+	}, nil
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send delivers a message to a single FCM registration token.
+func (d *FCMDispatcher) Send(ctx context.Context, target Target, msg Message) error {
+	body, err := json.Marshal(map[string]fcmMessage{
+		"message": {
+			Token:        target.Token,
+			Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+			Data:         msg.Data,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling FCM message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.sendURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating FCM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return &RetryableError{Err: fmt.Errorf("sending FCM request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var fcmErr fcmErrorResponse
+	_ = json.Unmarshal(respBody, &fcmErr)
+
+	errorCode := fcmErr.Error.Status
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode != "" {
+			errorCode = detail.ErrorCode
+		}
+	}
+	sendErr := fmt.Errorf("FCM responded %d (%s): %s", resp.StatusCode, errorCode, fcmErr.Error.Message)
+
+	switch {
+	case errorCode == "UNREGISTERED" || resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %v", ErrInvalidToken, sendErr)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return &RetryableError{Err: sendErr}
+	default:
+		return sendErr
+	}
+}
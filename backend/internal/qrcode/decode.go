@@ -0,0 +1,693 @@
+package qrcode
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ErrNotFound is returned when no decodable QR Code is found in an image.
+var ErrNotFound = errors.New("qrcode: no QR code found")
+
+// errUnsupportedMode is returned for segments other than numeric, alphanumeric, byte and ECI.
+var errUnsupportedMode = errors.New("qrcode: unsupported segment mode")
+
+const (
+	// maxFinderCandidates bounds how many finder pattern candidates are combined into triples.
+	maxFinderCandidates = 8
+	// alignmentSearchRadius is how far, in modules, the bottom-right alignment pattern is searched for
+	// around where the finder patterns place it.
+	alignmentSearchRadius = 8
+)
+
+// Decode finds a QR Code in img and returns its content. It reads versions 1 to 10 at any error correction
+// level, upright or rotated and with moderate perspective, which covers the small codes printed on
+// payment slips and screenshots of them.
+func Decode(img image.Image) ([]byte, error) {
+	bin := binarize(img)
+	finders := bin.findFinderPatterns()
+	if len(finders) > maxFinderCandidates {
+		finders = finders[:maxFinderCandidates]
+	}
+
+	for i := 0; i < len(finders); i++ {
+		for j := i + 1; j < len(finders); j++ {
+			for k := j + 1; k < len(finders); k++ {
+				if content, err := bin.decodeAt(finders[i], finders[j], finders[k]); err == nil {
+					return content, nil
+				}
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+type point struct {
+	x, y float64
+}
+
+func (p point) sub(q point) point     { return point{p.x - q.x, p.y - q.y} }
+func (p point) add(q point) point     { return point{p.x + q.x, p.y + q.y} }
+func (p point) scale(f float64) point { return point{p.x * f, p.y * f} }
+func (p point) dist(q point) float64  { return math.Hypot(p.x-q.x, p.y-q.y) }
+func (p point) cross(q point) float64 { return p.x*q.y - p.y*q.x }
+func (p point) dot(q point) float64   { return p.x*q.x + p.y*q.y }
+func (p point) length() float64       { return math.Hypot(p.x, p.y) }
+
+// bitmap is a thresholded image; true is dark.
+type bitmap struct {
+	width, height int
+	dark          []bool
+}
+
+func (b *bitmap) at(x, y int) bool {
+	if x < 0 || y < 0 || x >= b.width || y >= b.height {
+		return false
+	}
+	return b.dark[y*b.width+x]
+}
+
+func (b *bitmap) atPoint(p point) bool {
+	return b.at(int(math.Floor(p.x)), int(math.Floor(p.y)))
+}
+
+// binarize converts img to grayscale and thresholds it with Otsu's method.
+func binarize(img image.Image) *bitmap {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	gray := make([]uint8, width*height)
+	switch src := img.(type) {
+	case *image.YCbCr:
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				gray[y*width+x] = src.Y[src.YOffset(bounds.Min.X+x, bounds.Min.Y+y)]
+			}
+		}
+	case *image.Gray:
+		for y := 0; y < height; y++ {
+			copy(gray[y*width:(y+1)*width], src.Pix[src.PixOffset(bounds.Min.X, bounds.Min.Y+y):])
+		}
+	default:
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				gray[y*width+x] = color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray).Y
+			}
+		}
+	}
+
+	var histogram [256]int
+	for _, v := range gray {
+		histogram[v]++
+	}
+	threshold := otsuThreshold(histogram, len(gray))
+
+	bin := &bitmap{width: width, height: height, dark: make([]bool, len(gray))}
+	for i, v := range gray {
+		bin.dark[i] = int(v) <= threshold
+	}
+	return bin
+}
+
+// otsuThreshold returns the gray level that best separates the histogram into dark and light classes.
+func otsuThreshold(histogram [256]int, total int) int {
+	var sum float64
+	for i, count := range histogram {
+		sum += float64(i * count)
+	}
+	var sumDark, bestVariance float64
+	var weightDark int
+	best := 127
+	for i, count := range histogram {
+		weightDark += count
+		if weightDark == 0 {
+			continue
+		}
+		weightLight := total - weightDark
+		if weightLight == 0 {
+			break
+		}
+		sumDark += float64(i * count)
+		meanDark := sumDark / float64(weightDark)
+		meanLight := (sum - sumDark) / float64(weightLight)
+		variance := float64(weightDark) * float64(weightLight) * (meanDark - meanLight) * (meanDark - meanLight)
+		if variance > bestVariance {
+			bestVariance = variance
+			best = i
+		}
+	}
+	return best
+}
+
+// finderPattern is a candidate finder pattern centre, with the estimated module size and how many scans confirmed it.
+type finderPattern struct {
+	center     point
+	moduleSize float64
+	hits       int
+}
+
+// findFinderPatterns scans every row for the 1:1:3:1:1 dark-light-dark-light-dark run ratio of finder patterns,
+// confirms each hit along the column and the row through its centre, and returns the candidates most
+// often confirmed first.
+func (b *bitmap) findFinderPatterns() []finderPattern {
+	var candidates []finderPattern
+	for y := 0; y < b.height; y++ {
+		var runs [5]int
+		var runStarts [5]int
+		state := -1 // index of the current run in runs, -1 before the first dark module
+		for x := 0; x <= b.width; x++ {
+			dark := x < b.width && b.at(x, y)
+			if state < 0 {
+				if dark {
+					state = 0
+					runs = [5]int{1}
+					runStarts[0] = x
+				}
+				continue
+			}
+			if dark == (state%2 == 0) {
+				runs[state]++
+				continue
+			}
+			if state < 4 {
+				state++
+				runs[state] = 1
+				runStarts[state] = x
+				continue
+			}
+
+			// Five runs complete and a light module follows.
+			if moduleSize, ok := finderRatio(runs); ok {
+				centerX := float64(runStarts[2]) + float64(runs[2])/2
+				if c, ok := b.confirmFinder(centerX, float64(y)+0.5, runs[2], moduleSize); ok {
+					candidates = mergeFinder(candidates, c)
+				}
+			}
+			// Slide the window by two runs so the last dark run can start the next pattern.
+			runs = [5]int{runs[2], runs[3], runs[4], 1, 0}
+			runStarts = [5]int{runStarts[2], runStarts[3], runStarts[4], x, 0}
+			state = 3
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].hits > candidates[j].hits })
+	return candidates
+}
+
+// finderRatio checks five run lengths against 1:1:3:1:1 and returns the module size they imply.
+func finderRatio(runs [5]int) (float64, bool) {
+	total := 0
+	for _, r := range runs {
+		if r == 0 {
+			return 0, false
+		}
+		total += r
+	}
+	if total < 7 {
+		return 0, false
+	}
+	moduleSize := float64(total) / 7
+	variance := moduleSize / 2
+	return moduleSize,
+		math.Abs(moduleSize-float64(runs[0])) < variance &&
+			math.Abs(moduleSize-float64(runs[1])) < variance &&
+			math.Abs(3*moduleSize-float64(runs[2])) < 3*variance &&
+			math.Abs(moduleSize-float64(runs[3])) < variance &&
+			math.Abs(moduleSize-float64(runs[4])) < variance
+}
+
+// confirmFinder cross-checks a horizontal finder hit along its column, then re-centres it along its row.
+func (b *bitmap) confirmFinder(centerX, centerY float64, centerRun int, moduleSize float64) (finderPattern, bool) {
+	x := int(centerX)
+	vertical, verticalSize, ok := crossCheck(func(i int) bool { return b.at(x, i) }, int(centerY), b.height, centerRun)
+	if !ok || math.Abs(verticalSize-moduleSize) > moduleSize*0.4 {
+		return finderPattern{}, false
+	}
+	y := int(vertical)
+	horizontal, horizontalSize, ok := crossCheck(func(i int) bool { return b.at(i, y) }, x, b.width, centerRun)
+	if !ok {
+		return finderPattern{}, false
+	}
+	return finderPattern{
+		center:     point{horizontal, vertical},
+		moduleSize: (verticalSize + horizontalSize) / 2,
+		hits:       1,
+	}, true
+}
+
+// crossCheck measures the five runs of a finder pattern through center along one line, where dark reports
+// the colour at each position and limit is the line length. It returns the pattern's centre and module size.
+func crossCheck(dark func(int) bool, center, limit, maxRun int) (float64, float64, bool) {
+	if !dark(center) {
+		return 0, 0, false
+	}
+	var runs [5]int
+	i := center
+	for ; i >= 0 && dark(i); i-- {
+		runs[2]++
+	}
+	for ; i >= 0 && !dark(i) && runs[1] <= maxRun; i-- {
+		runs[1]++
+	}
+	for ; i >= 0 && dark(i) && runs[0] <= maxRun; i-- {
+		runs[0]++
+	}
+	i = center + 1
+	for ; i < limit && dark(i); i++ {
+		runs[2]++
+	}
+	for ; i < limit && !dark(i) && runs[3] <= maxRun; i++ {
+		runs[3]++
+	}
+	for ; i < limit && dark(i) && runs[4] <= maxRun; i++ {
+		runs[4]++
+	}
+	if runs[0] > maxRun || runs[1] > maxRun || runs[3] > maxRun || runs[4] > maxRun {
+		return 0, 0, false
+	}
+	moduleSize, ok := finderRatio(runs)
+	if !ok {
+		return 0, 0, false
+	}
+	end := i
+	return float64(end-runs[4]-runs[3]) - float64(runs[2])/2, moduleSize, true
+}
+
+// mergeFinder folds c into an existing candidate at the same spot, or adds it.
+func mergeFinder(candidates []finderPattern, c finderPattern) []finderPattern {
+	for i, existing := range candidates {
+		if existing.center.dist(c.center) <= existing.moduleSize*2 &&
+			math.Abs(existing.moduleSize-c.moduleSize) <= math.Max(1, existing.moduleSize*0.5) {
+			n := float64(existing.hits)
+			candidates[i] = finderPattern{
+				center:     existing.center.scale(n).add(c.center).scale(1 / (n + 1)),
+				moduleSize: (existing.moduleSize*n + c.moduleSize) / (n + 1),
+				hits:       existing.hits + 1,
+			}
+			return candidates
+		}
+	}
+	return append(candidates, c)
+}
+
+// decodeAt tries to read a symbol whose three finder patterns are a, b and c, in any order.
+func (b *bitmap) decodeAt(f1, f2, f3 finderPattern) ([]byte, error) {
+	topLeft, topRight, bottomLeft, ok := orderFinders(f1, f2, f3)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	// Runs are measured along rows and columns, so a symbol rotated by angle looks up to √2 times coarser.
+	moduleSize := (f1.moduleSize + f2.moduleSize + f3.moduleSize) / 3
+	angle := math.Atan2(topRight.y-topLeft.y, topRight.x-topLeft.x)
+	moduleSize *= math.Max(math.Abs(math.Cos(angle)), math.Abs(math.Sin(angle)))
+	side := (topLeft.dist(topRight) + topLeft.dist(bottomLeft)) / 2
+	estimate := int(math.Round((side/moduleSize + 7 - 17) / 4))
+	for _, version := range []int{estimate, estimate - 1, estimate + 1} {
+		if version < 1 || version > maxVersion {
+			continue
+		}
+		for _, transform := range b.transformsFor(version, topLeft, topRight, bottomLeft) {
+			if content, err := decodeGrid(b.sample(version, transform)); err == nil {
+				return content, nil
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// orderFinders identifies the top-left pattern as the corner of the right angle, and orients the other two
+// so that top-right to bottom-left turns clockwise. It rejects triples that are not roughly a right isosceles triangle.
+func orderFinders(f1, f2, f3 finderPattern) (point, point, point, bool) {
+	for _, f := range []finderPattern{f2, f3} {
+		if ratio := f.moduleSize / f1.moduleSize; ratio > 1.6 || ratio < 1/1.6 {
+			return point{}, point{}, point{}, false
+		}
+	}
+
+	a, b, c := f1.center, f2.center, f3.center
+	// The top-left pattern is opposite the longest side.
+	ab, bc, ac := a.dist(b), b.dist(c), a.dist(c)
+	switch {
+	case bc >= ab && bc >= ac:
+	case ac >= ab && ac >= bc:
+		a, b = b, a
+	default:
+		a, c = c, a
+	}
+
+	legB, legC := b.sub(a), c.sub(a)
+	lenB, lenC := legB.length(), legC.length()
+	if lenB == 0 || lenC == 0 || math.Max(lenB, lenC)/math.Min(lenB, lenC) > 1.4 {
+		return point{}, point{}, point{}, false
+	}
+	if math.Abs(legB.dot(legC)/(lenB*lenC)) > 0.3 {
+		return point{}, point{}, point{}, false
+	}
+	if legB.cross(legC) < 0 {
+		b, c = c, b
+	}
+	return a, b, c, true
+}
+
+// transformsFor returns the module-to-pixel transforms to try for a version: a perspective one anchored on
+// the bottom-right alignment pattern when it can be found, then the affine one implied by the finder patterns.
+func (b *bitmap) transformsFor(version int, topLeft, topRight, bottomLeft point) []perspective {
+	size := float64(version*4 + 17)
+	affineBottomRight := topRight.add(bottomLeft).sub(topLeft)
+	affine := quadToQuad(
+		[4]point{{3.5, 3.5}, {size - 3.5, 3.5}, {size - 3.5, size - 3.5}, {3.5, size - 3.5}},
+		[4]point{topLeft, topRight, affineBottomRight, bottomLeft},
+	)
+	if version == 1 {
+		return []perspective{affine}
+	}
+
+	alignment, ok := b.findAlignment(affine, size-6.5)
+	if !ok {
+		return []perspective{affine}
+	}
+	anchored := quadToQuad(
+		[4]point{{3.5, 3.5}, {size - 3.5, 3.5}, {size - 6.5, size - 6.5}, {3.5, size - 3.5}},
+		[4]point{topLeft, topRight, alignment, bottomLeft},
+	)
+	return []perspective{anchored, affine}
+}
+
+// findAlignment searches around the expected centre of the bottom-right alignment pattern (at module coordinates
+// (at, at)) for the spot that best matches its dark centre, light ring and dark ring, and returns its pixel position.
+func (b *bitmap) findAlignment(transform perspective, at float64) (point, bool) {
+	expected := transform.apply(point{at, at})
+	right := transform.apply(point{at + 1, at}).sub(expected)
+	down := transform.apply(point{at, at + 1}).sub(expected)
+	moduleSize := (right.length() + down.length()) / 2
+	radius := int(math.Ceil(moduleSize * alignmentSearchRadius))
+
+	bestScore := 0
+	var sum point
+	var count int
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			center := expected.add(point{float64(dx), float64(dy)})
+			score := 0
+			for my := -2; my <= 2; my++ {
+				for mx := -2; mx <= 2; mx++ {
+					ring := max(abs(mx), abs(my))
+					p := center.add(right.scale(float64(mx))).add(down.scale(float64(my)))
+					if b.atPoint(p) == (ring != 1) {
+						score++
+					}
+				}
+			}
+			switch {
+			case score > bestScore:
+				bestScore, sum, count = score, center, 1
+			case score == bestScore:
+				sum, count = sum.add(center), count+1
+			}
+		}
+	}
+	// All 25 modules must match, bar a couple of misread ones.
+	if bestScore < 23 {
+		return point{}, false
+	}
+	return sum.scale(1 / float64(count)), true
+}
+
+// sample reads the module grid of a version through transform.
+func (b *bitmap) sample(version int, transform perspective) [][]bool {
+	size := version*4 + 17
+	grid := newGrid(size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			grid[y][x] = b.atPoint(transform.apply(point{float64(x) + 0.5, float64(y) + 0.5}))
+		}
+	}
+	return grid
+}
+
+// perspective is a projective transform, as the 3x3 matrix applied to (x, y, 1).
+type perspective struct {
+	a11, a21, a31 float64
+	a12, a22, a32 float64
+	a13, a23, a33 float64
+}
+
+func (t perspective) apply(p point) point {
+	denominator := t.a13*p.x + t.a23*p.y + t.a33
+	return point{
+		(t.a11*p.x + t.a21*p.y + t.a31) / denominator,
+		(t.a12*p.x + t.a22*p.y + t.a32) / denominator,
+	}
+}
+
+// quadToQuad returns the transform mapping each corner of from onto the matching corner of to.
+func quadToQuad(from, to [4]point) perspective {
+	return squareToQuad(to).times(squareToQuad(from).adjoint())
+}
+
+// squareToQuad returns the transform mapping the unit square (0,0), (1,0), (1,1), (0,1) onto q.
+func squareToQuad(q [4]point) perspective {
+	dx3 := q[0].x - q[1].x + q[2].x - q[3].x
+	dy3 := q[0].y - q[1].y + q[2].y - q[3].y
+	if dx3 == 0 && dy3 == 0 {
+		return perspective{
+			q[1].x - q[0].x, q[2].x - q[1].x, q[0].x,
+			q[1].y - q[0].y, q[2].y - q[1].y, q[0].y,
+			0, 0, 1,
+		}
+	}
+	dx1, dx2 := q[1].x-q[2].x, q[3].x-q[2].x
+	dy1, dy2 := q[1].y-q[2].y, q[3].y-q[2].y
+	denominator := dx1*dy2 - dx2*dy1
+	a13 := (dx3*dy2 - dx2*dy3) / denominator
+	a23 := (dx1*dy3 - dx3*dy1) / denominator
+	return perspective{
+		q[1].x - q[0].x + a13*q[1].x, q[3].x - q[0].x + a23*q[3].x, q[0].x,
+		q[1].y - q[0].y + a13*q[1].y, q[3].y - q[0].y + a23*q[3].y, q[0].y,
+		a13, a23, 1,
+	}
+}
+
+// adjoint returns the adjugate matrix, which inverts the transform up to scale.
+func (t perspective) adjoint() perspective {
+	return perspective{
+		t.a22*t.a33 - t.a23*t.a32, t.a23*t.a31 - t.a21*t.a33, t.a21*t.a32 - t.a22*t.a31,
+		t.a13*t.a32 - t.a12*t.a33, t.a11*t.a33 - t.a13*t.a31, t.a12*t.a31 - t.a11*t.a32,
+		t.a12*t.a23 - t.a13*t.a22, t.a13*t.a21 - t.a11*t.a23, t.a11*t.a22 - t.a12*t.a21,
+	}
+}
+
+// times returns the transform applying o, then t.
+func (t perspective) times(o perspective) perspective {
+	return perspective{
+		t.a11*o.a11 + t.a21*o.a12 + t.a31*o.a13, t.a11*o.a21 + t.a21*o.a22 + t.a31*o.a23, t.a11*o.a31 + t.a21*o.a32 + t.a31*o.a33,
+		t.a12*o.a11 + t.a22*o.a12 + t.a32*o.a13, t.a12*o.a21 + t.a22*o.a22 + t.a32*o.a23, t.a12*o.a31 + t.a22*o.a32 + t.a32*o.a33,
+		t.a13*o.a11 + t.a23*o.a12 + t.a33*o.a13, t.a13*o.a21 + t.a23*o.a22 + t.a33*o.a23, t.a13*o.a31 + t.a23*o.a32 + t.a33*o.a33,
+	}
+}
+
+// decodeGrid reads the content of a sampled module grid: format information, codewords, error correction
+// and segments.
+func decodeGrid(grid [][]bool) ([]byte, error) {
+	size := len(grid)
+	version := (size - 17) / 4
+	level, mask, ok := readFormat(grid)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	c := newCode(version, level)
+	codewords := make([]byte, totalCodewords[version-1])
+	i := 0
+	c.walkDataModules(func(x, y int) {
+		if i < len(codewords)*8 && grid[y][x] != masked(mask, x, y) {
+			codewords[i>>3] |= 1 << (7 - i&7)
+		}
+		i++
+	})
+
+	layout := layoutOf(version, level)
+	blocks := make([][]byte, layout.blocks)
+	pos := 0
+	for j := 0; j <= layout.shortData; j++ {
+		for b := range blocks {
+			if j < layout.dataLength(b) {
+				blocks[b] = append(blocks[b], codewords[pos])
+				pos++
+			}
+		}
+	}
+	for j := 0; j < layout.ecPerBlock; j++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[pos])
+			pos++
+		}
+	}
+
+	var data []byte
+	for b, block := range blocks {
+		if err := reedSolomonCorrect(block, layout.ecPerBlock); err != nil {
+			return nil, err
+		}
+		data = append(data, block[:layout.dataLength(b)]...)
+	}
+	return parseSegments(data, version)
+}
+
+// readFormat reads both copies of the format information and returns the level and mask of the closest
+// valid format, allowing up to three wrong bits.
+func readFormat(grid [][]bool) (Level, int, bool) {
+	size := len(grid)
+	var first, second int
+	for i := 0; i <= 5; i++ {
+		first |= boolBit(grid[i][8]) << i
+	}
+	first |= boolBit(grid[7][8])<<6 | boolBit(grid[8][8])<<7 | boolBit(grid[8][7])<<8
+	for i := 9; i < 15; i++ {
+		first |= boolBit(grid[8][14-i]) << i
+	}
+	for i := 0; i < 8; i++ {
+		second |= boolBit(grid[8][size-1-i]) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= boolBit(grid[size-15+i][8]) << i
+	}
+
+	bestDistance := 4
+	var bestLevel Level
+	var bestMask int
+	for level := Low; level <= High; level++ {
+		for mask := 0; mask < 8; mask++ {
+			valid := formatInfo(level, mask)
+			distance := min(bitCount(first^valid), bitCount(second^valid))
+			if distance < bestDistance {
+				bestDistance, bestLevel, bestMask = distance, level, mask
+			}
+		}
+	}
+	return bestLevel, bestMask, bestDistance < 4
+}
+
+const alphanumericCharset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// parseSegments decodes the numeric, alphanumeric and byte segments of the data codewords.
+func parseSegments(data []byte, version int) ([]byte, error) {
+	r := &bitReader{data: data}
+	var out strings.Builder
+	for r.remaining() >= 4 {
+		switch mode := r.read(4); mode {
+		case 0b0000: // Terminator
+			return []byte(out.String()), nil
+		case 0b0001: // Numeric
+			count := r.read(numericCountBits(version))
+			for ; count >= 3; count -= 3 {
+				out.WriteString(padDigits(r.read(10), 3))
+			}
+			if count == 2 {
+				out.WriteString(padDigits(r.read(7), 2))
+			} else if count == 1 {
+				out.WriteString(padDigits(r.read(4), 1))
+			}
+		case 0b0010: // Alphanumeric
+			count := r.read(alphanumericCountBits(version))
+			for ; count >= 2; count -= 2 {
+				pair := r.read(11)
+				if pair/45 >= len(alphanumericCharset) {
+					return nil, errUnsupportedMode
+				}
+				out.WriteByte(alphanumericCharset[pair/45])
+				out.WriteByte(alphanumericCharset[pair%45])
+			}
+			if count == 1 {
+				out.WriteByte(alphanumericCharset[r.read(6)%45])
+			}
+		case 0b0100: // Byte
+			count := r.read(charCountBits(version))
+			for ; count > 0; count-- {
+				out.WriteByte(byte(r.read(8)))
+			}
+		case 0b0111: // ECI designator, whose assignment number takes one to three bytes
+			switch {
+			case r.read(1) == 0:
+				r.read(7)
+			case r.read(1) == 0:
+				r.read(14)
+			default:
+				r.read(22)
+			}
+		default:
+			return nil, errUnsupportedMode
+		}
+		if r.overrun {
+			return nil, ErrNotFound
+		}
+	}
+	return []byte(out.String()), nil
+}
+
+func numericCountBits(version int) int {
+	if version <= 9 {
+		return 10
+	}
+	return 12
+}
+
+func alphanumericCountBits(version int) int {
+	if version <= 9 {
+		return 9
+	}
+	return 11
+}
+
+func padDigits(value, width int) string {
+	digits := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		digits[i] = byte('0' + value%10)
+		value /= 10
+	}
+	return string(digits)
+}
+
+func boolBit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func bitCount(v int) int {
+	n := 0
+	for ; v != 0; v &= v - 1 {
+		n++
+	}
+	return n
+}
+
+type bitReader struct {
+	data    []byte
+	pos     int
+	overrun bool
+}
+
+func (r *bitReader) remaining() int {
+	return len(r.data)*8 - r.pos
+}
+
+func (r *bitReader) read(n int) int {
+	value := 0
+	for i := 0; i < n; i++ {
+		value <<= 1
+		if r.pos >= len(r.data)*8 {
+			r.overrun = true
+			continue
+		}
+		if r.data[r.pos>>3]&(1<<(7-r.pos&7)) != 0 {
+			value |= 1
+		}
+		r.pos++
+	}
+	return value
+}
@@ -0,0 +1,130 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// isoBlock is the version 1-M block of ISO/IEC 18004 Annex I: 16 data codewords and 10 error-correction codewords.
+var isoBlock = []byte{
+	0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11,
+	0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55,
+}
+
+func TestReedSolomonCorrectUpToLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions []int
+	}{
+		{"clean", nil},
+		{"one data error", []int{3}},
+		{"error-correction errors", []int{17, 25}},
+		{"five errors", []int{0, 6, 12, 19, 25}},
+	}
+	for _, tt := range tests {
+		block := append([]byte(nil), isoBlock...)
+		for i, p := range tt.positions {
+			block[p] ^= byte(0x5A + i)
+		}
+		if err := reedSolomonCorrect(block, 10); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !bytes.Equal(block, isoBlock) {
+			t.Errorf("%s: corrected block = % X, want % X", tt.name, block, isoBlock)
+		}
+	}
+}
+
+func TestReedSolomonCorrectBeyondLimit(t *testing.T) {
+	block := append([]byte(nil), isoBlock...)
+	for i, p := range []int{0, 4, 8, 12, 16, 20} {
+		block[p] ^= byte(0x5A + i)
+	}
+	if err := reedSolomonCorrect(block, 10); !errors.Is(err, errTooManyErrors) {
+		t.Errorf("six errors with ten error-correction codewords: got %v, want errTooManyErrors", err)
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	contents := []string{
+		"a",
+		"https://hubster.app/i/abc", // version 2
+		"00020101021229370016A000000677010111011300668012345675802TH530376463046197",
+		string(bytes.Repeat([]byte("0123456789"), 11)),    // version 7, with version information
+		string(bytes.Repeat([]byte{0x00, 0xFF, 'x'}, 71)), // version 10, the largest supported
+	}
+	for _, content := range contents {
+		c, err := Encode([]byte(content))
+		if err != nil {
+			t.Fatalf("Encode(%q): %v", content, err)
+		}
+		img := renderPNG(t, c, 3)
+		for turns := 0; turns < 4; turns++ {
+			got, err := Decode(img)
+			if err != nil {
+				t.Errorf("Decode version %d rotated %d°: %v", c.Version, turns*90, err)
+			} else if string(got) != content {
+				t.Errorf("Decode version %d rotated %d° = %q, want %q", c.Version, turns*90, got, content)
+			}
+			img = rotate(img)
+		}
+	}
+}
+
+func TestDecodeCorrectsDamagedModules(t *testing.T) {
+	content := "00020101021229370016A000000677010111011300668012345675802TH530376463046197"
+	c, err := Encode([]byte(content))
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	// Flip a few data modules in the lower right, away from the finder patterns and format information.
+	for _, xy := range [][2]int{{c.Size - 1, c.Size - 1}, {c.Size - 2, c.Size - 1}, {c.Size - 3, c.Size - 5}} {
+		c.modules[xy[1]][xy[0]] = !c.modules[xy[1]][xy[0]]
+	}
+	got, err := Decode(renderPNG(t, c, 4))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if string(got) != content {
+		t.Errorf("Decode = %q, want %q", got, content)
+	}
+}
+
+func TestDecodeBlankImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 200, 200))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	if _, err := Decode(img); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Decode(blank) = %v, want ErrNotFound", err)
+	}
+}
+
+func renderPNG(t *testing.T, c *Code, scale int) image.Image {
+	t.Helper()
+	data, err := c.PNG(scale)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+	return img
+}
+
+// rotate turns img a quarter turn clockwise.
+func rotate(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewGray(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(b.Max.Y-1-y, x-b.Min.X, img.At(x, y))
+		}
+	}
+	return out
+}
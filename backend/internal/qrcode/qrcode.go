@@ -0,0 +1,529 @@
+// Package qrcode encodes short byte strings as QR Code symbols (ISO/IEC 18004) and renders them as PNG.
+// It encodes in byte mode at error correction level M in versions 1 to 10, which fits up to 213 bytes:
+// enough for payment payloads without pulling in a third-party library.
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// ErrTooLong is returned when the content does not fit in the largest supported version.
+var ErrTooLong = errors.New("qrcode: content too long")
+
+// quietZone is the light border, in modules, required around a symbol.
+const quietZone = 4
+
+// Level is an error correction level.
+type Level int
+
+// Error correction levels, in the order of the tables below. Each recovers roughly 7, 15, 25 and 30% of the symbol.
+const (
+	Low Level = iota
+	Medium
+	Quartile
+	High
+)
+
+// formatBits are the two bits identifying each level in the format information.
+var formatBits = [...]int{Low: 0b01, Medium: 0b00, Quartile: 0b11, High: 0b10}
+
+// Error-correction codewords per block for versions 1 to 10, indexed by level then version - 1.
+var ecCodewordsPerBlock = [4][10]int{
+	{7, 10, 15, 20, 26, 18, 20, 24, 30, 18},
+	{10, 16, 26, 18, 24, 16, 18, 22, 22, 26},
+	{13, 22, 18, 26, 18, 24, 18, 22, 20, 24},
+	{17, 28, 22, 16, 22, 28, 26, 26, 24, 28},
+}
+
+// Number of error-correction blocks for versions 1 to 10, indexed by level then version - 1.
+var ecBlocks = [4][10]int{
+	{1, 1, 1, 1, 1, 2, 2, 2, 2, 4},
+	{1, 1, 1, 2, 2, 4, 4, 4, 5, 5},
+	{1, 1, 2, 2, 4, 4, 6, 6, 8, 8},
+	{1, 1, 2, 4, 4, 4, 5, 6, 8, 8},
+}
+
+// Total codewords (data and error correction) for versions 1 to 10, indexed by version - 1.
+var totalCodewords = [10]int{26, 44, 70, 100, 134, 172, 196, 242, 292, 346}
+
+// maxVersion is the largest version supported for encoding and decoding.
+const maxVersion = len(totalCodewords)
+
+// blockLayout describes how a version's codewords are split into error-correction blocks at a level.
+// Short blocks come first; long blocks hold one more data codeword.
+type blockLayout struct {
+	ecPerBlock  int
+	blocks      int
+	shortBlocks int
+	shortData   int
+}
+
+func layoutOf(version int, level Level) blockLayout {
+	ecPerBlock := ecCodewordsPerBlock[level][version-1]
+	blocks := ecBlocks[level][version-1]
+	total := totalCodewords[version-1]
+	return blockLayout{
+		ecPerBlock:  ecPerBlock,
+		blocks:      blocks,
+		shortBlocks: blocks - total%blocks,
+		shortData:   total/blocks - ecPerBlock,
+	}
+}
+
+// dataLength returns the number of data codewords in block i.
+func (l blockLayout) dataLength(i int) int {
+	if i < l.shortBlocks {
+		return l.shortData
+	}
+	return l.shortData + 1
+}
+
+func (l blockLayout) dataCodewords() int {
+	return l.blocks*l.shortData + l.blocks - l.shortBlocks
+}
+
+// Alignment pattern centre coordinates for versions 1 to 10, indexed by version - 1.
+var alignmentPositions = [][]int{
+	{},
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+}
+
+// Code is an encoded QR Code symbol.
+type Code struct {
+	Version  int
+	Size     int
+	Level    Level
+	modules  [][]bool // true is dark, indexed [y][x]
+	function [][]bool // modules reserved for patterns and format information
+}
+
+// newCode returns a symbol with its function patterns drawn and the format area reserved.
+func newCode(version int, level Level) *Code {
+	size := version*4 + 17
+	c := &Code{Version: version, Size: size, Level: level}
+	c.modules = newGrid(size)
+	c.function = newGrid(size)
+	c.drawFunctionPatterns()
+	return c
+}
+
+// Dark reports whether the module at column x and row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode encodes content in byte mode, choosing the smallest version it fits in and the mask with the lowest penalty.
+func Encode(content []byte) (*Code, error) {
+	version := 0
+	for v := 1; v <= maxVersion; v++ {
+		if dataBits(v, len(content)) <= layoutOf(v, Medium).dataCodewords()*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	codewords := addErrorCorrection(version, Medium, encodeData(version, Medium, content))
+
+	c := newCode(version, Medium)
+	c.drawCodewords(codewords)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if penalty := c.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		c.applyMask(mask) // XOR again to undo
+	}
+	c.applyMask(bestMask)
+	c.drawFormatBits(bestMask)
+	return c, nil
+}
+
+// PNG renders the symbol with a quiet zone, each module scale pixels wide.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+	dim := (c.Size + 2*quietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, dim, dim))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray((x+quietZone)*scale+dx, (y+quietZone)*scale+dy, color.Gray{Y: 0})
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func dataBits(version int, length int) int {
+	return 4 + charCountBits(version) + 8*length
+}
+
+// encodeData builds the data codewords: mode, length, content, terminator and padding.
+func encodeData(version int, level Level, content []byte) []byte {
+	capacity := layoutOf(version, level).dataCodewords() * 8
+	var bits bitBuffer
+	bits.append(0b0100, 4) // Byte mode
+	bits.append(uint32(len(content)), charCountBits(version))
+	for _, b := range content {
+		bits.append(uint32(b), 8)
+	}
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	for pad := uint32(0xEC); bits.len() < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// addErrorCorrection splits data into blocks, appends Reed-Solomon codewords to each and interleaves them.
+func addErrorCorrection(version int, level Level, data []byte) []byte {
+	layout := layoutOf(version, level)
+	divisor := reedSolomonDivisor(layout.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for i := 0; i < layout.blocks; i++ {
+		block := data[offset : offset+layout.dataLength(i)]
+		offset += len(block)
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, reedSolomonRemainder(block, divisor))
+	}
+
+	result := make([]byte, 0, totalCodewords[version-1])
+	for i := 0; i <= layout.shortData; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.Size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	positions := alignmentPositions[c.Version-1]
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue // Overlaps a finder pattern
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	c.drawFormatBits(0) // Reserves the area; the real bits are drawn once the mask is chosen
+	c.drawVersionBits()
+}
+
+// drawFinder draws a finder pattern centred on (cx, cy) together with its light separator.
+func (c *Code) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || x >= c.Size || y < 0 || y >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatInfo(c.Level, mask)
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.Size-8, true) // Always-dark module
+}
+
+// formatInfo returns the 15 format bits for a level and mask: five data bits, ten BCH bits, masked with 0x5412.
+func formatInfo(level Level, mask int) int {
+	data := formatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawVersionBits() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := c.Version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bit(bits, i)
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places the codewords in the data modules; remainder modules stay light.
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	c.walkDataModules(func(x, y int) {
+		if i < len(codewords)*8 {
+			c.modules[y][x] = bit(int(codewords[i>>3]), 7-(i&7))
+		}
+		i++
+	})
+}
+
+// walkDataModules visits the modules outside function patterns in placement order: two columns at a time
+// from the right, zigzagging up and down.
+func (c *Code) walkDataModules(visit func(x, y int)) {
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.function[y][x] {
+					visit(x, y)
+				}
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.function[y][x] {
+				continue
+			}
+			if masked(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// masked reports whether mask pattern mask inverts the module at column x and row y.
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores the symbol with the four rules of the standard; the mask with the lowest score is used.
+func (c *Code) penalty() int {
+	score := 0
+	for i := 0; i < c.Size; i++ {
+		row := make([]bool, c.Size)
+		col := make([]bool, c.Size)
+		for j := 0; j < c.Size; j++ {
+			row[j] = c.modules[i][j]
+			col[j] = c.modules[j][i]
+		}
+		score += linePenalty(row) + linePenalty(col)
+	}
+
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x < c.Size-1 && y < c.Size-1 {
+				v := c.modules[y][x]
+				if c.modules[y][x+1] == v && c.modules[y+1][x] == v && c.modules[y+1][x+1] == v {
+					score += 3
+				}
+			}
+		}
+	}
+
+	total := c.Size * c.Size
+	deviation := abs(dark*100/total - 50)
+	score += deviation / 5 * 10
+	return score
+}
+
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+// linePenalty scores runs of five or more same-coloured modules and finder-like patterns in one row or column.
+func linePenalty(line []bool) int {
+	score := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += 3 + run - 5
+		}
+		run = 1
+	}
+
+	for i := 0; i+len(finderLike) <= len(line); i++ {
+		matches := true
+		for j, dark := range finderLike {
+			if line[i+j] != dark {
+				matches = false
+				break
+			}
+		}
+		if matches && (lightRun(line, i-4, i) || lightRun(line, i+len(finderLike), i+len(finderLike)+4)) {
+			score += 40
+		}
+	}
+	return score
+}
+
+// lightRun reports whether line[from:to] is all light, treating modules outside the symbol as light.
+func lightRun(line []bool, from, to int) bool {
+	for i := from; i < to; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func bit(value int, i int) bool {
+	return (value>>i)&1 != 0
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+type bitBuffer struct {
+	data  []byte
+	nbits int
+}
+
+func (b *bitBuffer) len() int {
+	return b.nbits
+}
+
+func (b *bitBuffer) append(value uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if b.nbits%8 == 0 {
+			b.data = append(b.data, 0)
+		}
+		if (value>>i)&1 != 0 {
+			b.data[b.nbits/8] |= 1 << (7 - b.nbits%8)
+		}
+		b.nbits++
+	}
+}
+
+func (b *bitBuffer) bytes() []byte {
+	return b.data
+}
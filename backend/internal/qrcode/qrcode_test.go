@@ -0,0 +1,108 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestReedSolomonDivisor(t *testing.T) {
+	// The degree-7 generator polynomial of ISO/IEC 18004 Annex A, as exponents of 2.
+	exponents := []int{87, 229, 146, 149, 238, 102, 21}
+	got := reedSolomonDivisor(len(exponents))
+	for i, e := range exponents {
+		if got[i] != gfExp[e] {
+			t.Errorf("coefficient %d = %d, want 2^%d = %d", i, got[i], e, gfExp[e])
+		}
+	}
+}
+
+func TestReedSolomonRemainderKnownVectors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		ec   []byte
+	}{
+		{
+			// ISO/IEC 18004 Annex I: "01234567" as version 1-M.
+			name: "01234567",
+			data: []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			ec:   []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55},
+		},
+		{
+			// "HELLO WORLD" as version 1-M, the worked example commonly used alongside the specification.
+			name: "HELLO WORLD",
+			data: []byte{0x20, 0x5B, 0x0B, 0x78, 0xD1, 0x72, 0xDC, 0x4D, 0x43, 0x40, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			ec:   []byte{0xC4, 0x23, 0x27, 0x77, 0xEB, 0xD7, 0xE7, 0xE2, 0x5D, 0x17},
+		},
+	}
+	for _, tt := range tests {
+		if got := reedSolomonRemainder(tt.data, reedSolomonDivisor(len(tt.ec))); !bytes.Equal(got, tt.ec) {
+			t.Errorf("%s: error correction = % X, want % X", tt.name, got, tt.ec)
+		}
+	}
+}
+
+func TestFormatInfo(t *testing.T) {
+	tests := []struct {
+		level Level
+		mask  int
+		want  int
+	}{
+		{Low, 4, 0b110011000101111},
+		{Medium, 0, 0b101010000010010},
+		{Quartile, 0, 0b011010101011111},
+		{High, 0, 0b001011010001001},
+		{High, 7, 0b000100000111011},
+	}
+	for _, tt := range tests {
+		if got := formatInfo(tt.level, tt.mask); got != tt.want {
+			t.Errorf("formatInfo(%d, %d) = %015b, want %015b", tt.level, tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeVersionInformation(t *testing.T) {
+	// 110 bytes need version 7, the first version carrying version information: 000111110010010100.
+	c, err := Encode(bytes.Repeat([]byte("a"), 110))
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if c.Version != 7 {
+		t.Fatalf("Version = %d, want 7", c.Version)
+	}
+	var got int
+	for i := 0; i < 18; i++ {
+		if c.Dark(c.Size-11+i%3, i/3) {
+			got |= 1 << i
+		}
+	}
+	if want := 0b000111110010010100; got != want {
+		t.Errorf("version information = %018b, want %018b", got, want)
+	}
+}
+
+func TestEncodeChoosesSmallestVersion(t *testing.T) {
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{1, 1},
+		{14, 1},
+		{15, 2},
+		{213, 10},
+	}
+	for _, tt := range tests {
+		c, err := Encode(bytes.Repeat([]byte("x"), tt.length))
+		if err != nil {
+			t.Errorf("Encode(%d bytes): %v", tt.length, err)
+			continue
+		}
+		if c.Version != tt.version || c.Size != tt.version*4+17 {
+			t.Errorf("Encode(%d bytes) = version %d size %d, want version %d", tt.length, c.Version, c.Size, tt.version)
+		}
+	}
+	if _, err := Encode(bytes.Repeat([]byte("x"), 214)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode(214 bytes): got %v, want ErrTooLong", err)
+	}
+}
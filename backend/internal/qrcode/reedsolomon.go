@@ -0,0 +1,196 @@
+package qrcode
+
+import "errors"
+
+// gfMultiply multiplies two elements of GF(2^8) modulo the QR Code polynomial x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// reedSolomonDivisor returns the coefficients of the generator polynomial of the given degree,
+// highest power first and excluding the leading 1.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	var root byte = 1
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error-correction codewords of data for the given divisor.
+func reedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfExp and gfLog are exponent and logarithm tables of GF(2^8) for the generator 0x02.
+// gfExp is doubled in length so that sums of two logarithms need no reduction.
+var gfExp, gfLog = gfTables()
+
+func gfTables() (exp [512]byte, log [256]byte) {
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < len(exp); i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}
+
+func gfMul(x, y byte) byte {
+	if x == 0 || y == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[x])+int(gfLog[y])]
+}
+
+func gfDiv(x, y byte) byte {
+	if x == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[x])+255-int(gfLog[y])]
+}
+
+// gfPow returns 0x02 raised to the power n.
+func gfPow(n int) byte {
+	return gfExp[(n%255+255)%255]
+}
+
+// evalHighFirst evaluates a polynomial whose coefficients are ordered from the highest power down.
+func evalHighFirst(poly []byte, x byte) byte {
+	var y byte
+	for _, coef := range poly {
+		y = gfMul(y, x) ^ coef
+	}
+	return y
+}
+
+// evalLowFirst evaluates a polynomial whose coefficients are ordered from the constant term up.
+func evalLowFirst(poly []byte, x byte) byte {
+	var y byte
+	for i := len(poly) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ poly[i]
+	}
+	return y
+}
+
+// errTooManyErrors is returned when a block has more errors than its error-correction codewords can fix.
+var errTooManyErrors = errors.New("qrcode: too many errors to correct")
+
+// reedSolomonCorrect fixes errors in place in a block of data codewords followed by ecLen error-correction
+// codewords, using Berlekamp-Massey to find the error locator and Forney's formula for the error values.
+func reedSolomonCorrect(block []byte, ecLen int) error {
+	syndromes := make([]byte, ecLen)
+	clean := true
+	for j := range syndromes {
+		syndromes[j] = evalHighFirst(block, gfPow(j))
+		if syndromes[j] != 0 {
+			clean = false
+		}
+	}
+	if clean {
+		return nil
+	}
+
+	// Berlekamp-Massey: locator is the error locator polynomial, constant term first.
+	locator := make([]byte, ecLen+1)
+	locator[0] = 1
+	previous := make([]byte, ecLen+1)
+	previous[0] = 1
+	errorCount, shift := 0, 1
+	var previousDiscrepancy byte = 1
+	for n := 0; n < ecLen; n++ {
+		discrepancy := syndromes[n]
+		for i := 1; i <= errorCount; i++ {
+			discrepancy ^= gfMul(locator[i], syndromes[n-i])
+		}
+		if discrepancy == 0 {
+			shift++
+			continue
+		}
+		saved := append([]byte(nil), locator...)
+		coef := gfDiv(discrepancy, previousDiscrepancy)
+		for i := 0; i+shift < len(locator); i++ {
+			locator[i+shift] ^= gfMul(coef, previous[i])
+		}
+		if 2*errorCount <= n {
+			errorCount = n + 1 - errorCount
+			previous = saved
+			previousDiscrepancy = discrepancy
+			shift = 1
+		} else {
+			shift++
+		}
+	}
+	if errorCount > ecLen/2 {
+		return errTooManyErrors
+	}
+	locator = locator[:errorCount+1]
+
+	// The error evaluator is syndromes(x) * locator(x) mod x^ecLen; the derivative of the locator keeps odd powers.
+	evaluator := make([]byte, ecLen)
+	for k := range evaluator {
+		for i := 0; i <= min(k, errorCount); i++ {
+			evaluator[k] ^= gfMul(locator[i], syndromes[k-i])
+		}
+	}
+	derivative := make([]byte, errorCount)
+	for i := 1; i <= errorCount; i++ {
+		if i%2 == 1 {
+			derivative[i-1] = locator[i]
+		}
+	}
+
+	// Chien search: the codeword at position p has degree len(block)-1-p and locator root 2^-degree.
+	found := 0
+	for p := range block {
+		degree := len(block) - 1 - p
+		inverse := gfPow(-degree)
+		if evalLowFirst(locator, inverse) != 0 {
+			continue
+		}
+		denominator := evalLowFirst(derivative, inverse)
+		if denominator == 0 {
+			return errTooManyErrors
+		}
+		block[p] ^= gfMul(gfPow(degree), gfDiv(evalLowFirst(evaluator, inverse), denominator))
+		found++
+	}
+	if found != errorCount {
+		return errTooManyErrors
+	}
+
+	for j := 0; j < ecLen; j++ {
+		if evalHighFirst(block, gfPow(j)) != 0 {
+			return errTooManyErrors
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository defines the interface for the append-only audit log.
+// There are deliberately no update or delete methods.
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	List(ctx context.Context, filter models.AuditLogFilter, limit int, offset int) ([]models.AuditLog, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new AuditLogRepository.
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// Create appends an entry to the audit log, as part of the caller's transaction if there is one.
+func (r *auditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	return getDB(ctx, r.db).Create(entry).Error
+}
+
+// List retrieves a page of audit log entries matching the filter, newest first.
+func (r *auditLogRepository) List(ctx context.Context, filter models.AuditLogFilter, limit int, offset int) ([]models.AuditLog, error) {
+	query := getDB(ctx, r.db)
+	if filter.HostedSubscriptionID != nil {
+		query = query.Where("hosted_subscription_id = ?", *filter.HostedSubscriptionID)
+	}
+	if filter.ActorUserID != nil {
+		query = query.Where("actor_user_id = ?", *filter.ActorUserID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var entries []models.AuditLog
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&entries).Error
+	return entries, err
+}
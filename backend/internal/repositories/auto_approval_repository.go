@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// AutoApprovalRepository defines methods for auto-approval rules and the log of records they approved.
+type AutoApprovalRepository interface {
+	CreateRule(ctx context.Context, rule *models.AutoApprovalRule) error
+	GetRuleByID(ctx context.Context, id uint) (*models.AutoApprovalRule, error)
+	ListRulesBySubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.AutoApprovalRule, error)
+	UpdateRule(ctx context.Context, rule *models.AutoApprovalRule) error
+	DeleteRule(ctx context.Context, id uint) error
+	CreateApproval(ctx context.Context, approval *models.AutoApproval) error
+	GetApprovalByPaymentRecordID(ctx context.Context, paymentRecordID uint) (*models.AutoApproval, error)
+	ListApprovalsBySubscriptionID(ctx context.Context, hostedSubscriptionID uint, limit int, offset int) ([]models.AutoApproval, error)
+	MarkApprovalReverted(ctx context.Context, id uint, revertedByUserID uint, revertedAt time.Time) error
+}
+
+type autoApprovalRepository struct {
+	db *gorm.DB
+}
+
+// NewAutoApprovalRepository creates a new AutoApprovalRepository.
+func NewAutoApprovalRepository(db *gorm.DB) AutoApprovalRepository {
+	return &autoApprovalRepository{db: db}
+}
+
+// CreateRule persists a new auto-approval rule.
+func (r *autoApprovalRepository) CreateRule(ctx context.Context, rule *models.AutoApprovalRule) error {
+	return getDB(ctx, r.db).Create(rule).Error
+}
+
+// GetRuleByID retrieves an auto-approval rule by its ID.
+func (r *autoApprovalRepository) GetRuleByID(ctx context.Context, id uint) (*models.AutoApprovalRule, error) {
+	var rule models.AutoApprovalRule
+	err := getDB(ctx, r.db).First(&rule, id).Error
+	return &rule, err
+}
+
+// ListRulesBySubscriptionID retrieves a hosted subscription's auto-approval rules in the order they are evaluated.
+func (r *autoApprovalRepository) ListRulesBySubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.AutoApprovalRule, error) {
+	var rules []models.AutoApprovalRule
+	err := getDB(ctx, r.db).
+		Where("hosted_subscription_id = ?", hostedSubscriptionID).
+		Order("id asc").
+		Find(&rules).Error
+	return rules, err
+}
+
+// UpdateRule saves all fields of an auto-approval rule.
+func (r *autoApprovalRepository) UpdateRule(ctx context.Context, rule *models.AutoApprovalRule) error {
+	return getDB(ctx, r.db).Save(rule).Error
+}
+
+// DeleteRule removes an auto-approval rule. The log keeps the name and conditions of the records it approved.
+func (r *autoApprovalRepository) DeleteRule(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.AutoApprovalRule{}, id).Error
+}
+
+// CreateApproval logs a payment record approved by a rule.
+func (r *autoApprovalRepository) CreateApproval(ctx context.Context, approval *models.AutoApproval) error {
+	return getDB(ctx, r.db).Create(approval).Error
+}
+
+// GetApprovalByPaymentRecordID retrieves the auto-approval of a payment record.
+func (r *autoApprovalRepository) GetApprovalByPaymentRecordID(ctx context.Context, paymentRecordID uint) (*models.AutoApproval, error) {
+	var approval models.AutoApproval
+	err := getDB(ctx, r.db).Where("payment_record_id = ?", paymentRecordID).First(&approval).Error
+	return &approval, err
+}
+
+// ListApprovalsBySubscriptionID retrieves a page of a hosted subscription's auto-approvals, newest first.
+func (r *autoApprovalRepository) ListApprovalsBySubscriptionID(ctx context.Context, hostedSubscriptionID uint, limit int, offset int) ([]models.AutoApproval, error) {
+	var approvals []models.AutoApproval
+	err := getDB(ctx, r.db).
+		Where("hosted_subscription_id = ?", hostedSubscriptionID).
+		Order("id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&approvals).Error
+	return approvals, err
+}
+
+// MarkApprovalReverted records that the host reverted an auto-approval.
+func (r *autoApprovalRepository) MarkApprovalReverted(ctx context.Context, id uint, revertedByUserID uint, revertedAt time.Time) error {
+	return getDB(ctx, r.db).Model(&models.AutoApproval{}).Where("id = ?", id).Updates(map[string]any{
+		"reverted_at":         revertedAt,
+		"reverted_by_user_id": revertedByUserID,
+	}).Error
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// CalendarFeedRepository defines methods for users' calendar feeds.
+type CalendarFeedRepository interface {
+	Create(ctx context.Context, feed *models.CalendarFeed) error
+	GetByUserID(ctx context.Context, userID uint) (*models.CalendarFeed, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeed, error)
+	UpdateTokenHash(ctx context.Context, id uint, tokenHash string) error
+	MarkFetched(ctx context.Context, id uint, at time.Time) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type calendarFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedRepository creates a new CalendarFeedRepository.
+func NewCalendarFeedRepository(db *gorm.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+// Create persists a new CalendarFeed.
+func (r *calendarFeedRepository) Create(ctx context.Context, feed *models.CalendarFeed) error {
+	return getDB(ctx, r.db).Create(feed).Error
+}
+
+// GetByUserID retrieves the calendar feed of a user.
+func (r *calendarFeedRepository) GetByUserID(ctx context.Context, userID uint) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := getDB(ctx, r.db).Where("user_id = ?", userID).First(&feed).Error
+	return &feed, err
+}
+
+// GetByTokenHash retrieves the calendar feed whose token hashes to tokenHash.
+func (r *calendarFeedRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeed, error) {
+	var feed models.CalendarFeed
+	err := getDB(ctx, r.db).Where("token_hash = ?", tokenHash).First(&feed).Error
+	return &feed, err
+}
+
+// UpdateTokenHash replaces the token of a calendar feed, which stops the old feed URL from working.
+func (r *calendarFeedRepository) UpdateTokenHash(ctx context.Context, id uint, tokenHash string) error {
+	return getDB(ctx, r.db).Model(&models.CalendarFeed{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"token_hash": tokenHash, "last_fetched_at": nil}).Error
+}
+
+// MarkFetched records when a calendar app last fetched the feed.
+func (r *calendarFeedRepository) MarkFetched(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.CalendarFeed{}).
+		Where("id = ?", id).
+		UpdateColumn("last_fetched_at", at).Error
+}
+
+// Delete removes a CalendarFeed.
+func (r *calendarFeedRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.CalendarFeed{}, id).Error
+}
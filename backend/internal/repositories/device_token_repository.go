@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeviceTokenRepository defines methods for DeviceToken data.
+type DeviceTokenRepository interface {
+	Upsert(ctx context.Context, dt *models.DeviceToken) error
+	GetByID(ctx context.Context, id uint) (*models.DeviceToken, error)
+	ListByUserID(ctx context.Context, userID uint) ([]models.DeviceToken, error)
+	Delete(ctx context.Context, id uint) error
+	DeleteByToken(ctx context.Context, token string) error
+}
+
+type deviceTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceTokenRepository creates a new DeviceTokenRepository.
+func NewDeviceTokenRepository(db *gorm.DB) DeviceTokenRepository {
+	return &deviceTokenRepository{db: db}
+}
+
+// Upsert registers a device token, moving it to the given user if it was registered by someone else.
+func (r *deviceTokenRepository) Upsert(ctx context.Context, dt *models.DeviceToken) error {
+	return getDB(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "provider", "last_seen_at", "updated_at"}),
+	}).Create(dt).Error
+}
+
+// GetByID retrieves a specific DeviceToken by its ID.
+func (r *deviceTokenRepository) GetByID(ctx context.Context, id uint) (*models.DeviceToken, error) {
+	var dt models.DeviceToken
+	err := getDB(ctx, r.db).First(&dt, id).Error
+	return &dt, err
+}
+
+// ListByUserID retrieves all device tokens registered by a user.
+func (r *deviceTokenRepository) ListByUserID(ctx context.Context, userID uint) ([]models.DeviceToken, error) {
+	var tokens []models.DeviceToken
+	err := getDB(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("last_seen_at desc").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// Delete removes a device token by its ID.
+func (r *deviceTokenRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.DeviceToken{}, id).Error
+}
+
+// DeleteByToken removes a device token by its token value.
+func (r *deviceTokenRepository) DeleteByToken(ctx context.Context, token string) error {
+	return getDB(ctx, r.db).Where("token = ?", token).Delete(&models.DeviceToken{}).Error
+}
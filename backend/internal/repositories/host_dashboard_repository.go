@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// HostDashboardRepository defines the aggregate queries behind the host dashboard.
+type HostDashboardRepository interface {
+	SubscriptionSummaries(ctx context.Context, hostUserID uint, cycleStart time.Time, cycleEnd time.Time, overdueBefore time.Time) ([]models.HostDashboardSubscription, error)
+	ActionItems(ctx context.Context, hostUserID uint, overdueBefore time.Time, limit int) ([]models.HostDashboardActionItem, error)
+}
+
+type hostDashboardRepository struct {
+	db *gorm.DB
+}
+
+// NewHostDashboardRepository creates a new HostDashboardRepository.
+func NewHostDashboardRepository(db *gorm.DB) HostDashboardRepository {
+	return &hostDashboardRepository{db: db}
+}
+
+// SubscriptionSummaries aggregates every subscription of a host in one query. A cycle counts towards the
+// expected revenue when it falls due in [cycleStart, cycleEnd), whether it is still a member's next payment
+// date or was already paid; collected revenue is what payments allocated to those cycles.
+// Active members whose next payment date is before overdueBefore and who have no proof awaiting review are overdue.
+func (r *hostDashboardRepository) SubscriptionSummaries(ctx context.Context, hostUserID uint, cycleStart time.Time, cycleEnd time.Time, overdueBefore time.Time) ([]models.HostDashboardSubscription, error) {
+	var rows []models.HostDashboardSubscription
+	err := getDB(ctx, r.db).Raw(`
+		WITH owned AS (
+			SELECT id, subscription_title, total_slots, cost_per_cycle
+			FROM hosted_subscriptions
+			WHERE host_user_id = @host
+		), members AS (
+			SELECT m.hosted_subscription_id,
+				COUNT(*) AS members_count,
+				COUNT(*) FILTER (WHERE m.next_payment_date < @overdue_before AND m.payment_status <> @membership_proof_submitted) AS overdue_members,
+				COALESCE(SUM(-m.balance) FILTER (WHERE m.balance < 0), 0) AS outstanding_amount
+			FROM subscription_memberships m
+			JOIN owned o ON o.id = m.hosted_subscription_id
+			WHERE m.deleted_at IS NULL
+			GROUP BY m.hosted_subscription_id
+		), requests AS (
+			SELECT jr.hosted_subscription_id, COUNT(*) AS pending_join_requests
+			FROM join_requests jr
+			JOIN owned o ON o.id = jr.hosted_subscription_id
+			WHERE jr.status = @pending
+			GROUP BY jr.hosted_subscription_id
+		), proofs AS (
+			SELECT m.hosted_subscription_id,
+				COUNT(*) FILTER (WHERE pr.status = @proof_submitted) AS pending_payment_proofs,
+				COUNT(*) FILTER (WHERE pr.status = @disputed) AS disputed_payments
+			FROM payment_records pr
+			JOIN subscription_memberships m ON m.id = pr.subscription_membership_id
+			JOIN owned o ON o.id = m.hosted_subscription_id
+			WHERE pr.status IN (@proof_submitted, @disputed)
+			GROUP BY m.hosted_subscription_id
+		), cycle_allocations AS (
+			SELECT m.hosted_subscription_id, a.subscription_membership_id, a.cycle_due_date, a.amount
+			FROM payment_cycle_allocations a
+			JOIN subscription_memberships m ON m.id = a.subscription_membership_id
+			JOIN owned o ON o.id = m.hosted_subscription_id
+			WHERE a.cycle_due_date >= @cycle_start AND a.cycle_due_date < @cycle_end
+		), cycles_due AS (
+			SELECT hosted_subscription_id, COUNT(*) AS cycles
+			FROM (
+				SELECT hosted_subscription_id, subscription_membership_id, cycle_due_date FROM cycle_allocations
+				UNION
+				SELECT m.hosted_subscription_id, m.id, m.next_payment_date
+				FROM subscription_memberships m
+				JOIN owned o ON o.id = m.hosted_subscription_id
+				WHERE m.deleted_at IS NULL AND m.next_payment_date >= @cycle_start AND m.next_payment_date < @cycle_end
+			) due
+			GROUP BY hosted_subscription_id
+		), collected AS (
+			SELECT hosted_subscription_id, SUM(amount) AS collected_revenue
+			FROM cycle_allocations
+			GROUP BY hosted_subscription_id
+		)
+		SELECT o.id AS hosted_subscription_id,
+			o.subscription_title,
+			o.total_slots,
+			COALESCE(mb.members_count, 0) AS members_count,
+			COALESCE(rq.pending_join_requests, 0) AS pending_join_requests,
+			COALESCE(pf.pending_payment_proofs, 0) AS pending_payment_proofs,
+			COALESCE(pf.disputed_payments, 0) AS disputed_payments,
+			COALESCE(mb.overdue_members, 0) AS overdue_members,
+			COALESCE(mb.outstanding_amount, 0) AS outstanding_amount,
+			COALESCE(cd.cycles * o.cost_per_cycle / NULLIF(o.total_slots, 0), 0) AS expected_revenue,
+			COALESCE(c.collected_revenue, 0) AS collected_revenue
+		FROM owned o
+		LEFT JOIN members mb ON mb.hosted_subscription_id = o.id
+		LEFT JOIN requests rq ON rq.hosted_subscription_id = o.id
+		LEFT JOIN proofs pf ON pf.hosted_subscription_id = o.id
+		LEFT JOIN cycles_due cd ON cd.hosted_subscription_id = o.id
+		LEFT JOIN collected c ON c.hosted_subscription_id = o.id
+		ORDER BY o.id ASC`,
+		map[string]interface{}{
+			"host":                       hostUserID,
+			"overdue_before":             overdueBefore,
+			"membership_proof_submitted": models.PaymentStatusProofSubmitted,
+			"pending":                    models.JoinRequestStatusPending,
+			"proof_submitted":            models.PaymentRecordStatusProofSubmitted,
+			"disputed":                   models.PaymentRecordStatusDisputed,
+			"cycle_start":                cycleStart,
+			"cycle_end":                  cycleEnd,
+		},
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// ActionItems lists, oldest first, the pending join requests, payment proofs awaiting review, disputed payments
+// and overdue members across a host's subscriptions. An overdue member owes their outstanding balance, or
+// otherwise their share less any credit.
+func (r *hostDashboardRepository) ActionItems(ctx context.Context, hostUserID uint, overdueBefore time.Time, limit int) ([]models.HostDashboardActionItem, error) {
+	var items []models.HostDashboardActionItem
+	err := getDB(ctx, r.db).Raw(`
+		SELECT @join_request_item AS type, jr.id AS entity_id, jr.hosted_subscription_id, hs.subscription_title,
+			u.full_name AS user_name, NULL::double precision AS amount, jr.request_date AS since
+		FROM join_requests jr
+		JOIN hosted_subscriptions hs ON hs.id = jr.hosted_subscription_id
+		JOIN users u ON u.id = jr.requester_user_id
+		WHERE hs.host_user_id = @host AND jr.status = @pending
+		UNION ALL
+		SELECT CASE WHEN pr.status = @disputed THEN @disputed_item ELSE @proof_item END, pr.id, m.hosted_subscription_id,
+			hs.subscription_title, u.full_name, pr.amount_paid, COALESCE(pr.disputed_at, pr.submitted_at)
+		FROM payment_records pr
+		JOIN subscription_memberships m ON m.id = pr.subscription_membership_id
+		JOIN hosted_subscriptions hs ON hs.id = m.hosted_subscription_id
+		JOIN users u ON u.id = m.member_user_id
+		WHERE hs.host_user_id = @host AND pr.status IN (@proof_submitted, @disputed)
+		UNION ALL
+		SELECT @overdue_item, m.id, m.hosted_subscription_id, hs.subscription_title, u.full_name,
+			CASE WHEN m.balance < 0 THEN -m.balance
+				ELSE GREATEST(COALESCE(hs.cost_per_cycle / NULLIF(hs.total_slots, 0), 0) - m.balance, 0) END,
+			m.next_payment_date
+		FROM subscription_memberships m
+		JOIN hosted_subscriptions hs ON hs.id = m.hosted_subscription_id
+		JOIN users u ON u.id = m.member_user_id
+		WHERE hs.host_user_id = @host AND m.deleted_at IS NULL
+			AND m.next_payment_date < @overdue_before AND m.payment_status <> @membership_proof_submitted
+		ORDER BY since ASC, entity_id ASC
+		LIMIT @limit`,
+		map[string]interface{}{
+			"host":                       hostUserID,
+			"join_request_item":          models.HostDashboardActionJoinRequest,
+			"proof_item":                 models.HostDashboardActionPaymentProof,
+			"disputed_item":              models.HostDashboardActionDisputedPayment,
+			"overdue_item":               models.HostDashboardActionOverdueMember,
+			"pending":                    models.JoinRequestStatusPending,
+			"proof_submitted":            models.PaymentRecordStatusProofSubmitted,
+			"disputed":                   models.PaymentRecordStatusDisputed,
+			"overdue_before":             overdueBefore,
+			"membership_proof_submitted": models.PaymentStatusProofSubmitted,
+			"limit":                      limit,
+		},
+	).Scan(&items).Error
+	return items, err
+}
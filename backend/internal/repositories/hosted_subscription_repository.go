@@ -4,7 +4,9 @@ import (
 	"context"
 	"github.com/xNatthapol/hubster/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
+	"time"
 )
 
 // HostedSubscriptionRepository defines methods for HostedSubscription data.
@@ -13,6 +15,12 @@ type HostedSubscriptionRepository interface {
 	ListByHostID(ctx context.Context, hostID uint) ([]models.HostedSubscription, error)
 	ListFiltered(ctx context.Context, filters *models.ExploreSubscriptionFilters, sortBy string) ([]models.HostedSubscription, error)
 	GetByID(ctx context.Context, id uint) (*models.HostedSubscription, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*models.HostedSubscription, error)
+	ListHostUserIDs(ctx context.Context) ([]uint, error)
+	UpdatePromptPayID(ctx context.Context, id uint, promptPayID string) error
+	UpdateRenewalDate(ctx context.Context, id uint, renewalDate *time.Time) error
+	UpdateVisibility(ctx context.Context, id uint, visibility models.SubscriptionVisibility) error
+	UpdateTotalSlots(ctx context.Context, id uint, totalSlots int) error
 }
 
 type hostedSubscriptionRepository struct {
@@ -26,13 +34,13 @@ func NewHostedSubscriptionRepository(db *gorm.DB) HostedSubscriptionRepository {
 
 // Create persists a new HostedSubscription.
 func (r *hostedSubscriptionRepository) Create(ctx context.Context, hs *models.HostedSubscription) error {
-	return r.db.WithContext(ctx).Create(hs).Error
+	return getDB(ctx, r.db).Create(hs).Error
 }
 
 // ListByHostID retrieves all hosted subscriptions for a given host ID.
 func (r *hostedSubscriptionRepository) ListByHostID(ctx context.Context, hostID uint) ([]models.HostedSubscription, error) {
 	var subscriptions []models.HostedSubscription
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Preload("SubscriptionService").
 		Preload("Memberships.User").
 		Preload("User").
@@ -42,10 +50,11 @@ func (r *hostedSubscriptionRepository) ListByHostID(ctx context.Context, hostID
 	return subscriptions, err
 }
 
-// ListFiltered retrieves a filtered and sorted list of hosted subscriptions.
+// ListFiltered retrieves a filtered and sorted list of public hosted subscriptions.
 func (r *hostedSubscriptionRepository) ListFiltered(ctx context.Context, filters *models.ExploreSubscriptionFilters, sortBy string) ([]models.HostedSubscription, error) {
 	var subscriptions []models.HostedSubscription
-	query := r.db.WithContext(ctx).Model(&models.HostedSubscription{})
+	query := getDB(ctx, r.db).Model(&models.HostedSubscription{}).
+		Where("visibility = ?", models.VisibilityPublic)
 
 	// Apply filters
 	if filters != nil {
@@ -89,10 +98,59 @@ func (r *hostedSubscriptionRepository) ListFiltered(ctx context.Context, filters
 // GetByID retrieves a specific hosted subscription by its ID.
 func (r *hostedSubscriptionRepository) GetByID(ctx context.Context, id uint) (*models.HostedSubscription, error) {
 	var hs models.HostedSubscription
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Preload("SubscriptionService").
 		Preload("Memberships.User").
 		Preload("User").
 		First(&hs, id).Error
 	return &hs, err
 }
+
+// GetByIDForUpdate retrieves a hosted subscription with its members and locks its row until the surrounding
+// transaction ends, so changes to who takes its slots are made one after another.
+func (r *hostedSubscriptionRepository) GetByIDForUpdate(ctx context.Context, id uint) (*models.HostedSubscription, error) {
+	var hs models.HostedSubscription
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Memberships").
+		First(&hs, id).Error
+	return &hs, err
+}
+
+// ListHostUserIDs retrieves the IDs of all users hosting at least one subscription.
+func (r *hostedSubscriptionRepository) ListHostUserIDs(ctx context.Context) ([]uint, error) {
+	var hostIDs []uint
+	err := getDB(ctx, r.db).Model(&models.HostedSubscription{}).
+		Distinct("host_user_id").
+		Order("host_user_id").
+		Pluck("host_user_id", &hostIDs).Error
+	return hostIDs, err
+}
+
+// UpdatePromptPayID sets the PromptPay ID of a hosted subscription; an empty ID turns PromptPay off.
+func (r *hostedSubscriptionRepository) UpdatePromptPayID(ctx context.Context, id uint, promptPayID string) error {
+	return getDB(ctx, r.db).Model(&models.HostedSubscription{}).
+		Where("id = ?", id).
+		Update("prompt_pay_id", promptPayID).Error
+}
+
+// UpdateRenewalDate sets the renewal date of a hosted subscription; a nil date clears it.
+func (r *hostedSubscriptionRepository) UpdateRenewalDate(ctx context.Context, id uint, renewalDate *time.Time) error {
+	return getDB(ctx, r.db).Model(&models.HostedSubscription{}).
+		Where("id = ?", id).
+		Update("renewal_date", renewalDate).Error
+}
+
+// UpdateVisibility sets who can find and join a hosted subscription.
+func (r *hostedSubscriptionRepository) UpdateVisibility(ctx context.Context, id uint, visibility models.SubscriptionVisibility) error {
+	return getDB(ctx, r.db).Model(&models.HostedSubscription{}).
+		Where("id = ?", id).
+		Update("visibility", visibility).Error
+}
+
+// UpdateTotalSlots sets how many people, host included, share a hosted subscription.
+func (r *hostedSubscriptionRepository) UpdateTotalSlots(ctx context.Context, id uint, totalSlots int) error {
+	return getDB(ctx, r.db).Model(&models.HostedSubscription{}).
+		Where("id = ?", id).
+		Update("total_slots", totalSlots).Error
+}
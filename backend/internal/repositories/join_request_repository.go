@@ -4,14 +4,19 @@ import (
 	"context"
 	"github.com/xNatthapol/hubster/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // JoinRequestRepository defines methods for JoinRequest data.
 type JoinRequestRepository interface {
 	Create(ctx context.Context, jr *models.JoinRequest) error
 	FindPendingByRequesterAndSubscription(ctx context.Context, requesterID uint, subscriptionID uint) (*models.JoinRequest, error)
+	FindByRequesterAndSubscription(ctx context.Context, requesterID uint, subscriptionID uint) (*models.JoinRequest, error)
 	GetByID(ctx context.Context, id uint) (*models.JoinRequest, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*models.JoinRequest, error)
 	UpdateStatus(ctx context.Context, id uint, status models.JoinRequestStatus) error
+	Update(ctx context.Context, jr *models.JoinRequest) error
+	ReplaceAnswers(ctx context.Context, joinRequestID uint, answers []models.JoinRequestAnswer) error
 	ListBySubscriptionID(ctx context.Context, subscriptionID uint, statusFilter *models.JoinRequestStatus) ([]models.JoinRequest, error)
 	ListByRequesterID(ctx context.Context, requesterID uint) ([]models.JoinRequest, error)
 }
@@ -27,35 +32,73 @@ func NewJoinRequestRepository(db *gorm.DB) JoinRequestRepository {
 
 // Create persists a new JoinRequest.
 func (r *joinRequestRepository) Create(ctx context.Context, jr *models.JoinRequest) error {
-	return r.db.WithContext(ctx).Create(jr).Error
+	return getDB(ctx, r.db).Create(jr).Error
 }
 
 // FindPendingByRequesterAndSubscription checks if a user already has a pending request for a subscription.
 func (r *joinRequestRepository) FindPendingByRequesterAndSubscription(ctx context.Context, requesterID uint, subscriptionID uint) (*models.JoinRequest, error) {
 	var jr models.JoinRequest
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Where("requester_user_id = ? AND hosted_subscription_id = ? AND status = ?",
 			requesterID, subscriptionID, models.JoinRequestStatusPending).
 		First(&jr).Error
 	return &jr, err
 }
 
-// GetByID retrieves a specific JoinRequest by its ID, preloading the Requester User.
+// FindByRequesterAndSubscription retrieves a user's join request for a subscription, whatever its status.
+func (r *joinRequestRepository) FindByRequesterAndSubscription(ctx context.Context, requesterID uint, subscriptionID uint) (*models.JoinRequest, error) {
+	var jr models.JoinRequest
+	err := getDB(ctx, r.db).
+		Where("requester_user_id = ? AND hosted_subscription_id = ?", requesterID, subscriptionID).
+		First(&jr).Error
+	return &jr, err
+}
+
+// GetByID retrieves a specific JoinRequest by its ID, preloading the Requester User and their answers.
 func (r *joinRequestRepository) GetByID(ctx context.Context, id uint) (*models.JoinRequest, error) {
 	var jr models.JoinRequest
-	err := r.db.WithContext(ctx).Preload("User").First(&jr, id).Error
+	err := getDB(ctx, r.db).Preload("User").Preload("Answers", orderAnswers).First(&jr, id).Error
+	return &jr, err
+}
+
+// GetByIDForUpdate retrieves a JoinRequest and locks its row until the surrounding transaction ends, so it is
+// decided on only once.
+func (r *joinRequestRepository) GetByIDForUpdate(ctx context.Context, id uint) (*models.JoinRequest, error) {
+	var jr models.JoinRequest
+	err := getDB(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&jr, id).Error
 	return &jr, err
 }
 
 // UpdateStatus updates the status of a specific JoinRequest.
 func (r *joinRequestRepository) UpdateStatus(ctx context.Context, id uint, status models.JoinRequestStatus) error {
-	return r.db.WithContext(ctx).Model(&models.JoinRequest{}).Where("id = ?", id).Update("status", status).Error
+	return getDB(ctx, r.db).Model(&models.JoinRequest{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// Update saves changes to a JoinRequest. Its answers are left as they are; see ReplaceAnswers.
+func (r *joinRequestRepository) Update(ctx context.Context, jr *models.JoinRequest) error {
+	return getDB(ctx, r.db).Omit("User", "HostedSubscription", "Answers").Save(jr).Error
+}
+
+// ReplaceAnswers deletes the answers of a JoinRequest and persists the given ones in their place, setting their IDs.
+func (r *joinRequestRepository) ReplaceAnswers(ctx context.Context, joinRequestID uint, answers []models.JoinRequestAnswer) error {
+	db := getDB(ctx, r.db)
+	if err := db.Where("join_request_id = ?", joinRequestID).Delete(&models.JoinRequestAnswer{}).Error; err != nil {
+		return err
+	}
+	if len(answers) == 0 {
+		return nil
+	}
+	for i := range answers {
+		answers[i].ID = 0
+		answers[i].JoinRequestID = joinRequestID
+	}
+	return db.Create(&answers).Error
 }
 
 // ListBySubscriptionID retrieves join requests for a specific hosted subscription
 func (r *joinRequestRepository) ListBySubscriptionID(ctx context.Context, subscriptionID uint, statusFilter *models.JoinRequestStatus) ([]models.JoinRequest, error) {
 	var requests []models.JoinRequest
-	query := r.db.WithContext(ctx).Preload("User").Where("hosted_subscription_id = ?", subscriptionID)
+	query := getDB(ctx, r.db).Preload("User").Preload("Answers", orderAnswers).Where("hosted_subscription_id = ?", subscriptionID)
 
 	if statusFilter != nil && *statusFilter != "" {
 		query = query.Where("status = ?", *statusFilter)
@@ -68,11 +111,17 @@ func (r *joinRequestRepository) ListBySubscriptionID(ctx context.Context, subscr
 // ListByRequesterID retrieves all join requests made by a specific user.
 func (r *joinRequestRepository) ListByRequesterID(ctx context.Context, requesterID uint) ([]models.JoinRequest, error) {
 	var requests []models.JoinRequest
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Preload("User").
+		Preload("Answers", orderAnswers).
 		Preload("HostedSubscription.SubscriptionService").
 		Where("requester_user_id = ?", requesterID).
 		Order("created_at desc").
 		Find(&requests).Error
 	return requests, err
 }
+
+// orderAnswers preloads the answers of a JoinRequest in the order the questions were asked.
+func orderAnswers(db *gorm.DB) *gorm.DB {
+	return db.Order("id asc")
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// JournalRepository defines methods for the double-entry ledger and the reports built on it.
+type JournalRepository interface {
+	CreateEntry(ctx context.Context, entry *models.JournalEntry) error
+	ListEntriesByPaymentRecordID(ctx context.Context, paymentRecordID uint) ([]models.JournalEntry, error)
+	ListUnjournaledApprovedPayments(ctx context.Context, afterID uint, limit int) ([]models.PaymentRecord, error)
+	HostEarnings(ctx context.Context, hostUserID uint, from time.Time, to time.Time, timeZone string) ([]models.HostEarningsRow, error)
+	MemberSpend(ctx context.Context, memberUserID uint, year int, timeZone string) ([]models.MemberSpendRow, error)
+	Receivables(ctx context.Context, hostUserID uint, hostedSubscriptionID *uint) ([]models.ReceivableRow, error)
+}
+
+type journalRepository struct {
+	db *gorm.DB
+}
+
+// NewJournalRepository creates a new JournalRepository.
+func NewJournalRepository(db *gorm.DB) JournalRepository {
+	return &journalRepository{db: db}
+}
+
+// CreateEntry persists a journal entry together with its lines.
+func (r *journalRepository) CreateEntry(ctx context.Context, entry *models.JournalEntry) error {
+	return getDB(ctx, r.db).Create(entry).Error
+}
+
+// ListEntriesByPaymentRecordID retrieves the journal entries of a payment record with their lines, oldest first.
+func (r *journalRepository) ListEntriesByPaymentRecordID(ctx context.Context, paymentRecordID uint) ([]models.JournalEntry, error) {
+	var entries []models.JournalEntry
+	err := getDB(ctx, r.db).
+		Where("payment_record_id = ?", paymentRecordID).
+		Preload("Lines").
+		Order("id asc").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ListUnjournaledApprovedPayments retrieves approved payment records with an ID above afterID that have no
+// journal entries yet, including those of memberships that have since ended.
+func (r *journalRepository) ListUnjournaledApprovedPayments(ctx context.Context, afterID uint, limit int) ([]models.PaymentRecord, error) {
+	var records []models.PaymentRecord
+	err := getDB(ctx, r.db).
+		Where("id > ? AND status = ?", afterID, models.PaymentRecordStatusApproved).
+		Where("NOT EXISTS (SELECT 1 FROM journal_entries je WHERE je.payment_record_id = payment_records.id)").
+		Preload("SubscriptionMembership", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("SubscriptionMembership.HostedSubscription", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Order("id asc").
+		Limit(limit).
+		Find(&records).Error
+	return records, err
+}
+
+// HostEarnings sums a host's billed (earnings) and received (cash) amounts per subscription and month in [from, to).
+func (r *journalRepository) HostEarnings(ctx context.Context, hostUserID uint, from time.Time, to time.Time, timeZone string) ([]models.HostEarningsRow, error) {
+	var rows []models.HostEarningsRow
+	err := getDB(ctx, r.db).Raw(`
+		SELECT jl.hosted_subscription_id,
+			hs.subscription_title,
+			to_char(jl.occurred_at AT TIME ZONE ?, 'YYYY-MM') AS month,
+			COALESCE(SUM(CASE WHEN jl.account = ? THEN jl.credit - jl.debit ELSE 0 END), 0) AS earned,
+			COALESCE(SUM(CASE WHEN jl.account = ? THEN jl.debit - jl.credit ELSE 0 END), 0) AS collected
+		FROM journal_lines jl
+		JOIN hosted_subscriptions hs ON hs.id = jl.hosted_subscription_id
+		WHERE jl.host_user_id = ? AND jl.account IN ? AND jl.occurred_at >= ? AND jl.occurred_at < ?
+		GROUP BY jl.hosted_subscription_id, hs.subscription_title, month
+		ORDER BY month DESC, jl.hosted_subscription_id ASC`,
+		timeZone, models.LedgerAccountEarnings, models.LedgerAccountCash,
+		hostUserID, []models.LedgerAccount{models.LedgerAccountEarnings, models.LedgerAccountCash}, from, to,
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// MemberSpend sums what a member paid per subscription service in a calendar year.
+func (r *journalRepository) MemberSpend(ctx context.Context, memberUserID uint, year int, timeZone string) ([]models.MemberSpendRow, error) {
+	var rows []models.MemberSpendRow
+	err := getDB(ctx, r.db).Raw(`
+		SELECT jl.subscription_service_id,
+			ss.name AS service_name,
+			CAST(EXTRACT(YEAR FROM jl.occurred_at AT TIME ZONE ?) AS INTEGER) AS year,
+			COALESCE(SUM(jl.debit - jl.credit), 0) AS spent
+		FROM journal_lines jl
+		JOIN subscription_services ss ON ss.id = jl.subscription_service_id
+		WHERE jl.member_user_id = ? AND jl.account = ?
+			AND EXTRACT(YEAR FROM jl.occurred_at AT TIME ZONE ?) = ?
+		GROUP BY jl.subscription_service_id, ss.name, year
+		ORDER BY spent DESC, jl.subscription_service_id ASC`,
+		timeZone, memberUserID, models.LedgerAccountCash, timeZone, year,
+	).Scan(&rows).Error
+	return rows, err
+}
+
+// Receivables lists the memberships of a host whose receivable account has a debit balance.
+func (r *journalRepository) Receivables(ctx context.Context, hostUserID uint, hostedSubscriptionID *uint) ([]models.ReceivableRow, error) {
+	var rows []models.ReceivableRow
+	query := getDB(ctx, r.db).
+		Table("journal_lines jl").
+		Select(`jl.subscription_membership_id,
+			jl.hosted_subscription_id,
+			hs.subscription_title,
+			jl.member_user_id,
+			u.full_name AS member_name,
+			SUM(jl.debit - jl.credit) AS outstanding`).
+		Joins("JOIN hosted_subscriptions hs ON hs.id = jl.hosted_subscription_id").
+		Joins("JOIN users u ON u.id = jl.member_user_id").
+		Where("jl.host_user_id = ? AND jl.account = ?", hostUserID, models.LedgerAccountReceivable)
+	if hostedSubscriptionID != nil {
+		query = query.Where("jl.hosted_subscription_id = ?", *hostedSubscriptionID)
+	}
+	err := query.
+		Group("jl.subscription_membership_id, jl.hosted_subscription_id, hs.subscription_title, jl.member_user_id, u.full_name").
+		Having("SUM(jl.debit - jl.credit) > ?", 0.005).
+		Order("outstanding DESC").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// MembershipLedgerRepository defines methods for the balance ledgers of memberships.
+type MembershipLedgerRepository interface {
+	Create(ctx context.Context, entry *models.MembershipLedgerEntry) error
+	ListByMembershipID(ctx context.Context, membershipID uint, limit int, offset int) ([]models.MembershipLedgerEntry, error)
+	GetLatestByMembershipID(ctx context.Context, membershipID uint) (*models.MembershipLedgerEntry, error)
+	CreateAllocation(ctx context.Context, allocation *models.PaymentCycleAllocation) error
+	DeleteAllocationsByPaymentRecordID(ctx context.Context, paymentRecordID uint) error
+	EachStatementLine(ctx context.Context, hostedSubscriptionID uint, membershipID *uint, from time.Time, to time.Time, fn func(line *models.StatementLine) error) error
+}
+
+type membershipLedgerRepository struct {
+	db *gorm.DB
+}
+
+// NewMembershipLedgerRepository creates a new MembershipLedgerRepository.
+func NewMembershipLedgerRepository(db *gorm.DB) MembershipLedgerRepository {
+	return &membershipLedgerRepository{db: db}
+}
+
+// Create appends an entry to a membership's ledger.
+func (r *membershipLedgerRepository) Create(ctx context.Context, entry *models.MembershipLedgerEntry) error {
+	return getDB(ctx, r.db).Create(entry).Error
+}
+
+// ListByMembershipID retrieves a page of a membership's ledger, newest first.
+func (r *membershipLedgerRepository) ListByMembershipID(ctx context.Context, membershipID uint, limit int, offset int) ([]models.MembershipLedgerEntry, error) {
+	var entries []models.MembershipLedgerEntry
+	err := getDB(ctx, r.db).
+		Where("subscription_membership_id = ?", membershipID).
+		Order("created_at desc, id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error
+	return entries, err
+}
+
+// GetLatestByMembershipID retrieves the last entry appended to a membership's ledger.
+func (r *membershipLedgerRepository) GetLatestByMembershipID(ctx context.Context, membershipID uint) (*models.MembershipLedgerEntry, error) {
+	var entry models.MembershipLedgerEntry
+	err := getDB(ctx, r.db).
+		Where("subscription_membership_id = ?", membershipID).
+		Order("id desc").
+		First(&entry).Error
+	return &entry, err
+}
+
+// CreateAllocation records the share of a payment that went to one cycle.
+func (r *membershipLedgerRepository) CreateAllocation(ctx context.Context, allocation *models.PaymentCycleAllocation) error {
+	return getDB(ctx, r.db).Create(allocation).Error
+}
+
+// DeleteAllocationsByPaymentRecordID removes the cycle allocations of a payment whose approval was reverted.
+func (r *membershipLedgerRepository) DeleteAllocationsByPaymentRecordID(ctx context.Context, paymentRecordID uint) error {
+	return getDB(ctx, r.db).Where("payment_record_id = ?", paymentRecordID).Delete(&models.PaymentCycleAllocation{}).Error
+}
+
+// EachStatementLine calls fn with each ledger entry made in [from, to) on the memberships of a hosted subscription,
+// including members who have left, or on one of its memberships. Entries are grouped by membership and read one
+// row at a time. It stops at the first error fn returns.
+func (r *membershipLedgerRepository) EachStatementLine(ctx context.Context, hostedSubscriptionID uint, membershipID *uint, from time.Time, to time.Time, fn func(line *models.StatementLine) error) error {
+	query := getDB(ctx, r.db).
+		Table("membership_ledger_entries e").
+		Select("e.id, e.created_at, e.subscription_membership_id, u.full_name AS member_name, e.kind, e.amount, e.balance_after, e.description").
+		Joins("JOIN subscription_memberships sm ON sm.id = e.subscription_membership_id").
+		Joins("JOIN users u ON u.id = sm.member_user_id").
+		Where("sm.hosted_subscription_id = ? AND e.created_at >= ? AND e.created_at < ?", hostedSubscriptionID, from, to)
+	if membershipID != nil {
+		query = query.Where("e.subscription_membership_id = ?", *membershipID)
+	}
+
+	rows, err := query.Order("u.full_name ASC, e.subscription_membership_id ASC, e.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.StatementLine
+		if err := r.db.ScanRows(rows, &line); err != nil {
+			return err
+		}
+		if err := fn(&line); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
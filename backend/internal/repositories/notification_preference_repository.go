@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// NotificationPreferenceRepository defines methods for NotificationPreference data.
+type NotificationPreferenceRepository interface {
+	GetByUserID(ctx context.Context, userID uint) (*models.NotificationPreference, error)
+	Save(ctx context.Context, pref *models.NotificationPreference) error
+	CreateIfMissing(ctx context.Context, pref *models.NotificationPreference) error
+	ClaimDigest(ctx context.Context, userID uint, periodEnd time.Time, sentAt time.Time) (bool, error)
+}
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository creates a new NotificationPreferenceRepository.
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+// GetByUserID retrieves the stored preferences of a user.
+func (r *notificationPreferenceRepository) GetByUserID(ctx context.Context, userID uint) (*models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	err := getDB(ctx, r.db).Where("user_id = ?", userID).First(&pref).Error
+	return &pref, err
+}
+
+// Save creates or updates a user's preferences.
+func (r *notificationPreferenceRepository) Save(ctx context.Context, pref *models.NotificationPreference) error {
+	return getDB(ctx, r.db).Save(pref).Error
+}
+
+// CreateIfMissing stores the preferences unless the user already has a stored row.
+func (r *notificationPreferenceRepository) CreateIfMissing(ctx context.Context, pref *models.NotificationPreference) error {
+	return getDB(ctx, r.db).Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).
+		Create(pref).Error
+}
+
+// ClaimDigest records sentAt as the user's last digest unless a digest was already sent at or after periodEnd.
+// It reports false if one was, so of concurrent senders only one claims the period.
+func (r *notificationPreferenceRepository) ClaimDigest(ctx context.Context, userID uint, periodEnd time.Time, sentAt time.Time) (bool, error) {
+	result := getDB(ctx, r.db).Model(&models.NotificationPreference{}).
+		Where("user_id = ? AND (last_digest_sent_at IS NULL OR last_digest_sent_at < ?)", userID, periodEnd).
+		Update("last_digest_sent_at", sentAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+// NotificationRepository defines methods for Notification data.
+type NotificationRepository interface {
+	Create(ctx context.Context, n *models.Notification) error
+	GetByID(ctx context.Context, id uint) (*models.Notification, error)
+	ListByUserID(ctx context.Context, userID uint, unreadOnly bool, limit int, offset int) ([]models.Notification, error)
+	CountUnreadByUserID(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, id uint) error
+	MarkAllReadByUserID(ctx context.Context, userID uint) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository.
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create persists a new Notification.
+func (r *notificationRepository) Create(ctx context.Context, n *models.Notification) error {
+	return getDB(ctx, r.db).Create(n).Error
+}
+
+// GetByID retrieves a specific Notification by its ID.
+func (r *notificationRepository) GetByID(ctx context.Context, id uint) (*models.Notification, error) {
+	var n models.Notification
+	err := getDB(ctx, r.db).First(&n, id).Error
+	return &n, err
+}
+
+// ListByUserID retrieves a page of notifications for a user, newest first.
+func (r *notificationRepository) ListByUserID(ctx context.Context, userID uint, unreadOnly bool, limit int, offset int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := getDB(ctx, r.db).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+	err := query.Order("created_at desc").Limit(limit).Offset(offset).Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnreadByUserID counts the unread notifications of a user.
+func (r *notificationRepository) CountUnreadByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead flags a single notification as read.
+func (r *notificationRepository) MarkRead(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Model(&models.Notification{}).
+		Where("id = ? AND is_read = ?", id, false).
+		Updates(map[string]any{"is_read": true, "read_at": time.Now().UTC()}).Error
+}
+
+// MarkAllReadByUserID flags every unread notification of a user as read.
+func (r *notificationRepository) MarkAllReadByUserID(ctx context.Context, userID uint) error {
+	return getDB(ctx, r.db).Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Updates(map[string]any{"is_read": true, "read_at": time.Now().UTC()}).Error
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// OutboxRepository defines methods for the transactional outbox.
+type OutboxRepository interface {
+	Create(ctx context.Context, event *models.OutboxEvent) error
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration, maxAttempts int, limit int) ([]models.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id uint, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, id uint, lastError string, nextAttemptAt time.Time) error
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+	MarkProcessed(ctx context.Context, eventID string, subscriber string) (bool, error)
+	IsProcessed(ctx context.Context, eventID string, subscriber string) (bool, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new OutboxRepository.
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Create stores a new outbox event. Call it with the context of the transaction making the domain change.
+func (r *outboxRepository) Create(ctx context.Context, event *models.OutboxEvent) error {
+	return getDB(ctx, r.db).Create(event).Error
+}
+
+// ClaimPending returns unpublished events that are due for an attempt, oldest first, and leases them by pushing
+// their next attempt time forward, so concurrent relays do not deliver the same event at the same time. The claim
+// commits before it returns; no lock is held while the events are delivered.
+func (r *outboxRepository) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, maxAttempts int, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := getDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND next_attempt_at <= ? AND attempts < ?", now, maxAttempts).
+			Order("id asc").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+		return tx.Model(&models.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return events, err
+}
+
+// MarkPublished flags an outbox event as delivered to every subscriber.
+func (r *outboxRepository) MarkPublished(ctx context.Context, id uint, publishedAt time.Time) error {
+	return getDB(ctx, r.db).Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"published_at": publishedAt, "last_error": ""}).Error
+}
+
+// MarkFailed records a failed delivery attempt and when the event should be retried.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uint, lastError string, nextAttemptAt time.Time) error {
+	return getDB(ctx, r.db).Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
+
+// DeletePublishedBefore removes events published before the given time together with their processed markers.
+// It returns how many events were removed.
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := getDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		eventIDs := tx.Model(&models.OutboxEvent{}).Select("event_id").Where("published_at < ?", before)
+		if err := tx.Where("event_id IN (?)", eventIDs).Delete(&models.ProcessedOutboxEvent{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("published_at < ?", before).Delete(&models.OutboxEvent{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// MarkProcessed records that a subscriber handled an event. It reports false if the subscriber had already handled it.
+func (r *outboxRepository) MarkProcessed(ctx context.Context, eventID string, subscriber string) (bool, error) {
+	result := getDB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ProcessedOutboxEvent{EventID: eventID, Subscriber: subscriber})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsProcessed reports whether a subscriber has already handled an event.
+func (r *outboxRepository) IsProcessed(ctx context.Context, eventID string, subscriber string) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.ProcessedOutboxEvent{}).
+		Where("event_id = ? AND subscriber = ?", eventID, subscriber).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// PaymentRecordMessageRepository defines methods for the message threads of payment records.
+type PaymentRecordMessageRepository interface {
+	Create(ctx context.Context, message *models.PaymentRecordMessage) error
+	ListByPaymentRecordIDs(ctx context.Context, paymentRecordIDs []uint) ([]models.PaymentRecordMessage, error)
+}
+
+type paymentRecordMessageRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRecordMessageRepository creates a new PaymentRecordMessageRepository.
+func NewPaymentRecordMessageRepository(db *gorm.DB) PaymentRecordMessageRepository {
+	return &paymentRecordMessageRepository{db: db}
+}
+
+// Create adds a message to a payment record's thread.
+func (r *paymentRecordMessageRepository) Create(ctx context.Context, message *models.PaymentRecordMessage) error {
+	return getDB(ctx, r.db).Create(message).Error
+}
+
+// ListByPaymentRecordIDs retrieves the messages of the given payment records, oldest first, with their authors.
+func (r *paymentRecordMessageRepository) ListByPaymentRecordIDs(ctx context.Context, paymentRecordIDs []uint) ([]models.PaymentRecordMessage, error) {
+	var messages []models.PaymentRecordMessage
+	if len(paymentRecordIDs) == 0 {
+		return messages, nil
+	}
+	err := getDB(ctx, r.db).
+		Preload("Author").
+		Where("payment_record_id IN ?", paymentRecordIDs).
+		Order("created_at ASC, id ASC").
+		Find(&messages).Error
+	return messages, err
+}
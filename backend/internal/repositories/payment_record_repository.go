@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"fmt"
 	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/phash"
 	"gorm.io/gorm"
 	"time"
 )
@@ -11,9 +13,25 @@ import (
 type PaymentRecordRepository interface {
 	Create(ctx context.Context, pr *models.PaymentRecord) error
 	GetByID(ctx context.Context, id uint) (*models.PaymentRecord, error)
-	UpdateStatus(ctx context.Context, id uint, status models.PaymentRecordStatus, reviewedByUserID *uint) error
+	UpdateStatus(ctx context.Context, id uint, from []models.PaymentRecordStatus, status models.PaymentRecordStatus, reviewedByUserID *uint) (bool, error)
+	MarkDeclined(ctx context.Context, id uint, from []models.PaymentRecordStatus, reviewedByUserID uint, reason string) (bool, error)
+	MarkDisputed(ctx context.Context, id uint, disputedAt time.Time) (bool, error)
+	MarkSuperseded(ctx context.Context, id uint, supersededByRecordID uint) (bool, error)
+	MarkAutoApproved(ctx context.Context, id uint, ruleID uint) (bool, error)
+	MarkAutoApprovalReverted(ctx context.Context, id uint) error
+	CountOtherWithTransactionReference(ctx context.Context, id uint, transactionRef string) (int64, error)
+	CountOnTimeApprovals(ctx context.Context, membershipID uint) (int64, error)
 	ListBySubscriptionMembershipID(ctx context.Context, membershipID uint) ([]models.PaymentRecord, error)
+	FindLatestApprovedByMembershipID(ctx context.Context, membershipID uint) (*models.PaymentRecord, error)
+	ListBySlip(ctx context.Context, sendingBankCode string, transactionRef string) ([]models.PaymentRecord, error)
+	ListBySimilarProofImage(ctx context.Context, pr *models.PaymentRecord, maxDistance int) ([]models.PaymentRecord, error)
 	ListByHostedSubscriptionIDAndStatus(ctx context.Context, hostedSubscriptionID uint, status models.PaymentRecordStatus) ([]models.PaymentRecord, error)
+	CountByHostedSubscriptionIDAndStatuses(ctx context.Context, hostedSubscriptionID uint, statuses []models.PaymentRecordStatus) (int64, error)
+	SumApprovedByHostedSubscriptionIDBetween(ctx context.Context, hostedSubscriptionID uint, from time.Time, to time.Time) (float64, error)
+	ListAwaitingReviewByMemberUserID(ctx context.Context, memberUserID uint) ([]models.PaymentRecord, error)
+	GetReviewStatsByMemberUserIDs(ctx context.Context, memberUserIDs []uint) (map[uint]models.RequesterReviewStats, error)
+	ListRecentByMemberUserIDs(ctx context.Context, memberUserIDs []uint, limit int) (map[uint][]models.RequesterPaymentRow, error)
+	EachForExport(ctx context.Context, filter models.PaymentRecordExportFilter, fn func(row *models.PaymentRecordExportRow) error) error
 }
 
 type paymentRecordRepository struct {
@@ -27,22 +45,26 @@ func NewPaymentRecordRepository(db *gorm.DB) PaymentRecordRepository {
 
 // Create persists a new PaymentRecord.
 func (r *paymentRecordRepository) Create(ctx context.Context, pr *models.PaymentRecord) error {
-	return r.db.WithContext(ctx).Create(pr).Error
+	return getDB(ctx, r.db).Create(pr).Error
 }
 
 // GetByID retrieves a specific PaymentRecord by its ID.
 func (r *paymentRecordRepository) GetByID(ctx context.Context, id uint) (*models.PaymentRecord, error) {
 	var pr models.PaymentRecord
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Preload("SubscriptionMembership.User").
 		Preload("SubscriptionMembership.HostedSubscription.User").
 		Preload("SubscriptionMembership.HostedSubscription.SubscriptionService").
+		Preload("Allocations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("cycle_due_date asc")
+		}).
 		First(&pr, id).Error
 	return &pr, err
 }
 
-// UpdateStatus updates the status, reviewer, and notes of a specific PaymentRecord.
-func (r *paymentRecordRepository) UpdateStatus(ctx context.Context, id uint, status models.PaymentRecordStatus, reviewedByUserID *uint) error {
+// UpdateStatus updates the status and reviewer of a specific PaymentRecord, provided it is still in one of the
+// from statuses. It reports false if it was not, e.g. because a concurrent review got there first.
+func (r *paymentRecordRepository) UpdateStatus(ctx context.Context, id uint, from []models.PaymentRecordStatus, status models.PaymentRecordStatus, reviewedByUserID *uint) (bool, error) {
 	updates := map[string]any{
 		"status":      status,
 		"reviewed_at": time.Now().UTC(),
@@ -50,28 +72,356 @@ func (r *paymentRecordRepository) UpdateStatus(ctx context.Context, id uint, sta
 	if reviewedByUserID != nil {
 		updates["reviewed_by_user_id"] = reviewedByUserID
 	}
-	return r.db.WithContext(ctx).Model(&models.PaymentRecord{}).Where("id = ?", id).Updates(updates).Error
+	result := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkDeclined declines a PaymentRecord, recording the reviewer and their reason, provided it is still in one of
+// the from statuses. It reports false if it was not.
+func (r *paymentRecordRepository) MarkDeclined(ctx context.Context, id uint, from []models.PaymentRecordStatus, reviewedByUserID uint, reason string) (bool, error) {
+	result := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(map[string]any{
+			"status":              models.PaymentRecordStatusDeclined,
+			"reviewed_at":         time.Now().UTC(),
+			"reviewed_by_user_id": reviewedByUserID,
+			"decline_reason":      reason,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkDisputed marks a declined PaymentRecord as disputed by its member. It reports false if the record was no
+// longer declined.
+func (r *paymentRecordRepository) MarkDisputed(ctx context.Context, id uint, disputedAt time.Time) (bool, error) {
+	result := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Where("id = ? AND status = ?", id, models.PaymentRecordStatusDeclined).
+		Updates(map[string]any{
+			"status":      models.PaymentRecordStatusDisputed,
+			"disputed_at": disputedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkSuperseded marks a declined or disputed PaymentRecord as replaced by a corrected submission. It reports false
+// if the record was in neither status, e.g. because the host approved it or another resubmission got there first.
+func (r *paymentRecordRepository) MarkSuperseded(ctx context.Context, id uint, supersededByRecordID uint) (bool, error) {
+	result := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Where("id = ? AND status IN ?", id, []models.PaymentRecordStatus{models.PaymentRecordStatusDeclined, models.PaymentRecordStatusDisputed}).
+		Updates(map[string]any{
+			"status":                  models.PaymentRecordStatusSuperseded,
+			"superseded_by_record_id": supersededByRecordID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkAutoApproved approves a submitted PaymentRecord on behalf of the host through one of their auto-approval
+// rules. It reports false if the record was no longer awaiting review.
+func (r *paymentRecordRepository) MarkAutoApproved(ctx context.Context, id uint, ruleID uint) (bool, error) {
+	result := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Where("id = ? AND status = ?", id, models.PaymentRecordStatusProofSubmitted).
+		Updates(map[string]any{
+			"status":                models.PaymentRecordStatusApproved,
+			"reviewed_at":           time.Now().UTC(),
+			"auto_approval_rule_id": ruleID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkAutoApprovalReverted puts an auto-approved PaymentRecord back up for the host's review.
+func (r *paymentRecordRepository) MarkAutoApprovalReverted(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Model(&models.PaymentRecord{}).Where("id = ?", id).Updates(map[string]any{
+		"status":                models.PaymentRecordStatusProofSubmitted,
+		"reviewed_at":           nil,
+		"reviewed_by_user_id":   nil,
+		"auto_approval_rule_id": nil,
+	}).Error
+}
+
+// CountOtherWithTransactionReference counts the payment records, across all subscriptions and other than id,
+// that give the same transaction reference. Superseded records are left out, like in ListBySlip.
+func (r *paymentRecordRepository) CountOtherWithTransactionReference(ctx context.Context, id uint, transactionRef string) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Where("id <> ? AND LOWER(transaction_reference) = LOWER(?) AND status <> ?", id, transactionRef, models.PaymentRecordStatusSuperseded).
+		Count(&count).Error
+	return count, err
+}
+
+// CountOnTimeApprovals counts a membership's approved payment records that were submitted no later than
+// the day the first cycle they paid for was due.
+func (r *paymentRecordRepository) CountOnTimeApprovals(ctx context.Context, membershipID uint) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Where("subscription_membership_id = ? AND status = ?", membershipID, models.PaymentRecordStatusApproved).
+		Where(`submitted_at < (SELECT date_trunc('day', MIN(a.cycle_due_date)) + interval '1 day'
+			FROM payment_cycle_allocations a WHERE a.payment_record_id = payment_records.id)`).
+		Count(&count).Error
+	return count, err
 }
 
 // ListBySubscriptionMembershipID retrieves all payment records for a specific membership, ordered by creation.
 func (r *paymentRecordRepository) ListBySubscriptionMembershipID(ctx context.Context, membershipID uint) ([]models.PaymentRecord, error) {
 	var records []models.PaymentRecord
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Where("subscription_membership_id = ?", membershipID).
+		Preload("Allocations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("cycle_due_date asc")
+		}).
 		Order("created_at desc").
 		Find(&records).Error
 	return records, err
 }
 
+// FindLatestApprovedByMembershipID retrieves the most recently approved payment record of a membership.
+func (r *paymentRecordRepository) FindLatestApprovedByMembershipID(ctx context.Context, membershipID uint) (*models.PaymentRecord, error) {
+	var pr models.PaymentRecord
+	err := getDB(ctx, r.db).
+		Where("subscription_membership_id = ? AND status = ?", membershipID, models.PaymentRecordStatusApproved).
+		Order("reviewed_at desc, id desc").
+		First(&pr).Error
+	return &pr, err
+}
+
+// ListBySlip retrieves the payment records, across all subscriptions, backed by the bank slip with this reference,
+// oldest first. Superseded records are left out: a corrected proof may reuse the slip of the one it replaces.
+func (r *paymentRecordRepository) ListBySlip(ctx context.Context, sendingBankCode string, transactionRef string) ([]models.PaymentRecord, error) {
+	var records []models.PaymentRecord
+	err := getDB(ctx, r.db).
+		Where("slip_transaction_ref = ? AND slip_sending_bank_code = ? AND status <> ?", transactionRef, sendingBankCode, models.PaymentRecordStatusSuperseded).
+		Order("id asc").
+		Find(&records).Error
+	return records, err
+}
+
+// ListBySimilarProofImage retrieves the payment records, across all subscriptions, whose proof image hash is within
+// maxDistance differing bits of pr's, oldest first. Proofs pr's own cycle has already replaced are left out.
+// Candidates are narrowed down by the indexed hash bands, which only finds every match while maxDistance is below
+// phash.BandCount.
+func (r *paymentRecordRepository) ListBySimilarProofImage(ctx context.Context, pr *models.PaymentRecord, maxDistance int) ([]models.PaymentRecord, error) {
+	var records []models.PaymentRecord
+	if pr.ProofImageHash == nil {
+		return records, nil
+	}
+	if maxDistance >= phash.BandCount {
+		return nil, fmt.Errorf("proof image hash distance %d is too wide for %d hash bands", maxDistance, phash.BandCount)
+	}
+	bands := phash.Bands(uint64(*pr.ProofImageHash))
+	err := getDB(ctx, r.db).
+		Where(`(proof_image_hash_band0 = ? OR proof_image_hash_band1 = ? OR proof_image_hash_band2 = ?
+			OR proof_image_hash_band3 = ? OR proof_image_hash_band4 = ?)`, bands[0], bands[1], bands[2], bands[3], bands[4]).
+		Where("bit_count((proof_image_hash # ?)::bit(64)) <= ?", *pr.ProofImageHash, maxDistance).
+		Where("NOT (status = ? AND subscription_membership_id = ? AND payment_cycle_identifier = ?)",
+			models.PaymentRecordStatusSuperseded, pr.SubscriptionMembershipID, pr.PaymentCycleIdentifier).
+		Order("id asc").
+		Find(&records).Error
+	return records, err
+}
+
 // ListByHostedSubscriptionIDAndStatus retrieves payment records for a hosted subscription filtered by status.
 func (r *paymentRecordRepository) ListByHostedSubscriptionIDAndStatus(ctx context.Context, hostedSubscriptionID uint, status models.PaymentRecordStatus) ([]models.PaymentRecord, error) {
 	var records []models.PaymentRecord
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Joins("JOIN subscription_memberships sm ON sm.id = payment_records.subscription_membership_id").
 		Where("sm.hosted_subscription_id = ? AND payment_records.status = ?", hostedSubscriptionID, status).
 		Preload("SubscriptionMembership.User").
 		Preload("SubscriptionMembership.HostedSubscription").
+		Preload("Allocations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("cycle_due_date asc")
+		}).
 		Order("payment_records.created_at asc").
 		Find(&records).Error
 	return records, err
 }
+
+// CountByHostedSubscriptionIDAndStatuses counts the payment records of a hosted subscription in any of the given statuses.
+func (r *paymentRecordRepository) CountByHostedSubscriptionIDAndStatuses(ctx context.Context, hostedSubscriptionID uint, statuses []models.PaymentRecordStatus) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Joins("JOIN subscription_memberships sm ON sm.id = payment_records.subscription_membership_id").
+		Where("sm.hosted_subscription_id = ? AND payment_records.status IN ?", hostedSubscriptionID, statuses).
+		Count(&count).Error
+	return count, err
+}
+
+// SumApprovedByHostedSubscriptionIDBetween sums the amounts of payments for a hosted subscription approved in [from, to).
+func (r *paymentRecordRepository) SumApprovedByHostedSubscriptionIDBetween(ctx context.Context, hostedSubscriptionID uint, from time.Time, to time.Time) (float64, error) {
+	var total float64
+	err := getDB(ctx, r.db).Model(&models.PaymentRecord{}).
+		Joins("JOIN subscription_memberships sm ON sm.id = payment_records.subscription_membership_id").
+		Where("sm.hosted_subscription_id = ? AND payment_records.status = ?", hostedSubscriptionID, models.PaymentRecordStatusApproved).
+		Where("payment_records.reviewed_at >= ? AND payment_records.reviewed_at < ?", from, to).
+		Select("COALESCE(SUM(payment_records.amount_paid), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// ListAwaitingReviewByMemberUserID retrieves a member's submitted and disputed payment records across all of
+// their current memberships, oldest first.
+func (r *paymentRecordRepository) ListAwaitingReviewByMemberUserID(ctx context.Context, memberUserID uint) ([]models.PaymentRecord, error) {
+	var records []models.PaymentRecord
+	err := getDB(ctx, r.db).
+		Joins("JOIN subscription_memberships sm ON sm.id = payment_records.subscription_membership_id AND sm.deleted_at IS NULL").
+		Where("sm.member_user_id = ? AND payment_records.status IN ?", memberUserID,
+			[]models.PaymentRecordStatus{models.PaymentRecordStatusProofSubmitted, models.PaymentRecordStatusDisputed}).
+		Order("payment_records.submitted_at asc").
+		Find(&records).Error
+	return records, err
+}
+
+// GetReviewStatsByMemberUserIDs sums up, for each of the users, their memberships, current and ended, and how
+// their payment records across all of them were reviewed. Users without any membership are left out.
+func (r *paymentRecordRepository) GetReviewStatsByMemberUserIDs(ctx context.Context, memberUserIDs []uint) (map[uint]models.RequesterReviewStats, error) {
+	stats := make(map[uint]models.RequesterReviewStats, len(memberUserIDs))
+	if len(memberUserIDs) == 0 {
+		return stats, nil
+	}
+
+	var paymentRows []struct {
+		MemberUserID uint
+		models.RequesterReviewStats
+	}
+	err := getDB(ctx, r.db).
+		Table("payment_records pr").
+		Select(`sm.member_user_id,
+			COUNT(*) FILTER (WHERE pr.status = @approved) AS payments_approved,
+			COUNT(*) FILTER (WHERE pr.status = @approved AND pr.submitted_at < (
+				SELECT date_trunc('day', MIN(a.cycle_due_date)) + interval '1 day'
+				FROM payment_cycle_allocations a WHERE a.payment_record_id = pr.id)) AS payments_on_time,
+			COUNT(*) FILTER (WHERE pr.status = @declined) AS payments_declined,
+			COUNT(*) FILTER (WHERE pr.status = @disputed) AS payments_disputed,
+			COUNT(*) FILTER (WHERE pr.status IN @awaiting) AS payments_awaiting_review,
+			COALESCE(SUM(pr.amount_paid) FILTER (WHERE pr.status = @approved), 0) AS total_paid`,
+			map[string]any{
+				"approved": models.PaymentRecordStatusApproved,
+				"declined": models.PaymentRecordStatusDeclined,
+				"disputed": models.PaymentRecordStatusDisputed,
+				"awaiting": []models.PaymentRecordStatus{models.PaymentRecordStatusProofSubmitted, models.PaymentRecordStatusRequiresAttention},
+			}).
+		Joins("JOIN subscription_memberships sm ON sm.id = pr.subscription_membership_id").
+		Where("sm.member_user_id IN ?", memberUserIDs).
+		Group("sm.member_user_id").
+		Scan(&paymentRows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range paymentRows {
+		stats[row.MemberUserID] = row.RequesterReviewStats
+	}
+
+	var membershipRows []struct {
+		MemberUserID      uint
+		ActiveMemberships int64
+		EndedMemberships  int64
+	}
+	err = getDB(ctx, r.db).
+		Table("subscription_memberships").
+		Select(`member_user_id,
+			COUNT(*) FILTER (WHERE deleted_at IS NULL) AS active_memberships,
+			COUNT(*) FILTER (WHERE deleted_at IS NOT NULL) AS ended_memberships`).
+		Where("member_user_id IN ?", memberUserIDs).
+		Group("member_user_id").
+		Scan(&membershipRows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range membershipRows {
+		userStats := stats[row.MemberUserID]
+		userStats.ActiveMemberships = row.ActiveMemberships
+		userStats.EndedMemberships = row.EndedMemberships
+		stats[row.MemberUserID] = userStats
+	}
+	return stats, nil
+}
+
+// ListRecentByMemberUserIDs retrieves, for each of the users, up to limit of their most recent payment records
+// across all of their memberships, current and ended, leaving out those replaced by a corrected proof.
+func (r *paymentRecordRepository) ListRecentByMemberUserIDs(ctx context.Context, memberUserIDs []uint, limit int) (map[uint][]models.RequesterPaymentRow, error) {
+	history := make(map[uint][]models.RequesterPaymentRow, len(memberUserIDs))
+	if len(memberUserIDs) == 0 {
+		return history, nil
+	}
+
+	ranked := getDB(ctx, r.db).
+		Table("payment_records pr").
+		Select(`sm.member_user_id, ss.name AS service_name, pr.payment_cycle_identifier, pr.amount_expected,
+			pr.amount_paid, pr.status, pr.submitted_at, pr.reviewed_at,
+			ROW_NUMBER() OVER (PARTITION BY sm.member_user_id ORDER BY pr.submitted_at DESC, pr.id DESC) AS recency`).
+		Joins("JOIN subscription_memberships sm ON sm.id = pr.subscription_membership_id").
+		Joins("JOIN hosted_subscriptions hs ON hs.id = sm.hosted_subscription_id").
+		Joins("JOIN subscription_services ss ON ss.id = hs.subscription_service_id").
+		Where("sm.member_user_id IN ? AND pr.status <> ?", memberUserIDs, models.PaymentRecordStatusSuperseded)
+
+	var rows []struct {
+		MemberUserID uint
+		models.RequesterPaymentRow
+	}
+	err := getDB(ctx, r.db).
+		Table("(?) AS recent", ranked).
+		Where("recent.recency <= ?", limit).
+		Order("recent.member_user_id, recent.recency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		history[row.MemberUserID] = append(history[row.MemberUserID], row.RequesterPaymentRow)
+	}
+	return history, nil
+}
+
+// EachForExport calls fn with each payment record matching the filter, oldest first, reading them one row at a
+// time so an export never holds the whole history in memory. It stops at the first error fn returns.
+func (r *paymentRecordRepository) EachForExport(ctx context.Context, filter models.PaymentRecordExportFilter, fn func(row *models.PaymentRecordExportRow) error) error {
+	query := getDB(ctx, r.db).
+		Table("payment_records pr").
+		Select(`pr.id, pr.submitted_at, pr.payment_cycle_identifier, pr.cycle_count,
+			u.full_name AS member_name, u.email AS member_email,
+			pr.amount_expected, pr.amount_paid, pr.payment_method, pr.transaction_reference,
+			pr.status, pr.reviewed_at, pr.decline_reason, pr.slip_check_status`).
+		Joins("JOIN subscription_memberships sm ON sm.id = pr.subscription_membership_id").
+		Joins("JOIN users u ON u.id = sm.member_user_id").
+		Where("sm.hosted_subscription_id = ?", filter.HostedSubscriptionID)
+	if filter.From != nil {
+		query = query.Where("pr.submitted_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("pr.submitted_at < ?", *filter.To)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("pr.status IN ?", filter.Statuses)
+	}
+
+	rows, err := query.Order("pr.submitted_at ASC, pr.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.PaymentRecordExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
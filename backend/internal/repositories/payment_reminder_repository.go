@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PaymentReminderRepository defines methods for reminder schedules and sent reminders.
+type PaymentReminderRepository interface {
+	ListRulesBySubscriptionIDs(ctx context.Context, hostedSubscriptionIDs []uint) ([]models.PaymentReminderRule, error)
+	ReplaceSchedule(ctx context.Context, hostedSubscriptionID uint, offsetDays []int) error
+	RecordDispatch(ctx context.Context, dispatch *models.PaymentReminderDispatch) (bool, error)
+}
+
+type paymentReminderRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentReminderRepository creates a new PaymentReminderRepository.
+func NewPaymentReminderRepository(db *gorm.DB) PaymentReminderRepository {
+	return &paymentReminderRepository{db: db}
+}
+
+// ListRulesBySubscriptionIDs retrieves the reminder rules of the given hosted subscriptions.
+func (r *paymentReminderRepository) ListRulesBySubscriptionIDs(ctx context.Context, hostedSubscriptionIDs []uint) ([]models.PaymentReminderRule, error) {
+	var rules []models.PaymentReminderRule
+	if len(hostedSubscriptionIDs) == 0 {
+		return rules, nil
+	}
+	err := getDB(ctx, r.db).
+		Where("hosted_subscription_id IN ?", hostedSubscriptionIDs).
+		Order("offset_days asc").
+		Find(&rules).Error
+	return rules, err
+}
+
+// ReplaceSchedule swaps a hosted subscription's reminder rules for the given offsets and marks its schedule as customized.
+func (r *paymentReminderRepository) ReplaceSchedule(ctx context.Context, hostedSubscriptionID uint, offsetDays []int) error {
+	return getDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("hosted_subscription_id = ?", hostedSubscriptionID).Delete(&models.PaymentReminderRule{}).Error; err != nil {
+			return err
+		}
+		if len(offsetDays) > 0 {
+			rules := make([]models.PaymentReminderRule, 0, len(offsetDays))
+			for _, offset := range offsetDays {
+				rules = append(rules, models.PaymentReminderRule{HostedSubscriptionID: hostedSubscriptionID, OffsetDays: offset})
+			}
+			if err := tx.Create(&rules).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.HostedSubscription{}).
+			Where("id = ?", hostedSubscriptionID).
+			Update("custom_reminder_schedule", true).Error
+	})
+}
+
+// RecordDispatch stores a sent reminder. It reports false without error if the same reminder was already recorded.
+func (r *paymentReminderRepository) RecordDispatch(ctx context.Context, dispatch *models.PaymentReminderDispatch) (bool, error) {
+	result := getDB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(dispatch)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
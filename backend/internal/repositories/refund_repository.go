@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// RefundRepository defines methods for Refund data.
+type RefundRepository interface {
+	Create(ctx context.Context, refund *models.Refund) error
+	GetByID(ctx context.Context, id uint) (*models.Refund, error)
+	ListByMembershipID(ctx context.Context, membershipID uint) ([]models.Refund, error)
+	SumByMembershipID(ctx context.Context, membershipID uint) (float64, error)
+	SumByPaymentRecordID(ctx context.Context, paymentRecordID uint) (float64, error)
+	MarkAcknowledged(ctx context.Context, refund *models.Refund) error
+}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository creates a new RefundRepository.
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+// Create persists a new Refund.
+func (r *refundRepository) Create(ctx context.Context, refund *models.Refund) error {
+	return getDB(ctx, r.db).Create(refund).Error
+}
+
+// GetByID retrieves a specific Refund by its ID.
+func (r *refundRepository) GetByID(ctx context.Context, id uint) (*models.Refund, error) {
+	var refund models.Refund
+	err := getDB(ctx, r.db).First(&refund, id).Error
+	return &refund, err
+}
+
+// ListByMembershipID retrieves the refunds of a membership, newest first.
+func (r *refundRepository) ListByMembershipID(ctx context.Context, membershipID uint) ([]models.Refund, error) {
+	var refunds []models.Refund
+	err := getDB(ctx, r.db).
+		Where("subscription_membership_id = ?", membershipID).
+		Order("created_at desc").
+		Find(&refunds).Error
+	return refunds, err
+}
+
+// SumByMembershipID sums the amounts refunded on a membership.
+func (r *refundRepository) SumByMembershipID(ctx context.Context, membershipID uint) (float64, error) {
+	var total float64
+	err := getDB(ctx, r.db).Model(&models.Refund{}).
+		Where("subscription_membership_id = ?", membershipID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// SumByPaymentRecordID sums the amounts refunded against a payment record.
+func (r *refundRepository) SumByPaymentRecordID(ctx context.Context, paymentRecordID uint) (float64, error) {
+	var total float64
+	err := getDB(ctx, r.db).Model(&models.Refund{}).
+		Where("payment_record_id = ?", paymentRecordID).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// MarkAcknowledged records that the member confirmed receiving a refund.
+func (r *refundRepository) MarkAcknowledged(ctx context.Context, refund *models.Refund) error {
+	return getDB(ctx, r.db).Model(&models.Refund{}).
+		Where("id = ?", refund.ID).
+		Updates(map[string]any{
+			"status":          refund.Status,
+			"acknowledged_at": refund.AcknowledgedAt,
+		}).Error
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+)
+
+// ScreeningQuestionRepository defines methods for the screening questions of hosted subscriptions.
+type ScreeningQuestionRepository interface {
+	ListByHostedSubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.ScreeningQuestion, error)
+	Replace(ctx context.Context, hostedSubscriptionID uint, questions []models.ScreeningQuestion) error
+}
+
+type screeningQuestionRepository struct {
+	db *gorm.DB
+}
+
+// NewScreeningQuestionRepository creates a new ScreeningQuestionRepository.
+func NewScreeningQuestionRepository(db *gorm.DB) ScreeningQuestionRepository {
+	return &screeningQuestionRepository{db: db}
+}
+
+// ListByHostedSubscriptionID retrieves the screening questions of a subscription in the order they are asked.
+func (r *screeningQuestionRepository) ListByHostedSubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.ScreeningQuestion, error) {
+	var questions []models.ScreeningQuestion
+	err := getDB(ctx, r.db).
+		Where("hosted_subscription_id = ?", hostedSubscriptionID).
+		Order("position asc").
+		Find(&questions).Error
+	return questions, err
+}
+
+// Replace deletes the screening questions of a subscription and persists the given ones in their place.
+// Call it within a transaction so the subscription is never left without its questions.
+func (r *screeningQuestionRepository) Replace(ctx context.Context, hostedSubscriptionID uint, questions []models.ScreeningQuestion) error {
+	db := getDB(ctx, r.db)
+	if err := db.Where("hosted_subscription_id = ?", hostedSubscriptionID).Delete(&models.ScreeningQuestion{}).Error; err != nil {
+		return err
+	}
+	if len(questions) == 0 {
+		return nil
+	}
+	return db.Create(&questions).Error
+}
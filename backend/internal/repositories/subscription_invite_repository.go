@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SubscriptionInviteRepository defines methods for invites to hosted subscriptions.
+type SubscriptionInviteRepository interface {
+	Create(ctx context.Context, invite *models.SubscriptionInvite) error
+	GetByID(ctx context.Context, id uint) (*models.SubscriptionInvite, error)
+	GetByCode(ctx context.Context, code string) (*models.SubscriptionInvite, error)
+	GetByCodeForUpdate(ctx context.Context, code string) (*models.SubscriptionInvite, error)
+	ListByHostedSubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.SubscriptionInvite, error)
+	IncrementUseCount(ctx context.Context, id uint) error
+	Revoke(ctx context.Context, id uint, at time.Time) error
+}
+
+type subscriptionInviteRepository struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionInviteRepository creates a new SubscriptionInviteRepository.
+func NewSubscriptionInviteRepository(db *gorm.DB) SubscriptionInviteRepository {
+	return &subscriptionInviteRepository{db: db}
+}
+
+// Create persists a new SubscriptionInvite.
+func (r *subscriptionInviteRepository) Create(ctx context.Context, invite *models.SubscriptionInvite) error {
+	return getDB(ctx, r.db).Create(invite).Error
+}
+
+// GetByID retrieves a specific SubscriptionInvite by its ID.
+func (r *subscriptionInviteRepository) GetByID(ctx context.Context, id uint) (*models.SubscriptionInvite, error) {
+	var invite models.SubscriptionInvite
+	err := getDB(ctx, r.db).First(&invite, id).Error
+	return &invite, err
+}
+
+// GetByCode retrieves the SubscriptionInvite with the given code.
+func (r *subscriptionInviteRepository) GetByCode(ctx context.Context, code string) (*models.SubscriptionInvite, error) {
+	var invite models.SubscriptionInvite
+	err := getDB(ctx, r.db).Where("code = ?", code).First(&invite).Error
+	return &invite, err
+}
+
+// GetByCodeForUpdate retrieves the SubscriptionInvite with the given code and locks its row until the
+// surrounding transaction ends, so concurrent acceptances cannot exceed its maximum uses.
+func (r *subscriptionInviteRepository) GetByCodeForUpdate(ctx context.Context, code string) (*models.SubscriptionInvite, error) {
+	var invite models.SubscriptionInvite
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("code = ?", code).
+		First(&invite).Error
+	return &invite, err
+}
+
+// ListByHostedSubscriptionID retrieves all invites to a hosted subscription, newest first.
+func (r *subscriptionInviteRepository) ListByHostedSubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.SubscriptionInvite, error) {
+	var invites []models.SubscriptionInvite
+	err := getDB(ctx, r.db).
+		Where("hosted_subscription_id = ?", hostedSubscriptionID).
+		Order("created_at desc").
+		Find(&invites).Error
+	return invites, err
+}
+
+// IncrementUseCount records one more use of an invite.
+func (r *subscriptionInviteRepository) IncrementUseCount(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Model(&models.SubscriptionInvite{}).
+		Where("id = ?", id).
+		Update("use_count", gorm.Expr("use_count + 1")).Error
+}
+
+// Revoke stops an invite from being used again.
+func (r *subscriptionInviteRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.SubscriptionInvite{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
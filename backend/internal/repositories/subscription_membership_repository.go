@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/xNatthapol/hubster/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -16,6 +17,11 @@ type SubscriptionMembershipRepository interface {
 	GetByID(ctx context.Context, id uint) (*models.SubscriptionMembership, error)
 	UpdatePaymentStatus(ctx context.Context, id uint, status models.PaymentStatusType) error
 	UpdatePaymentAndNextDueDate(ctx context.Context, id uint, status models.PaymentStatusType, nextDueDate *time.Time) error
+	GetByIDIncludingEnded(ctx context.Context, id uint) (*models.SubscriptionMembership, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*models.SubscriptionMembership, error)
+	UpdateBilling(ctx context.Context, membership *models.SubscriptionMembership) error
+	Delete(ctx context.Context, id uint) error
+	ListDueBetween(ctx context.Context, from time.Time, to time.Time) ([]models.SubscriptionMembership, error)
 }
 
 type subscriptionMembershipRepository struct {
@@ -29,13 +35,13 @@ func NewSubscriptionMembershipRepository(db *gorm.DB) SubscriptionMembershipRepo
 
 // Create persists a new SubscriptionMembership.
 func (r *subscriptionMembershipRepository) Create(ctx context.Context, sm *models.SubscriptionMembership) error {
-	return r.db.WithContext(ctx).Create(sm).Error
+	return getDB(ctx, r.db).Create(sm).Error
 }
 
 // FindByUserAndSubscription finds an active membership for a user in a specific subscription.
 func (r *subscriptionMembershipRepository) FindByUserAndSubscription(ctx context.Context, userID uint, hostedSubscriptionID uint) (*models.SubscriptionMembership, error) {
 	var sm models.SubscriptionMembership
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Where("member_user_id = ? AND hosted_subscription_id = ?", userID, hostedSubscriptionID).
 		First(&sm).Error
 	return &sm, err
@@ -44,7 +50,7 @@ func (r *subscriptionMembershipRepository) FindByUserAndSubscription(ctx context
 // ListByUserID retrieves all memberships for a user, preloading HostedSubscription and its Service.
 func (r *subscriptionMembershipRepository) ListByUserID(ctx context.Context, userID uint) ([]models.SubscriptionMembership, error) {
 	var memberships []models.SubscriptionMembership
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Where("member_user_id = ?", userID).
 		Preload("HostedSubscription.SubscriptionService").
 		Preload("HostedSubscription.User").
@@ -56,7 +62,7 @@ func (r *subscriptionMembershipRepository) ListByUserID(ctx context.Context, use
 // ListByHostedSubscriptionID retrieves all memberships for a hosted subscription, preloading member (User) details.
 func (r *subscriptionMembershipRepository) ListByHostedSubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.SubscriptionMembership, error) {
 	var memberships []models.SubscriptionMembership
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Where("hosted_subscription_id = ?", hostedSubscriptionID).
 		Preload("User").
 		Order("created_at asc").
@@ -66,14 +72,14 @@ func (r *subscriptionMembershipRepository) ListByHostedSubscriptionID(ctx contex
 
 func (r *subscriptionMembershipRepository) GetByID(ctx context.Context, id uint) (*models.SubscriptionMembership, error) {
 	var sm models.SubscriptionMembership
-	err := r.db.WithContext(ctx).
+	err := getDB(ctx, r.db).
 		Preload("HostedSubscription").
 		First(&sm, id).Error
 	return &sm, err
 }
 
 func (r *subscriptionMembershipRepository) UpdatePaymentStatus(ctx context.Context, id uint, status models.PaymentStatusType) error {
-	return r.db.WithContext(ctx).Model(&models.SubscriptionMembership{}).Where("id = ?", id).Update("payment_status", status).Error
+	return getDB(ctx, r.db).Model(&models.SubscriptionMembership{}).Where("id = ?", id).Update("payment_status", status).Error
 }
 
 func (r *subscriptionMembershipRepository) UpdatePaymentAndNextDueDate(ctx context.Context, id uint, status models.PaymentStatusType, nextDueDate *time.Time) error {
@@ -85,5 +91,60 @@ func (r *subscriptionMembershipRepository) UpdatePaymentAndNextDueDate(ctx conte
 	} else {
 		updates["next_payment_date"] = gorm.Expr("NULL")
 	}
-	return r.db.WithContext(ctx).Model(&models.SubscriptionMembership{}).Where("id = ?", id).Updates(updates).Error
+	return getDB(ctx, r.db).Model(&models.SubscriptionMembership{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// GetByIDIncludingEnded retrieves a membership even if the member has since left.
+func (r *subscriptionMembershipRepository) GetByIDIncludingEnded(ctx context.Context, id uint) (*models.SubscriptionMembership, error) {
+	var sm models.SubscriptionMembership
+	err := getDB(ctx, r.db).
+		Unscoped().
+		Preload("HostedSubscription", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		First(&sm, id).Error
+	return &sm, err
+}
+
+// GetByIDForUpdate retrieves a membership, ended or not, and locks its row until the surrounding transaction
+// ends, so concurrent approvals and refunds apply to the balance one after another.
+func (r *subscriptionMembershipRepository) GetByIDForUpdate(ctx context.Context, id uint) (*models.SubscriptionMembership, error) {
+	var sm models.SubscriptionMembership
+	err := getDB(ctx, r.db).
+		Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&sm, id).Error
+	return &sm, err
+}
+
+// UpdateBilling saves a membership's payment status, next due date, balance and billed-through date.
+func (r *subscriptionMembershipRepository) UpdateBilling(ctx context.Context, membership *models.SubscriptionMembership) error {
+	return getDB(ctx, r.db).Model(&models.SubscriptionMembership{}).
+		Where("id = ?", membership.ID).
+		Updates(map[string]any{
+			"payment_status":    membership.PaymentStatus,
+			"next_payment_date": membership.NextPaymentDate,
+			"balance":           membership.Balance,
+			"billed_through":    membership.BilledThrough,
+		}).Error
+}
+
+// Delete soft-deletes a membership so it no longer counts towards the subscription's slots.
+func (r *subscriptionMembershipRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.SubscriptionMembership{}, id).Error
+}
+
+// ListDueBetween retrieves memberships whose next payment falls in [from, to) and have no proof awaiting review,
+// whether submitted or disputed.
+func (r *subscriptionMembershipRepository) ListDueBetween(ctx context.Context, from time.Time, to time.Time) ([]models.SubscriptionMembership, error) {
+	var memberships []models.SubscriptionMembership
+	err := getDB(ctx, r.db).
+		Where("next_payment_date >= ? AND next_payment_date < ?", from, to).
+		Where("payment_status <> ?", models.PaymentStatusProofSubmitted).
+		Where("NOT EXISTS (SELECT 1 FROM payment_records pr WHERE pr.subscription_membership_id = subscription_memberships.id AND pr.status IN ?)",
+			[]models.PaymentRecordStatus{models.PaymentRecordStatusProofSubmitted, models.PaymentRecordStatusDisputed}).
+		Preload("HostedSubscription").
+		Order("next_payment_date asc").
+		Find(&memberships).Error
+	return memberships, err
 }
@@ -25,25 +25,25 @@ func NewSubscriptionServiceRepository(db *gorm.DB) SubscriptionServiceRepository
 
 // CreateSubscriptionService creates a new subscription service record.
 func (r *subscriptionServiceRepository) CreateSubscriptionService(ctx context.Context, service *models.SubscriptionService) error {
-	return r.db.WithContext(ctx).Create(service).Error
+	return getDB(ctx, r.db).Create(service).Error
 }
 
 func (r *subscriptionServiceRepository) GetByID(ctx context.Context, id uint) (*models.SubscriptionService, error) {
 	var service models.SubscriptionService
-	err := r.db.WithContext(ctx).First(&service, id).Error
+	err := getDB(ctx, r.db).First(&service, id).Error
 	return &service, err
 }
 
 // FindSubscriptionServiceByName finds a subscription service by its name.
 func (r *subscriptionServiceRepository) FindSubscriptionServiceByName(ctx context.Context, name string) (*models.SubscriptionService, error) {
 	var service models.SubscriptionService
-	err := r.db.WithContext(ctx).Where("name = ?", name).First(&service).Error
+	err := getDB(ctx, r.db).Where("name = ?", name).First(&service).Error
 	return &service, err
 }
 
 // ListSubscriptionServices retrieves all subscription services.
 func (r *subscriptionServiceRepository) ListSubscriptionServices(ctx context.Context) ([]models.SubscriptionService, error) {
 	var services []models.SubscriptionService
-	err := r.db.WithContext(ctx).Order("name asc").Find(&services).Error
+	err := getDB(ctx, r.db).Order("name asc").Find(&services).Error
 	return services, err
 }
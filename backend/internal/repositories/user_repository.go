@@ -23,21 +23,21 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) CreateUser(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return getDB(ctx, r.db).Create(user).Error
 }
 
 func (r *userRepository) UpdateUser(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return getDB(ctx, r.db).Save(user).Error
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
+	result := getDB(ctx, r.db).Where("email = ?", email).First(&user)
 	return &user, result.Error
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	result := r.db.WithContext(ctx).First(&user, id)
+	result := getDB(ctx, r.db).First(&user, id)
 	return &user, result.Error
 }
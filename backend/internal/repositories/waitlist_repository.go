@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// activeWaitlistStatuses are the statuses of entries still in the queue.
+var activeWaitlistStatuses = []models.WaitlistEntryStatus{models.WaitlistStatusWaiting, models.WaitlistStatusOffered}
+
+// WaitlistRepository defines methods for the waitlists of hosted subscriptions.
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry *models.WaitlistEntry) error
+	GetByID(ctx context.Context, id uint) (*models.WaitlistEntry, error)
+	GetByIDForUpdate(ctx context.Context, id uint) (*models.WaitlistEntry, error)
+	Update(ctx context.Context, entry *models.WaitlistEntry) error
+	FindActiveByUserAndSubscription(ctx context.Context, userID uint, hostedSubscriptionID uint) (*models.WaitlistEntry, error)
+	FindByJoinRequestID(ctx context.Context, joinRequestID uint) (*models.WaitlistEntry, error)
+	ListActiveByUserID(ctx context.Context, userID uint) ([]models.WaitlistEntry, error)
+	ListActiveByHostedSubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.WaitlistEntry, error)
+	CountWaitingBefore(ctx context.Context, hostedSubscriptionID uint, entryID uint) (int64, error)
+	CountReservedSlots(ctx context.Context, hostedSubscriptionID uint) (int64, error)
+	LockQueue(ctx context.Context, hostedSubscriptionID uint) error
+	ListNextWaiting(ctx context.Context, hostedSubscriptionID uint, limit int) ([]models.WaitlistEntry, error)
+	ExpireOffers(ctx context.Context, now time.Time) ([]uint, error)
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitlistRepository creates a new WaitlistRepository.
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+// Create persists a new WaitlistEntry.
+func (r *waitlistRepository) Create(ctx context.Context, entry *models.WaitlistEntry) error {
+	return getDB(ctx, r.db).Create(entry).Error
+}
+
+// GetByID retrieves a specific WaitlistEntry by its ID, preloading its HostedSubscription.
+func (r *waitlistRepository) GetByID(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := getDB(ctx, r.db).Preload("HostedSubscription").First(&entry, id).Error
+	return &entry, err
+}
+
+// GetByIDForUpdate retrieves a WaitlistEntry and locks its row until the surrounding transaction ends, so a
+// claim and the expiry of the same offer cannot both succeed.
+func (r *waitlistRepository) GetByIDForUpdate(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&entry, id).Error
+	return &entry, err
+}
+
+// Update saves changes to a WaitlistEntry.
+func (r *waitlistRepository) Update(ctx context.Context, entry *models.WaitlistEntry) error {
+	return getDB(ctx, r.db).Omit("HostedSubscription", "User").Save(entry).Error
+}
+
+// FindActiveByUserAndSubscription retrieves the user's waiting or offered entry for a subscription.
+func (r *waitlistRepository) FindActiveByUserAndSubscription(ctx context.Context, userID uint, hostedSubscriptionID uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := getDB(ctx, r.db).
+		Where("user_id = ? AND hosted_subscription_id = ? AND status IN ?", userID, hostedSubscriptionID, activeWaitlistStatuses).
+		First(&entry).Error
+	return &entry, err
+}
+
+// FindByJoinRequestID retrieves the claimed WaitlistEntry whose claim filed the given join request.
+func (r *waitlistRepository) FindByJoinRequestID(ctx context.Context, joinRequestID uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := getDB(ctx, r.db).
+		Where("join_request_id = ? AND status = ?", joinRequestID, models.WaitlistStatusClaimed).
+		First(&entry).Error
+	return &entry, err
+}
+
+// ListActiveByUserID retrieves a user's waiting and offered entries, oldest first, preloading their HostedSubscription.
+func (r *waitlistRepository) ListActiveByUserID(ctx context.Context, userID uint) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := getDB(ctx, r.db).
+		Preload("HostedSubscription").
+		Where("user_id = ? AND status IN ?", userID, activeWaitlistStatuses).
+		Order("id asc").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ListActiveByHostedSubscriptionID retrieves the waiting and offered entries of a subscription in queue order,
+// preloading their User.
+func (r *waitlistRepository) ListActiveByHostedSubscriptionID(ctx context.Context, hostedSubscriptionID uint) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := getDB(ctx, r.db).
+		Preload("User").
+		Where("hosted_subscription_id = ? AND status IN ?", hostedSubscriptionID, activeWaitlistStatuses).
+		Order("id asc").
+		Find(&entries).Error
+	return entries, err
+}
+
+// CountWaitingBefore counts the entries waiting ahead of the given entry in its subscription's queue.
+func (r *waitlistRepository) CountWaitingBefore(ctx context.Context, hostedSubscriptionID uint, entryID uint) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.WaitlistEntry{}).
+		Where("hosted_subscription_id = ? AND status = ? AND id < ?", hostedSubscriptionID, models.WaitlistStatusWaiting, entryID).
+		Count(&count).Error
+	return count, err
+}
+
+// CountReservedSlots counts the slots of a subscription held for waitlisted users: open offers, and claimed
+// offers whose join request the host has not yet decided on.
+func (r *waitlistRepository) CountReservedSlots(ctx context.Context, hostedSubscriptionID uint) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.WaitlistEntry{}).
+		Where("hosted_subscription_id = ?", hostedSubscriptionID).
+		Where(
+			r.db.Where("status = ?", models.WaitlistStatusOffered).
+				Or("status = ? AND EXISTS (SELECT 1 FROM join_requests jr WHERE jr.id = waitlist_entries.join_request_id AND jr.status = ?)",
+					models.WaitlistStatusClaimed, models.JoinRequestStatusPending),
+		).
+		Count(&count).Error
+	return count, err
+}
+
+// LockQueue locks the hosted subscription's row until the surrounding transaction ends, so concurrent
+// offers for the same subscription are made one after another.
+func (r *waitlistRepository) LockQueue(ctx context.Context, hostedSubscriptionID uint) error {
+	var hs models.HostedSubscription
+	return getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&hs, hostedSubscriptionID).Error
+}
+
+// ListNextWaiting retrieves up to limit waiting entries of a subscription, first in line first.
+func (r *waitlistRepository) ListNextWaiting(ctx context.Context, hostedSubscriptionID uint, limit int) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := getDB(ctx, r.db).
+		Where("hosted_subscription_id = ? AND status = ?", hostedSubscriptionID, models.WaitlistStatusWaiting).
+		Order("id asc").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+// ExpireOffers marks the offers that ran out by now as expired and returns the IDs of the hosted
+// subscriptions whose slots they held.
+func (r *waitlistRepository) ExpireOffers(ctx context.Context, now time.Time) ([]uint, error) {
+	var hostedSubscriptionIDs []uint
+	err := getDB(ctx, r.db).Raw(`
+		UPDATE waitlist_entries SET status = ?, updated_at = ?
+		WHERE status = ? AND offer_expires_at <= ?
+		RETURNING hosted_subscription_id`,
+		models.WaitlistStatusExpired, now, models.WaitlistStatusOffered, now,
+	).Scan(&hostedSubscriptionIDs).Error
+	return hostedSubscriptionIDs, err
+}
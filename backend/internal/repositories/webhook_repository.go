@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"github.com/xNatthapol/hubster/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// WebhookRepository defines methods for webhook endpoints and their deliveries.
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	GetEndpointByID(ctx context.Context, id uint) (*models.WebhookEndpoint, error)
+	ListEndpointsByUserID(ctx context.Context, userID uint) ([]models.WebhookEndpoint, error)
+	ListActiveEndpointsByUserIDs(ctx context.Context, userIDs []uint) ([]models.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	DeleteEndpoint(ctx context.Context, id uint) error
+
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id uint) (*models.WebhookDelivery, error)
+	ListDeliveriesByEndpointID(ctx context.Context, endpointID uint, limit int, offset int) ([]models.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error
+	ResetDelivery(ctx context.Context, id uint, nextAttemptAt time.Time) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new WebhookRepository.
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// CreateEndpoint persists a new WebhookEndpoint.
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	return getDB(ctx, r.db).Create(endpoint).Error
+}
+
+// GetEndpointByID retrieves a specific WebhookEndpoint by its ID.
+func (r *webhookRepository) GetEndpointByID(ctx context.Context, id uint) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := getDB(ctx, r.db).First(&endpoint, id).Error
+	return &endpoint, err
+}
+
+// ListEndpointsByUserID retrieves all webhook endpoints registered by a user.
+func (r *webhookRepository) ListEndpointsByUserID(ctx context.Context, userID uint) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := getDB(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at asc").
+		Find(&endpoints).Error
+	return endpoints, err
+}
+
+// ListActiveEndpointsByUserIDs retrieves the active webhook endpoints of the given users.
+func (r *webhookRepository) ListActiveEndpointsByUserIDs(ctx context.Context, userIDs []uint) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	if len(userIDs) == 0 {
+		return endpoints, nil
+	}
+	err := getDB(ctx, r.db).
+		Where("user_id IN ? AND is_active = ?", userIDs, true).
+		Find(&endpoints).Error
+	return endpoints, err
+}
+
+// UpdateEndpoint saves changes to a WebhookEndpoint.
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	return getDB(ctx, r.db).Save(endpoint).Error
+}
+
+// DeleteEndpoint removes a WebhookEndpoint together with its delivery history.
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		deliveryIDs := tx.Model(&models.WebhookDelivery{}).Select("id").Where("webhook_endpoint_id = ?", id)
+		if err := tx.Where("webhook_delivery_id IN (?)", deliveryIDs).Delete(&models.WebhookDeliveryAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("webhook_endpoint_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookEndpoint{}, id).Error
+	})
+}
+
+// CreateDelivery queues a delivery. A delivery of the same event to the same endpoint is silently skipped.
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return getDB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(delivery).Error
+}
+
+// GetDeliveryByID retrieves a specific WebhookDelivery with its attempts, newest first.
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := getDB(ctx, r.db).
+		Preload("DeliveryAttempts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("attempt_number desc")
+		}).
+		First(&delivery, id).Error
+	return &delivery, err
+}
+
+// ListDeliveriesByEndpointID retrieves a page of an endpoint's deliveries, newest first.
+func (r *webhookRepository) ListDeliveriesByEndpointID(ctx context.Context, endpointID uint, limit int, offset int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := getDB(ctx, r.db).
+		Where("webhook_endpoint_id = ?", endpointID).
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDueDeliveries returns pending deliveries that are due and leases them by pushing their next
+// attempt time forward, so concurrent dispatchers do not send the same delivery twice.
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := getDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("WebhookEndpoint").
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+			Order("next_attempt_at asc").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return deliveries, err
+}
+
+// RecordAttempt stores an attempt and the delivery's resulting state together.
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	return getDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Attempts keep counting up across redeliveries, unlike the delivery's attempt counter.
+		var previous int64
+		if err := tx.Model(&models.WebhookDeliveryAttempt{}).Where("webhook_delivery_id = ?", delivery.ID).Count(&previous).Error; err != nil {
+			return err
+		}
+		attempt.WebhookDeliveryID = delivery.ID
+		attempt.AttemptNumber = int(previous) + 1
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id = ?", delivery.ID).
+			Updates(map[string]any{
+				"status":           delivery.Status,
+				"attempts":         delivery.Attempts,
+				"next_attempt_at":  delivery.NextAttemptAt,
+				"last_attempt_at":  delivery.LastAttemptAt,
+				"last_status_code": delivery.LastStatusCode,
+				"last_error":       delivery.LastError,
+			}).Error
+	})
+}
+
+// ResetDelivery puts a delivery back in the queue with a fresh set of attempts.
+func (r *webhookRepository) ResetDelivery(ctx context.Context, id uint, nextAttemptAt time.Time) error {
+	return getDB(ctx, r.db).Model(&models.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          models.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const journalBackfillBatchSize = 100
+
+var (
+	ErrUnbalancedJournalEntry = errors.New("journal entry debits do not equal its credits")
+	ErrInvalidReportRange     = errors.New("report range must end after it starts")
+)
+
+// JournalParty identifies whose books a journal entry touches.
+type JournalParty struct {
+	HostUserID               uint
+	MemberUserID             uint
+	HostedSubscriptionID     uint
+	SubscriptionServiceID    uint
+	SubscriptionMembershipID uint
+}
+
+// journalPartyOf returns the party of a payment record. The membership and its hosted subscription must be preloaded.
+func journalPartyOf(pr *models.PaymentRecord) JournalParty {
+	return journalPartyOfMembership(&pr.SubscriptionMembership)
+}
+
+// journalPartyOfMembership returns the party of a membership. Its hosted subscription must be preloaded.
+func journalPartyOfMembership(membership *models.SubscriptionMembership) JournalParty {
+	return JournalParty{
+		HostUserID:               membership.HostedSubscription.HostUserID,
+		MemberUserID:             membership.MemberUserID,
+		HostedSubscriptionID:     membership.HostedSubscriptionID,
+		SubscriptionServiceID:    membership.HostedSubscription.SubscriptionServiceID,
+		SubscriptionMembershipID: membership.ID,
+	}
+}
+
+// AccountingService defines the interface for posting to the double-entry ledger and reporting from it.
+type AccountingService interface {
+	PostCycleCharge(ctx context.Context, party JournalParty, amount float64, occurredAt time.Time, paymentRecordID *uint, description string) error
+	PostPayment(ctx context.Context, party JournalParty, amount float64, occurredAt time.Time, paymentRecordID *uint, description string) error
+	PostRefund(ctx context.Context, party JournalParty, fromCredit float64, fromEarnings float64, occurredAt time.Time, paymentRecordID *uint, description string) error
+	ReverseApproval(ctx context.Context, paymentRecordID uint, description string) error
+	BackfillApprovedPayments(ctx context.Context) (int, error)
+	HostEarnings(ctx context.Context, hostUserID uint, from *time.Time, to *time.Time) ([]models.HostEarningsRow, error)
+	MemberSpend(ctx context.Context, memberUserID uint, year int) ([]models.MemberSpendRow, error)
+	Receivables(ctx context.Context, hostUserID uint, hostedSubscriptionID *uint) ([]models.ReceivableRow, error)
+}
+
+type accountingService struct {
+	journalRepo repositories.JournalRepository
+	hsRepo      repositories.HostedSubscriptionRepository
+	transactor  repositories.Transactor
+	location    *time.Location
+}
+
+// NewAccountingService creates a new AccountingService. Reports group by month and year in location.
+func NewAccountingService(
+	journalRepo repositories.JournalRepository,
+	hsRepo repositories.HostedSubscriptionRepository,
+	transactor repositories.Transactor,
+	location *time.Location,
+) AccountingService {
+	return &accountingService{journalRepo: journalRepo, hsRepo: hsRepo, transactor: transactor, location: location}
+}
+
+// PostCycleCharge records a billed cycle: the member owes the host (receivable) and the host has earned it.
+func (s *accountingService) PostCycleCharge(ctx context.Context, party JournalParty, amount float64, occurredAt time.Time, paymentRecordID *uint, description string) error {
+	return s.post(ctx, models.JournalEntryCycleCharge, party, occurredAt, paymentRecordID, description,
+		models.LedgerAccountReceivable, models.LedgerAccountEarnings, amount)
+}
+
+// PostPayment records money the host received from the member, settling the member's receivable.
+func (s *accountingService) PostPayment(ctx context.Context, party JournalParty, amount float64, occurredAt time.Time, paymentRecordID *uint, description string) error {
+	return s.post(ctx, models.JournalEntryPayment, party, occurredAt, paymentRecordID, description,
+		models.LedgerAccountCash, models.LedgerAccountReceivable, amount)
+}
+
+// PostRefund records money the host returned to the member. The part that returns the member's credit settles
+// their receivable; the rest gives back earnings, e.g. for the unused days of a cycle.
+func (s *accountingService) PostRefund(ctx context.Context, party JournalParty, fromCredit float64, fromEarnings float64, occurredAt time.Time, paymentRecordID *uint, description string) error {
+	fromCredit = models.RoundMoney(fromCredit)
+	fromEarnings = models.RoundMoney(fromEarnings)
+	entry := &models.JournalEntry{
+		OccurredAt:      occurredAt.UTC(),
+		Kind:            models.JournalEntryRefund,
+		Description:     description,
+		PaymentRecordID: paymentRecordID,
+		Lines: []models.JournalLine{
+			newJournalLine(party, occurredAt, models.LedgerAccountCash, 0, models.RoundMoney(fromCredit+fromEarnings)),
+		},
+	}
+	if fromCredit != 0 {
+		entry.Lines = append(entry.Lines, newJournalLine(party, occurredAt, models.LedgerAccountReceivable, fromCredit, 0))
+	}
+	if fromEarnings != 0 {
+		entry.Lines = append(entry.Lines, newJournalLine(party, occurredAt, models.LedgerAccountEarnings, fromEarnings, 0))
+	}
+	if err := checkJournalBalanced(entry); err != nil {
+		return err
+	}
+	if err := s.journalRepo.CreateEntry(ctx, entry); err != nil {
+		return fmt.Errorf("creating %s journal entry: %w", entry.Kind, err)
+	}
+	return nil
+}
+
+// ReverseApproval cancels the cycle charges and payment journaled when a payment record was approved. Each reversal
+// mirrors the original entry with debits and credits swapped and keeps its date, so the reports of that month net out.
+func (s *accountingService) ReverseApproval(ctx context.Context, paymentRecordID uint, description string) error {
+	entries, err := s.journalRepo.ListEntriesByPaymentRecordID(ctx, paymentRecordID)
+	if err != nil {
+		return fmt.Errorf("listing journal entries of payment record %d: %w", paymentRecordID, err)
+	}
+	for _, original := range entries {
+		if original.Kind != models.JournalEntryCycleCharge && original.Kind != models.JournalEntryPayment {
+			continue
+		}
+		reversal := &models.JournalEntry{
+			OccurredAt:      original.OccurredAt,
+			Kind:            models.JournalEntryReversal,
+			Description:     description,
+			PaymentRecordID: &paymentRecordID,
+			Lines:           make([]models.JournalLine, 0, len(original.Lines)),
+		}
+		for _, line := range original.Lines {
+			line.ID = 0
+			line.JournalEntryID = 0
+			line.Debit, line.Credit = line.Credit, line.Debit
+			reversal.Lines = append(reversal.Lines, line)
+		}
+		if err := s.journalRepo.CreateEntry(ctx, reversal); err != nil {
+			return fmt.Errorf("reversing %s journal entry %d: %w", original.Kind, original.ID, err)
+		}
+	}
+	return nil
+}
+
+// post writes a two-line entry debiting one account and crediting another by the same amount.
+func (s *accountingService) post(
+	ctx context.Context,
+	kind models.JournalEntryKind,
+	party JournalParty,
+	occurredAt time.Time,
+	paymentRecordID *uint,
+	description string,
+	debitAccount models.LedgerAccount,
+	creditAccount models.LedgerAccount,
+	amount float64,
+) error {
+	amount = models.RoundMoney(amount)
+	if amount == 0 {
+		return nil
+	}
+	entry := &models.JournalEntry{
+		OccurredAt:      occurredAt.UTC(),
+		Kind:            kind,
+		Description:     description,
+		PaymentRecordID: paymentRecordID,
+		Lines: []models.JournalLine{
+			newJournalLine(party, occurredAt, debitAccount, amount, 0),
+			newJournalLine(party, occurredAt, creditAccount, 0, amount),
+		},
+	}
+	if err := checkJournalBalanced(entry); err != nil {
+		return err
+	}
+	if err := s.journalRepo.CreateEntry(ctx, entry); err != nil {
+		return fmt.Errorf("creating %s journal entry: %w", kind, err)
+	}
+	return nil
+}
+
+func newJournalLine(party JournalParty, occurredAt time.Time, account models.LedgerAccount, debit float64, credit float64) models.JournalLine {
+	// A negative amount reverses the entry, so it moves to the other side of the line.
+	if debit < 0 || credit < 0 {
+		debit, credit = -credit, -debit
+	}
+	return models.JournalLine{
+		OccurredAt:               occurredAt.UTC(),
+		Account:                  account,
+		HostUserID:               party.HostUserID,
+		MemberUserID:             party.MemberUserID,
+		HostedSubscriptionID:     party.HostedSubscriptionID,
+		SubscriptionServiceID:    party.SubscriptionServiceID,
+		SubscriptionMembershipID: party.SubscriptionMembershipID,
+		Debit:                    debit,
+		Credit:                   credit,
+	}
+}
+
+func checkJournalBalanced(entry *models.JournalEntry) error {
+	var debits, credits float64
+	for _, line := range entry.Lines {
+		debits += line.Debit
+		credits += line.Credit
+	}
+	if math.Abs(debits-credits) >= 0.005 {
+		return fmt.Errorf("%w: %.2f debit, %.2f credit", ErrUnbalancedJournalEntry, debits, credits)
+	}
+	return nil
+}
+
+// BackfillApprovedPayments journals payments that were approved before the ledger existed, as a charge for the
+// amount expected and a payment for the amount paid. It is idempotent and returns how many payments it journaled.
+func (s *accountingService) BackfillApprovedPayments(ctx context.Context) (int, error) {
+	journaled := 0
+	var afterID uint
+	for {
+		records, err := s.journalRepo.ListUnjournaledApprovedPayments(ctx, afterID, journalBackfillBatchSize)
+		if err != nil {
+			return journaled, fmt.Errorf("listing unjournaled approved payments: %w", err)
+		}
+		if len(records) == 0 {
+			return journaled, nil
+		}
+		for i := range records {
+			pr := &records[i]
+			afterID = pr.ID
+			occurredAt := pr.SubmittedAt
+			if pr.ReviewedAt != nil {
+				occurredAt = *pr.ReviewedAt
+			}
+			party := journalPartyOf(pr)
+			description := fmt.Sprintf("Payment for %s", pr.PaymentCycleIdentifier)
+			err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				if err := s.PostCycleCharge(ctx, party, pr.AmountExpected, occurredAt, &pr.ID, description); err != nil {
+					return err
+				}
+				return s.PostPayment(ctx, party, pr.AmountPaid, occurredAt, &pr.ID, description)
+			})
+			if err != nil {
+				return journaled, fmt.Errorf("journaling payment record %d: %w", pr.ID, err)
+			}
+			journaled++
+		}
+	}
+}
+
+// HostEarnings reports a host's billed and collected amounts per subscription and month.
+// The range defaults to the last twelve months.
+func (s *accountingService) HostEarnings(ctx context.Context, hostUserID uint, from *time.Time, to *time.Time) ([]models.HostEarningsRow, error) {
+	end := time.Now().UTC()
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(-1, 0, 0)
+	if from != nil {
+		start = *from
+	}
+	if !end.After(start) {
+		return nil, ErrInvalidReportRange
+	}
+
+	rows, err := s.journalRepo.HostEarnings(ctx, hostUserID, start, end, s.location.String())
+	if err != nil {
+		return nil, fmt.Errorf("reporting host earnings: %w", err)
+	}
+	return rows, nil
+}
+
+// MemberSpend reports what a member paid per subscription service in a year. A zero year means the current one.
+func (s *accountingService) MemberSpend(ctx context.Context, memberUserID uint, year int) ([]models.MemberSpendRow, error) {
+	if year == 0 {
+		year = time.Now().In(s.location).Year()
+	}
+	rows, err := s.journalRepo.MemberSpend(ctx, memberUserID, year, s.location.String())
+	if err != nil {
+		return nil, fmt.Errorf("reporting member spend: %w", err)
+	}
+	return rows, nil
+}
+
+// Receivables reports what members owe a host, optionally for one of the host's subscriptions.
+func (s *accountingService) Receivables(ctx context.Context, hostUserID uint, hostedSubscriptionID *uint) ([]models.ReceivableRow, error) {
+	if hostedSubscriptionID != nil {
+		hs, err := s.hsRepo.GetByID(ctx, *hostedSubscriptionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrSubscriptionNotFound
+			}
+			return nil, fmt.Errorf("fetching hosted subscription for ownership check: %w", err)
+		}
+		if hs.HostUserID != hostUserID {
+			return nil, ErrForbidden
+		}
+	}
+
+	rows, err := s.journalRepo.Receivables(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("reporting receivables: %w", err)
+	}
+	for i := range rows {
+		rows[i].Outstanding = models.RoundMoney(rows[i].Outstanding)
+	}
+	return rows, nil
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/xNatthapol/hubster/internal/middleware"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAuditLogPageSize = 50
+	maxAuditLogPageSize     = 200
+	maxAuditUserAgentLength = 255
+)
+
+// Fields that change on every write and would only add noise to a diff.
+var ignoredAuditFields = map[string]bool{
+	"createdAt": true,
+	"updatedAt": true,
+}
+
+// AuditEntry describes one state-changing action for the audit log.
+// Before and After are snapshots of the target entity; either may be nil for creations and deletions.
+type AuditEntry struct {
+	Action               models.AuditAction
+	EntityID             uint
+	HostedSubscriptionID *uint
+	Before               any
+	After                any
+	// ActorUserID overrides the authenticated user of the request, e.g. for sign ups.
+	ActorUserID *uint
+}
+
+// AuditService defines the interface for recording and querying the audit log.
+type AuditService interface {
+	Record(ctx context.Context, entry AuditEntry) error
+	ListForHostedSubscription(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, filter models.AuditLogFilter, limit int, offset int) ([]models.AuditLog, error)
+	ListAll(ctx context.Context, adminUserID uint, filter models.AuditLogFilter, limit int, offset int) ([]models.AuditLog, error)
+}
+
+type auditService struct {
+	auditRepo repositories.AuditLogRepository
+	hsRepo    repositories.HostedSubscriptionRepository
+	userRepo  repositories.UserRepository
+}
+
+// NewAuditService creates a new AuditService.
+func NewAuditService(auditRepo repositories.AuditLogRepository, hsRepo repositories.HostedSubscriptionRepository, userRepo repositories.UserRepository) AuditService {
+	return &auditService{auditRepo: auditRepo, hsRepo: hsRepo, userRepo: userRepo}
+}
+
+// Record appends an entry to the audit log. The actor, IP address and user agent are taken from the
+// request context, which carries the locals set by the auth and request metadata middleware.
+// When called inside a transaction, the entry is written as part of it.
+func (s *auditService) Record(ctx context.Context, entry AuditEntry) error {
+	changes, err := diffAuditSnapshots(entry.Before, entry.After)
+	if err != nil {
+		return fmt.Errorf("diffing %s snapshots: %w", entry.Action, err)
+	}
+
+	entityType, _, _ := strings.Cut(string(entry.Action), ".")
+	auditLog := &models.AuditLog{
+		ActorUserID:          entry.ActorUserID,
+		Action:               entry.Action,
+		EntityType:           entityType,
+		EntityID:             entry.EntityID,
+		HostedSubscriptionID: entry.HostedSubscriptionID,
+		Changes:              changes,
+	}
+	if auditLog.ActorUserID == nil {
+		if userID, ok := ctx.Value(middleware.UserIDKey).(uint); ok {
+			auditLog.ActorUserID = &userID
+		}
+	}
+	if ip, ok := ctx.Value(middleware.ClientIPKey).(string); ok {
+		auditLog.IPAddress = ip
+	}
+	if userAgent, ok := ctx.Value(middleware.UserAgentKey).(string); ok {
+		if len(userAgent) > maxAuditUserAgentLength {
+			userAgent = userAgent[:maxAuditUserAgentLength]
+		}
+		auditLog.UserAgent = userAgent
+	}
+
+	if err := s.auditRepo.Create(ctx, auditLog); err != nil {
+		return fmt.Errorf("recording %s audit entry: %w", entry.Action, err)
+	}
+	return nil
+}
+
+// ListForHostedSubscription retrieves the audit log of a subscription owned by the host.
+func (s *auditService) ListForHostedSubscription(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, filter models.AuditLogFilter, limit int, offset int) ([]models.AuditLog, error) {
+	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching hosted subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+
+	filter.HostedSubscriptionID = &hostedSubscriptionID
+	return s.list(ctx, filter, limit, offset)
+}
+
+// ListAll retrieves the audit log across all users. Only admins may do so.
+func (s *auditService) ListAll(ctx context.Context, adminUserID uint, filter models.AuditLogFilter, limit int, offset int) ([]models.AuditLog, error) {
+	user, err := s.userRepo.FindByID(ctx, adminUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("fetching user for admin check: %w", err)
+	}
+	if !user.IsAdmin {
+		return nil, ErrForbidden
+	}
+	return s.list(ctx, filter, limit, offset)
+}
+
+func (s *auditService) list(ctx context.Context, filter models.AuditLogFilter, limit int, offset int) ([]models.AuditLog, error) {
+	if limit <= 0 {
+		limit = defaultAuditLogPageSize
+	}
+	limit = min(limit, maxAuditLogPageSize)
+	offset = max(offset, 0)
+
+	entries, err := s.auditRepo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// diffAuditSnapshots compares the JSON representation of two snapshots field by field.
+// Only scalar fields are compared; preloaded associations are audited through their own entries,
+// and fields hidden from JSON (such as password hashes and secrets) never reach the log.
+func diffAuditSnapshots(before, after any) (models.AuditChanges, error) {
+	beforeFields, err := auditSnapshotFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditSnapshotFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := models.AuditChanges{}
+	for field, newValue := range afterFields {
+		oldValue, existed := beforeFields[field]
+		if !existed || !reflect.DeepEqual(oldValue, newValue) {
+			changes[field] = models.AuditChange{Before: oldValue, After: newValue}
+		}
+	}
+	for field, oldValue := range beforeFields {
+		if _, exists := afterFields[field]; !exists {
+			changes[field] = models.AuditChange{Before: oldValue}
+		}
+	}
+	return changes, nil
+}
+
+func auditSnapshotFields(snapshot any) (map[string]any, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for field, value := range fields {
+		if ignoredAuditFields[field] || !isScalarAuditValue(value) {
+			delete(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// isScalarAuditValue reports whether a decoded JSON value is a scalar or a list of scalars.
+func isScalarAuditValue(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		return false
+	case []any:
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]any, []any:
+				return false
+			}
+		}
+	}
+	return true
+}
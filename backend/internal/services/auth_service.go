@@ -25,12 +25,14 @@ type AuthService interface {
 }
 
 type authService struct {
-	userRepo repositories.UserRepository
-	cfg      *config.Config
+	userRepo   repositories.UserRepository
+	transactor repositories.Transactor
+	auditSvc   AuditService
+	cfg        *config.Config
 }
 
-func NewAuthService(userRepo repositories.UserRepository, cfg *config.Config) AuthService {
-	return &authService{userRepo: userRepo, cfg: cfg}
+func NewAuthService(userRepo repositories.UserRepository, transactor repositories.Transactor, auditSvc AuditService, cfg *config.Config) AuthService {
+	return &authService{userRepo: userRepo, transactor: transactor, auditSvc: auditSvc, cfg: cfg}
 }
 
 func (s *authService) SignUpUser(ctx context.Context, email, password string, fullName string) (*models.User, error) {
@@ -55,7 +57,17 @@ func (s *authService) SignUpUser(ctx context.Context, email, password string, fu
 		FullName: fullName,
 	}
 
-	err = s.userRepo.CreateUser(ctx, newUser)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.CreateUser(ctx, newUser); err != nil {
+			return err
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:      models.AuditUserSignUp,
+			EntityID:    newUser.ID,
+			After:       newUser,
+			ActorUserID: &newUser.ID,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
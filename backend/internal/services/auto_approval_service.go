@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	maxAutoApprovalRulesPerSubscription = 10
+	defaultAutoApprovalLogLimit         = 20
+	maxAutoApprovalLogLimit             = 100
+)
+
+// Custom errors for AutoApprovalService
+var (
+	ErrAutoApprovalRuleNotFound     = errors.New("auto-approval rule not found")
+	ErrAutoApprovalRuleNoConditions = errors.New("an auto-approval rule needs at least one condition")
+	ErrTooManyAutoApprovalRules     = errors.New("maximum number of auto-approval rules reached")
+)
+
+// AutoApprovalService defines the interface for managing a hosted subscription's auto-approval rules and log.
+// Rules are evaluated, and auto-approvals reverted, by the PaymentService.
+type AutoApprovalService interface {
+	ListRules(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) ([]models.AutoApprovalRule, error)
+	CreateRule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.AutoApprovalRuleRequest) (*models.AutoApprovalRule, error)
+	UpdateRule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, ruleID uint, req *models.AutoApprovalRuleRequest) (*models.AutoApprovalRule, error)
+	DeleteRule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, ruleID uint) error
+	ListApprovals(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, limit int, offset int) ([]models.AutoApproval, error)
+}
+
+type autoApprovalService struct {
+	autoApprovalRepo repositories.AutoApprovalRepository
+	hostedSubRepo    repositories.HostedSubscriptionRepository
+	transactor       repositories.Transactor
+	auditSvc         AuditService
+}
+
+// NewAutoApprovalService creates a new AutoApprovalService.
+func NewAutoApprovalService(
+	autoApprovalRepo repositories.AutoApprovalRepository,
+	hostedSubRepo repositories.HostedSubscriptionRepository,
+	transactor repositories.Transactor,
+	auditSvc AuditService,
+) AutoApprovalService {
+	return &autoApprovalService{autoApprovalRepo: autoApprovalRepo, hostedSubRepo: hostedSubRepo, transactor: transactor, auditSvc: auditSvc}
+}
+
+// ListRules retrieves the auto-approval rules of a hosted subscription owned by the host, in evaluation order.
+func (s *autoApprovalService) ListRules(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) ([]models.AutoApprovalRule, error) {
+	if _, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID); err != nil {
+		return nil, err
+	}
+	rules, err := s.autoApprovalRepo.ListRulesBySubscriptionID(ctx, hostedSubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing auto-approval rules: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRule adds an auto-approval rule to a hosted subscription owned by the host. It is evaluated after the existing ones.
+func (s *autoApprovalService) CreateRule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.AutoApprovalRuleRequest) (*models.AutoApprovalRule, error) {
+	if _, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID); err != nil {
+		return nil, err
+	}
+	existing, err := s.autoApprovalRepo.ListRulesBySubscriptionID(ctx, hostedSubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing auto-approval rules: %w", err)
+	}
+	if len(existing) >= maxAutoApprovalRulesPerSubscription {
+		return nil, ErrTooManyAutoApprovalRules
+	}
+
+	rule := &models.AutoApprovalRule{HostedSubscriptionID: hostedSubscriptionID}
+	applyAutoApprovalRuleRequest(rule, req)
+	if !rule.HasConditions() {
+		return nil, ErrAutoApprovalRuleNoConditions
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.autoApprovalRepo.CreateRule(ctx, rule); err != nil {
+			return fmt.Errorf("creating auto-approval rule: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditAutoApprovalRuleCreate,
+			EntityID:             rule.ID,
+			HostedSubscriptionID: &hostedSubscriptionID,
+			After:                rule,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule replaces the name, conditions and enabled state of one of a hosted subscription's auto-approval rules.
+func (s *autoApprovalService) UpdateRule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, ruleID uint, req *models.AutoApprovalRuleRequest) (*models.AutoApprovalRule, error) {
+	rule, err := s.getOwnedRule(ctx, hostUserID, hostedSubscriptionID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	before := *rule
+	applyAutoApprovalRuleRequest(rule, req)
+	if !rule.HasConditions() {
+		return nil, ErrAutoApprovalRuleNoConditions
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.autoApprovalRepo.UpdateRule(ctx, rule); err != nil {
+			return fmt.Errorf("updating auto-approval rule: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditAutoApprovalRuleUpdate,
+			EntityID:             rule.ID,
+			HostedSubscriptionID: &hostedSubscriptionID,
+			Before:               &before,
+			After:                rule,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule removes one of a hosted subscription's auto-approval rules.
+func (s *autoApprovalService) DeleteRule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, ruleID uint) error {
+	rule, err := s.getOwnedRule(ctx, hostUserID, hostedSubscriptionID, ruleID)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.autoApprovalRepo.DeleteRule(ctx, ruleID); err != nil {
+			return fmt.Errorf("deleting auto-approval rule: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditAutoApprovalRuleDelete,
+			EntityID:             ruleID,
+			HostedSubscriptionID: &hostedSubscriptionID,
+			Before:               rule,
+		})
+	})
+}
+
+// ListApprovals retrieves a page of the payment records a hosted subscription's rules approved, newest first,
+// with the rule that approved each and whether the host reverted it.
+func (s *autoApprovalService) ListApprovals(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, limit int, offset int) ([]models.AutoApproval, error) {
+	if _, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultAutoApprovalLogLimit
+	}
+	limit = min(limit, maxAutoApprovalLogLimit)
+	offset = max(offset, 0)
+
+	approvals, err := s.autoApprovalRepo.ListApprovalsBySubscriptionID(ctx, hostedSubscriptionID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing auto-approvals: %w", err)
+	}
+	return approvals, nil
+}
+
+func (s *autoApprovalService) getOwnedSubscription(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.HostedSubscription, error) {
+	hostedSub, err := s.hostedSubRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+	return hostedSub, nil
+}
+
+func (s *autoApprovalService) getOwnedRule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, ruleID uint) (*models.AutoApprovalRule, error) {
+	if _, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID); err != nil {
+		return nil, err
+	}
+	rule, err := s.autoApprovalRepo.GetRuleByID(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAutoApprovalRuleNotFound
+		}
+		return nil, fmt.Errorf("fetching auto-approval rule: %w", err)
+	}
+	if rule.HostedSubscriptionID != hostedSubscriptionID {
+		return nil, ErrAutoApprovalRuleNotFound
+	}
+	return rule, nil
+}
+
+func applyAutoApprovalRuleRequest(rule *models.AutoApprovalRule, req *models.AutoApprovalRuleRequest) {
+	rule.Name = req.Name
+	rule.Enabled = req.Enabled
+	rule.RequireExactAmount = req.RequireExactAmount
+	rule.RequireUniqueReference = req.RequireUniqueReference
+	rule.RequireVerifiedSlip = req.RequireVerifiedSlip
+	rule.MinOnTimeApprovals = req.MinOnTimeApprovals
+}
@@ -0,0 +1,23 @@
+package services
+
+// BulkItemResult is the outcome of reviewing one item of a bulk request. Err is nil when the review succeeded,
+// and is the error the single-item review would have returned otherwise.
+type BulkItemResult struct {
+	ID     uint
+	Status string // The item's status after a successful review
+	Err    error
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence, so a bulk request reviews each item once.
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/ical"
+	"github.com/xNatthapol/hubster/internal/mail"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	calendarProductID       = "-//Hubster//Payment Calendar//EN"
+	calendarRefreshInterval = 6 * time.Hour
+	calendarUIDDomain       = "hubster"
+)
+
+// Custom errors for CalendarService
+var (
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+	ErrInvalidRenewalDate   = errors.New("invalid renewal date: use YYYY-MM-DD")
+)
+
+// CalendarService defines the interface for users' iCalendar feeds of payment due dates and renewal dates.
+type CalendarService interface {
+	GetFeed(ctx context.Context, userID uint) (*models.CalendarFeedResponse, error)
+	IssueFeedToken(ctx context.Context, userID uint) (string, *models.CalendarFeedResponse, error)
+	DeleteFeed(ctx context.Context, userID uint) error
+	RenderFeed(ctx context.Context, token string) ([]byte, error)
+	GetRenewalDate(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.RenewalDateResponse, error)
+	UpdateRenewalDate(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateRenewalDateRequest) (*models.RenewalDateResponse, error)
+}
+
+type calendarService struct {
+	feedRepo       repositories.CalendarFeedRepository
+	membershipRepo repositories.SubscriptionMembershipRepository
+	hostedSubRepo  repositories.HostedSubscriptionRepository
+	transactor     repositories.Transactor
+	auditSvc       AuditService
+	location       *time.Location
+}
+
+// NewCalendarService creates a new CalendarService. Payment due dates are published as the calendar day
+// they fall on in location.
+func NewCalendarService(
+	feedRepo repositories.CalendarFeedRepository,
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	hostedSubRepo repositories.HostedSubscriptionRepository,
+	transactor repositories.Transactor,
+	auditSvc AuditService,
+	location *time.Location,
+) CalendarService {
+	return &calendarService{
+		feedRepo:       feedRepo,
+		membershipRepo: membershipRepo,
+		hostedSubRepo:  hostedSubRepo,
+		transactor:     transactor,
+		auditSvc:       auditSvc,
+		location:       location,
+	}
+}
+
+// GetFeed returns whether the user has a calendar feed. The feed URL cannot be shown again; a new one is issued instead.
+func (s *calendarService) GetFeed(ctx context.Context, userID uint) (*models.CalendarFeedResponse, error) {
+	feed, err := s.feedRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.CalendarFeedResponse{Enabled: false}, nil
+		}
+		return nil, fmt.Errorf("fetching calendar feed: %w", err)
+	}
+	return calendarFeedResponse(feed), nil
+}
+
+// IssueFeedToken creates the user's calendar feed, or rotates its token so that the previous feed URL stops
+// working. The token is returned only this once.
+func (s *calendarService) IssueFeedToken(ctx context.Context, userID uint) (string, *models.CalendarFeedResponse, error) {
+	token, err := generateCalendarFeedToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var feed *models.CalendarFeed
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.feedRepo.GetByUserID(ctx, userID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			feed = &models.CalendarFeed{UserID: userID, TokenHash: hashCalendarFeedToken(token)}
+			if err := s.feedRepo.Create(ctx, feed); err != nil {
+				return fmt.Errorf("creating calendar feed: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("fetching calendar feed: %w", err)
+		default:
+			feed = existing
+			if err := s.feedRepo.UpdateTokenHash(ctx, feed.ID, hashCalendarFeedToken(token)); err != nil {
+				return fmt.Errorf("rotating calendar feed token: %w", err)
+			}
+			feed.UpdatedAt = time.Now()
+			feed.LastFetchedAt = nil
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:   models.AuditCalendarFeedIssue,
+			EntityID: feed.ID,
+		})
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return token, calendarFeedResponse(feed), nil
+}
+
+// DeleteFeed turns the user's calendar feed off.
+func (s *calendarService) DeleteFeed(ctx context.Context, userID uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		feed, err := s.feedRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCalendarFeedNotFound
+			}
+			return fmt.Errorf("fetching calendar feed: %w", err)
+		}
+		if err := s.feedRepo.Delete(ctx, feed.ID); err != nil {
+			return fmt.Errorf("deleting calendar feed: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:   models.AuditCalendarFeedDelete,
+			EntityID: feed.ID,
+			Before:   feed,
+		})
+	})
+}
+
+// RenderFeed encodes the calendar of the feed with the given token: an all-day event on the next payment date
+// of each of the user's memberships, and a recurring event on the renewal date of each subscription they host.
+// Event UIDs are derived from the membership or subscription, so a moved date updates the existing event.
+func (s *calendarService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := s.feedRepo.GetByTokenHash(ctx, hashCalendarFeedToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCalendarFeedNotFound
+		}
+		return nil, fmt.Errorf("fetching calendar feed: %w", err)
+	}
+
+	memberships, err := s.membershipRepo.ListByUserID(ctx, feed.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("listing memberships for calendar feed %d: %w", feed.ID, err)
+	}
+	hostedSubs, err := s.hostedSubRepo.ListByHostID(ctx, feed.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("listing hosted subscriptions for calendar feed %d: %w", feed.ID, err)
+	}
+
+	cal := ical.Calendar{
+		ProductID:       calendarProductID,
+		Name:            "Hubster payments",
+		RefreshInterval: calendarRefreshInterval,
+	}
+	for i := range memberships {
+		if event, ok := s.membershipEvent(&memberships[i]); ok {
+			cal.Events = append(cal.Events, event)
+		}
+	}
+	for i := range hostedSubs {
+		if event, ok := renewalEvent(&hostedSubs[i]); ok {
+			cal.Events = append(cal.Events, event)
+		}
+	}
+
+	now := time.Now()
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, cal, now); err != nil {
+		return nil, fmt.Errorf("encoding calendar feed %d: %w", feed.ID, err)
+	}
+	if err := s.feedRepo.MarkFetched(ctx, feed.ID, now); err != nil {
+		log.Printf("Warning: Failed to record fetch of calendar feed %d: %v", feed.ID, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GetRenewalDate returns the renewal date of a hosted subscription owned by the host.
+func (s *calendarService) GetRenewalDate(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.RenewalDateResponse, error) {
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return renewalDateResponse(hostedSub), nil
+}
+
+// UpdateRenewalDate sets or clears the renewal date of a hosted subscription owned by the host.
+func (s *calendarService) UpdateRenewalDate(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateRenewalDateRequest) (*models.RenewalDateResponse, error) {
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	renewalDate, err := parseRenewalDate(req.RenewalDate)
+	if err != nil {
+		return nil, err
+	}
+
+	before := renewalDateResponse(hostedSub)
+	hostedSub.RenewalDate = renewalDate
+	after := renewalDateResponse(hostedSub)
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.hostedSubRepo.UpdateRenewalDate(ctx, hostedSub.ID, renewalDate); err != nil {
+			return fmt.Errorf("saving renewal date: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditRenewalDateUpdate,
+			EntityID:             hostedSub.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               before,
+			After:                after,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// membershipEvent is the all-day event on a membership's next payment date, if it has one.
+func (s *calendarService) membershipEvent(membership *models.SubscriptionMembership) (ical.Event, bool) {
+	if membership.NextPaymentDate == nil {
+		return ical.Event{}, false
+	}
+	hostedSub := &membership.HostedSubscription
+
+	summary := fmt.Sprintf("Pay %s", hostedSub.SubscriptionTitle)
+	if amount := membershipAmountDue(membership); amount > 0 {
+		summary += " (" + mail.FormatAmount(amount, mail.LocaleEnglish) + ")"
+	}
+	description := fmt.Sprintf("%s payment to %s for %s.", hostedSub.BillingCycle, hostedSub.User.FullName, hostedSub.SubscriptionTitle)
+	if membership.OutstandingAmount() > 0 {
+		description += " Includes an outstanding balance from earlier cycles."
+	}
+
+	return ical.Event{
+		UID:          fmt.Sprintf("membership-%d@%s", membership.ID, calendarUIDDomain),
+		Summary:      summary,
+		Description:  description,
+		Date:         startOfDay(*membership.NextPaymentDate, s.location),
+		LastModified: membership.UpdatedAt,
+	}, true
+}
+
+// renewalEvent is the event on a hosted subscription's renewal date, repeating every billing cycle.
+func renewalEvent(hostedSub *models.HostedSubscription) (ical.Event, bool) {
+	if hostedSub.RenewalDate == nil {
+		return ical.Event{}, false
+	}
+	rule := "FREQ=YEARLY"
+	if hostedSub.BillingCycle == models.BillingMonthly {
+		rule = "FREQ=MONTHLY"
+		// A plan renewing on the 29th to 31st renews on the last day of shorter months rather than skipping them
+		if day := hostedSub.RenewalDate.Day(); day > 28 {
+			days := make([]string, 0, day-27)
+			for d := 28; d <= day; d++ {
+				days = append(days, strconv.Itoa(d))
+			}
+			rule += ";BYMONTHDAY=" + strings.Join(days, ",") + ";BYSETPOS=-1"
+		}
+	}
+	return ical.Event{
+		UID:            fmt.Sprintf("hosted-subscription-%d-renewal@%s", hostedSub.ID, calendarUIDDomain),
+		Summary:        fmt.Sprintf("%s renews", hostedSub.SubscriptionTitle),
+		Description:    fmt.Sprintf("Your %s plan renews at %s.", hostedSub.SubscriptionService.Name, mail.FormatAmount(hostedSub.CostPerCycle, mail.LocaleEnglish)),
+		Date:           *hostedSub.RenewalDate,
+		RecurrenceRule: rule,
+		LastModified:   hostedSub.UpdatedAt,
+	}, true
+}
+
+func (s *calendarService) getOwnedSubscription(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.HostedSubscription, error) {
+	hostedSub, err := s.hostedSubRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+	return hostedSub, nil
+}
+
+// parseRenewalDate parses a YYYY-MM-DD renewal date; an empty string is no date.
+func parseRenewalDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil, ErrInvalidRenewalDate
+	}
+	return &date, nil
+}
+
+func renewalDateResponse(hostedSub *models.HostedSubscription) *models.RenewalDateResponse {
+	return &models.RenewalDateResponse{
+		HostedSubscriptionID: hostedSub.ID,
+		BillingCycle:         hostedSub.BillingCycle,
+		RenewalDate:          hostedSub.RenewalDate,
+	}
+}
+
+func calendarFeedResponse(feed *models.CalendarFeed) *models.CalendarFeedResponse {
+	return &models.CalendarFeedResponse{
+		Enabled:       true,
+		IssuedAt:      &feed.UpdatedAt,
+		LastFetchedAt: feed.LastFetchedAt,
+	}
+}
+
+// generateCalendarFeedToken returns a new random feed token.
+func generateCalendarFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating calendar feed token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashCalendarFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
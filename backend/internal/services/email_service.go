@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/xNatthapol/hubster/internal/mail"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+)
+
+// EmailService defines the interface for sending templated emails to users.
+type EmailService interface {
+	// SendTemplate renders tmpl in the user's preferred language and emails it, unless the user opted out of that kind of email.
+	SendTemplate(ctx context.Context, userID uint, tmpl mail.Template, data any) error
+}
+
+type emailService struct {
+	mailer   mail.Mailer
+	renderer *mail.Renderer
+	userRepo repositories.UserRepository
+	prefSvc  NotificationPreferenceService
+}
+
+// NewEmailService creates a new EmailService. A nil mailer disables sending.
+func NewEmailService(
+	mailer mail.Mailer,
+	renderer *mail.Renderer,
+	userRepo repositories.UserRepository,
+	prefSvc NotificationPreferenceService,
+) EmailService {
+	if mailer == nil {
+		log.Println("WARNING: EmailService created without a mailer. Emails will not be sent.")
+	}
+	return &emailService{mailer: mailer, renderer: renderer, userRepo: userRepo, prefSvc: prefSvc}
+}
+
+// SendTemplate renders and sends an email to the user if they allow emails of this kind.
+func (s *emailService) SendTemplate(ctx context.Context, userID uint, tmpl mail.Template, data any) error {
+	if s.mailer == nil {
+		return nil
+	}
+
+	pref, err := s.prefSvc.GetPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !emailAllowed(pref, tmpl) {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("fetching user %d: %w", userID, err)
+	}
+
+	msg, err := s.renderer.Render(tmpl, mail.ParseLocale(pref.Locale, mail.LocaleThai), data)
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	return s.mailer.Send(ctx, msg)
+}
+
+func emailAllowed(pref *models.NotificationPreference, tmpl mail.Template) bool {
+	switch tmpl {
+	case mail.TemplateJoinApproved:
+		return pref.EmailJoinApprovals
+	case mail.TemplatePaymentReminder:
+		return pref.EmailPaymentReminders
+	case mail.TemplatePaymentApproved, mail.TemplatePaymentDeclined:
+		return pref.EmailPaymentOutcomes
+	case mail.TemplateHostDigest:
+		return pref.EmailWeeklyDigest
+	default:
+		return true
+	}
+}
+
+type emailNotificationChannel struct {
+	emailSvc          EmailService
+	hostedSubRepo     repositories.HostedSubscriptionRepository
+	membershipRepo    repositories.SubscriptionMembershipRepository
+	paymentRecordRepo repositories.PaymentRecordRepository
+}
+
+// NewEmailNotificationChannel creates a NotificationChannel that emails the notifications members care about:
+// join approvals, payment reminders and payment review outcomes. Other notification types are in-app only.
+func NewEmailNotificationChannel(
+	emailSvc EmailService,
+	hostedSubRepo repositories.HostedSubscriptionRepository,
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	paymentRecordRepo repositories.PaymentRecordRepository,
+) NotificationChannel {
+	return &emailNotificationChannel{
+		emailSvc:          emailSvc,
+		hostedSubRepo:     hostedSubRepo,
+		membershipRepo:    membershipRepo,
+		paymentRecordRepo: paymentRecordRepo,
+	}
+}
+
+func (c *emailNotificationChannel) Name() string {
+	return "email"
+}
+
+// Deliver emails the notification to its recipient if its type has an email template.
+func (c *emailNotificationChannel) Deliver(ctx context.Context, notification *models.Notification) error {
+	switch notification.Type {
+	case models.NotificationJoinRequestApproved:
+		return c.deliverJoinApproved(ctx, notification)
+	case models.NotificationPaymentDue:
+		return c.deliverPaymentReminder(ctx, notification)
+	case models.NotificationPaymentProofApproved, models.NotificationPaymentProofDeclined:
+		return c.deliverPaymentOutcome(ctx, notification)
+	default:
+		return nil
+	}
+}
+
+func (c *emailNotificationChannel) deliverJoinApproved(ctx context.Context, notification *models.Notification) error {
+	if notification.HostedSubscriptionID == nil {
+		return nil
+	}
+	hostedSub, err := c.hostedSubRepo.GetByID(ctx, *notification.HostedSubscriptionID)
+	if err != nil {
+		return fmt.Errorf("fetching hosted subscription %d: %w", *notification.HostedSubscriptionID, err)
+	}
+	membership, err := c.membershipRepo.FindByUserAndSubscription(ctx, notification.UserID, hostedSub.ID)
+	if err != nil {
+		return fmt.Errorf("fetching membership of user %d: %w", notification.UserID, err)
+	}
+
+	return c.emailSvc.SendTemplate(ctx, notification.UserID, mail.TemplateJoinApproved, mail.JoinApprovedData{
+		RecipientName:    memberName(hostedSub, notification.UserID),
+		HostName:         hostedSub.User.FullName,
+		SubscriptionName: hostedSub.SubscriptionTitle,
+		Amount:           costPerSlot(hostedSub),
+		DueDate:          membership.NextPaymentDate,
+	})
+}
+
+func (c *emailNotificationChannel) deliverPaymentReminder(ctx context.Context, notification *models.Notification) error {
+	if notification.HostedSubscriptionID == nil {
+		return nil
+	}
+	membership, err := c.membershipRepo.FindByUserAndSubscription(ctx, notification.UserID, *notification.HostedSubscriptionID)
+	if err != nil {
+		return fmt.Errorf("fetching membership of user %d: %w", notification.UserID, err)
+	}
+	if membership.NextPaymentDate == nil {
+		return nil
+	}
+	hostedSub, err := c.hostedSubRepo.GetByID(ctx, membership.HostedSubscriptionID)
+	if err != nil {
+		return fmt.Errorf("fetching hosted subscription %d: %w", membership.HostedSubscriptionID, err)
+	}
+	membership.HostedSubscription = *hostedSub
+	amountDue := membershipAmountDue(membership)
+	if amountDue <= 0 {
+		// Paid or covered by credit since the reminder was created.
+		return nil
+	}
+
+	return c.emailSvc.SendTemplate(ctx, notification.UserID, mail.TemplatePaymentReminder, mail.PaymentReminderData{
+		RecipientName:    memberName(hostedSub, notification.UserID),
+		SubscriptionName: hostedSub.SubscriptionTitle,
+		Amount:           amountDue,
+		DueDate:          *membership.NextPaymentDate,
+		Overdue:          membership.NextPaymentDate.Before(notification.CreatedAt),
+	})
+}
+
+func (c *emailNotificationChannel) deliverPaymentOutcome(ctx context.Context, notification *models.Notification) error {
+	if notification.PaymentRecordID == nil {
+		return nil
+	}
+	record, err := c.paymentRecordRepo.GetByID(ctx, *notification.PaymentRecordID)
+	if err != nil {
+		return fmt.Errorf("fetching payment record %d: %w", *notification.PaymentRecordID, err)
+	}
+	membership := record.SubscriptionMembership
+
+	if notification.Type == models.NotificationPaymentProofDeclined {
+		return c.emailSvc.SendTemplate(ctx, notification.UserID, mail.TemplatePaymentDeclined, mail.PaymentDeclinedData{
+			RecipientName:    membership.User.FullName,
+			SubscriptionName: membership.HostedSubscription.SubscriptionTitle,
+			CycleLabel:       record.PaymentCycleIdentifier,
+			Reason:           record.DeclineReason,
+		})
+	}
+
+	// The membership loaded with the record may predate the approval, so re-read it for the new due date.
+	current, err := c.membershipRepo.GetByID(ctx, membership.ID)
+	if err != nil {
+		return fmt.Errorf("fetching membership %d: %w", membership.ID, err)
+	}
+	return c.emailSvc.SendTemplate(ctx, notification.UserID, mail.TemplatePaymentApproved, mail.PaymentApprovedData{
+		RecipientName:    membership.User.FullName,
+		SubscriptionName: membership.HostedSubscription.SubscriptionTitle,
+		CycleLabel:       record.PaymentCycleIdentifier,
+		Amount:           record.AmountPaid,
+		NextDueDate:      current.NextPaymentDate,
+	})
+}
+
+func costPerSlot(hostedSub *models.HostedSubscription) float64 {
+	if hostedSub.TotalSlots <= 0 {
+		return 0
+	}
+	return hostedSub.CostPerCycle / float64(hostedSub.TotalSlots)
+}
+
+// memberName looks up a member's name among the memberships preloaded with the hosted subscription.
+func memberName(hostedSub *models.HostedSubscription, userID uint) string {
+	for _, membership := range hostedSub.Memberships {
+		if membership.MemberUserID == userID {
+			return membership.User.FullName
+		}
+	}
+	return ""
+}
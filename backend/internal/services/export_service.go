@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/mail"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/pdf"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Custom errors for ExportService
+var (
+	ErrInvalidStatementMonth = errors.New("statement month must be formatted as YYYY-MM")
+)
+
+// ExportFile is a generated download. Write streams it once the request has been authorized.
+type ExportFile struct {
+	Filename    string
+	ContentType string
+	Write       func(ctx context.Context, w io.Writer) error
+}
+
+// ExportService defines the interface for exporting payment history as CSV files and PDF statements.
+type ExportService interface {
+	PaymentRecordsCSV(ctx context.Context, hostUserID uint, filter models.PaymentRecordExportFilter) (*ExportFile, error)
+	SubscriptionStatementPDF(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, month string) (*ExportFile, error)
+	MembershipStatementPDF(ctx context.Context, userID uint, membershipID uint, month string) (*ExportFile, error)
+}
+
+type exportService struct {
+	hostedSubRepo     repositories.HostedSubscriptionRepository
+	membershipRepo    repositories.SubscriptionMembershipRepository
+	paymentRecordRepo repositories.PaymentRecordRepository
+	ledgerRepo        repositories.MembershipLedgerRepository
+	userRepo          repositories.UserRepository
+	location          *time.Location
+}
+
+// NewExportService creates a new ExportService. Times and statement months are in location.
+func NewExportService(
+	hostedSubRepo repositories.HostedSubscriptionRepository,
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	paymentRecordRepo repositories.PaymentRecordRepository,
+	ledgerRepo repositories.MembershipLedgerRepository,
+	userRepo repositories.UserRepository,
+	location *time.Location,
+) ExportService {
+	return &exportService{
+		hostedSubRepo:     hostedSubRepo,
+		membershipRepo:    membershipRepo,
+		paymentRecordRepo: paymentRecordRepo,
+		ledgerRepo:        ledgerRepo,
+		userRepo:          userRepo,
+		location:          location,
+	}
+}
+
+var paymentRecordCSVHeader = []string{
+	"id", "submitted_at", "payment_cycle", "cycle_count", "member_name", "member_email",
+	"amount_expected", "amount_paid", "payment_method", "transaction_reference",
+	"status", "reviewed_at", "decline_reason", "slip_check_status",
+}
+
+// PaymentRecordsCSV exports the payment records of a hosted subscription owned by the host as CSV, oldest first.
+func (s *exportService) PaymentRecordsCSV(ctx context.Context, hostUserID uint, filter models.PaymentRecordExportFilter) (*ExportFile, error) {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, ErrInvalidReportRange
+	}
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, filter.HostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportFile{
+		Filename:    fmt.Sprintf("payment-records-%d-%s.csv", hostedSub.ID, time.Now().In(s.location).Format("20060102")),
+		ContentType: "text/csv; charset=utf-8",
+		Write: func(ctx context.Context, w io.Writer) error {
+			cw := csv.NewWriter(w)
+			if err := cw.Write(paymentRecordCSVHeader); err != nil {
+				return err
+			}
+			err := s.paymentRecordRepo.EachForExport(ctx, filter, func(row *models.PaymentRecordExportRow) error {
+				return cw.Write([]string{
+					fmt.Sprint(row.ID),
+					row.SubmittedAt.In(s.location).Format(time.RFC3339),
+					csvSafe(row.PaymentCycleIdentifier),
+					fmt.Sprint(row.CycleCount),
+					csvSafe(row.MemberName),
+					csvSafe(row.MemberEmail),
+					fmt.Sprintf("%.2f", row.AmountExpected),
+					fmt.Sprintf("%.2f", row.AmountPaid),
+					csvSafe(row.PaymentMethod),
+					csvSafe(row.TransactionReference),
+					string(row.Status),
+					s.formatOptionalTime(row.ReviewedAt),
+					csvSafe(row.DeclineReason),
+					string(row.SlipCheckStatus),
+				})
+			})
+			if err != nil {
+				return fmt.Errorf("exporting payment records: %w", err)
+			}
+			cw.Flush()
+			return cw.Error()
+		},
+	}, nil
+}
+
+// SubscriptionStatementPDF renders a hosted subscription's monthly statement for its host: every member's ledger
+// entries in the month, grouped by member, with each member's opening and closing balance and the month's totals.
+func (s *exportService) SubscriptionStatementPDF(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, month string) (*ExportFile, error) {
+	from, to, err := s.parseStatementMonth(month)
+	if err != nil {
+		return nil, err
+	}
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	header := statementHeader{
+		Title:   hostedSub.SubscriptionTitle,
+		Details: []string{"Host: " + hostedSub.User.FullName},
+		From:    from,
+		To:      to,
+	}
+	return &ExportFile{
+		Filename:    fmt.Sprintf("statement-%d-%s.pdf", hostedSub.ID, from.Format("2006-01")),
+		ContentType: "application/pdf",
+		Write: func(ctx context.Context, w io.Writer) error {
+			return s.writeStatement(ctx, w, header, hostedSub.ID, nil)
+		},
+	}, nil
+}
+
+// MembershipStatementPDF renders one membership's monthly statement, for the member or the subscription's host.
+// Members who have left can still get statements for the months they were billed in.
+func (s *exportService) MembershipStatementPDF(ctx context.Context, userID uint, membershipID uint, month string) (*ExportFile, error) {
+	from, to, err := s.parseStatementMonth(month)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := s.membershipRepo.GetByIDIncludingEnded(ctx, membershipID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMembershipNotFound
+		}
+		return nil, fmt.Errorf("fetching membership: %w", err)
+	}
+	if membership.MemberUserID != userID && membership.HostedSubscription.HostUserID != userID {
+		return nil, ErrForbidden
+	}
+	member, err := s.userRepo.FindByID(ctx, membership.MemberUserID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching member: %w", err)
+	}
+
+	header := statementHeader{
+		Title:   membership.HostedSubscription.SubscriptionTitle,
+		Details: []string{"Member: " + member.FullName, fmt.Sprintf("Membership #%d", membership.ID)},
+		From:    from,
+		To:      to,
+	}
+	return &ExportFile{
+		Filename:    fmt.Sprintf("statement-membership-%d-%s.pdf", membership.ID, from.Format("2006-01")),
+		ContentType: "application/pdf",
+		Write: func(ctx context.Context, w io.Writer) error {
+			return s.writeStatement(ctx, w, header, membership.HostedSubscriptionID, &membership.ID)
+		},
+	}, nil
+}
+
+// writeStatement streams the ledger entries of a statement into a PDF as they are read.
+func (s *exportService) writeStatement(ctx context.Context, w io.Writer, header statementHeader, hostedSubscriptionID uint, membershipID *uint) error {
+	printer := newStatementPrinter(w, header, s.location)
+
+	var current *memberStatementTotals
+	var totals memberStatementTotals
+	members := 0
+	err := s.ledgerRepo.EachStatementLine(ctx, hostedSubscriptionID, membershipID, header.From, header.To, func(line *models.StatementLine) error {
+		if current == nil || current.membershipID != line.SubscriptionMembershipID {
+			if current != nil {
+				printer.memberSummary(current)
+			}
+			members++
+			current = &memberStatementTotals{
+				membershipID: line.SubscriptionMembershipID,
+				opening:      models.RoundMoney(line.BalanceAfter - line.Amount),
+			}
+			printer.memberHeading(line.MemberName, line.SubscriptionMembershipID)
+		}
+		current.add(line)
+		totals.add(line)
+		printer.line(line)
+		return nil
+	})
+	if err != nil {
+		// Leave the document unfinished so a failed statement cannot pass for a complete one.
+		return fmt.Errorf("reading statement lines: %w", err)
+	}
+
+	if current == nil {
+		printer.note("No activity in this period.")
+	} else {
+		printer.memberSummary(current)
+		if membershipID == nil {
+			printer.totals(members, &totals)
+		}
+	}
+	return printer.doc.Close()
+}
+
+func (s *exportService) parseStatementMonth(month string) (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation("2006-01", month, s.location)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidStatementMonth
+	}
+	return from, from.AddDate(0, 1, 0), nil
+}
+
+func (s *exportService) getOwnedSubscription(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.HostedSubscription, error) {
+	hostedSub, err := s.hostedSubRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+	return hostedSub, nil
+}
+
+func (s *exportService) formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.In(s.location).Format(time.RFC3339)
+}
+
+// csvSafe keeps member-entered text from being run as a formula when the export is opened in a spreadsheet.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// memberStatementTotals sums one member's, or the whole statement's, ledger entries by kind.
+type memberStatementTotals struct {
+	membershipID uint
+	opening      float64
+	closing      float64
+	charged      float64
+	paid         float64
+	refunded     float64
+	reversed     float64
+}
+
+func (t *memberStatementTotals) add(line *models.StatementLine) {
+	switch line.Kind {
+	case models.MembershipLedgerCharge:
+		t.charged -= line.Amount
+	case models.MembershipLedgerPayment:
+		t.paid += line.Amount
+	case models.MembershipLedgerRefund:
+		t.refunded -= line.Amount
+	case models.MembershipLedgerReversal:
+		t.reversed += line.Amount
+	}
+	t.closing = line.BalanceAfter
+}
+
+type statementHeader struct {
+	Title   string
+	Details []string
+	From    time.Time
+	To      time.Time
+}
+
+const (
+	statementMargin     = 48.0
+	statementLineHeight = 14.0
+	statementFontSize   = 9.0
+)
+
+// Column positions of the statement table. Amount and balance are right-aligned on their x.
+const (
+	columnDate        = statementMargin
+	columnDescription = 118.0
+	columnKind        = 380.0
+	columnAmount      = 480.0
+	columnBalance     = pdf.PageWidth - statementMargin
+)
+
+// statementPrinter lays out a statement top to bottom, starting a new page when the current one is full.
+type statementPrinter struct {
+	doc      *pdf.Document
+	header   statementHeader
+	location *time.Location
+	y        float64
+	pages    int
+}
+
+func newStatementPrinter(w io.Writer, header statementHeader, location *time.Location) *statementPrinter {
+	p := &statementPrinter{
+		doc:      pdf.New(w, fmt.Sprintf("%s statement %s", header.Title, header.From.Format("2006-01"))),
+		header:   header,
+		location: location,
+	}
+	p.newPage()
+	return p
+}
+
+func (p *statementPrinter) newPage() {
+	p.doc.NewPage()
+	p.pages++
+	p.y = pdf.PageHeight - statementMargin
+	period := fmt.Sprintf("%s - %s", mail.FormatDate(p.header.From, p.location, mail.LocaleEnglish),
+		mail.FormatDate(p.header.To.AddDate(0, 0, -1), p.location, mail.LocaleEnglish))
+
+	if p.pages > 1 {
+		p.doc.Text(statementMargin, p.y, pdf.Regular, 8, fmt.Sprintf("%s, %s (page %d)", p.header.Title, period, p.pages))
+		p.y -= 2 * statementLineHeight
+		return
+	}
+	p.doc.Text(statementMargin, p.y, pdf.Bold, 18, "Hubster statement")
+	p.y -= 26
+	p.doc.Text(statementMargin, p.y, pdf.Bold, 12, p.header.Title)
+	p.y -= statementLineHeight + 2
+	for _, detail := range append(p.header.Details, "Period: "+period,
+		"Generated: "+mail.FormatDate(time.Now(), p.location, mail.LocaleEnglish)) {
+		p.doc.Text(statementMargin, p.y, pdf.Regular, statementFontSize, detail)
+		p.y -= statementLineHeight
+	}
+	p.doc.Text(statementMargin, p.y, pdf.Regular, 8, "Amounts in THB. A positive balance is credit; a negative balance is owed.")
+	p.y -= 2 * statementLineHeight
+}
+
+// ensure starts a new page unless lines more lines fit on the current one.
+func (p *statementPrinter) ensure(lines int) {
+	if p.y-float64(lines)*statementLineHeight < statementMargin {
+		p.newPage()
+	}
+}
+
+func (p *statementPrinter) memberHeading(memberName string, membershipID uint) {
+	p.ensure(4)
+	p.y -= 4
+	p.doc.Text(statementMargin, p.y, pdf.Bold, 11, fmt.Sprintf("%s (membership #%d)", memberName, membershipID))
+	p.y -= statementLineHeight + 2
+	p.doc.Text(columnDate, p.y, pdf.Bold, statementFontSize, "Date")
+	p.doc.Text(columnDescription, p.y, pdf.Bold, statementFontSize, "Description")
+	p.doc.Text(columnKind, p.y, pdf.Bold, statementFontSize, "Type")
+	p.rightAligned(columnAmount, pdf.Bold, "Amount")
+	p.rightAligned(columnBalance, pdf.Bold, "Balance")
+	p.y -= 4
+	p.doc.Line(statementMargin, p.y, columnBalance, p.y, 0.5)
+	p.y -= statementLineHeight - 2
+}
+
+func (p *statementPrinter) line(line *models.StatementLine) {
+	p.ensure(1)
+	p.doc.Text(columnDate, p.y, pdf.Regular, statementFontSize, mail.FormatDate(line.CreatedAt, p.location, mail.LocaleEnglish))
+	p.doc.Text(columnDescription, p.y, pdf.Regular, statementFontSize,
+		pdf.Truncate(line.Description, pdf.Regular, statementFontSize, columnKind-columnDescription-8))
+	p.doc.Text(columnKind, p.y, pdf.Regular, statementFontSize, string(line.Kind))
+	p.rightAligned(columnAmount, pdf.Regular, statementAmount(line.Amount))
+	p.rightAligned(columnBalance, pdf.Regular, statementAmount(line.BalanceAfter))
+	p.y -= statementLineHeight
+}
+
+func (p *statementPrinter) memberSummary(t *memberStatementTotals) {
+	p.ensure(3)
+	p.y -= 2
+	p.doc.Line(statementMargin, p.y+statementLineHeight-4, columnBalance, p.y+statementLineHeight-4, 0.25)
+	summary := fmt.Sprintf("Opening %s   Charged %s   Paid %s   Refunded %s",
+		statementAmount(t.opening), statementAmount(t.charged), statementAmount(t.paid), statementAmount(t.refunded))
+	if t.reversed != 0 {
+		summary += "   Reversed " + statementAmount(t.reversed)
+	}
+	p.doc.Text(statementMargin, p.y, pdf.Regular, statementFontSize, summary)
+	p.y -= statementLineHeight
+	p.doc.Text(columnKind, p.y, pdf.Bold, statementFontSize, "Closing balance")
+	p.rightAligned(columnBalance, pdf.Bold, statementAmount(t.closing))
+	p.y -= 2 * statementLineHeight
+}
+
+func (p *statementPrinter) totals(members int, t *memberStatementTotals) {
+	p.ensure(6)
+	p.doc.Line(statementMargin, p.y+statementLineHeight-4, columnBalance, p.y+statementLineHeight-4, 1)
+	p.doc.Text(statementMargin, p.y, pdf.Bold, 11, "Totals")
+	p.y -= statementLineHeight + 2
+	rows := [][2]string{
+		{"Members with activity", fmt.Sprint(members)},
+		{"Charged", statementAmount(t.charged)},
+		{"Paid", statementAmount(t.paid)},
+		{"Refunded", statementAmount(t.refunded)},
+	}
+	if t.reversed != 0 {
+		rows = append(rows, [2]string{"Reversed", statementAmount(t.reversed)})
+	}
+	for _, row := range rows {
+		p.ensure(1)
+		p.doc.Text(statementMargin, p.y, pdf.Regular, statementFontSize, row[0])
+		p.rightAligned(columnAmount, pdf.Regular, row[1])
+		p.y -= statementLineHeight
+	}
+}
+
+func (p *statementPrinter) note(text string) {
+	p.ensure(1)
+	p.doc.Text(statementMargin, p.y, pdf.Regular, statementFontSize, text)
+	p.y -= statementLineHeight
+}
+
+func (p *statementPrinter) rightAligned(x float64, font pdf.Font, text string) {
+	p.doc.Text(x-pdf.TextWidth(text, font, statementFontSize), p.y, font, statementFontSize, text)
+}
+
+func statementAmount(amount float64) string {
+	return mail.GroupThousands(fmt.Sprintf("%.2f", models.RoundMoney(amount)))
+}
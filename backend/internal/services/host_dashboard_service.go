@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+)
+
+const (
+	defaultDashboardActionItems = 50
+	maxDashboardActionItems     = 200
+)
+
+// HostDashboardService defines the interface for the summary of everything a host manages.
+type HostDashboardService interface {
+	GetDashboard(ctx context.Context, hostUserID uint, actionItemLimit int) (*models.HostDashboardResponse, error)
+}
+
+type hostDashboardService struct {
+	dashboardRepo repositories.HostDashboardRepository
+	location      *time.Location
+}
+
+// NewHostDashboardService creates a new HostDashboardService. The current cycle is the calendar month in location.
+func NewHostDashboardService(dashboardRepo repositories.HostDashboardRepository, location *time.Location) HostDashboardService {
+	return &hostDashboardService{dashboardRepo: dashboardRepo, location: location}
+}
+
+// GetDashboard summarizes each of the host's subscriptions with their totals, and lists the oldest action items.
+// Members are overdue from the day after their payment date.
+func (s *hostDashboardService) GetDashboard(ctx context.Context, hostUserID uint, actionItemLimit int) (*models.HostDashboardResponse, error) {
+	if actionItemLimit <= 0 {
+		actionItemLimit = defaultDashboardActionItems
+	}
+	actionItemLimit = min(actionItemLimit, maxDashboardActionItems)
+
+	now := time.Now().In(s.location)
+	today := startOfDay(now, s.location)
+	cycleStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, s.location)
+	cycleEnd := cycleStart.AddDate(0, 1, 0)
+
+	subscriptions, err := s.dashboardRepo.SubscriptionSummaries(ctx, hostUserID, cycleStart, cycleEnd, today)
+	if err != nil {
+		return nil, fmt.Errorf("summarizing hosted subscriptions: %w", err)
+	}
+	actionItems, err := s.dashboardRepo.ActionItems(ctx, hostUserID, today, actionItemLimit)
+	if err != nil {
+		return nil, fmt.Errorf("listing action items: %w", err)
+	}
+
+	resp := &models.HostDashboardResponse{
+		CycleStart:    cycleStart,
+		CycleEnd:      cycleEnd,
+		Subscriptions: make([]models.HostDashboardSubscription, 0, len(subscriptions)),
+		ActionItems:   make([]models.HostDashboardActionItem, 0, len(actionItems)),
+	}
+	totals := &resp.Totals
+	for _, sub := range subscriptions {
+		sub.OutstandingAmount = models.RoundMoney(sub.OutstandingAmount)
+		sub.ExpectedRevenue = models.RoundMoney(sub.ExpectedRevenue)
+		sub.CollectedRevenue = models.RoundMoney(sub.CollectedRevenue)
+
+		totals.Subscriptions++
+		totals.MembersCount += sub.MembersCount
+		totals.PendingJoinRequests += sub.PendingJoinRequests
+		totals.PendingPaymentProofs += sub.PendingPaymentProofs
+		totals.DisputedPayments += sub.DisputedPayments
+		totals.OverdueMembers += sub.OverdueMembers
+		totals.OutstandingAmount += sub.OutstandingAmount
+		totals.ExpectedRevenue += sub.ExpectedRevenue
+		totals.CollectedRevenue += sub.CollectedRevenue
+		resp.Subscriptions = append(resp.Subscriptions, sub)
+	}
+	totals.OutstandingAmount = models.RoundMoney(totals.OutstandingAmount)
+	totals.ExpectedRevenue = models.RoundMoney(totals.ExpectedRevenue)
+	totals.CollectedRevenue = models.RoundMoney(totals.CollectedRevenue)
+
+	for _, item := range actionItems {
+		if item.Amount != nil {
+			amount := models.RoundMoney(*item.Amount)
+			item.Amount = &amount
+		}
+		resp.ActionItems = append(resp.ActionItems, item)
+	}
+	return resp, nil
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/mail"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+)
+
+const (
+	hostDigestCheckInterval = time.Hour
+	hostDigestWeekday       = time.Monday
+	hostDigestHour          = 9
+)
+
+// HostDigestJob emails every host a weekly summary of their hosted subscriptions on Monday morning.
+type HostDigestJob struct {
+	hostedSubRepo     repositories.HostedSubscriptionRepository
+	joinRequestRepo   repositories.JoinRequestRepository
+	paymentRecordRepo repositories.PaymentRecordRepository
+	prefSvc           NotificationPreferenceService
+	emailSvc          EmailService
+	transactor        repositories.Transactor
+	location          *time.Location
+}
+
+// NewHostDigestJob creates a new HostDigestJob. The send time and week boundaries are computed in location.
+func NewHostDigestJob(
+	hostedSubRepo repositories.HostedSubscriptionRepository,
+	joinRequestRepo repositories.JoinRequestRepository,
+	paymentRecordRepo repositories.PaymentRecordRepository,
+	prefSvc NotificationPreferenceService,
+	emailSvc EmailService,
+	transactor repositories.Transactor,
+	location *time.Location,
+) *HostDigestJob {
+	return &HostDigestJob{
+		hostedSubRepo:     hostedSubRepo,
+		joinRequestRepo:   joinRequestRepo,
+		paymentRecordRepo: paymentRecordRepo,
+		prefSvc:           prefSvc,
+		emailSvc:          emailSvc,
+		transactor:        transactor,
+		location:          location,
+	}
+}
+
+// Start checks hourly in the background whether the digest is due, until ctx is cancelled.
+func (j *HostDigestJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(hostDigestCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := j.RunOnce(ctx, time.Now().UTC()); err != nil {
+				log.Printf("ERROR: Host digest run failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce sends the digest covering the previous seven days to every host who has not received it yet today.
+// It does nothing unless now is Monday after the send hour in the job's location.
+func (j *HostDigestJob) RunOnce(ctx context.Context, now time.Time) error {
+	local := now.In(j.location)
+	if local.Weekday() != hostDigestWeekday || local.Hour() < hostDigestHour {
+		return nil
+	}
+	periodEnd := startOfDay(local, j.location)
+	periodStart := periodEnd.AddDate(0, 0, -7)
+
+	hostIDs, err := j.hostedSubRepo.ListHostUserIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("listing hosts: %w", err)
+	}
+
+	for _, hostID := range hostIDs {
+		pref, err := j.prefSvc.GetPreferences(ctx, hostID)
+		if err != nil {
+			log.Printf("Warning: Failed to load notification preferences of host %d: %v", hostID, err)
+			continue
+		}
+		if !pref.EmailWeeklyDigest {
+			continue
+		}
+		if pref.LastDigestSentAt != nil && !pref.LastDigestSentAt.Before(periodEnd) {
+			continue
+		}
+
+		// Claim the period before sending so concurrent instances send it once. A failed send rolls the claim
+		// back and the next run retries.
+		err = j.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			claimed, err := j.prefSvc.ClaimDigest(ctx, hostID, periodEnd, now)
+			if err != nil || !claimed {
+				return err
+			}
+			return j.sendDigest(ctx, hostID, periodStart, periodEnd)
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to send weekly digest to host %d: %v", hostID, err)
+		}
+	}
+	return nil
+}
+
+func (j *HostDigestJob) sendDigest(ctx context.Context, hostID uint, periodStart time.Time, periodEnd time.Time) error {
+	subscriptions, err := j.hostedSubRepo.ListByHostID(ctx, hostID)
+	if err != nil {
+		return fmt.Errorf("listing hosted subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	pendingStatus := models.JoinRequestStatusPending
+	data := mail.HostDigestData{
+		RecipientName: subscriptions[0].User.FullName,
+		WeekStart:     periodStart,
+		Subscriptions: make([]mail.HostDigestSubscription, 0, len(subscriptions)),
+	}
+	for _, hs := range subscriptions {
+		pendingRequests, err := j.joinRequestRepo.ListBySubscriptionID(ctx, hs.ID, &pendingStatus)
+		if err != nil {
+			return fmt.Errorf("listing pending join requests of subscription %d: %w", hs.ID, err)
+		}
+		pendingProofs, err := j.paymentRecordRepo.CountByHostedSubscriptionIDAndStatuses(ctx, hs.ID, awaitingReviewStatuses)
+		if err != nil {
+			return fmt.Errorf("listing pending payment proofs of subscription %d: %w", hs.ID, err)
+		}
+		collected, err := j.paymentRecordRepo.SumApprovedByHostedSubscriptionIDBetween(ctx, hs.ID, periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("summing collected payments of subscription %d: %w", hs.ID, err)
+		}
+
+		data.Subscriptions = append(data.Subscriptions, mail.HostDigestSubscription{
+			Title:                hs.SubscriptionTitle,
+			MembersCount:         len(hs.Memberships),
+			TotalSlots:           hs.TotalSlots,
+			PendingJoinRequests:  len(pendingRequests),
+			PendingPaymentProofs: int(pendingProofs),
+			CollectedAmount:      collected,
+		})
+		data.TotalCollected += collected
+	}
+
+	return j.emailSvc.SendTemplate(ctx, hostID, mail.TemplateHostDigest, data)
+}
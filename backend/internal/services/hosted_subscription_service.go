@@ -4,13 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/xNatthapol/hubster/internal/events"
 	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/promptpay"
 	"github.com/xNatthapol/hubster/internal/repositories"
 	"gorm.io/gorm"
 	"log"
+	"strings"
 	"time"
 )
 
+// requesterPaymentHistoryLimit is how many of a requester's most recent payments their join requests show the host.
+const requesterPaymentHistoryLimit = 10
+
 var (
 	ErrSubscriptionNotFound   = errors.New("hosted subscription not found")
 	ErrSubscriptionFull       = errors.New("subscription has no available slots")
@@ -22,6 +28,9 @@ var (
 	ErrJoinRequestNotPending  = errors.New("join request is not in pending state")
 	ErrCannotManageRequest    = errors.New("you are not authorized to manage this join request")
 	ErrForbidden              = errors.New("forbidden: action not allowed")
+	ErrInviteRequired         = errors.New("this subscription is private: you need an invite to join it")
+	ErrTotalSlotsBelowMembers = errors.New("total slots cannot be fewer than the host, current members and slots held for the waitlist")
+	ErrInvalidScreeningAnswer = errors.New("invalid answers to the screening questions")
 )
 
 // HostedSubscriptionService defines the interface for managing hosted subscriptions.
@@ -30,13 +39,21 @@ type HostedSubscriptionService interface {
 	ListHostedSubscriptionsByUserID(ctx context.Context, hostUserID uint) ([]models.HostedSubscriptionResponse, error)
 	ExploreAllHostedSubscriptions(ctx context.Context, filters *models.ExploreSubscriptionFilters, sortBy string) ([]models.HostedSubscriptionResponse, error)
 	GetHostedSubscriptionDetailsByID(ctx context.Context, id uint, authenticatedUserID uint) (*models.HostedSubscriptionResponse, error)
-	CreateJoinRequest(ctx context.Context, requesterUserID uint, hostedSubscriptionID uint) (*models.JoinRequest, error)
-	ListJoinRequestsForHost(ctx context.Context, hostUserID uint, subscriptionID uint, statusFilter *models.JoinRequestStatus) ([]models.JoinRequest, error)
+	CreateJoinRequest(ctx context.Context, requesterUserID uint, hostedSubscriptionID uint, req *models.CreateJoinRequestRequest) (*models.JoinRequest, error)
+	ListJoinRequestsForHost(ctx context.Context, hostUserID uint, subscriptionID uint, statusFilter *models.JoinRequestStatus) ([]models.JoinRequestDetailsResponse, error)
 	ApproveJoinRequest(ctx context.Context, hostUserID uint, requestID uint) (*models.SubscriptionMembership, error)
 	DeclineJoinRequest(ctx context.Context, hostUserID uint, requestID uint) error
+	BulkApproveJoinRequests(ctx context.Context, hostUserID uint, requestIDs []uint) []BulkItemResult
+	BulkDeclineJoinRequests(ctx context.Context, hostUserID uint, requestIDs []uint) []BulkItemResult
 	ListMyJoinRequests(ctx context.Context, requesterUserID uint) ([]models.JoinRequest, error)
 	ListMyMemberships(ctx context.Context, memberUserID uint) ([]models.SubscriptionMembershipResponse, error)
 	ListMembersOfSubscription(ctx context.Context, authenticatedUserID uint, hostedSubscriptionID uint) ([]models.SubscriptionMembershipResponse, error)
+	LeaveSubscription(ctx context.Context, memberUserID uint, membershipID uint) error
+	RemoveMember(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, membershipID uint) error
+	UpdateVisibility(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateVisibilityRequest) (*models.HostedSubscriptionResponse, error)
+	UpdateTotalSlots(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateTotalSlotsRequest) (*models.HostedSubscriptionResponse, error)
+	GetScreeningQuestions(ctx context.Context, userID uint, hostedSubscriptionID uint) ([]models.ScreeningQuestion, error)
+	UpdateScreeningQuestions(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateScreeningQuestionsRequest) ([]models.ScreeningQuestion, error)
 }
 
 type hostedSubscriptionService struct {
@@ -45,6 +62,12 @@ type hostedSubscriptionService struct {
 	membershipRepo  repositories.SubscriptionMembershipRepository
 	subServiceRepo  repositories.SubscriptionServiceRepository
 	userRepo        repositories.UserRepository
+	waitlistRepo    repositories.WaitlistRepository
+	screeningRepo   repositories.ScreeningQuestionRepository
+	paymentRepo     repositories.PaymentRecordRepository
+	transactor      repositories.Transactor
+	eventPublisher  events.Publisher
+	auditSvc        AuditService
 }
 
 // NewHostedSubscriptionService creates a new HostedSubscriptionService.
@@ -54,6 +77,12 @@ func NewHostedSubscriptionService(
 	membershipRepo repositories.SubscriptionMembershipRepository,
 	subServiceRepo repositories.SubscriptionServiceRepository,
 	userRepo repositories.UserRepository,
+	waitlistRepo repositories.WaitlistRepository,
+	screeningRepo repositories.ScreeningQuestionRepository,
+	paymentRepo repositories.PaymentRecordRepository,
+	transactor repositories.Transactor,
+	eventPublisher events.Publisher,
+	auditSvc AuditService,
 ) HostedSubscriptionService {
 	return &hostedSubscriptionService{
 		hsRepo:          hsRepo,
@@ -61,6 +90,12 @@ func NewHostedSubscriptionService(
 		membershipRepo:  membershipRepo,
 		subServiceRepo:  subServiceRepo,
 		userRepo:        userRepo,
+		waitlistRepo:    waitlistRepo,
+		screeningRepo:   screeningRepo,
+		paymentRepo:     paymentRepo,
+		transactor:      transactor,
+		eventPublisher:  eventPublisher,
+		auditSvc:        auditSvc,
 	}
 }
 
@@ -74,6 +109,21 @@ func (s *hostedSubscriptionService) CreateHostedSubscription(ctx context.Context
 		return nil, fmt.Errorf("validating subscription service ID: %w", err)
 	}
 
+	var promptPayID string
+	if req.PromptPayID != "" {
+		if promptPayID, err = promptpay.NormalizeID(req.PromptPayID); err != nil {
+			return nil, ErrInvalidPromptPayID
+		}
+	}
+	renewalDate, err := parseRenewalDate(req.RenewalDate)
+	if err != nil {
+		return nil, err
+	}
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = models.VisibilityPublic
+	}
+
 	hsDB := &models.HostedSubscription{
 		HostUserID:            hostUserID,
 		SubscriptionServiceID: req.SubscriptionServiceID,
@@ -83,11 +133,25 @@ func (s *hostedSubscriptionService) CreateHostedSubscription(ctx context.Context
 		CostPerCycle:          req.CostPerCycle,
 		BillingCycle:          req.BillingCycle,
 		PaymentQRCodeURL:      req.PaymentQRCodeURL,
+		PromptPayID:           promptPayID,
 		Description:           req.Description,
+		RenewalDate:           renewalDate,
+		Visibility:            visibility,
 	}
 
-	if err := s.hsRepo.Create(ctx, hsDB); err != nil {
-		return nil, fmt.Errorf("failed to create hosted subscription in repository: %w", err)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.hsRepo.Create(ctx, hsDB); err != nil {
+			return fmt.Errorf("failed to create hosted subscription in repository: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditHostedSubscriptionCreate,
+			EntityID:             hsDB.ID,
+			HostedSubscriptionID: &hsDB.ID,
+			After:                hsDB,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	fullHs, err := s.hsRepo.GetByID(ctx, hsDB.ID)
@@ -96,7 +160,7 @@ func (s *hostedSubscriptionService) CreateHostedSubscription(ctx context.Context
 		return nil, fmt.Errorf("failed to retrieve created hosted subscription for response mapping")
 	}
 
-	mappedSubs := s.mapDbSubsToResponseSubs(ctx, []models.HostedSubscription{*fullHs})
+	mappedSubs := mapHostedSubscriptionResponses([]models.HostedSubscription{*fullHs})
 	if len(mappedSubs) == 0 {
 		return nil, fmt.Errorf("failed to map created hosted subscription to response")
 	}
@@ -109,7 +173,7 @@ func (s *hostedSubscriptionService) ListHostedSubscriptionsByUserID(ctx context.
 	if err != nil {
 		return nil, fmt.Errorf("failed to list hosted subscriptions by user ID: %w", err)
 	}
-	return s.mapDbSubsToResponseSubs(ctx, dbSubscriptions), nil
+	return mapHostedSubscriptionResponses(dbSubscriptions), nil
 }
 
 // ExploreAllHostedSubscriptions retrieves a list of all hosted subscriptions with filters and sorting.
@@ -118,24 +182,43 @@ func (s *hostedSubscriptionService) ExploreAllHostedSubscriptions(ctx context.Co
 	if err != nil {
 		return nil, fmt.Errorf("failed to explore hosted subscriptions: %w", err)
 	}
-	return s.mapDbSubsToResponseSubs(ctx, dbSubscriptions), nil
+	return mapHostedSubscriptionResponses(dbSubscriptions), nil
 }
 
 // GetHostedSubscriptionDetailsByID
+// Private subscriptions are only shown to their host, members and pending requesters; to everyone else they do
+// not exist, and an invite is the way in.
 func (s *hostedSubscriptionService) GetHostedSubscriptionDetailsByID(ctx context.Context, id uint, authenticatedUserID uint) (*models.HostedSubscriptionResponse, error) {
 	dbSub, err := s.hsRepo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
 		return nil, fmt.Errorf("failed to get subscription details: %w", err)
 	}
-	responseSubs := s.mapDbSubsToResponseSubs(ctx, []models.HostedSubscription{*dbSub})
+	if dbSub.Visibility == models.VisibilityPrivate {
+		canSee, err := s.isParticipant(ctx, dbSub, authenticatedUserID)
+		if err != nil {
+			return nil, err
+		}
+		if !canSee {
+			return nil, ErrSubscriptionNotFound
+		}
+	}
+	responseSubs := mapHostedSubscriptionResponses([]models.HostedSubscription{*dbSub})
 	if len(responseSubs) == 0 {
 		return nil, fmt.Errorf("failed to map subscription details")
 	}
 	return &responseSubs[0], nil
 }
 
-// CreateJoinRequest handles the logic for a user requesting to join a subscription.
-func (s *hostedSubscriptionService) CreateJoinRequest(ctx context.Context, requesterUserID uint, hostedSubscriptionID uint) (*models.JoinRequest, error) {
+// CreateJoinRequest handles the logic for a user requesting to join a subscription, with an optional message to
+// the host and answers to the subscription's screening questions.
+func (s *hostedSubscriptionService) CreateJoinRequest(ctx context.Context, requesterUserID uint, hostedSubscriptionID uint, req *models.CreateJoinRequestRequest) (*models.JoinRequest, error) {
+	if req == nil {
+		req = &models.CreateJoinRequestRequest{}
+	}
+
 	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -147,6 +230,9 @@ func (s *hostedSubscriptionService) CreateJoinRequest(ctx context.Context, reque
 	if hostedSub.HostUserID == requesterUserID {
 		return nil, ErrHostCannotJoinOwn
 	}
+	if hostedSub.Visibility == models.VisibilityPrivate {
+		return nil, ErrInviteRequired
+	}
 
 	_, err = s.membershipRepo.FindByUserAndSubscription(ctx, requesterUserID, hostedSubscriptionID)
 	if err == nil {
@@ -164,33 +250,67 @@ func (s *hostedSubscriptionService) CreateJoinRequest(ctx context.Context, reque
 		return nil, fmt.Errorf("checking existing join request: %w", err)
 	}
 
+	// Slots offered to or claimed by waitlisted users are taken until the offer or the claim is settled.
+	reserved, err := s.waitlistRepo.CountReservedSlots(ctx, hostedSubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("counting reserved slots: %w", err)
+	}
 	currentMembersCount := len(hostedSub.Memberships)
-	if (currentMembersCount + 1) >= hostedSub.TotalSlots {
+	if (currentMembersCount + 1 + int(reserved)) >= hostedSub.TotalSlots {
 		return nil, ErrSubscriptionFull
 	}
 
-	joinReq := &models.JoinRequest{
-		RequesterUserID:      requesterUserID,
-		HostedSubscriptionID: hostedSubscriptionID,
-		RequestDate:          time.Now().Local(),
-		Status:               models.JoinRequestStatusPending,
+	answers, err := screeningAnswers(ctx, s.screeningRepo, hostedSubscriptionID, req.Answers)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.joinRequestRepo.Create(ctx, joinReq); err != nil {
-		return nil, fmt.Errorf("creating join request: %w", err)
+	var joinReq *models.JoinRequest
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var before *models.JoinRequest
+		var err error
+		joinReq, before, err = fileJoinRequest(ctx, s.joinRequestRepo, &models.JoinRequest{
+			RequesterUserID:      requesterUserID,
+			HostedSubscriptionID: hostedSubscriptionID,
+			Status:               models.JoinRequestStatusPending,
+			Message:              strings.TrimSpace(req.Message),
+			Answers:              answers,
+		})
+		if err != nil {
+			return err
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditJoinRequestCreate,
+			EntityID:             joinReq.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               before,
+			After:                joinReq,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.JoinRequestCreated, requesterUserID, hostedSub.HostUserID, requesterUserID)
+		event.HostedSubscriptionID = hostedSub.ID
+		event.JoinRequestID = joinReq.ID
+		event.Status = string(models.JoinRequestStatusPending)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	fullJoinRequest, err := s.joinRequestRepo.GetByID(ctx, joinReq.ID)
 	if err != nil {
 		log.Printf("Warning: JoinRequest %d created, but failed to fetch its full details for response: %v", joinReq.ID, err)
-		return joinReq, nil
+		fullJoinRequest = joinReq
 	}
-
 	return fullJoinRequest, nil
 }
 
-// ListJoinRequestsForHost retrieves join requests for a specific subscription owned by the host.
-func (s *hostedSubscriptionService) ListJoinRequestsForHost(ctx context.Context, hostUserID uint, subscriptionID uint, statusFilter *models.JoinRequestStatus) ([]models.JoinRequest, error) {
+// ListJoinRequestsForHost retrieves join requests for a specific subscription owned by the host, each with the
+// requester's review stats and most recent payments.
+func (s *hostedSubscriptionService) ListJoinRequestsForHost(ctx context.Context, hostUserID uint, subscriptionID uint, statusFilter *models.JoinRequestStatus) ([]models.JoinRequestDetailsResponse, error) {
 	hostedSub, err := s.hsRepo.GetByID(ctx, subscriptionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -202,7 +322,37 @@ func (s *hostedSubscriptionService) ListJoinRequestsForHost(ctx context.Context,
 		return nil, ErrForbidden
 	}
 
-	return s.joinRequestRepo.ListBySubscriptionID(ctx, subscriptionID, statusFilter)
+	requests, err := s.joinRequestRepo.ListBySubscriptionID(ctx, subscriptionID, statusFilter)
+	if err != nil {
+		return nil, fmt.Errorf("listing join requests: %w", err)
+	}
+	requesterIDs := make([]uint, 0, len(requests))
+	for _, request := range requests {
+		requesterIDs = append(requesterIDs, request.RequesterUserID)
+	}
+	requesterIDs = uniqueIDs(requesterIDs)
+	stats, err := s.paymentRepo.GetReviewStatsByMemberUserIDs(ctx, requesterIDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetching review stats of requesters: %w", err)
+	}
+	histories, err := s.paymentRepo.ListRecentByMemberUserIDs(ctx, requesterIDs, requesterPaymentHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("fetching payment history of requesters: %w", err)
+	}
+
+	details := make([]models.JoinRequestDetailsResponse, 0, len(requests))
+	for _, request := range requests {
+		history := histories[request.RequesterUserID]
+		if history == nil {
+			history = []models.RequesterPaymentRow{}
+		}
+		details = append(details, models.JoinRequestDetailsResponse{
+			JoinRequest:    request,
+			ReviewStats:    stats[request.RequesterUserID],
+			PaymentHistory: history,
+		})
+	}
+	return details, nil
 }
 
 // ApproveJoinRequest allows a host to approve a pending join request.
@@ -228,39 +378,75 @@ func (s *hostedSubscriptionService) ApproveJoinRequest(ctx context.Context, host
 		return nil, ErrJoinRequestNotPending
 	}
 
-	_, err = s.membershipRepo.FindByUserAndSubscription(ctx, joinReq.RequesterUserID, joinReq.HostedSubscriptionID)
-	if err == nil {
-		_ = s.joinRequestRepo.UpdateStatus(ctx, joinReq.ID, models.JoinRequestStatusApproved)
-		return nil, ErrAlreadyMember
-	}
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, fmt.Errorf("checking existing membership: %w", err)
-	}
-
-	currentMembersCount := len(hostedSub.Memberships)
-	if (currentMembersCount + 1) >= hostedSub.TotalSlots {
-		_ = s.joinRequestRepo.UpdateStatus(ctx, joinReq.ID, models.JoinRequestStatusDeclined)
-		return nil, ErrSubscriptionFull
-	}
+	membership := newMembership(joinReq.RequesterUserID, joinReq.HostedSubscriptionID)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock the subscription, then the request, and check both again: concurrent approvals must neither
+		// decide the same request twice nor fill more slots than there are.
+		lockedSub, err := s.hsRepo.GetByIDForUpdate(ctx, hostedSub.ID)
+		if err != nil {
+			return fmt.Errorf("locking hosted subscription: %w", err)
+		}
+		joinReq, err = s.joinRequestRepo.GetByIDForUpdate(ctx, requestID)
+		if err != nil {
+			return fmt.Errorf("locking join request: %w", err)
+		}
+		if joinReq.Status != models.JoinRequestStatusPending {
+			return ErrJoinRequestNotPending
+		}
 
-	now := time.Now().UTC()
-	var initialNextPaymentDate time.Time
-	year, month, _ := now.Date()
-	initialNextPaymentDate = time.Date(year, month+1, 1, 0, 0, 0, 0, now.Location()).Add(-time.Nanosecond)
+		_, err = s.membershipRepo.FindByUserAndSubscription(ctx, joinReq.RequesterUserID, joinReq.HostedSubscriptionID)
+		if err == nil {
+			return ErrAlreadyMember
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("checking existing membership: %w", err)
+		}
+		reserved, err := s.reservedSlotsFor(ctx, joinReq)
+		if err != nil {
+			return err
+		}
+		if (len(lockedSub.Memberships) + 1 + reserved) >= lockedSub.TotalSlots {
+			return ErrSubscriptionFull
+		}
 
-	membership := &models.SubscriptionMembership{
-		MemberUserID:         joinReq.RequesterUserID,
-		HostedSubscriptionID: joinReq.HostedSubscriptionID,
-		JoinedDate:           time.Now().UTC(),
-		PaymentStatus:        models.PaymentStatusDue,
-		NextPaymentDate:      &initialNextPaymentDate,
-	}
-	if err := s.membershipRepo.Create(ctx, membership); err != nil {
-		return nil, fmt.Errorf("creating subscription membership: %w", err)
-	}
+		if err := s.membershipRepo.Create(ctx, membership); err != nil {
+			return fmt.Errorf("creating subscription membership: %w", err)
+		}
+		if err := s.joinRequestRepo.UpdateStatus(ctx, joinReq.ID, models.JoinRequestStatusApproved); err != nil {
+			return fmt.Errorf("updating join request status: %w", err)
+		}
+		approved := *joinReq
+		approved.Status = models.JoinRequestStatusApproved
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditJoinRequestApprove,
+			EntityID:             joinReq.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               joinReq,
+			After:                &approved,
+		})
+		if err != nil {
+			return err
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditMembershipCreate,
+			EntityID:             membership.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			After:                membership,
+		})
+		if err != nil {
+			return err
+		}
 
-	if err := s.joinRequestRepo.UpdateStatus(ctx, joinReq.ID, models.JoinRequestStatusApproved); err != nil {
-		log.Printf("CRITICAL: Created membership %d but failed to update JoinRequest %d to Approved: %v", membership.ID, joinReq.ID, err)
+		event := events.New(events.JoinRequestApproved, hostUserID, joinReq.RequesterUserID, hostUserID)
+		event.HostedSubscriptionID = hostedSub.ID
+		event.JoinRequestID = joinReq.ID
+		event.MembershipID = membership.ID
+		event.Status = string(models.JoinRequestStatusApproved)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		// A full subscription or an existing membership leaves the request pending for the host to decide on.
+		return nil, err
 	}
 
 	fullMembership, fetchErr := s.membershipRepo.GetByID(ctx, membership.ID)
@@ -271,6 +457,40 @@ func (s *hostedSubscriptionService) ApproveJoinRequest(ctx context.Context, host
 	return fullMembership, nil
 }
 
+// BulkApproveJoinRequests approves several join requests, each exactly as ApproveJoinRequest would and in its
+// own transaction. Requests are taken in order, so once the subscription fills up the rest fail as full and stay
+// pending.
+func (s *hostedSubscriptionService) BulkApproveJoinRequests(ctx context.Context, hostUserID uint, requestIDs []uint) []BulkItemResult {
+	ids := uniqueIDs(requestIDs)
+	results := make([]BulkItemResult, 0, len(ids))
+	for _, id := range ids {
+		result := BulkItemResult{ID: id}
+		if _, err := s.ApproveJoinRequest(ctx, hostUserID, id); err != nil {
+			result.Err = err
+		} else {
+			result.Status = string(models.JoinRequestStatusApproved)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// BulkDeclineJoinRequests declines several join requests, each exactly as DeclineJoinRequest would and in its own transaction.
+func (s *hostedSubscriptionService) BulkDeclineJoinRequests(ctx context.Context, hostUserID uint, requestIDs []uint) []BulkItemResult {
+	ids := uniqueIDs(requestIDs)
+	results := make([]BulkItemResult, 0, len(ids))
+	for _, id := range ids {
+		result := BulkItemResult{ID: id}
+		if err := s.DeclineJoinRequest(ctx, hostUserID, id); err != nil {
+			result.Err = err
+		} else {
+			result.Status = string(models.JoinRequestStatusDeclined)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
 // DeclineJoinRequest allows a host to decline a pending join request.
 func (s *hostedSubscriptionService) DeclineJoinRequest(ctx context.Context, hostUserID uint, requestID uint) error {
 	joinReq, err := s.joinRequestRepo.GetByID(ctx, requestID)
@@ -293,7 +513,36 @@ func (s *hostedSubscriptionService) DeclineJoinRequest(ctx context.Context, host
 		return ErrJoinRequestNotPending
 	}
 
-	return s.joinRequestRepo.UpdateStatus(ctx, joinReq.ID, models.JoinRequestStatusDeclined)
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		joinReq, err := s.joinRequestRepo.GetByIDForUpdate(ctx, requestID)
+		if err != nil {
+			return fmt.Errorf("locking join request: %w", err)
+		}
+		if joinReq.Status != models.JoinRequestStatusPending {
+			return ErrJoinRequestNotPending
+		}
+		if err := s.joinRequestRepo.UpdateStatus(ctx, joinReq.ID, models.JoinRequestStatusDeclined); err != nil {
+			return err
+		}
+		declined := *joinReq
+		declined.Status = models.JoinRequestStatusDeclined
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditJoinRequestDecline,
+			EntityID:             joinReq.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               joinReq,
+			After:                &declined,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.JoinRequestDeclined, hostUserID, joinReq.RequesterUserID, hostUserID)
+		event.HostedSubscriptionID = hostedSub.ID
+		event.JoinRequestID = joinReq.ID
+		event.Status = string(models.JoinRequestStatusDeclined)
+		return s.publish(ctx, event)
+	})
 }
 
 // ListMyJoinRequests retrieves all join requests made by the specified user.
@@ -314,7 +563,7 @@ func (s *hostedSubscriptionService) ListMyMemberships(ctx context.Context, membe
 
 	responseMemberships := make([]models.SubscriptionMembershipResponse, 0, len(dbMemberships))
 	for _, dbMembership := range dbMemberships {
-		hsResponses := s.mapDbSubsToResponseSubs(ctx, []models.HostedSubscription{dbMembership.HostedSubscription})
+		hsResponses := mapHostedSubscriptionResponses([]models.HostedSubscription{dbMembership.HostedSubscription})
 		if len(hsResponses) == 0 {
 			log.Printf("Warning: Failed to map HostedSubscription for membership ID %d", dbMembership.ID)
 			continue
@@ -330,12 +579,16 @@ func (s *hostedSubscriptionService) ListMyMemberships(ctx context.Context, membe
 			JoinedDate:              dbMembership.JoinedDate,
 			PaymentStatus:           dbMembership.PaymentStatus,
 			NextPaymentDate:         dbMembership.NextPaymentDate,
+			Balance:                 dbMembership.Balance,
+			OutstandingAmount:       dbMembership.OutstandingAmount(),
+			CreditBalance:           dbMembership.CreditBalance(),
 			HostedSubscriptionTitle: dbMembership.HostedSubscription.SubscriptionTitle,
 			ServiceProviderName:     dbMembership.HostedSubscription.SubscriptionService.Name,
 			ServiceProviderLogoURL:  dbMembership.HostedSubscription.SubscriptionService.LogoURL,
 			HostName:                dbMembership.HostedSubscription.User.FullName,
 			CostPerSlot:             costPerSlot,
 			PaymentQRCodeURL:        dbMembership.HostedSubscription.PaymentQRCodeURL,
+			PromptPayEnabled:        dbMembership.HostedSubscription.PromptPayID != "",
 		}
 		responseMemberships = append(responseMemberships, respMembership)
 	}
@@ -387,12 +640,16 @@ func (s *hostedSubscriptionService) ListMembersOfSubscription(ctx context.Contex
 			JoinedDate:              dbMembership.JoinedDate,
 			PaymentStatus:           dbMembership.PaymentStatus,
 			NextPaymentDate:         dbMembership.NextPaymentDate,
+			Balance:                 dbMembership.Balance,
+			OutstandingAmount:       dbMembership.OutstandingAmount(),
+			CreditBalance:           dbMembership.CreditBalance(),
 			HostedSubscriptionTitle: hs.SubscriptionTitle,
 			ServiceProviderName:     hs.SubscriptionService.Name,
 			ServiceProviderLogoURL:  hs.SubscriptionService.LogoURL,
 			HostName:                hs.User.FullName,
 			CostPerSlot:             costPerSlot,
 			PaymentQRCodeURL:        hs.PaymentQRCodeURL,
+			PromptPayEnabled:        hs.PromptPayID != "",
 		}
 		responseMemberships = append(responseMemberships, respMembership)
 	}
@@ -400,8 +657,402 @@ func (s *hostedSubscriptionService) ListMembersOfSubscription(ctx context.Contex
 	return responseMemberships, nil
 }
 
-// mapDbSubsToResponseSubs helper function
-func (s *hostedSubscriptionService) mapDbSubsToResponseSubs(ctx context.Context, dbSubscriptions []models.HostedSubscription) []models.HostedSubscriptionResponse {
+// LeaveSubscription lets a member give up their slot in a hosted subscription.
+func (s *hostedSubscriptionService) LeaveSubscription(ctx context.Context, memberUserID uint, membershipID uint) error {
+	membership, err := s.membershipRepo.GetByID(ctx, membershipID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMembershipNotFound
+		}
+		return fmt.Errorf("fetching membership: %w", err)
+	}
+	if membership.MemberUserID != memberUserID {
+		return ErrNotMember
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.membershipRepo.Delete(ctx, membershipID); err != nil {
+			return fmt.Errorf("deleting membership: %w", err)
+		}
+		err := s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditMembershipLeave,
+			EntityID:             membership.ID,
+			HostedSubscriptionID: &membership.HostedSubscriptionID,
+			Before:               membership,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.MemberLeft, memberUserID, membership.HostedSubscription.HostUserID, memberUserID)
+		event.HostedSubscriptionID = membership.HostedSubscriptionID
+		event.MembershipID = membership.ID
+		return s.publish(ctx, event)
+	})
+}
+
+// RemoveMember lets the host end a membership of a subscription they own, e.g. a member who stopped paying.
+// The membership is kept as ended, so the host can still refund the unused part of the member's payment.
+func (s *hostedSubscriptionService) RemoveMember(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, membershipID uint) error {
+	membership, err := s.membershipRepo.GetByID(ctx, membershipID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMembershipNotFound
+		}
+		return fmt.Errorf("fetching membership: %w", err)
+	}
+	if membership.HostedSubscriptionID != hostedSubscriptionID {
+		return ErrMembershipNotFound
+	}
+	if membership.HostedSubscription.HostUserID != hostUserID {
+		return ErrForbidden
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.membershipRepo.Delete(ctx, membershipID); err != nil {
+			return fmt.Errorf("deleting membership: %w", err)
+		}
+		err := s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditMembershipRemove,
+			EntityID:             membership.ID,
+			HostedSubscriptionID: &membership.HostedSubscriptionID,
+			Before:               membership,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.MemberRemoved, hostUserID, hostUserID, membership.MemberUserID)
+		event.HostedSubscriptionID = membership.HostedSubscriptionID
+		event.MembershipID = membership.ID
+		return s.publish(ctx, event)
+	})
+}
+
+// UpdateVisibility changes who can find and join a hosted subscription owned by the host. Existing members,
+// join requests and invites are unaffected.
+func (s *hostedSubscriptionService) UpdateVisibility(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateVisibilityRequest) (*models.HostedSubscriptionResponse, error) {
+	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+
+	if hostedSub.Visibility != req.Visibility {
+		before := map[string]models.SubscriptionVisibility{"visibility": hostedSub.Visibility}
+		after := map[string]models.SubscriptionVisibility{"visibility": req.Visibility}
+		err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.hsRepo.UpdateVisibility(ctx, hostedSub.ID, req.Visibility); err != nil {
+				return fmt.Errorf("saving visibility: %w", err)
+			}
+			return s.auditSvc.Record(ctx, AuditEntry{
+				Action:               models.AuditVisibilityUpdate,
+				EntityID:             hostedSub.ID,
+				HostedSubscriptionID: &hostedSub.ID,
+				Before:               before,
+				After:                after,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		hostedSub.Visibility = req.Visibility
+	}
+
+	return &mapHostedSubscriptionResponses([]models.HostedSubscription{*hostedSub})[0], nil
+}
+
+// UpdateTotalSlots changes how many people, host included, share a subscription. It cannot drop below the host,
+// the members and the slots held for waitlisted users. Slots added are offered to its waitlist.
+func (s *hostedSubscriptionService) UpdateTotalSlots(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateTotalSlotsRequest) (*models.HostedSubscriptionResponse, error) {
+	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+	if req.TotalSlots < len(hostedSub.Memberships)+1 {
+		return nil, ErrTotalSlotsBelowMembers
+	}
+
+	if hostedSub.TotalSlots != req.TotalSlots {
+		err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			// Lock the subscription and check again, so a concurrent approval, invite or waitlist claim cannot
+			// take a slot that is being removed.
+			lockedSub, err := s.hsRepo.GetByIDForUpdate(ctx, hostedSub.ID)
+			if err != nil {
+				return fmt.Errorf("locking hosted subscription: %w", err)
+			}
+			reserved, err := s.waitlistRepo.CountReservedSlots(ctx, hostedSub.ID)
+			if err != nil {
+				return fmt.Errorf("counting reserved slots: %w", err)
+			}
+			if req.TotalSlots < len(lockedSub.Memberships)+1+int(reserved) {
+				return ErrTotalSlotsBelowMembers
+			}
+
+			before := map[string]int{"total_slots": lockedSub.TotalSlots}
+			after := map[string]int{"total_slots": req.TotalSlots}
+			if err := s.hsRepo.UpdateTotalSlots(ctx, hostedSub.ID, req.TotalSlots); err != nil {
+				return fmt.Errorf("saving total slots: %w", err)
+			}
+			err = s.auditSvc.Record(ctx, AuditEntry{
+				Action:               models.AuditTotalSlotsUpdate,
+				EntityID:             hostedSub.ID,
+				HostedSubscriptionID: &hostedSub.ID,
+				Before:               before,
+				After:                after,
+			})
+			if err != nil {
+				return err
+			}
+
+			event := events.New(events.SlotsChanged, hostUserID, hostUserID)
+			event.HostedSubscriptionID = hostedSub.ID
+			return s.publish(ctx, event)
+		})
+		if err != nil {
+			return nil, err
+		}
+		hostedSub.TotalSlots = req.TotalSlots
+	}
+
+	return &mapHostedSubscriptionResponses([]models.HostedSubscription{*hostedSub})[0], nil
+}
+
+// GetScreeningQuestions retrieves the questions asked of everyone requesting to join a subscription. Private
+// subscriptions only show them to their participants.
+func (s *hostedSubscriptionService) GetScreeningQuestions(ctx context.Context, userID uint, hostedSubscriptionID uint) ([]models.ScreeningQuestion, error) {
+	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching hosted subscription: %w", err)
+	}
+	if hostedSub.Visibility == models.VisibilityPrivate {
+		participant, err := s.isParticipant(ctx, hostedSub, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !participant {
+			return nil, ErrSubscriptionNotFound
+		}
+	}
+
+	questions, err := s.screeningRepo.ListByHostedSubscriptionID(ctx, hostedSub.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing screening questions: %w", err)
+	}
+	return questions, nil
+}
+
+// UpdateScreeningQuestions replaces the screening questions of a subscription owned by the host. Join requests
+// already sent keep the questions they answered.
+func (s *hostedSubscriptionService) UpdateScreeningQuestions(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateScreeningQuestionsRequest) ([]models.ScreeningQuestion, error) {
+	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+
+	questions := make([]models.ScreeningQuestion, 0, len(req.Questions))
+	for i, input := range req.Questions {
+		questions = append(questions, models.ScreeningQuestion{
+			HostedSubscriptionID: hostedSub.ID,
+			Position:             i + 1,
+			Question:             strings.TrimSpace(input.Question),
+			Required:             input.Required == nil || *input.Required,
+		})
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		previous, err := s.screeningRepo.ListByHostedSubscriptionID(ctx, hostedSub.ID)
+		if err != nil {
+			return fmt.Errorf("listing screening questions: %w", err)
+		}
+		if err := s.screeningRepo.Replace(ctx, hostedSub.ID, questions); err != nil {
+			return fmt.Errorf("saving screening questions: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditScreeningQuestionsUpdate,
+			EntityID:             hostedSub.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               map[string][]string{"screening_questions": screeningQuestionTexts(previous)},
+			After:                map[string][]string{"screening_questions": screeningQuestionTexts(questions)},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// screeningAnswers checks a requester's answers against the subscription's screening questions: each must answer
+// one of them at most once, and every required question needs a non-blank answer. It returns the answers with the
+// question they answer, in the order the questions are asked. The result is never nil, so fileJoinRequest replaces
+// earlier answers with it even when there are none.
+func screeningAnswers(ctx context.Context, screeningRepo repositories.ScreeningQuestionRepository, hostedSubscriptionID uint, inputs []models.ScreeningAnswerInput) ([]models.JoinRequestAnswer, error) {
+	questions, err := screeningRepo.ListByHostedSubscriptionID(ctx, hostedSubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing screening questions: %w", err)
+	}
+
+	asked := make(map[uint]bool, len(questions))
+	for _, question := range questions {
+		asked[question.ID] = true
+	}
+	given := make(map[uint]string, len(inputs))
+	for _, input := range inputs {
+		if !asked[input.QuestionID] {
+			return nil, fmt.Errorf("%w: question %d is not asked for this subscription", ErrInvalidScreeningAnswer, input.QuestionID)
+		}
+		if _, dup := given[input.QuestionID]; dup {
+			return nil, fmt.Errorf("%w: question %d is answered more than once", ErrInvalidScreeningAnswer, input.QuestionID)
+		}
+		given[input.QuestionID] = strings.TrimSpace(input.Answer)
+	}
+
+	answers := make([]models.JoinRequestAnswer, 0, len(given))
+	for _, question := range questions {
+		answer := given[question.ID]
+		if answer == "" {
+			if question.Required {
+				return nil, fmt.Errorf("%w: question %d is required", ErrInvalidScreeningAnswer, question.ID)
+			}
+			continue
+		}
+		answers = append(answers, models.JoinRequestAnswer{
+			ScreeningQuestionID: question.ID,
+			Question:            question.Question,
+			Answer:              answer,
+		})
+	}
+	return answers, nil
+}
+
+func screeningQuestionTexts(questions []models.ScreeningQuestion) []string {
+	texts := make([]string, 0, len(questions))
+	for _, question := range questions {
+		texts = append(texts, question.Question)
+	}
+	return texts
+}
+
+// reservedSlotsFor counts the slots held for waitlisted users other than the one who filed the join request.
+func (s *hostedSubscriptionService) reservedSlotsFor(ctx context.Context, joinReq *models.JoinRequest) (int, error) {
+	reserved, err := s.waitlistRepo.CountReservedSlots(ctx, joinReq.HostedSubscriptionID)
+	if err != nil {
+		return 0, fmt.Errorf("counting reserved slots: %w", err)
+	}
+	_, err = s.waitlistRepo.FindByJoinRequestID(ctx, joinReq.ID)
+	if err == nil {
+		reserved--
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("checking waitlist claim: %w", err)
+	}
+	return int(reserved), nil
+}
+
+// isParticipant reports whether the user hosts the subscription, is one of its members or has a pending
+// request to join it. The subscription must be loaded with its members.
+func (s *hostedSubscriptionService) isParticipant(ctx context.Context, hostedSub *models.HostedSubscription, userID uint) (bool, error) {
+	if hostedSub.HostUserID == userID {
+		return true, nil
+	}
+	for _, membership := range hostedSub.Memberships {
+		if membership.MemberUserID == userID {
+			return true, nil
+		}
+	}
+	_, err := s.joinRequestRepo.FindPendingByRequesterAndSubscription(ctx, userID, hostedSub.ID)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, fmt.Errorf("checking existing join request: %w", err)
+	}
+	return false, nil
+}
+
+// newMembership is a new member's membership, due for its first payment at the end of the current month.
+func newMembership(memberUserID uint, hostedSubscriptionID uint) *models.SubscriptionMembership {
+	now := time.Now().UTC()
+	year, month, _ := now.Date()
+	initialNextPaymentDate := time.Date(year, month+1, 1, 0, 0, 0, 0, now.Location()).Add(-time.Nanosecond)
+
+	return &models.SubscriptionMembership{
+		MemberUserID:         memberUserID,
+		HostedSubscriptionID: hostedSubscriptionID,
+		JoinedDate:           now,
+		PaymentStatus:        models.PaymentStatusDue,
+		NextPaymentDate:      &initialNextPaymentDate,
+	}
+}
+
+// fileJoinRequest files the draft as the user's join request for a subscription, reusing their earlier request
+// if there is one, as a user has one join request per subscription. The draft's message and answers replace
+// the earlier ones; a draft with nil answers was not screened and keeps them. It returns the request as it was
+// before, or nil if it was created. A pending request can be approved this way but not filed again.
+func fileJoinRequest(ctx context.Context, joinRequestRepo repositories.JoinRequestRepository, draft *models.JoinRequest) (*models.JoinRequest, *models.JoinRequest, error) {
+	existing, err := joinRequestRepo.FindByRequesterAndSubscription(ctx, draft.RequesterUserID, draft.HostedSubscriptionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		draft.RequestDate = time.Now().Local()
+		if err := joinRequestRepo.Create(ctx, draft); err != nil {
+			return nil, nil, fmt.Errorf("creating join request: %w", err)
+		}
+		return draft, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("checking existing join request: %w", err)
+	}
+
+	if existing.Status == models.JoinRequestStatusPending && draft.Status == models.JoinRequestStatusPending {
+		return nil, nil, ErrAlreadyRequestedToJoin
+	}
+	before := *existing
+	if existing.Status != models.JoinRequestStatusPending {
+		existing.RequestDate = time.Now().Local()
+	}
+	existing.Status = draft.Status
+	existing.InviteID = draft.InviteID
+	existing.Message = draft.Message
+	if err := joinRequestRepo.Update(ctx, existing); err != nil {
+		return nil, nil, fmt.Errorf("updating join request: %w", err)
+	}
+	if draft.Answers != nil {
+		if err := joinRequestRepo.ReplaceAnswers(ctx, existing.ID, draft.Answers); err != nil {
+			return nil, nil, fmt.Errorf("saving join request answers: %w", err)
+		}
+		existing.Answers = draft.Answers
+	}
+	return existing, &before, nil
+}
+
+// publish records a domain event in the outbox as part of the caller's transaction.
+func (s *hostedSubscriptionService) publish(ctx context.Context, event events.Event) error {
+	if err := s.eventPublisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("recording %s event: %w", event.Type, err)
+	}
+	return nil
+}
+
+// mapHostedSubscriptionResponses maps hosted subscriptions, loaded with their service, host and members, to responses.
+func mapHostedSubscriptionResponses(dbSubscriptions []models.HostedSubscription) []models.HostedSubscriptionResponse {
 	responseSubscriptions := make([]models.HostedSubscriptionResponse, 0, len(dbSubscriptions))
 	for _, dbSub := range dbSubscriptions {
 		var costPerSlot float64
@@ -444,6 +1095,9 @@ func (s *hostedSubscriptionService) mapDbSubsToResponseSubs(ctx context.Context,
 			CostPerCycle:            dbSub.CostPerCycle,
 			BillingCycle:            dbSub.BillingCycle,
 			PaymentQRCodeURL:        dbSub.PaymentQRCodeURL,
+			PromptPayEnabled:        dbSub.PromptPayID != "",
+			RenewalDate:             dbSub.RenewalDate,
+			Visibility:              dbSub.Visibility,
 			Description:             dbSub.Description,
 			CreatedAt:               dbSub.CreatedAt,
 			UpdatedAt:               dbSub.UpdatedAt,
@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/events"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	inviteCodeLength = 10
+	// Upper-case letters and digits without the look-alikes 0, O, 1 and I. Its 32 characters keep the
+	// random selection unbiased.
+	inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// Custom errors for InviteService
+var (
+	ErrInviteNotFound      = errors.New("invite not found")
+	ErrInviteUnavailable   = errors.New("this invite has expired, been revoked or been used up")
+	ErrInvalidInviteExpiry = errors.New("invite expiry must be in the future")
+)
+
+// InviteService defines the interface for invites to hosted subscriptions.
+type InviteService interface {
+	CreateInvite(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.CreateSubscriptionInviteRequest) (*models.SubscriptionInviteResponse, error)
+	ListInvites(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) ([]models.SubscriptionInviteResponse, error)
+	RevokeInvite(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, inviteID uint) (*models.SubscriptionInviteResponse, error)
+	PreviewInvite(ctx context.Context, userID uint, code string) (*models.InvitePreviewResponse, error)
+	AcceptInvite(ctx context.Context, userID uint, code string) (*models.AcceptInviteResponse, error)
+}
+
+type inviteService struct {
+	inviteRepo      repositories.SubscriptionInviteRepository
+	hsRepo          repositories.HostedSubscriptionRepository
+	joinRequestRepo repositories.JoinRequestRepository
+	membershipRepo  repositories.SubscriptionMembershipRepository
+	waitlistRepo    repositories.WaitlistRepository
+	transactor      repositories.Transactor
+	eventPublisher  events.Publisher
+	auditSvc        AuditService
+}
+
+// NewInviteService creates a new InviteService.
+func NewInviteService(
+	inviteRepo repositories.SubscriptionInviteRepository,
+	hsRepo repositories.HostedSubscriptionRepository,
+	joinRequestRepo repositories.JoinRequestRepository,
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	waitlistRepo repositories.WaitlistRepository,
+	transactor repositories.Transactor,
+	eventPublisher events.Publisher,
+	auditSvc AuditService,
+) InviteService {
+	return &inviteService{
+		inviteRepo:      inviteRepo,
+		hsRepo:          hsRepo,
+		joinRequestRepo: joinRequestRepo,
+		membershipRepo:  membershipRepo,
+		waitlistRepo:    waitlistRepo,
+		transactor:      transactor,
+		eventPublisher:  eventPublisher,
+		auditSvc:        auditSvc,
+	}
+}
+
+// CreateInvite creates an invite to a hosted subscription owned by the host. Invites approve automatically
+// unless the request says otherwise.
+func (s *inviteService) CreateInvite(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.CreateSubscriptionInviteRequest) (*models.SubscriptionInviteResponse, error) {
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, ErrInvalidInviteExpiry
+	}
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+
+	invite := &models.SubscriptionInvite{
+		HostedSubscriptionID: hostedSub.ID,
+		CreatedByUserID:      hostUserID,
+		Code:                 code,
+		AutoApprove:          req.AutoApprove == nil || *req.AutoApprove,
+		ExpiresAt:            req.ExpiresAt,
+		MaxUses:              req.MaxUses,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.inviteRepo.Create(ctx, invite); err != nil {
+			return fmt.Errorf("creating invite: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditInviteCreate,
+			EntityID:             invite.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			After:                invite,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inviteResponse(invite, now), nil
+}
+
+// ListInvites lists all invites to a hosted subscription owned by the host, newest first.
+func (s *inviteService) ListInvites(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) ([]models.SubscriptionInviteResponse, error) {
+	if _, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID); err != nil {
+		return nil, err
+	}
+	invites, err := s.inviteRepo.ListByHostedSubscriptionID(ctx, hostedSubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("listing invites: %w", err)
+	}
+
+	now := time.Now()
+	responses := make([]models.SubscriptionInviteResponse, 0, len(invites))
+	for i := range invites {
+		responses = append(responses, *inviteResponse(&invites[i], now))
+	}
+	return responses, nil
+}
+
+// RevokeInvite stops an invite to a hosted subscription owned by the host from being used again. People who
+// already joined with it stay members. Revoking an invite twice has no further effect.
+func (s *inviteService) RevokeInvite(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, inviteID uint) (*models.SubscriptionInviteResponse, error) {
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	invite, err := s.inviteRepo.GetByID(ctx, inviteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteNotFound
+		}
+		return nil, fmt.Errorf("fetching invite: %w", err)
+	}
+	if invite.HostedSubscriptionID != hostedSub.ID {
+		return nil, ErrInviteNotFound
+	}
+
+	now := time.Now()
+	if invite.RevokedAt == nil {
+		before := *invite
+		invite.RevokedAt = &now
+		err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.inviteRepo.Revoke(ctx, invite.ID, now); err != nil {
+				return fmt.Errorf("revoking invite: %w", err)
+			}
+			return s.auditSvc.Record(ctx, AuditEntry{
+				Action:               models.AuditInviteRevoke,
+				EntityID:             invite.ID,
+				HostedSubscriptionID: &hostedSub.ID,
+				Before:               &before,
+				After:                invite,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return inviteResponse(invite, now), nil
+}
+
+// PreviewInvite shows what a usable invite is for, whatever the visibility of its subscription.
+func (s *inviteService) PreviewInvite(ctx context.Context, userID uint, code string) (*models.InvitePreviewResponse, error) {
+	invite, err := s.inviteRepo.GetByCode(ctx, normalizeInviteCode(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteNotFound
+		}
+		return nil, fmt.Errorf("fetching invite: %w", err)
+	}
+	if invite.Status(time.Now()) != models.InviteStatusActive {
+		return nil, ErrInviteUnavailable
+	}
+	hostedSub, err := s.hsRepo.GetByID(ctx, invite.HostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteNotFound
+		}
+		return nil, fmt.Errorf("fetching hosted subscription: %w", err)
+	}
+
+	return &models.InvitePreviewResponse{
+		Code:         invite.Code,
+		AutoApprove:  invite.AutoApprove,
+		ExpiresAt:    invite.ExpiresAt,
+		Subscription: mapHostedSubscriptionResponses([]models.HostedSubscription{*hostedSub})[0],
+	}, nil
+}
+
+// AcceptInvite uses an invite on behalf of the user. An invite that approves automatically makes them a member
+// straight away and records their join request as approved; any other invite files a pending join request for
+// the host, marked with the invite. A user's earlier declined or cancelled request is reused, as a user has one
+// join request per subscription. Each acceptance takes one use of the invite.
+//
+// Invites deliberately skip the screening questions: the host picked who to invite. Answers the user gave on
+// an earlier request are kept for the host to see.
+func (s *inviteService) AcceptInvite(ctx context.Context, userID uint, code string) (*models.AcceptInviteResponse, error) {
+	var (
+		joinReq    *models.JoinRequest
+		membership *models.SubscriptionMembership
+	)
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		invite, err := s.inviteRepo.GetByCodeForUpdate(ctx, normalizeInviteCode(code))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInviteNotFound
+			}
+			return fmt.Errorf("fetching invite: %w", err)
+		}
+		if invite.Status(time.Now()) != models.InviteStatusActive {
+			return ErrInviteUnavailable
+		}
+
+		// Lock the subscription so concurrent invites, approvals and waitlist offers cannot fill more slots than there are.
+		hostedSub, err := s.hsRepo.GetByIDForUpdate(ctx, invite.HostedSubscriptionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInviteNotFound
+			}
+			return fmt.Errorf("locking hosted subscription: %w", err)
+		}
+		if hostedSub.HostUserID == userID {
+			return ErrHostCannotJoinOwn
+		}
+		_, err = s.membershipRepo.FindByUserAndSubscription(ctx, userID, hostedSub.ID)
+		if err == nil {
+			return ErrAlreadyMember
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("checking existing membership: %w", err)
+		}
+		reserved, err := s.waitlistRepo.CountReservedSlots(ctx, hostedSub.ID)
+		if err != nil {
+			return fmt.Errorf("counting reserved slots: %w", err)
+		}
+		if len(hostedSub.Memberships)+1+int(reserved) >= hostedSub.TotalSlots {
+			return ErrSubscriptionFull
+		}
+
+		status := models.JoinRequestStatusPending
+		if invite.AutoApprove {
+			status = models.JoinRequestStatusApproved
+		}
+		var before *models.JoinRequest
+		joinReq, before, err = fileJoinRequest(ctx, s.joinRequestRepo, &models.JoinRequest{
+			RequesterUserID:      userID,
+			HostedSubscriptionID: invite.HostedSubscriptionID,
+			Status:               status,
+			InviteID:             &invite.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.inviteRepo.IncrementUseCount(ctx, invite.ID); err != nil {
+			return fmt.Errorf("recording invite use: %w", err)
+		}
+		used := *invite
+		used.UseCount++
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditInviteAccept,
+			EntityID:             invite.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               invite,
+			After:                &used,
+		})
+		if err != nil {
+			return err
+		}
+		joinReqAction := models.AuditJoinRequestCreate
+		if before != nil && invite.AutoApprove {
+			joinReqAction = models.AuditJoinRequestApprove
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               joinReqAction,
+			EntityID:             joinReq.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               before,
+			After:                joinReq,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !invite.AutoApprove {
+			event := events.New(events.JoinRequestCreated, userID, hostedSub.HostUserID, userID)
+			event.HostedSubscriptionID = hostedSub.ID
+			event.JoinRequestID = joinReq.ID
+			event.Status = string(models.JoinRequestStatusPending)
+			return s.publish(ctx, event)
+		}
+
+		membership = newMembership(userID, hostedSub.ID)
+		if err := s.membershipRepo.Create(ctx, membership); err != nil {
+			return fmt.Errorf("creating subscription membership: %w", err)
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditMembershipCreate,
+			EntityID:             membership.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			After:                membership,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.MemberJoined, userID, hostedSub.HostUserID, userID)
+		event.HostedSubscriptionID = hostedSub.ID
+		event.JoinRequestID = joinReq.ID
+		event.MembershipID = membership.ID
+		event.Status = string(models.JoinRequestStatusApproved)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.AcceptInviteResponse{JoinRequest: joinReq, Membership: membership}
+	if fullJoinRequest, err := s.joinRequestRepo.GetByID(ctx, joinReq.ID); err == nil {
+		response.JoinRequest = fullJoinRequest
+	} else {
+		log.Printf("Warning: JoinRequest %d saved through an invite, but failed to fetch its full details for response: %v", joinReq.ID, err)
+	}
+	if membership != nil {
+		if fullMembership, err := s.membershipRepo.GetByID(ctx, membership.ID); err == nil {
+			response.Membership = fullMembership
+		} else {
+			log.Printf("Warning: Membership %d created through an invite, but failed to fetch full details for response: %v", membership.ID, err)
+		}
+	}
+	return response, nil
+}
+
+func (s *inviteService) getOwnedSubscription(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.HostedSubscription, error) {
+	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+	return hostedSub, nil
+}
+
+func (s *inviteService) publish(ctx context.Context, event events.Event) error {
+	if err := s.eventPublisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("recording %s event: %w", event.Type, err)
+	}
+	return nil
+}
+
+func inviteResponse(invite *models.SubscriptionInvite, now time.Time) *models.SubscriptionInviteResponse {
+	return &models.SubscriptionInviteResponse{
+		ID:                   invite.ID,
+		HostedSubscriptionID: invite.HostedSubscriptionID,
+		Code:                 invite.Code,
+		AutoApprove:          invite.AutoApprove,
+		ExpiresAt:            invite.ExpiresAt,
+		MaxUses:              invite.MaxUses,
+		UseCount:             invite.UseCount,
+		Status:               invite.Status(now),
+		RevokedAt:            invite.RevokedAt,
+		CreatedAt:            invite.CreatedAt,
+	}
+}
+
+// generateInviteCode returns a new random invite code that is easy to read out and type.
+func generateInviteCode() (string, error) {
+	b := make([]byte, inviteCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating invite code: %w", err)
+	}
+	for i := range b {
+		b[i] = inviteCodeAlphabet[int(b[i])%len(inviteCodeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// normalizeInviteCode makes codes typed by hand match, e.g. " abcd2345ef " and "ABCD2345EF".
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
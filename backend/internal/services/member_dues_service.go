@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+)
+
+const projectedSpendMonths = 12
+
+// MemberDuesService defines the interface for the summary of what a member owes across their memberships.
+type MemberDuesService interface {
+	GetSummary(ctx context.Context, memberUserID uint) (*models.MemberDuesSummaryResponse, error)
+}
+
+type memberDuesService struct {
+	membershipRepo    repositories.SubscriptionMembershipRepository
+	paymentRecordRepo repositories.PaymentRecordRepository
+	location          *time.Location
+}
+
+// NewMemberDuesService creates a new MemberDuesService. Days and months are counted in location.
+func NewMemberDuesService(
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	paymentRecordRepo repositories.PaymentRecordRepository,
+	location *time.Location,
+) MemberDuesService {
+	return &memberDuesService{membershipRepo: membershipRepo, paymentRecordRepo: paymentRecordRepo, location: location}
+}
+
+// GetSummary sorts the next payment of each of the member's memberships into overdue, due this month and upcoming,
+// with the host's QR code for each, and projects what the member will pay over the next twelve months at
+// current prices.
+func (s *memberDuesService) GetSummary(ctx context.Context, memberUserID uint) (*models.MemberDuesSummaryResponse, error) {
+	memberships, err := s.membershipRepo.ListByUserID(ctx, memberUserID)
+	if err != nil {
+		return nil, fmt.Errorf("listing memberships: %w", err)
+	}
+	awaitingReview, err := s.paymentRecordRepo.ListAwaitingReviewByMemberUserID(ctx, memberUserID)
+	if err != nil {
+		return nil, fmt.Errorf("listing payments awaiting review: %w", err)
+	}
+	underReview := make(map[uint]float64, len(awaitingReview))
+	for _, pr := range awaitingReview {
+		underReview[pr.SubscriptionMembershipID] += pr.AmountPaid
+	}
+
+	now := time.Now().In(s.location)
+	today := startOfDay(now, s.location)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, s.location)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	summary := &models.MemberDuesSummaryResponse{
+		MonthStart:     monthStart,
+		MonthEnd:       monthEnd,
+		Overdue:        []models.MemberDueItem{},
+		DueThisMonth:   []models.MemberDueItem{},
+		Upcoming:       []models.MemberDueItem{},
+		ProjectedSpend: make([]models.MemberProjectedMonth, projectedSpendMonths),
+	}
+	for i := range summary.ProjectedSpend {
+		summary.ProjectedSpend[i].Month = monthStart.AddDate(0, i, 0).Format("2006-01")
+	}
+
+	for i := range memberships {
+		membership := &memberships[i]
+		item := s.dueItem(membership, models.RoundMoney(underReview[membership.ID]))
+		summary.TotalUnderReview += item.AmountUnderReview
+		summary.TotalCredit += membership.CreditBalance()
+
+		switch {
+		case membership.NextPaymentDate == nil:
+			continue
+		case membership.NextPaymentDate.Before(today):
+			summary.Overdue = append(summary.Overdue, item)
+			summary.TotalOverdue += item.Amount
+			summary.TotalDueThisMonth += item.Amount
+		case membership.NextPaymentDate.Before(monthEnd):
+			summary.DueThisMonth = append(summary.DueThisMonth, item)
+			summary.TotalDueThisMonth += item.Amount
+		default:
+			summary.Upcoming = append(summary.Upcoming, item)
+		}
+		s.projectSpend(summary.ProjectedSpend, monthStart, membership)
+	}
+
+	for _, items := range [][]models.MemberDueItem{summary.Overdue, summary.DueThisMonth, summary.Upcoming} {
+		sort.SliceStable(items, func(i, j int) bool { return items[i].DueDate.Before(*items[j].DueDate) })
+	}
+	for i := range summary.ProjectedSpend {
+		summary.ProjectedSpend[i].Amount = models.RoundMoney(summary.ProjectedSpend[i].Amount)
+		summary.ProjectedTotal += summary.ProjectedSpend[i].Amount
+	}
+	summary.TotalOverdue = models.RoundMoney(summary.TotalOverdue)
+	summary.TotalDueThisMonth = models.RoundMoney(summary.TotalDueThisMonth)
+	summary.TotalUnderReview = models.RoundMoney(summary.TotalUnderReview)
+	summary.TotalCredit = models.RoundMoney(summary.TotalCredit)
+	summary.ProjectedTotal = models.RoundMoney(summary.ProjectedTotal)
+	return summary, nil
+}
+
+func (s *memberDuesService) dueItem(membership *models.SubscriptionMembership, amountUnderReview float64) models.MemberDueItem {
+	hostedSub := &membership.HostedSubscription
+	item := models.MemberDueItem{
+		SubscriptionMembershipID: membership.ID,
+		HostedSubscriptionID:     membership.HostedSubscriptionID,
+		SubscriptionTitle:        hostedSub.SubscriptionTitle,
+		ServiceProviderName:      hostedSub.SubscriptionService.Name,
+		ServiceProviderLogoURL:   hostedSub.SubscriptionService.LogoURL,
+		HostName:                 hostedSub.User.FullName,
+		DueDate:                  membership.NextPaymentDate,
+		Amount:                   membershipAmountDue(membership),
+		PaymentStatus:            membership.PaymentStatus,
+		AmountUnderReview:        amountUnderReview,
+		PaymentQRCodeURL:         hostedSub.PaymentQRCodeURL,
+	}
+	if hostedSub.PromptPayID != "" && item.Amount > 0 {
+		qr, err := membershipPaymentQR(membership)
+		if err != nil {
+			log.Printf("Warning: Failed to build PromptPay QR for membership %d: %v", membership.ID, err)
+		} else {
+			item.PromptPay = qr
+		}
+	}
+	return item
+}
+
+// projectSpend adds the membership's cycles falling due before the end of the projection to the months they
+// fall in. The first cycle costs what is due now; later cycles cost the member's share, less any credit left.
+// An overdue cycle is counted in the first month.
+func (s *memberDuesService) projectSpend(months []models.MemberProjectedMonth, monthStart time.Time, membership *models.SubscriptionMembership) {
+	if membership.NextPaymentDate == nil {
+		return
+	}
+	projectionEnd := monthStart.AddDate(0, len(months), 0)
+	share := costPerSlot(&membership.HostedSubscription)
+	credit := membership.CreditBalance()
+
+	dueDate := *membership.NextPaymentDate
+	for first := true; dueDate.Before(projectionEnd); first = false {
+		var amount float64
+		if first && membership.OutstandingAmount() > 0 {
+			amount = membership.OutstandingAmount()
+		} else {
+			amount = max(share-credit, 0)
+			credit = max(credit-share, 0)
+		}
+
+		index := 0
+		if local := dueDate.In(s.location); !local.Before(monthStart) {
+			index = (local.Year()-monthStart.Year())*12 + int(local.Month()) - int(monthStart.Month())
+		}
+		months[index].Amount += amount
+
+		dueDate = nextCycleDueDate(dueDate, membership.HostedSubscription.BillingCycle, membership.ID)
+	}
+}
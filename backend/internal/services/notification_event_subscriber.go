@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xNatthapol/hubster/internal/events"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type notificationEventSubscriber struct {
+	notificationSvc   NotificationService
+	hostedSubRepo     repositories.HostedSubscriptionRepository
+	joinRequestRepo   repositories.JoinRequestRepository
+	paymentRecordRepo repositories.PaymentRecordRepository
+	refundRepo        repositories.RefundRepository
+	userRepo          repositories.UserRepository
+}
+
+// NewNotificationEventSubscriber creates an OutboxSubscriber that turns domain events into in-app notifications.
+func NewNotificationEventSubscriber(
+	notificationSvc NotificationService,
+	hostedSubRepo repositories.HostedSubscriptionRepository,
+	joinRequestRepo repositories.JoinRequestRepository,
+	paymentRecordRepo repositories.PaymentRecordRepository,
+	refundRepo repositories.RefundRepository,
+	userRepo repositories.UserRepository,
+) OutboxSubscriber {
+	return &notificationEventSubscriber{
+		notificationSvc:   notificationSvc,
+		hostedSubRepo:     hostedSubRepo,
+		joinRequestRepo:   joinRequestRepo,
+		paymentRecordRepo: paymentRecordRepo,
+		refundRepo:        refundRepo,
+		userRepo:          userRepo,
+	}
+}
+
+func (s *notificationEventSubscriber) Name() string {
+	return "notifications"
+}
+
+// Handle notifies the user affected by the event. Events without a matching notification are ignored.
+func (s *notificationEventSubscriber) Handle(ctx context.Context, event events.Event) error {
+	notification, err := s.buildNotification(ctx, event)
+	if err != nil {
+		// The entity may have been removed since the event was recorded; there is nobody left to notify.
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if notification == nil {
+		return nil
+	}
+	return s.notificationSvc.Notify(ctx, notification)
+}
+
+func (s *notificationEventSubscriber) buildNotification(ctx context.Context, event events.Event) (*models.Notification, error) {
+	switch event.Type {
+	case events.JoinRequestCreated, events.JoinRequestApproved, events.JoinRequestDeclined:
+		joinReq, err := s.joinRequestRepo.GetByID(ctx, event.JoinRequestID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching join request %d: %w", event.JoinRequestID, err)
+		}
+		hostedSub, err := s.hostedSubRepo.GetByID(ctx, joinReq.HostedSubscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching hosted subscription %d: %w", joinReq.HostedSubscriptionID, err)
+		}
+
+		notification := &models.Notification{
+			HostedSubscriptionID: &hostedSub.ID,
+			JoinRequestID:        &joinReq.ID,
+		}
+		switch event.Type {
+		case events.JoinRequestCreated:
+			requesterName := joinReq.User.FullName
+			if requesterName == "" {
+				requesterName = "Someone"
+			}
+			notification.UserID = hostedSub.HostUserID
+			notification.Type = models.NotificationJoinRequestReceived
+			notification.Title = "New join request"
+			notification.Message = fmt.Sprintf("%s wants to join %s.", requesterName, hostedSub.SubscriptionTitle)
+		case events.JoinRequestApproved:
+			notification.UserID = joinReq.RequesterUserID
+			notification.Type = models.NotificationJoinRequestApproved
+			notification.Title = "Join request approved"
+			notification.Message = fmt.Sprintf("You are now a member of %s.", hostedSub.SubscriptionTitle)
+		default:
+			notification.UserID = joinReq.RequesterUserID
+			notification.Type = models.NotificationJoinRequestDeclined
+			notification.Title = "Join request declined"
+			notification.Message = fmt.Sprintf("Your request to join %s was declined.", hostedSub.SubscriptionTitle)
+		}
+		return notification, nil
+
+	case events.PaymentProofSubmitted, events.PaymentProofApproved, events.PaymentProofDeclined,
+		events.PaymentProofDisputed, events.PaymentProofReverted, events.PaymentMessagePosted:
+		pr, err := s.paymentRecordRepo.GetByID(ctx, event.PaymentRecordID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching payment record %d: %w", event.PaymentRecordID, err)
+		}
+		membership := pr.SubscriptionMembership
+		title := membership.HostedSubscription.SubscriptionTitle
+
+		notification := &models.Notification{
+			HostedSubscriptionID: &membership.HostedSubscriptionID,
+			PaymentRecordID:      &pr.ID,
+		}
+		switch event.Type {
+		case events.PaymentProofSubmitted:
+			notification.UserID = membership.HostedSubscription.HostUserID
+			notification.Type = models.NotificationPaymentProofReceived
+			notification.Title = "New payment proof"
+			notification.Message = fmt.Sprintf("A member submitted payment proof for %s (%s).", title, pr.PaymentCycleIdentifier)
+		case events.PaymentProofApproved:
+			notification.UserID = membership.MemberUserID
+			notification.Type = models.NotificationPaymentProofApproved
+			notification.Title = "Payment approved"
+			notification.Message = fmt.Sprintf("Your payment for %s (%s) was approved.", title, pr.PaymentCycleIdentifier)
+		case events.PaymentProofReverted:
+			notification.UserID = membership.MemberUserID
+			notification.Type = models.NotificationPaymentProofReverted
+			notification.Title = "Payment back under review"
+			notification.Message = fmt.Sprintf("The automatic approval of your payment for %s (%s) was withdrawn; the host will review it.", title, pr.PaymentCycleIdentifier)
+		case events.PaymentProofDisputed:
+			notification.UserID = membership.HostedSubscription.HostUserID
+			notification.Type = models.NotificationPaymentDisputed
+			notification.Title = "Payment disputed"
+			notification.Message = fmt.Sprintf("A member disputed your decision on their payment for %s (%s).", title, pr.PaymentCycleIdentifier)
+		case events.PaymentMessagePosted:
+			// Notify whichever of host and member did not write the message.
+			notification.UserID = membership.HostedSubscription.HostUserID
+			if event.ActorUserID == notification.UserID {
+				notification.UserID = membership.MemberUserID
+			}
+			notification.Type = models.NotificationPaymentMessage
+			notification.Title = "New message about a payment"
+			notification.Message = fmt.Sprintf("There is a new message about the payment for %s (%s).", title, pr.PaymentCycleIdentifier)
+		default:
+			notification.UserID = membership.MemberUserID
+			notification.Type = models.NotificationPaymentProofDeclined
+			notification.Title = "Payment declined"
+			notification.Message = fmt.Sprintf("Your payment for %s (%s) was declined. Please submit a new proof.", title, pr.PaymentCycleIdentifier)
+			if pr.DeclineReason != "" {
+				notification.Message = fmt.Sprintf("Your payment for %s (%s) was declined: %s", title, pr.PaymentCycleIdentifier, pr.DeclineReason)
+			}
+		}
+		return notification, nil
+
+	case events.MemberJoined:
+		hostedSub, err := s.hostedSubRepo.GetByID(ctx, event.HostedSubscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching hosted subscription %d: %w", event.HostedSubscriptionID, err)
+		}
+		memberName := "Someone"
+		if member, err := s.userRepo.FindByID(ctx, event.ActorUserID); err == nil {
+			memberName = member.FullName
+		}
+		return &models.Notification{
+			UserID:               hostedSub.HostUserID,
+			Type:                 models.NotificationMemberJoined,
+			Title:                "New member",
+			Message:              fmt.Sprintf("%s joined %s with an invite.", memberName, hostedSub.SubscriptionTitle),
+			HostedSubscriptionID: &hostedSub.ID,
+		}, nil
+
+	case events.MemberLeft:
+		hostedSub, err := s.hostedSubRepo.GetByID(ctx, event.HostedSubscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching hosted subscription %d: %w", event.HostedSubscriptionID, err)
+		}
+		memberName := "A member"
+		if member, err := s.userRepo.FindByID(ctx, event.ActorUserID); err == nil {
+			memberName = member.FullName
+		}
+		return &models.Notification{
+			UserID:               hostedSub.HostUserID,
+			Type:                 models.NotificationMemberLeft,
+			Title:                "Member left",
+			Message:              fmt.Sprintf("%s left %s.", memberName, hostedSub.SubscriptionTitle),
+			HostedSubscriptionID: &hostedSub.ID,
+		}, nil
+
+	case events.MemberRemoved:
+		hostedSub, err := s.hostedSubRepo.GetByID(ctx, event.HostedSubscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching hosted subscription %d: %w", event.HostedSubscriptionID, err)
+		}
+		for _, userID := range event.RecipientUserIDs {
+			if userID == hostedSub.HostUserID {
+				continue
+			}
+			return &models.Notification{
+				UserID:               userID,
+				Type:                 models.NotificationMemberRemoved,
+				Title:                "Removed from subscription",
+				Message:              fmt.Sprintf("The host removed you from %s. Any refund for the unused part of your payment will show up under the membership.", hostedSub.SubscriptionTitle),
+				HostedSubscriptionID: &hostedSub.ID,
+			}, nil
+		}
+		return nil, nil
+
+	case events.WaitlistSlotOffered:
+		if len(event.RecipientUserIDs) == 0 {
+			return nil, nil
+		}
+		hostedSub, err := s.hostedSubRepo.GetByID(ctx, event.HostedSubscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching hosted subscription %d: %w", event.HostedSubscriptionID, err)
+		}
+		return &models.Notification{
+			UserID:               event.RecipientUserIDs[0],
+			Type:                 models.NotificationWaitlistSlotOffered,
+			Title:                "A slot opened up",
+			Message:              fmt.Sprintf("A slot opened up in %s. Claim it within %d hours before it is offered to the next in line.", hostedSub.SubscriptionTitle, int(waitlistClaimWindow.Hours())),
+			HostedSubscriptionID: &hostedSub.ID,
+		}, nil
+
+	case events.RefundIssued, events.RefundAcknowledged:
+		refund, err := s.refundRepo.GetByID(ctx, event.RefundID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching refund %d: %w", event.RefundID, err)
+		}
+		hostedSub, err := s.hostedSubRepo.GetByID(ctx, refund.HostedSubscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching hosted subscription %d: %w", refund.HostedSubscriptionID, err)
+		}
+
+		notification := &models.Notification{
+			HostedSubscriptionID: &refund.HostedSubscriptionID,
+			PaymentRecordID:      &refund.PaymentRecordID,
+		}
+		if event.Type == events.RefundIssued {
+			notification.UserID = refund.MemberUserID
+			notification.Type = models.NotificationRefundIssued
+			notification.Title = "Refund sent"
+			notification.Message = fmt.Sprintf("The host of %s sent you a refund of %.2f. Please confirm once you receive it.", hostedSub.SubscriptionTitle, refund.Amount)
+		} else {
+			notification.UserID = refund.HostUserID
+			notification.Type = models.NotificationRefundAcknowledged
+			notification.Title = "Refund received"
+			notification.Message = fmt.Sprintf("A member confirmed receiving your refund of %.2f for %s.", refund.Amount, hostedSub.SubscriptionTitle)
+		}
+		return notification, nil
+
+	default:
+		return nil, nil
+	}
+}
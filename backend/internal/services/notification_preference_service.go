@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/mail"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// NotificationPreferenceService defines the interface for managing users' notification preferences.
+type NotificationPreferenceService interface {
+	GetPreferences(ctx context.Context, userID uint) (*models.NotificationPreference, error)
+	UpdatePreferences(ctx context.Context, userID uint, req *models.UpdateNotificationPreferenceRequest) (*models.NotificationPreference, error)
+	ClaimDigest(ctx context.Context, userID uint, periodEnd time.Time, sentAt time.Time) (bool, error)
+}
+
+type notificationPreferenceService struct {
+	prefRepo      repositories.NotificationPreferenceRepository
+	transactor    repositories.Transactor
+	auditSvc      AuditService
+	defaultLocale mail.Locale
+}
+
+// NewNotificationPreferenceService creates a new NotificationPreferenceService.
+// Users who never saved preferences get defaultLocale.
+func NewNotificationPreferenceService(prefRepo repositories.NotificationPreferenceRepository, transactor repositories.Transactor, auditSvc AuditService, defaultLocale mail.Locale) NotificationPreferenceService {
+	return &notificationPreferenceService{prefRepo: prefRepo, transactor: transactor, auditSvc: auditSvc, defaultLocale: defaultLocale}
+}
+
+// GetPreferences returns the user's stored preferences, or the defaults if none were saved yet.
+func (s *notificationPreferenceService) GetPreferences(ctx context.Context, userID uint) (*models.NotificationPreference, error) {
+	pref, err := s.prefRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.defaultPreferences(userID), nil
+		}
+		return nil, fmt.Errorf("fetching notification preferences: %w", err)
+	}
+	return pref, nil
+}
+
+// UpdatePreferences applies the provided changes to the user's preferences.
+func (s *notificationPreferenceService) UpdatePreferences(ctx context.Context, userID uint, req *models.UpdateNotificationPreferenceRequest) (*models.NotificationPreference, error) {
+	pref, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	before := *pref
+
+	if req.Locale != nil {
+		pref.Locale = string(mail.ParseLocale(*req.Locale, s.defaultLocale))
+	}
+	if req.EmailJoinApprovals != nil {
+		pref.EmailJoinApprovals = *req.EmailJoinApprovals
+	}
+	if req.EmailPaymentReminders != nil {
+		pref.EmailPaymentReminders = *req.EmailPaymentReminders
+	}
+	if req.EmailPaymentOutcomes != nil {
+		pref.EmailPaymentOutcomes = *req.EmailPaymentOutcomes
+	}
+	if req.EmailWeeklyDigest != nil {
+		pref.EmailWeeklyDigest = *req.EmailWeeklyDigest
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.prefRepo.Save(ctx, pref); err != nil {
+			return fmt.Errorf("saving notification preferences: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{Action: models.AuditNotificationPrefsUpdate, EntityID: pref.ID, Before: &before, After: pref})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
+
+// ClaimDigest records that the weekly digest for the period ending at periodEnd is being sent to the user at
+// sentAt. It reports false if it was already sent. Call it in the transaction sending the digest, so a failed
+// send releases the claim.
+func (s *notificationPreferenceService) ClaimDigest(ctx context.Context, userID uint, periodEnd time.Time, sentAt time.Time) (bool, error) {
+	pref, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if pref.ID == 0 {
+		if err := s.prefRepo.CreateIfMissing(ctx, pref); err != nil {
+			return false, fmt.Errorf("storing default notification preferences: %w", err)
+		}
+	}
+	claimed, err := s.prefRepo.ClaimDigest(ctx, userID, periodEnd, sentAt)
+	if err != nil {
+		return false, fmt.Errorf("claiming weekly digest: %w", err)
+	}
+	return claimed, nil
+}
+
+func (s *notificationPreferenceService) defaultPreferences(userID uint) *models.NotificationPreference {
+	return &models.NotificationPreference{
+		UserID:                userID,
+		Locale:                string(s.defaultLocale),
+		EmailJoinApprovals:    true,
+		EmailPaymentReminders: true,
+		EmailPaymentOutcomes:  true,
+		EmailWeeklyDigest:     true,
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/events"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultNotificationPageSize = 20
+	maxNotificationPageSize     = 100
+	channelDeliveryTimeout      = time.Minute
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// NotificationService defines the interface for in-app notifications.
+type NotificationService interface {
+	Notify(ctx context.Context, notification *models.Notification) error
+	ListNotifications(ctx context.Context, userID uint, unreadOnly bool, limit int, offset int) ([]models.Notification, error)
+	GetUnreadCount(ctx context.Context, userID uint) (int64, error)
+	MarkAsRead(ctx context.Context, userID uint, notificationID uint) (*models.Notification, error)
+	MarkAllAsRead(ctx context.Context, userID uint) error
+}
+
+// NotificationChannel delivers stored notifications outside the app, e.g. as push messages.
+type NotificationChannel interface {
+	Name() string
+	Deliver(ctx context.Context, notification *models.Notification) error
+}
+
+type notificationService struct {
+	notificationRepo repositories.NotificationRepository
+	transactor       repositories.Transactor
+	eventPublisher   events.Publisher
+}
+
+// NewNotificationService creates a new NotificationService. Channels receive the notifications through
+// NewNotificationChannelSubscriber once they are committed.
+func NewNotificationService(
+	notificationRepo repositories.NotificationRepository,
+	transactor repositories.Transactor,
+	eventPublisher events.Publisher,
+) NotificationService {
+	return &notificationService{notificationRepo: notificationRepo, transactor: transactor, eventPublisher: eventPublisher}
+}
+
+// Notify stores a new notification for its recipient together with the event that hands it to the delivery channels.
+func (s *notificationService) Notify(ctx context.Context, notification *models.Notification) error {
+	if notification.UserID == 0 {
+		return fmt.Errorf("notification has no recipient")
+	}
+	notification.IsRead = false
+	notification.ReadAt = nil
+
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.notificationRepo.Create(txCtx, notification); err != nil {
+			return fmt.Errorf("creating notification: %w", err)
+		}
+		event := events.New(events.NotificationCreated, 0, notification.UserID)
+		event.NotificationID = notification.ID
+		if err := s.eventPublisher.Publish(txCtx, event); err != nil {
+			return fmt.Errorf("recording %s event: %w", event.Type, err)
+		}
+		return nil
+	})
+}
+
+// ListNotifications retrieves a page of the user's notifications, newest first.
+func (s *notificationService) ListNotifications(ctx context.Context, userID uint, unreadOnly bool, limit int, offset int) ([]models.Notification, error) {
+	if limit <= 0 {
+		limit = defaultNotificationPageSize
+	}
+	limit = min(limit, maxNotificationPageSize)
+	offset = max(offset, 0)
+
+	notifications, err := s.notificationRepo.ListByUserID(ctx, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// GetUnreadCount returns how many unread notifications the user has.
+func (s *notificationService) GetUnreadCount(ctx context.Context, userID uint) (int64, error) {
+	count, err := s.notificationRepo.CountUnreadByUserID(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("counting unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkAsRead marks one of the user's notifications as read.
+func (s *notificationService) MarkAsRead(ctx context.Context, userID uint, notificationID uint) (*models.Notification, error) {
+	notification, err := s.notificationRepo.GetByID(ctx, notificationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("fetching notification: %w", err)
+	}
+	if notification.UserID != userID {
+		return nil, ErrForbidden
+	}
+
+	if err := s.notificationRepo.MarkRead(ctx, notificationID); err != nil {
+		return nil, fmt.Errorf("marking notification as read: %w", err)
+	}
+
+	updated, err := s.notificationRepo.GetByID(ctx, notificationID)
+	if err != nil {
+		return nil, fmt.Errorf("re-fetching notification after marking read: %w", err)
+	}
+	return updated, nil
+}
+
+// MarkAllAsRead marks every unread notification of the user as read.
+func (s *notificationService) MarkAllAsRead(ctx context.Context, userID uint) error {
+	if err := s.notificationRepo.MarkAllReadByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("marking all notifications as read: %w", err)
+	}
+	return nil
+}
+
+type notificationChannelSubscriber struct {
+	channel          NotificationChannel
+	notificationRepo repositories.NotificationRepository
+}
+
+// NewNotificationChannelSubscriber creates an OutboxSubscriber that delivers new notifications through the channel.
+// Each channel subscribes on its own, so the relay retries a failed channel without resending through the others.
+func NewNotificationChannelSubscriber(channel NotificationChannel, notificationRepo repositories.NotificationRepository) OutboxSubscriber {
+	return &notificationChannelSubscriber{channel: channel, notificationRepo: notificationRepo}
+}
+
+func (s *notificationChannelSubscriber) Name() string {
+	return "notification_" + s.channel.Name()
+}
+
+func (s *notificationChannelSubscriber) SendsExternally() {}
+
+// Handle delivers the notification of a NotificationCreated event. Other events are ignored.
+func (s *notificationChannelSubscriber) Handle(ctx context.Context, event events.Event) error {
+	if event.Type != events.NotificationCreated {
+		return nil
+	}
+	notification, err := s.notificationRepo.GetByID(ctx, event.NotificationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("fetching notification %d: %w", event.NotificationID, err)
+	}
+
+	deliverCtx, cancel := context.WithTimeout(ctx, channelDeliveryTimeout)
+	defer cancel()
+	if err := s.channel.Deliver(deliverCtx, notification); err != nil {
+		return fmt.Errorf("delivering notification %d: %w", notification.ID, err)
+	}
+	return nil
+}
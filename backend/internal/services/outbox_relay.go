@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/events"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+)
+
+const (
+	outboxPollInterval    = 500 * time.Millisecond
+	outboxBatchSize       = 50
+	outboxLease           = 5 * time.Minute
+	outboxBatchDeadline   = 2 * time.Minute // No event is started after it, so the last one is done well within the lease
+	outboxMaxAttempts     = 15
+	outboxInitialBackoff  = 2 * time.Second
+	outboxMaxBackoff      = 10 * time.Minute
+	outboxRetention       = 7 * 24 * time.Hour
+	outboxCleanupInterval = time.Hour
+)
+
+// OutboxSubscriber handles events published by the outbox relay. Delivery is at least once:
+// the relay skips events a subscriber has already handled, but Handle may still see an event again
+// if recording that it was handled fails.
+type OutboxSubscriber interface {
+	Name() string
+	Handle(ctx context.Context, event events.Event) error
+}
+
+// ExternalOutboxSubscriber is an OutboxSubscriber that sends events out of the database, e.g. as push messages or
+// emails. The relay hands it events outside of any transaction and records an event as handled once Handle
+// succeeds, so a slow provider holds no database connection or lock.
+type ExternalOutboxSubscriber interface {
+	OutboxSubscriber
+	SendsExternally()
+}
+
+type outboxPublisher struct {
+	outboxRepo repositories.OutboxRepository
+}
+
+// NewOutboxPublisher creates an events.Publisher that stores events in the outbox.
+// Publish with the context of the transaction making the domain change so both commit together.
+func NewOutboxPublisher(outboxRepo repositories.OutboxRepository) events.Publisher {
+	return &outboxPublisher{outboxRepo: outboxRepo}
+}
+
+// Publish stores the event in the outbox for the relay to deliver.
+func (p *outboxPublisher) Publish(ctx context.Context, event events.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encoding event %s: %w", event.ID, err)
+	}
+	return p.outboxRepo.Create(ctx, &models.OutboxEvent{
+		EventID:       event.ID,
+		EventType:     string(event.Type),
+		Payload:       string(payload),
+		NextAttemptAt: event.OccurredAt,
+	})
+}
+
+// OutboxRelay delivers stored outbox events to the in-process subscribers, retrying failed deliveries with backoff.
+type OutboxRelay struct {
+	outboxRepo  repositories.OutboxRepository
+	transactor  repositories.Transactor
+	subscribers []OutboxSubscriber
+}
+
+// NewOutboxRelay creates a new OutboxRelay delivering to the given subscribers.
+func NewOutboxRelay(outboxRepo repositories.OutboxRepository, transactor repositories.Transactor, subscribers ...OutboxSubscriber) *OutboxRelay {
+	return &OutboxRelay{outboxRepo: outboxRepo, transactor: transactor, subscribers: subscribers}
+}
+
+// Start polls the outbox in the background until ctx is cancelled.
+func (r *OutboxRelay) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+		lastCleanup := time.Time{}
+
+		for {
+			for {
+				processed, err := r.RunOnce(ctx, time.Now().UTC())
+				if err != nil {
+					log.Printf("ERROR: Outbox relay run failed: %v", err)
+					break
+				}
+				// Keep draining while full batches come back.
+				if processed < outboxBatchSize {
+					break
+				}
+			}
+
+			if time.Since(lastCleanup) >= outboxCleanupInterval {
+				lastCleanup = time.Now()
+				if deleted, err := r.outboxRepo.DeletePublishedBefore(ctx, time.Now().UTC().Add(-outboxRetention)); err != nil {
+					log.Printf("Warning: Failed to clean up published outbox events: %v", err)
+				} else if deleted > 0 {
+					log.Printf("INFO: Removed %d published outbox events", deleted)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce claims and leases a batch of due events, delivers each to every subscriber and records the outcome.
+// Events not started by outboxBatchDeadline are left for when their lease runs out. It returns how many events
+// were claimed.
+func (r *OutboxRelay) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	outboxEvents, err := r.outboxRepo.ClaimPending(ctx, now, outboxLease, outboxMaxAttempts, outboxBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("claiming outbox events: %w", err)
+	}
+
+	deadline := time.Now().Add(outboxBatchDeadline)
+	for _, outboxEvent := range outboxEvents {
+		if time.Now().After(deadline) {
+			break
+		}
+		if deliverErr := r.deliver(ctx, outboxEvent); deliverErr != nil {
+			attempt := outboxEvent.Attempts + 1
+			if attempt >= outboxMaxAttempts {
+				log.Printf("CRITICAL: Giving up on outbox event %s (%s) after %d attempts: %v", outboxEvent.EventID, outboxEvent.EventType, attempt, deliverErr)
+			} else {
+				log.Printf("Warning: Delivering outbox event %s (%s) failed (attempt %d): %v", outboxEvent.EventID, outboxEvent.EventType, attempt, deliverErr)
+			}
+			if err := r.outboxRepo.MarkFailed(ctx, outboxEvent.ID, deliverErr.Error(), time.Now().UTC().Add(outboxBackoff(attempt))); err != nil {
+				return len(outboxEvents), fmt.Errorf("recording failed outbox event %d: %w", outboxEvent.ID, err)
+			}
+			continue
+		}
+		if err := r.outboxRepo.MarkPublished(ctx, outboxEvent.ID, time.Now().UTC()); err != nil {
+			return len(outboxEvents), fmt.Errorf("marking outbox event %d published: %w", outboxEvent.ID, err)
+		}
+	}
+	return len(outboxEvents), nil
+}
+
+func (r *OutboxRelay) deliver(ctx context.Context, outboxEvent models.OutboxEvent) error {
+	var event events.Event
+	if err := json.Unmarshal([]byte(outboxEvent.Payload), &event); err != nil {
+		return fmt.Errorf("decoding payload: %w", err)
+	}
+
+	var errs []error
+	for _, subscriber := range r.subscribers {
+		if err := r.deliverTo(ctx, subscriber, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", subscriber.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliverTo hands the event to one subscriber unless it already handled it. For most subscribers the processed
+// marker and any writes they make with the given context commit together. External subscribers run outside any
+// transaction, and the marker is recorded after they succeed.
+func (r *OutboxRelay) deliverTo(ctx context.Context, subscriber OutboxSubscriber, event events.Event) error {
+	if _, ok := subscriber.(ExternalOutboxSubscriber); ok {
+		processed, err := r.outboxRepo.IsProcessed(ctx, event.ID, subscriber.Name())
+		if err != nil {
+			return fmt.Errorf("checking processed event: %w", err)
+		}
+		if processed {
+			return nil
+		}
+		if err := subscriber.Handle(ctx, event); err != nil {
+			return err
+		}
+		if _, err := r.outboxRepo.MarkProcessed(ctx, event.ID, subscriber.Name()); err != nil {
+			return fmt.Errorf("recording processed event: %w", err)
+		}
+		return nil
+	}
+
+	return r.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		first, err := r.outboxRepo.MarkProcessed(txCtx, event.ID, subscriber.Name())
+		if err != nil {
+			return fmt.Errorf("recording processed event: %w", err)
+		}
+		if !first {
+			return nil
+		}
+		return subscriber.Handle(txCtx, event)
+	})
+}
+
+func outboxBackoff(attempt int) time.Duration {
+	backoff := outboxInitialBackoff
+	for i := 1; i < attempt && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxBackoff)
+}
+
+type realtimeOutboxSubscriber struct {
+	bus events.Publisher
+}
+
+// NewRealtimeOutboxSubscriber creates an OutboxSubscriber that forwards events to connected clients through the event bus.
+func NewRealtimeOutboxSubscriber(bus events.Publisher) OutboxSubscriber {
+	return &realtimeOutboxSubscriber{bus: bus}
+}
+
+func (s *realtimeOutboxSubscriber) Name() string {
+	return "realtime"
+}
+
+func (s *realtimeOutboxSubscriber) SendsExternally() {}
+
+// Handle publishes the event on the real-time bus.
+func (s *realtimeOutboxSubscriber) Handle(ctx context.Context, event events.Event) error {
+	return s.bus.Publish(ctx, event)
+}
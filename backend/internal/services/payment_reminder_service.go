@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	paymentReminderInterval = time.Hour
+	// maxReminderOffsetDays bounds how far before or after the due date a reminder can be scheduled.
+	maxReminderOffsetDays = 14
+)
+
+// PaymentReminderService defines the interface for managing hosted subscriptions' payment reminder schedules.
+type PaymentReminderService interface {
+	GetSchedule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.ReminderScheduleResponse, error)
+	UpdateSchedule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateReminderScheduleRequest) (*models.ReminderScheduleResponse, error)
+}
+
+type paymentReminderService struct {
+	reminderRepo  repositories.PaymentReminderRepository
+	hostedSubRepo repositories.HostedSubscriptionRepository
+	transactor    repositories.Transactor
+	auditSvc      AuditService
+}
+
+// NewPaymentReminderService creates a new PaymentReminderService.
+func NewPaymentReminderService(
+	reminderRepo repositories.PaymentReminderRepository,
+	hostedSubRepo repositories.HostedSubscriptionRepository,
+	transactor repositories.Transactor,
+	auditSvc AuditService,
+) PaymentReminderService {
+	return &paymentReminderService{reminderRepo: reminderRepo, hostedSubRepo: hostedSubRepo, transactor: transactor, auditSvc: auditSvc}
+}
+
+// GetSchedule returns the reminder schedule of a hosted subscription owned by the host.
+func (s *paymentReminderService) GetSchedule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.ReminderScheduleResponse, error) {
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildSchedule(ctx, hostedSub)
+}
+
+// UpdateSchedule replaces the reminder schedule of a hosted subscription owned by the host.
+func (s *paymentReminderService) UpdateSchedule(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdateReminderScheduleRequest) (*models.ReminderScheduleResponse, error) {
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.buildSchedule(ctx, hostedSub)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := slices.Clone(req.OffsetDays)
+	slices.Sort(offsets)
+	offsets = slices.Compact(offsets)
+	var after *models.ReminderScheduleResponse
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reminderRepo.ReplaceSchedule(ctx, hostedSub.ID, offsets); err != nil {
+			return fmt.Errorf("saving reminder schedule: %w", err)
+		}
+
+		hostedSub.CustomReminderSchedule = true
+		var err error
+		if after, err = s.buildSchedule(ctx, hostedSub); err != nil {
+			return err
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditReminderScheduleUpdate,
+			EntityID:             hostedSub.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               before,
+			After:                after,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+func (s *paymentReminderService) getOwnedSubscription(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.HostedSubscription, error) {
+	hostedSub, err := s.hostedSubRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+	return hostedSub, nil
+}
+
+func (s *paymentReminderService) buildSchedule(ctx context.Context, hostedSub *models.HostedSubscription) (*models.ReminderScheduleResponse, error) {
+	schedules, err := loadReminderSchedules(ctx, s.reminderRepo, []models.HostedSubscription{*hostedSub})
+	if err != nil {
+		return nil, err
+	}
+	return &models.ReminderScheduleResponse{
+		HostedSubscriptionID: hostedSub.ID,
+		OffsetDays:           schedules[hostedSub.ID],
+		IsDefault:            !hostedSub.CustomReminderSchedule,
+	}, nil
+}
+
+// loadReminderSchedules returns the reminder offsets of each hosted subscription, sorted ascending.
+// Subscriptions that never customized their schedule get the default one.
+func loadReminderSchedules(ctx context.Context, reminderRepo repositories.PaymentReminderRepository, hostedSubs []models.HostedSubscription) (map[uint][]int, error) {
+	schedules := make(map[uint][]int, len(hostedSubs))
+	var customIDs []uint
+	for _, hs := range hostedSubs {
+		if hs.CustomReminderSchedule {
+			schedules[hs.ID] = []int{}
+			customIDs = append(customIDs, hs.ID)
+		} else {
+			schedules[hs.ID] = slices.Clone(models.DefaultReminderOffsetDays)
+		}
+	}
+
+	rules, err := reminderRepo.ListRulesBySubscriptionIDs(ctx, customIDs)
+	if err != nil {
+		return nil, fmt.Errorf("listing reminder rules: %w", err)
+	}
+	for _, rule := range rules {
+		schedules[rule.HostedSubscriptionID] = append(schedules[rule.HostedSubscriptionID], rule.OffsetDays)
+	}
+	return schedules, nil
+}
+
+// PaymentReminderJob reminds members about upcoming and overdue payments according to each
+// hosted subscription's reminder schedule. Every scheduled reminder is sent at most once per payment cycle.
+type PaymentReminderJob struct {
+	membershipRepo  repositories.SubscriptionMembershipRepository
+	reminderRepo    repositories.PaymentReminderRepository
+	notificationSvc NotificationService
+	transactor      repositories.Transactor
+	location        *time.Location
+}
+
+// NewPaymentReminderJob creates a new PaymentReminderJob. Days are counted in location.
+func NewPaymentReminderJob(
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	reminderRepo repositories.PaymentReminderRepository,
+	notificationSvc NotificationService,
+	transactor repositories.Transactor,
+	location *time.Location,
+) *PaymentReminderJob {
+	return &PaymentReminderJob{
+		membershipRepo:  membershipRepo,
+		reminderRepo:    reminderRepo,
+		notificationSvc: notificationSvc,
+		transactor:      transactor,
+		location:        location,
+	}
+}
+
+// Start runs the job hourly in the background until ctx is cancelled.
+func (j *PaymentReminderJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(paymentReminderInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := j.RunOnce(ctx, time.Now().UTC()); err != nil {
+				log.Printf("ERROR: Payment reminder run failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce sends the reminders that have become due by now. For each membership only the most recent
+// scheduled reminder that has passed is considered, so a missed run catches up without a burst of stale reminders.
+// Reminders quote the amount the member owes, and members whose proof awaits review or whose credit covers the
+// cycle are not reminded.
+func (j *PaymentReminderJob) RunOnce(ctx context.Context, now time.Time) error {
+	today := startOfDay(now, j.location)
+	memberships, err := j.membershipRepo.ListDueBetween(ctx,
+		today.AddDate(0, 0, -maxReminderOffsetDays),
+		today.AddDate(0, 0, maxReminderOffsetDays+1))
+	if err != nil {
+		return fmt.Errorf("listing memberships due for payment: %w", err)
+	}
+	if len(memberships) == 0 {
+		return nil
+	}
+
+	hostedSubs := make([]models.HostedSubscription, 0, len(memberships))
+	seen := make(map[uint]bool, len(memberships))
+	for _, membership := range memberships {
+		if !seen[membership.HostedSubscriptionID] {
+			seen[membership.HostedSubscriptionID] = true
+			hostedSubs = append(hostedSubs, membership.HostedSubscription)
+		}
+	}
+	schedules, err := loadReminderSchedules(ctx, j.reminderRepo, hostedSubs)
+	if err != nil {
+		return err
+	}
+
+	for _, membership := range memberships {
+		dueDay := startOfDay(*membership.NextPaymentDate, j.location)
+		daysFromDue := int(math.Round(today.Sub(dueDay).Hours() / 24))
+
+		offset, ok := latestPassedOffset(schedules[membership.HostedSubscriptionID], daysFromDue)
+		if !ok {
+			continue
+		}
+		if err := j.remind(ctx, membership, offset); err != nil {
+			log.Printf("Warning: Failed to send payment reminder for membership %d: %v", membership.ID, err)
+		}
+	}
+	return nil
+}
+
+func (j *PaymentReminderJob) remind(ctx context.Context, membership models.SubscriptionMembership, offset int) error {
+	// Quote what the member actually owes; credit from earlier payments may already cover the cycle.
+	amountDue := membershipAmountDue(&membership)
+	if amountDue <= 0 {
+		return nil
+	}
+
+	hostedSub := membership.HostedSubscription
+	dueDate := membership.NextPaymentDate.In(j.location).Format("2 Jan 2006")
+
+	var title, message string
+	switch {
+	case offset < 0:
+		title = "Payment due soon"
+		message = fmt.Sprintf("Your payment of %.2f for %s is due on %s.", amountDue, hostedSub.SubscriptionTitle, dueDate)
+	case offset == 0:
+		title = "Payment due today"
+		message = fmt.Sprintf("Your payment of %.2f for %s is due today.", amountDue, hostedSub.SubscriptionTitle)
+	default:
+		title = "Payment overdue"
+		message = fmt.Sprintf("Your payment of %.2f for %s was due on %s and is now overdue.", amountDue, hostedSub.SubscriptionTitle, dueDate)
+	}
+
+	// The dispatch is recorded together with the notification, so a failed notification is retried on the next run.
+	return j.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		recorded, err := j.reminderRepo.RecordDispatch(ctx, &models.PaymentReminderDispatch{
+			SubscriptionMembershipID: membership.ID,
+			OffsetDays:               offset,
+			DueDate:                  *membership.NextPaymentDate,
+		})
+		if err != nil {
+			return fmt.Errorf("recording reminder: %w", err)
+		}
+		if !recorded {
+			return nil
+		}
+
+		return j.notificationSvc.Notify(ctx, &models.Notification{
+			UserID:               membership.MemberUserID,
+			Type:                 models.NotificationPaymentDue,
+			Title:                title,
+			Message:              message,
+			HostedSubscriptionID: &membership.HostedSubscriptionID,
+		})
+	})
+}
+
+// latestPassedOffset returns the largest offset in the ascending schedule that is not after daysFromDue.
+func latestPassedOffset(offsets []int, daysFromDue int) (int, bool) {
+	for i := len(offsets) - 1; i >= 0; i-- {
+		if offsets[i] <= daysFromDue {
+			return offsets[i], true
+		}
+	}
+	return 0, false
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
@@ -4,50 +4,110 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/xNatthapol/hubster/internal/events"
 	"github.com/xNatthapol/hubster/internal/models"
 	"github.com/xNatthapol/hubster/internal/repositories"
 	"gorm.io/gorm"
 	"log"
 )
 
+const (
+	defaultLedgerPageSize = 50
+	maxLedgerPageSize     = 200
+
+	// maxCyclesCoveredPerPayment bounds how far ahead a single payment's credit can move the due date.
+	maxCyclesCoveredPerPayment = 24
+)
+
+// maxSupersededThreadDepth bounds how many resubmissions back a message thread is collected.
+const maxSupersededThreadDepth = 20
+
+// maxProofImageHashDistance is how many of the 64 perceptual hash bits two proof images may differ in and
+// still count as the same picture; re-encoded or rescaled copies stay well within it.
+const maxProofImageHashDistance = 4
+
 // Custom errors for PaymentService
 var (
-	ErrMembershipNotFound         = errors.New("subscription membership not found")
-	ErrNotMember                  = errors.New("user is not the member of this subscription slot")
-	ErrInvalidPaymentCycle        = errors.New("invalid payment cycle identifier for this membership")
-	ErrPaymentAlreadyProcessed    = errors.New("a payment record for this cycle has already been processed (approved/declined)")
-	ErrPaymentRecordNotFound      = errors.New("payment record not found")
-	ErrPaymentRecordNotModifiable = errors.New("payment record is not in a state that can be modified by host")
+	ErrMembershipNotFound            = errors.New("subscription membership not found")
+	ErrNotMember                     = errors.New("user is not the member of this subscription slot")
+	ErrInvalidPaymentCycle           = errors.New("invalid payment cycle identifier for this membership")
+	ErrPaymentAlreadyProcessed       = errors.New("a payment record for this cycle has already been processed (approved/declined)")
+	ErrPaymentRecordNotFound         = errors.New("payment record not found")
+	ErrPaymentRecordNotModifiable    = errors.New("payment record is not in a state that can be modified by host")
+	ErrPaymentRecordNotDisputable    = errors.New("only a declined payment record can be disputed")
+	ErrPaymentRecordNotResubmittable = errors.New("only a declined or disputed payment record can be resubmitted")
+	ErrPrepaymentAmountTooLow        = errors.New("amount paid does not cover every cycle being prepaid")
+	ErrSlipWarningsNotAcknowledged   = errors.New("the payment slip needs attention: acknowledge the slip warnings to approve")
+	ErrPaymentNotAutoApproved        = errors.New("payment record is not approved by an auto-approval rule")
+	ErrAutoApprovalNotRevertible     = errors.New("the membership's balance has changed since the payment was auto-approved")
+	ErrPaymentRecordAlreadyReviewed  = errors.New("payment record was reviewed by someone else in the meantime")
 )
 
+// awaitingReviewStatuses are the statuses in which a host can still approve or decline a record.
+var awaitingReviewStatuses = []models.PaymentRecordStatus{models.PaymentRecordStatusProofSubmitted, models.PaymentRecordStatusDisputed}
+
 // PaymentService defines the interface for payment-related operations.
 type PaymentService interface {
 	SubmitPaymentProof(ctx context.Context, memberUserID uint, membershipID uint, req *models.CreatePaymentRecordRequest) (*models.PaymentRecord, error)
 	GetPaymentRecordDetails(ctx context.Context, paymentRecordID uint, accessorUserID uint, isHostAction bool) (*models.PaymentRecordResponse, error)
-	ApprovePaymentProof(ctx context.Context, hostUserID uint, paymentRecordID uint) (*models.PaymentRecordResponse, error)
-	DeclinePaymentProof(ctx context.Context, hostUserID uint, paymentRecordID uint) (*models.PaymentRecordResponse, error)
+	ApprovePaymentProof(ctx context.Context, hostUserID uint, paymentRecordID uint, acknowledgeSlipWarnings bool) (*models.PaymentRecordResponse, error)
+	RevertAutoApproval(ctx context.Context, hostUserID uint, paymentRecordID uint) (*models.PaymentRecordResponse, error)
+	BulkApprovePaymentProofs(ctx context.Context, hostUserID uint, paymentRecordIDs []uint, acknowledgeSlipWarnings bool) []BulkItemResult
+	BulkDeclinePaymentProofs(ctx context.Context, hostUserID uint, paymentRecordIDs []uint, reason string) []BulkItemResult
+	DeclinePaymentProof(ctx context.Context, hostUserID uint, paymentRecordID uint, reason string) (*models.PaymentRecordResponse, error)
+	DisputePaymentRecord(ctx context.Context, memberUserID uint, paymentRecordID uint, req *models.DisputePaymentRecordRequest) (*models.PaymentRecordResponse, error)
+	ResubmitPaymentProof(ctx context.Context, memberUserID uint, paymentRecordID uint, req *models.ResubmitPaymentProofRequest) (*models.PaymentRecord, error)
+	ListPaymentRecordMessages(ctx context.Context, accessorUserID uint, paymentRecordID uint) ([]models.PaymentRecordMessageResponse, error)
+	PostPaymentRecordMessage(ctx context.Context, authorUserID uint, paymentRecordID uint, req *models.CreatePaymentRecordMessageRequest) (*models.PaymentRecordMessageResponse, error)
 	ListPaymentRecordsForMembership(ctx context.Context, memberUserID uint, membershipID uint) ([]models.PaymentRecord, error)
 	ListPaymentRecordsForHost(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, statusFilter models.PaymentRecordStatus) ([]models.PaymentRecordResponse, error)
+	ListMembershipLedger(ctx context.Context, accessorUserID uint, membershipID uint, limit int, offset int) ([]models.MembershipLedgerEntry, error)
 }
 
 type paymentService struct {
 	paymentRecordRepo repositories.PaymentRecordRepository
+	messageRepo       repositories.PaymentRecordMessageRepository
+	ledgerRepo        repositories.MembershipLedgerRepository
 	membershipRepo    repositories.SubscriptionMembershipRepository
 	hsRepo            repositories.HostedSubscriptionRepository
+	transactor        repositories.Transactor
+	eventPublisher    events.Publisher
+	auditSvc          AuditService
+	accountingSvc     AccountingService
+	proofInspector    ProofImageInspector
+	autoApprovalRepo  repositories.AutoApprovalRepository
 }
 
 // NewPaymentService creates a new PaymentService instance.
 func NewPaymentService(
 	prRepo repositories.PaymentRecordRepository,
+	messageRepo repositories.PaymentRecordMessageRepository,
+	ledgerRepo repositories.MembershipLedgerRepository,
 	memRepo repositories.SubscriptionMembershipRepository,
 	hsRepo repositories.HostedSubscriptionRepository,
+	transactor repositories.Transactor,
+	eventPublisher events.Publisher,
+	auditSvc AuditService,
+	accountingSvc AccountingService,
+	proofInspector ProofImageInspector,
+	autoApprovalRepo repositories.AutoApprovalRepository,
 ) PaymentService {
 	return &paymentService{
 		paymentRecordRepo: prRepo,
+		messageRepo:       messageRepo,
+		ledgerRepo:        ledgerRepo,
 		membershipRepo:    memRepo,
 		hsRepo:            hsRepo,
+		transactor:        transactor,
+		eventPublisher:    eventPublisher,
+		auditSvc:          auditSvc,
+		accountingSvc:     accountingSvc,
+		proofInspector:    proofInspector,
+		autoApprovalRepo:  autoApprovalRepo,
 	}
 }
 
@@ -71,10 +131,16 @@ func (s *paymentService) SubmitPaymentProof(ctx context.Context, memberUserID ui
 	} else {
 		return nil, fmt.Errorf("could not determine amount expected for membership ID %d", membershipID)
 	}
+	cycleCount := max(req.CycleCount, 1)
+	amountExpected = models.RoundMoney(amountExpected * float64(cycleCount))
+	if err := validatePrepayment(membership, cycleCount, amountExpected, req.AmountPaid); err != nil {
+		return nil, err
+	}
 
 	paymentRecord := &models.PaymentRecord{
 		SubscriptionMembershipID: membershipID,
 		PaymentCycleIdentifier:   req.PaymentCycleIdentifier,
+		CycleCount:               cycleCount,
 		AmountExpected:           amountExpected,
 		AmountPaid:               req.AmountPaid,
 		ProofImageURL:            req.ProofImageURL,
@@ -83,17 +149,109 @@ func (s *paymentService) SubmitPaymentProof(ctx context.Context, memberUserID ui
 		SubmittedAt:              time.Now().UTC(),
 		Status:                   models.PaymentRecordStatusProofSubmitted,
 	}
+	s.inspectProof(ctx, paymentRecord)
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.flagReusedProof(ctx, paymentRecord, 0); err != nil {
+			return err
+		}
+		if err := s.paymentRecordRepo.Create(ctx, paymentRecord); err != nil {
+			return fmt.Errorf("creating payment record: %w", err)
+		}
+		err := s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditPaymentRecordSubmit,
+			EntityID:             paymentRecord.ID,
+			HostedSubscriptionID: &membership.HostedSubscriptionID,
+			After:                paymentRecord,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.membershipRepo.UpdatePaymentStatus(ctx, membershipID, models.PaymentStatusProofSubmitted); err != nil {
+			return fmt.Errorf("updating membership payment status: %w", err)
+		}
 
-	if err := s.paymentRecordRepo.Create(ctx, paymentRecord); err != nil {
-		return nil, fmt.Errorf("creating payment record: %w", err)
+		event := events.New(events.PaymentProofSubmitted, memberUserID, membership.HostedSubscription.HostUserID, memberUserID)
+		event.HostedSubscriptionID = membership.HostedSubscriptionID
+		event.MembershipID = membershipID
+		event.PaymentRecordID = paymentRecord.ID
+		event.Status = string(paymentRecord.Status)
+		if err := s.publish(ctx, event); err != nil {
+			return err
+		}
+		return s.autoApprove(ctx, paymentRecord, membership)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return paymentRecord, nil
+}
 
-	if err := s.membershipRepo.UpdatePaymentStatus(ctx, membershipID, models.PaymentStatusProofSubmitted); err != nil {
-		log.Printf("CRITICAL: Created PaymentRecord %d but failed to update SubscriptionMembership %d status to ProofSubmitted: %v",
-			paymentRecord.ID, membershipID, err)
+// inspectProof reads the bank slip QR code in the proof image of a new payment record and fingerprints the image.
+// The slip's reference fills in a missing transaction reference, or flags the record when the member gave
+// a different one. A proof without a readable slip is still accepted and reviewed by eye.
+func (s *paymentService) inspectProof(ctx context.Context, pr *models.PaymentRecord) {
+	inspection, err := s.proofInspector.Inspect(ctx, pr.ProofImageURL)
+	if err != nil {
+		log.Printf("Warning: Could not inspect the proof image for membership %d: %v", pr.SubscriptionMembershipID, err)
+		pr.SlipCheckStatus = models.SlipCheckUnreadable
+		return
 	}
+	pr.SetProofImageHash(inspection.PerceptualHash)
 
-	return paymentRecord, nil
+	slip := inspection.Slip
+	if slip == nil {
+		pr.SlipCheckStatus = models.SlipCheckNoSlip
+		return
+	}
+	pr.SlipSendingBankCode = slip.SendingBankCode
+	pr.SlipTransactionRef = slip.TransactionReference
+	given := strings.TrimSpace(pr.TransactionReference)
+	switch {
+	case given == "":
+		pr.TransactionReference = slip.TransactionReference
+		pr.SlipCheckStatus = models.SlipCheckVerified
+	case strings.EqualFold(given, slip.TransactionReference):
+		pr.SlipCheckStatus = models.SlipCheckVerified
+	default:
+		pr.SlipCheckStatus = models.SlipCheckMismatch
+	}
+}
+
+// flagReusedProof flags a new payment record whose slip already backs another record, or whose proof image
+// closely matches an earlier proof. supersededRecordID is the record the new one replaces, which may reuse both.
+func (s *paymentService) flagReusedProof(ctx context.Context, pr *models.PaymentRecord, supersededRecordID uint) error {
+	if pr.SlipTransactionRef != "" {
+		others, err := s.paymentRecordRepo.ListBySlip(ctx, pr.SlipSendingBankCode, pr.SlipTransactionRef)
+		if err != nil {
+			return fmt.Errorf("looking up payment records with the same slip: %w", err)
+		}
+		for _, other := range others {
+			if other.ID == supersededRecordID {
+				continue
+			}
+			pr.SlipCheckStatus = models.SlipCheckDuplicate
+			pr.SlipDuplicateOfRecordID = &other.ID
+			break
+		}
+	}
+
+	similar, err := s.paymentRecordRepo.ListBySimilarProofImage(ctx, pr, maxProofImageHashDistance)
+	if err != nil {
+		return fmt.Errorf("looking up payment records with a similar proof image: %w", err)
+	}
+	for _, other := range similar {
+		if other.ID == supersededRecordID {
+			continue
+		}
+		// Slips from the same banking app share a layout; two different slip references are two different transfers.
+		if pr.SlipTransactionRef != "" && other.SlipTransactionRef != "" && other.SlipTransactionRef != pr.SlipTransactionRef {
+			continue
+		}
+		pr.SimilarProofRecordID = &other.ID
+		break
+	}
+	return nil
 }
 
 // ListPaymentRecordsForHost retrieves payment records for a specific hosted subscription filtered by status.
@@ -135,25 +293,7 @@ func (s *paymentService) ListPaymentRecordsForHost(ctx context.Context, hostUser
 			subTitle = fmt.Sprintf("Subscription ID %d", pr.SubscriptionMembership.HostedSubscriptionID)
 		}
 
-		responses[i] = models.PaymentRecordResponse{
-			ID:                       pr.ID,
-			CreatedAt:                pr.CreatedAt,
-			UpdatedAt:                pr.UpdatedAt,
-			SubscriptionMembershipID: pr.SubscriptionMembershipID,
-			PaymentCycleIdentifier:   pr.PaymentCycleIdentifier,
-			AmountExpected:           pr.AmountExpected,
-			AmountPaid:               pr.AmountPaid,
-			PaymentMethod:            pr.PaymentMethod,
-			TransactionReference:     pr.TransactionReference,
-			ProofImageURL:            pr.ProofImageURL,
-			SubmittedAt:              pr.SubmittedAt,
-			Status:                   pr.Status,
-			ReviewedByUserID:         pr.ReviewedByUserID,
-			ReviewedAt:               pr.ReviewedAt,
-			MemberName:               memberName,
-			MemberProfilePictureURL:  memberAvatar,
-			SubscriptionTitle:        subTitle,
-		}
+		responses[i] = *newPaymentRecordResponse(&pr, memberName, memberAvatar, subTitle)
 	}
 	return responses, nil
 }
@@ -192,30 +332,13 @@ func (s *paymentService) GetPaymentRecordDetails(ctx context.Context, paymentRec
 		subTitle = "Subscription (Details Missing)"
 	}
 
-	response := &models.PaymentRecordResponse{
-		ID:                       pr.ID,
-		CreatedAt:                pr.CreatedAt,
-		UpdatedAt:                pr.UpdatedAt,
-		SubscriptionMembershipID: pr.SubscriptionMembershipID,
-		PaymentCycleIdentifier:   pr.PaymentCycleIdentifier,
-		AmountExpected:           pr.AmountExpected,
-		AmountPaid:               pr.AmountPaid,
-		PaymentMethod:            pr.PaymentMethod,
-		TransactionReference:     pr.TransactionReference,
-		ProofImageURL:            pr.ProofImageURL,
-		SubmittedAt:              pr.SubmittedAt,
-		Status:                   pr.Status,
-		ReviewedByUserID:         pr.ReviewedByUserID,
-		ReviewedAt:               pr.ReviewedAt,
-		MemberName:               memberName,
-		MemberProfilePictureURL:  memberAvatar,
-		SubscriptionTitle:        subTitle,
-	}
+	response := newPaymentRecordResponse(pr, memberName, memberAvatar, subTitle)
 	return response, nil
 }
 
-// ApprovePaymentProof allows a host to approve a payment proof.
-func (s *paymentService) ApprovePaymentProof(ctx context.Context, hostUserID uint, paymentRecordID uint) (*models.PaymentRecordResponse, error) {
+// ApprovePaymentProof allows a host to approve a payment proof. A proof whose slip mismatches the given
+// transaction reference or was already used is only approved once the host acknowledges it.
+func (s *paymentService) ApprovePaymentProof(ctx context.Context, hostUserID uint, paymentRecordID uint, acknowledgeSlipWarnings bool) (*models.PaymentRecordResponse, error) {
 	pr, err := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -228,70 +351,210 @@ func (s *paymentService) ApprovePaymentProof(ctx context.Context, hostUserID uin
 		return nil, ErrForbidden
 	}
 
-	if pr.Status != models.PaymentRecordStatusProofSubmitted {
+	if !isAwaitingReview(pr.Status) {
 		return nil, ErrPaymentRecordNotModifiable
 	}
+	if pr.SlipCheckStatus.NeedsAcknowledgement() && !acknowledgeSlipWarnings {
+		return nil, fmt.Errorf("%w (slip check: %s)", ErrSlipWarningsNotAcknowledged, pr.SlipCheckStatus)
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The status is checked again as part of the update, so of concurrent reviews only one applies the payment.
+		updated, err := s.paymentRecordRepo.UpdateStatus(ctx, paymentRecordID, awaitingReviewStatuses, models.PaymentRecordStatusApproved, &hostUserID)
+		if err != nil {
+			return fmt.Errorf("updating payment record status: %w", err)
+		}
+		if !updated {
+			return ErrPaymentRecordAlreadyReviewed
+		}
+		if err := s.applyApprovedPayment(ctx, pr); err != nil {
+			return err
+		}
+		if err := s.recordReview(ctx, models.AuditPaymentRecordApprove, pr, models.PaymentRecordStatusApproved, hostUserID, ""); err != nil {
+			return err
+		}
 
-	err = s.paymentRecordRepo.UpdateStatus(ctx, paymentRecordID, models.PaymentRecordStatusApproved, &hostUserID)
+		event := events.New(events.PaymentProofApproved, hostUserID, pr.SubscriptionMembership.MemberUserID, hostUserID)
+		event.HostedSubscriptionID = pr.SubscriptionMembership.HostedSubscriptionID
+		event.MembershipID = pr.SubscriptionMembershipID
+		event.PaymentRecordID = pr.ID
+		event.Status = string(models.PaymentRecordStatusApproved)
+		return s.publish(ctx, event)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("updating payment record status: %w", err)
+		return nil, err
 	}
 
-	// Calculate NextPaymentDate
-	var nextPaymentDate time.Time
-	baseDateForNext := time.Now().UTC()
-	if pr.SubscriptionMembership.NextPaymentDate != nil && !pr.SubscriptionMembership.NextPaymentDate.IsZero() {
-		if pr.SubscriptionMembership.NextPaymentDate.After(baseDateForNext) {
-			baseDateForNext = *pr.SubscriptionMembership.NextPaymentDate
+	updatedPRFull, fetchErr := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
+	if fetchErr != nil {
+		return nil, fmt.Errorf("re-fetching payment record after approval: %w", fetchErr)
+	}
+	return mapReviewedPaymentRecord(updatedPRFull), nil
+}
+
+// BulkApprovePaymentProofs approves several payment proofs, each exactly as ApprovePaymentProof would and in its
+// own transaction, so one that cannot be approved does not hold back the others.
+func (s *paymentService) BulkApprovePaymentProofs(ctx context.Context, hostUserID uint, paymentRecordIDs []uint, acknowledgeSlipWarnings bool) []BulkItemResult {
+	ids := uniqueIDs(paymentRecordIDs)
+	results := make([]BulkItemResult, 0, len(ids))
+	for _, id := range ids {
+		result := BulkItemResult{ID: id}
+		if pr, err := s.ApprovePaymentProof(ctx, hostUserID, id, acknowledgeSlipWarnings); err != nil {
+			result.Err = err
+		} else {
+			result.Status = string(pr.Status)
 		}
-	} else {
-		baseDateForNext = pr.SubscriptionMembership.JoinedDate
+		results = append(results, result)
 	}
+	return results
+}
 
-	if pr.SubscriptionMembership.HostedSubscription.BillingCycle == models.BillingMonthly {
-		nextPaymentDate = baseDateForNext.AddDate(0, 1, 0)
-	} else if pr.SubscriptionMembership.HostedSubscription.BillingCycle == models.BillingAnnually {
-		nextPaymentDate = baseDateForNext.AddDate(1, 0, 0)
-	} else {
-		log.Printf("Warning: Unknown billing cycle '%s' for membership %d",
-			pr.SubscriptionMembership.HostedSubscription.BillingCycle, pr.SubscriptionMembershipID)
-		nextPaymentDate = baseDateForNext.AddDate(0, 1, 0)
+// BulkDeclinePaymentProofs declines several payment proofs with the same reason, each exactly as
+// DeclinePaymentProof would and in its own transaction.
+func (s *paymentService) BulkDeclinePaymentProofs(ctx context.Context, hostUserID uint, paymentRecordIDs []uint, reason string) []BulkItemResult {
+	ids := uniqueIDs(paymentRecordIDs)
+	results := make([]BulkItemResult, 0, len(ids))
+	for _, id := range ids {
+		result := BulkItemResult{ID: id}
+		if pr, err := s.DeclinePaymentProof(ctx, hostUserID, id, reason); err != nil {
+			result.Err = err
+		} else {
+			result.Status = string(pr.Status)
+		}
+		results = append(results, result)
 	}
-	nextPaymentDatePtr := &nextPaymentDate
+	return results
+}
 
-	err = s.membershipRepo.UpdatePaymentAndNextDueDate(ctx, pr.SubscriptionMembershipID, models.PaymentStatusPaid, nextPaymentDatePtr)
+// autoApprove approves a newly submitted payment record through the first of the host's enabled auto-approval
+// rules whose conditions it meets, and logs the rule along with the membership's billing before the approval so
+// the host can revert it. Proofs flagged by the slip or image checks are left for the host.
+func (s *paymentService) autoApprove(ctx context.Context, pr *models.PaymentRecord, membership *models.SubscriptionMembership) error {
+	if pr.BlocksAutoApproval() {
+		return nil
+	}
+	rules, err := s.autoApprovalRepo.ListRulesBySubscriptionID(ctx, membership.HostedSubscriptionID)
 	if err != nil {
-		log.Printf("CRITICAL: Approved PaymentRecord %d but failed to update SubscriptionMembership %d status/next_due_date: %v", pr.ID, pr.SubscriptionMembershipID, err)
+		return fmt.Errorf("listing auto-approval rules: %w", err)
+	}
+	var rule *models.AutoApprovalRule
+	for i := range rules {
+		if !rules[i].Enabled || !rules[i].HasConditions() {
+			continue
+		}
+		met, err := s.meetsAutoApprovalRule(ctx, pr, &rules[i])
+		if err != nil {
+			return err
+		}
+		if met {
+			rule = &rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		return nil
 	}
 
-	updatedPRFull, fetchErr := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
-	if fetchErr != nil {
-		return nil, fmt.Errorf("re-fetching payment record after approval: %w", fetchErr)
+	locked, err := s.membershipRepo.GetByIDForUpdate(ctx, membership.ID)
+	if err != nil {
+		return fmt.Errorf("locking membership %d: %w", membership.ID, err)
 	}
-	// Map using the same logic as GetPaymentRecordDetails
-	var memberName string
-	var memberAvatar *string
-	var subTitle string
-	if updatedPRFull.SubscriptionMembership.ID != 0 {
-		if updatedPRFull.SubscriptionMembership.User.ID != 0 {
-			memberName = updatedPRFull.SubscriptionMembership.User.FullName
-			memberAvatar = updatedPRFull.SubscriptionMembership.User.ProfilePictureURL
+	approval := &models.AutoApproval{
+		PaymentRecordID:          pr.ID,
+		SubscriptionMembershipID: membership.ID,
+		HostedSubscriptionID:     membership.HostedSubscriptionID,
+		RuleID:                   rule.ID,
+		RuleName:                 rule.Name,
+		RuleConditions:           rule.Conditions(),
+		AmountPaid:               pr.AmountPaid,
+		PreviousBalance:          locked.Balance,
+		PreviousNextPaymentDate:  locked.NextPaymentDate,
+		PreviousBilledThrough:    locked.BilledThrough,
+	}
+
+	approved, err := s.paymentRecordRepo.MarkAutoApproved(ctx, pr.ID, rule.ID)
+	if err != nil {
+		return fmt.Errorf("auto-approving payment record: %w", err)
+	}
+	if !approved {
+		return nil
+	}
+	pr.SubscriptionMembership = *membership
+	if err := s.applyApprovedPayment(ctx, pr); err != nil {
+		return err
+	}
+	latest, err := s.ledgerRepo.GetLatestByMembershipID(ctx, membership.ID)
+	if err != nil {
+		return fmt.Errorf("fetching latest ledger entry: %w", err)
+	}
+	approval.LastLedgerEntryID = latest.ID
+	if err := s.autoApprovalRepo.CreateApproval(ctx, approval); err != nil {
+		return fmt.Errorf("logging auto-approval: %w", err)
+	}
+
+	before := *pr
+	reviewedAt := time.Now().UTC()
+	pr.Status = models.PaymentRecordStatusApproved
+	pr.ReviewedAt = &reviewedAt
+	pr.AutoApprovalRuleID = &rule.ID
+	err = s.auditSvc.Record(ctx, AuditEntry{
+		Action:               models.AuditPaymentRecordAutoApprove,
+		EntityID:             pr.ID,
+		HostedSubscriptionID: &membership.HostedSubscriptionID,
+		Before:               &before,
+		After:                pr,
+	})
+	if err != nil {
+		return err
+	}
+
+	hostUserID := membership.HostedSubscription.HostUserID
+	event := events.New(events.PaymentProofApproved, hostUserID, membership.MemberUserID, hostUserID)
+	event.HostedSubscriptionID = membership.HostedSubscriptionID
+	event.MembershipID = membership.ID
+	event.PaymentRecordID = pr.ID
+	event.Status = string(models.PaymentRecordStatusApproved)
+	return s.publish(ctx, event)
+}
+
+// meetsAutoApprovalRule reports whether a submitted payment record meets every condition of an auto-approval rule.
+func (s *paymentService) meetsAutoApprovalRule(ctx context.Context, pr *models.PaymentRecord, rule *models.AutoApprovalRule) (bool, error) {
+	if rule.RequireExactAmount && models.RoundMoney(pr.AmountPaid) != models.RoundMoney(pr.AmountExpected) {
+		return false, nil
+	}
+	if rule.RequireVerifiedSlip && pr.SlipCheckStatus != models.SlipCheckVerified {
+		return false, nil
+	}
+	if rule.RequireUniqueReference {
+		reference := strings.TrimSpace(pr.TransactionReference)
+		if reference == "" {
+			return false, nil
+		}
+		others, err := s.paymentRecordRepo.CountOtherWithTransactionReference(ctx, pr.ID, reference)
+		if err != nil {
+			return false, fmt.Errorf("counting payment records with the same reference: %w", err)
 		}
-		if updatedPRFull.SubscriptionMembership.HostedSubscription.ID != 0 {
-			subTitle = updatedPRFull.SubscriptionMembership.HostedSubscription.SubscriptionTitle
+		if others > 0 {
+			return false, nil
 		}
 	}
-	response := &models.PaymentRecordResponse{
-		ID: updatedPRFull.ID, CreatedAt: updatedPRFull.CreatedAt, UpdatedAt: updatedPRFull.UpdatedAt, SubscriptionMembershipID: updatedPRFull.SubscriptionMembershipID, PaymentCycleIdentifier: updatedPRFull.PaymentCycleIdentifier,
-		AmountExpected: updatedPRFull.AmountExpected, AmountPaid: updatedPRFull.AmountPaid, PaymentMethod: updatedPRFull.PaymentMethod, TransactionReference: updatedPRFull.TransactionReference,
-		ProofImageURL: updatedPRFull.ProofImageURL, SubmittedAt: updatedPRFull.SubmittedAt, Status: updatedPRFull.Status, ReviewedByUserID: updatedPRFull.ReviewedByUserID, ReviewedAt: updatedPRFull.ReviewedAt,
-		MemberName: memberName, MemberProfilePictureURL: memberAvatar, SubscriptionTitle: subTitle,
+	if rule.MinOnTimeApprovals > 0 {
+		onTime, err := s.paymentRecordRepo.CountOnTimeApprovals(ctx, pr.SubscriptionMembershipID)
+		if err != nil {
+			return false, fmt.Errorf("counting on-time approvals: %w", err)
+		}
+		if onTime < int64(rule.MinOnTimeApprovals) {
+			return false, nil
+		}
 	}
-	return response, nil
+	return true, nil
 }
 
-// DeclinePaymentProof allows a host to decline a payment proof.
-func (s *paymentService) DeclinePaymentProof(ctx context.Context, hostUserID uint, paymentRecordID uint) (*models.PaymentRecordResponse, error) {
+// RevertAutoApproval lets the host take back an approval made by one of their auto-approval rules. The payment and
+// the cycle charges its approval posted are reversed on the membership's ledger and in the journal, the membership's
+// due date is restored, and the record goes back to awaiting the host's review. It is refused once anything else
+// has been posted to the membership's ledger since, as the approval can then no longer be undone on its own.
+func (s *paymentService) RevertAutoApproval(ctx context.Context, hostUserID uint, paymentRecordID uint) (*models.PaymentRecordResponse, error) {
 	pr, err := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -299,46 +562,590 @@ func (s *paymentService) DeclinePaymentProof(ctx context.Context, hostUserID uin
 		}
 		return nil, fmt.Errorf("fetching payment record: %w", err)
 	}
-
 	if pr.SubscriptionMembership.HostedSubscription.HostUserID != hostUserID {
 		return nil, ErrForbidden
 	}
-	if pr.Status != models.PaymentRecordStatusProofSubmitted {
-		return nil, ErrPaymentRecordNotModifiable
+	if pr.Status != models.PaymentRecordStatusApproved || pr.AutoApprovalRuleID == nil {
+		return nil, ErrPaymentNotAutoApproved
+	}
+	approval, err := s.autoApprovalRepo.GetApprovalByPaymentRecordID(ctx, pr.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotAutoApproved
+		}
+		return nil, fmt.Errorf("fetching auto-approval: %w", err)
+	}
+	if approval.RevertedAt != nil {
+		return nil, ErrPaymentNotAutoApproved
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		membership, err := s.membershipRepo.GetByIDForUpdate(ctx, pr.SubscriptionMembershipID)
+		if err != nil {
+			return fmt.Errorf("locking membership %d: %w", pr.SubscriptionMembershipID, err)
+		}
+		latest, err := s.ledgerRepo.GetLatestByMembershipID(ctx, membership.ID)
+		if err != nil {
+			return fmt.Errorf("fetching latest ledger entry: %w", err)
+		}
+		if latest.ID != approval.LastLedgerEntryID {
+			return ErrAutoApprovalNotRevertible
+		}
+
+		description := fmt.Sprintf("Reverted auto-approval of payment for %s", pr.PaymentCycleIdentifier)
+		reversal := &models.MembershipLedgerEntry{
+			SubscriptionMembershipID: membership.ID,
+			PaymentRecordID:          &pr.ID,
+			Kind:                     models.MembershipLedgerReversal,
+			Amount:                   models.RoundMoney(approval.PreviousBalance - membership.Balance),
+			BalanceAfter:             approval.PreviousBalance,
+			Description:              description,
+		}
+		if err := s.ledgerRepo.Create(ctx, reversal); err != nil {
+			return fmt.Errorf("recording reversal on ledger: %w", err)
+		}
+		if err := s.ledgerRepo.DeleteAllocationsByPaymentRecordID(ctx, pr.ID); err != nil {
+			return fmt.Errorf("removing cycle allocations: %w", err)
+		}
+		if err := s.accountingSvc.ReverseApproval(ctx, pr.ID, description); err != nil {
+			return err
+		}
+		membership.Balance = approval.PreviousBalance
+		membership.NextPaymentDate = approval.PreviousNextPaymentDate
+		membership.BilledThrough = approval.PreviousBilledThrough
+		membership.PaymentStatus = models.PaymentStatusProofSubmitted
+		if err := s.membershipRepo.UpdateBilling(ctx, membership); err != nil {
+			return fmt.Errorf("restoring membership billing: %w", err)
+		}
+
+		if err := s.paymentRecordRepo.MarkAutoApprovalReverted(ctx, pr.ID); err != nil {
+			return fmt.Errorf("reopening payment record: %w", err)
+		}
+		if err := s.autoApprovalRepo.MarkApprovalReverted(ctx, approval.ID, hostUserID, time.Now().UTC()); err != nil {
+			return fmt.Errorf("logging reverted auto-approval: %w", err)
+		}
+		reopened := *pr
+		reopened.Status = models.PaymentRecordStatusProofSubmitted
+		reopened.ReviewedAt = nil
+		reopened.ReviewedByUserID = nil
+		reopened.AutoApprovalRuleID = nil
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditPaymentRecordRevertAuto,
+			EntityID:             pr.ID,
+			HostedSubscriptionID: &pr.SubscriptionMembership.HostedSubscriptionID,
+			Before:               pr,
+			After:                &reopened,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.PaymentProofReverted, hostUserID, pr.SubscriptionMembership.MemberUserID, hostUserID)
+		event.HostedSubscriptionID = pr.SubscriptionMembership.HostedSubscriptionID
+		event.MembershipID = pr.SubscriptionMembershipID
+		event.PaymentRecordID = pr.ID
+		event.Status = string(models.PaymentRecordStatusProofSubmitted)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reopened, err := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
+	if err != nil {
+		return nil, fmt.Errorf("re-fetching payment record after reverting its auto-approval: %w", err)
+	}
+	return mapReviewedPaymentRecord(reopened), nil
+}
+
+// applyApprovedPayment credits an approved payment to the membership's ledger. The cycle being paid is charged
+// first if it has not been yet, then the due date advances once per cycle the balance fully covers.
+// Any credit left after a covered cycle is applied to the following one by charging it right away.
+// The share of the payment that went to each cycle is recorded as an allocation of the payment record.
+func (s *paymentService) applyApprovedPayment(ctx context.Context, pr *models.PaymentRecord) error {
+	membership, err := s.membershipRepo.GetByIDForUpdate(ctx, pr.SubscriptionMembershipID)
+	if err != nil {
+		return fmt.Errorf("locking membership %d: %w", pr.SubscriptionMembershipID, err)
+	}
+	billingCycle := pr.SubscriptionMembership.HostedSubscription.BillingCycle
+	cycleCost := models.RoundMoney(pr.AmountExpected / float64(max(pr.CycleCount, 1)))
+
+	dueDate := membership.JoinedDate
+	if membership.NextPaymentDate != nil && !membership.NextPaymentDate.IsZero() {
+		dueDate = *membership.NextPaymentDate
+	}
+	if membership.BilledThrough == nil || membership.BilledThrough.Before(dueDate) {
+		if err := s.postCharge(ctx, pr, membership, dueDate, cycleCost); err != nil {
+			return err
+		}
+	}
+	cycleOutstanding := membership.OutstandingAmount()
+
+	paid := models.RoundMoney(pr.AmountPaid)
+	membership.Balance = models.RoundMoney(membership.Balance + paid)
+	description := fmt.Sprintf("Payment for %s", pr.PaymentCycleIdentifier)
+	if pr.CycleCount > 1 {
+		description = fmt.Sprintf("Prepayment of %d cycles for %s", pr.CycleCount, pr.PaymentCycleIdentifier)
+	}
+	payment := &models.MembershipLedgerEntry{
+		SubscriptionMembershipID: membership.ID,
+		PaymentRecordID:          &pr.ID,
+		Kind:                     models.MembershipLedgerPayment,
+		Amount:                   paid,
+		BalanceAfter:             membership.Balance,
+		Description:              description,
+	}
+	if err := s.ledgerRepo.Create(ctx, payment); err != nil {
+		return fmt.Errorf("recording payment on ledger: %w", err)
+	}
+	if err := s.accountingSvc.PostPayment(ctx, journalPartyOf(pr), paid, time.Now().UTC(), &pr.ID, description); err != nil {
+		return err
+	}
+
+	unallocated := paid
+	membership.PaymentStatus = models.PaymentStatusDue
+	for covered := 0; covered < maxCyclesCoveredPerPayment; covered++ {
+		if share := min(unallocated, cycleOutstanding); share > 0 {
+			allocation := &models.PaymentCycleAllocation{
+				PaymentRecordID:          pr.ID,
+				SubscriptionMembershipID: membership.ID,
+				CycleDueDate:             dueDate,
+				Amount:                   share,
+				FullyPaid:                membership.Balance >= 0,
+			}
+			if err := s.ledgerRepo.CreateAllocation(ctx, allocation); err != nil {
+				return fmt.Errorf("recording allocation to cycle due %s: %w", dueDate.Format("2006-01-02"), err)
+			}
+			unallocated = models.RoundMoney(unallocated - share)
+		}
+		if membership.Balance < 0 {
+			break
+		}
+
+		nextDueDate := nextCycleDueDate(dueDate, billingCycle, membership.ID)
+		dueDate = nextDueDate
+		membership.NextPaymentDate = &nextDueDate
+		membership.PaymentStatus = models.PaymentStatusPaid
+		if membership.Balance == 0 || cycleCost <= 0 {
+			break
+		}
+		if err := s.postCharge(ctx, pr, membership, nextDueDate, cycleCost); err != nil {
+			return err
+		}
+		cycleOutstanding = cycleCost
+	}
+
+	if err := s.membershipRepo.UpdateBilling(ctx, membership); err != nil {
+		return fmt.Errorf("updating membership billing: %w", err)
+	}
+	return nil
+}
+
+// validatePrepayment checks that a payment covering several cycles pays for all of them, after the member's
+// credit. A single-cycle payment may fall short; the remainder stays outstanding on the ledger.
+func validatePrepayment(membership *models.SubscriptionMembership, cycleCount int, amountExpected float64, amountPaid float64) error {
+	if cycleCount <= 1 {
+		return nil
+	}
+	if models.RoundMoney(amountPaid+membership.CreditBalance()) < amountExpected {
+		return ErrPrepaymentAmountTooLow
+	}
+	return nil
+}
+
+// postCharge bills the cycle due on dueDate to the membership's ledger.
+// The charge is journaled against the payment record whose approval billed it.
+func (s *paymentService) postCharge(ctx context.Context, pr *models.PaymentRecord, membership *models.SubscriptionMembership, dueDate time.Time, amount float64) error {
+	membership.Balance = models.RoundMoney(membership.Balance - amount)
+	membership.BilledThrough = &dueDate
+	charge := &models.MembershipLedgerEntry{
+		SubscriptionMembershipID: membership.ID,
+		Kind:                     models.MembershipLedgerCharge,
+		Amount:                   -amount,
+		BalanceAfter:             membership.Balance,
+		CycleDueDate:             &dueDate,
+		Description:              fmt.Sprintf("Cycle due %s", dueDate.Format("2006-01-02")),
+	}
+	if err := s.ledgerRepo.Create(ctx, charge); err != nil {
+		return fmt.Errorf("recording charge on ledger: %w", err)
+	}
+	return s.accountingSvc.PostCycleCharge(ctx, journalPartyOf(pr), amount, time.Now().UTC(), &pr.ID, charge.Description)
+}
+
+// nextCycleDueDate returns the due date of the cycle after the one due on dueDate. An overdue cycle is
+// counted from today, so a late payment does not leave the member owing for the time they were behind.
+func nextCycleDueDate(dueDate time.Time, billingCycle models.BillingCycleType, membershipID uint) time.Time {
+	base := dueDate
+	if now := time.Now().UTC(); base.Before(now) {
+		base = now
+	}
+	switch billingCycle {
+	case models.BillingMonthly:
+		return base.AddDate(0, 1, 0)
+	case models.BillingAnnually:
+		return base.AddDate(1, 0, 0)
+	default:
+		log.Printf("Warning: Unknown billing cycle '%s' for membership %d", billingCycle, membershipID)
+		return base.AddDate(0, 1, 0)
+	}
+}
+
+// ListMembershipLedger retrieves a page of a membership's ledger for its member or the subscription's host.
+func (s *paymentService) ListMembershipLedger(ctx context.Context, accessorUserID uint, membershipID uint, limit int, offset int) ([]models.MembershipLedgerEntry, error) {
+	membership, err := s.membershipRepo.GetByID(ctx, membershipID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMembershipNotFound
+		}
+		return nil, fmt.Errorf("fetching membership: %w", err)
 	}
+	if membership.MemberUserID != accessorUserID && membership.HostedSubscription.HostUserID != accessorUserID {
+		return nil, ErrForbidden
+	}
+
+	if limit <= 0 {
+		limit = defaultLedgerPageSize
+	}
+	limit = min(limit, maxLedgerPageSize)
+	offset = max(offset, 0)
+
+	entries, err := s.ledgerRepo.ListByMembershipID(ctx, membershipID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing membership ledger: %w", err)
+	}
+	return entries, nil
+}
 
-	err = s.paymentRecordRepo.UpdateStatus(ctx, paymentRecordID, models.PaymentRecordStatusDeclined, &hostUserID)
+// DeclinePaymentProof allows a host to decline a payment proof, optionally explaining why.
+// The reason is also posted to the record's message thread.
+func (s *paymentService) DeclinePaymentProof(ctx context.Context, hostUserID uint, paymentRecordID uint, reason string) (*models.PaymentRecordResponse, error) {
+	pr, err := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
 	if err != nil {
-		return nil, fmt.Errorf("updating payment record status: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentRecordNotFound
+		}
+		return nil, fmt.Errorf("fetching payment record: %w", err)
+	}
+
+	if pr.SubscriptionMembership.HostedSubscription.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+	if !isAwaitingReview(pr.Status) {
+		return nil, ErrPaymentRecordNotModifiable
 	}
+	reason = strings.TrimSpace(reason)
 
-	err = s.membershipRepo.UpdatePaymentStatus(ctx, pr.SubscriptionMembershipID, models.PaymentStatusDue)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updated, err := s.paymentRecordRepo.MarkDeclined(ctx, paymentRecordID, awaitingReviewStatuses, hostUserID, reason)
+		if err != nil {
+			return fmt.Errorf("updating payment record status: %w", err)
+		}
+		if !updated {
+			return ErrPaymentRecordAlreadyReviewed
+		}
+		if reason != "" {
+			message := &models.PaymentRecordMessage{
+				PaymentRecordID: pr.ID,
+				AuthorUserID:    hostUserID,
+				Kind:            models.PaymentRecordMessageDecline,
+				Body:            reason,
+			}
+			if err := s.messageRepo.Create(ctx, message); err != nil {
+				return fmt.Errorf("posting decline reason: %w", err)
+			}
+		}
+		if err := s.membershipRepo.UpdatePaymentStatus(ctx, pr.SubscriptionMembershipID, models.PaymentStatusDue); err != nil {
+			return fmt.Errorf("updating membership payment status: %w", err)
+		}
+		if err := s.recordReview(ctx, models.AuditPaymentRecordDecline, pr, models.PaymentRecordStatusDeclined, hostUserID, reason); err != nil {
+			return err
+		}
+
+		event := events.New(events.PaymentProofDeclined, hostUserID, pr.SubscriptionMembership.MemberUserID, hostUserID)
+		event.HostedSubscriptionID = pr.SubscriptionMembership.HostedSubscriptionID
+		event.MembershipID = pr.SubscriptionMembershipID
+		event.PaymentRecordID = pr.ID
+		event.Status = string(models.PaymentRecordStatusDeclined)
+		return s.publish(ctx, event)
+	})
 	if err != nil {
-		log.Printf("CRITICAL: Declined PaymentRecord %d but failed to update SubscriptionMembership %d status: %v", pr.ID, pr.SubscriptionMembershipID, err)
+		return nil, err
 	}
+
 	updatedPRFull, fetchErr := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
 	if fetchErr != nil {
 		return nil, fmt.Errorf("re-fetching payment record after decline: %w", fetchErr)
 	}
-	var memberName string
-	var memberAvatar *string
-	var subTitle string
-	if updatedPRFull.SubscriptionMembership.ID != 0 {
-		if updatedPRFull.SubscriptionMembership.User.ID != 0 {
-			memberName = updatedPRFull.SubscriptionMembership.User.FullName
-			memberAvatar = updatedPRFull.SubscriptionMembership.User.ProfilePictureURL
+	return mapReviewedPaymentRecord(updatedPRFull), nil
+}
+
+// DisputePaymentRecord lets a member contest a declined payment with a comment and additional evidence.
+// The record goes back into the host's review queue.
+func (s *paymentService) DisputePaymentRecord(ctx context.Context, memberUserID uint, paymentRecordID uint, req *models.DisputePaymentRecordRequest) (*models.PaymentRecordResponse, error) {
+	pr, err := s.getMemberPaymentRecord(ctx, memberUserID, paymentRecordID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status != models.PaymentRecordStatusDeclined {
+		return nil, ErrPaymentRecordNotDisputable
+	}
+
+	disputedAt := time.Now().UTC()
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		updated, err := s.paymentRecordRepo.MarkDisputed(ctx, pr.ID, disputedAt)
+		if err != nil {
+			return fmt.Errorf("marking payment record as disputed: %w", err)
+		}
+		if !updated {
+			return ErrPaymentRecordNotDisputable
+		}
+		message := &models.PaymentRecordMessage{
+			PaymentRecordID: pr.ID,
+			AuthorUserID:    memberUserID,
+			Kind:            models.PaymentRecordMessageDispute,
+			Body:            strings.TrimSpace(req.Comment),
+			AttachmentURLs:  req.EvidenceURLs,
 		}
-		if updatedPRFull.SubscriptionMembership.HostedSubscription.ID != 0 {
-			subTitle = updatedPRFull.SubscriptionMembership.HostedSubscription.SubscriptionTitle
+		if err := s.messageRepo.Create(ctx, message); err != nil {
+			return fmt.Errorf("posting dispute comment: %w", err)
+		}
+
+		disputed := *pr
+		disputed.Status = models.PaymentRecordStatusDisputed
+		disputed.DisputedAt = &disputedAt
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditPaymentRecordDispute,
+			EntityID:             pr.ID,
+			HostedSubscriptionID: &pr.SubscriptionMembership.HostedSubscriptionID,
+			Before:               pr,
+			After:                &disputed,
+		})
+		if err != nil {
+			return err
 		}
+
+		event := events.New(events.PaymentProofDisputed, memberUserID, pr.SubscriptionMembership.HostedSubscription.HostUserID, memberUserID)
+		event.HostedSubscriptionID = pr.SubscriptionMembership.HostedSubscriptionID
+		event.MembershipID = pr.SubscriptionMembershipID
+		event.PaymentRecordID = pr.ID
+		event.Status = string(models.PaymentRecordStatusDisputed)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
 	}
-	response := &models.PaymentRecordResponse{
-		ID: updatedPRFull.ID, CreatedAt: updatedPRFull.CreatedAt, UpdatedAt: updatedPRFull.UpdatedAt, SubscriptionMembershipID: updatedPRFull.SubscriptionMembershipID, PaymentCycleIdentifier: updatedPRFull.PaymentCycleIdentifier,
-		AmountExpected: updatedPRFull.AmountExpected, AmountPaid: updatedPRFull.AmountPaid, PaymentMethod: updatedPRFull.PaymentMethod, TransactionReference: updatedPRFull.TransactionReference,
-		ProofImageURL: updatedPRFull.ProofImageURL, SubmittedAt: updatedPRFull.SubmittedAt, Status: updatedPRFull.Status, ReviewedByUserID: updatedPRFull.ReviewedByUserID, ReviewedAt: updatedPRFull.ReviewedAt,
-		MemberName: memberName, MemberProfilePictureURL: memberAvatar, SubscriptionTitle: subTitle,
+
+	updated, err := s.paymentRecordRepo.GetByID(ctx, pr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("re-fetching payment record after dispute: %w", err)
+	}
+	return mapReviewedPaymentRecord(updated), nil
+}
+
+// ResubmitPaymentProof submits a corrected proof for the cycle of a declined or disputed record.
+// The new record supersedes the old one, whose message thread carries over.
+func (s *paymentService) ResubmitPaymentProof(ctx context.Context, memberUserID uint, paymentRecordID uint, req *models.ResubmitPaymentProofRequest) (*models.PaymentRecord, error) {
+	previous, err := s.getMemberPaymentRecord(ctx, memberUserID, paymentRecordID)
+	if err != nil {
+		return nil, err
+	}
+	if previous.Status != models.PaymentRecordStatusDeclined && previous.Status != models.PaymentRecordStatusDisputed {
+		return nil, ErrPaymentRecordNotResubmittable
+	}
+
+	membership := previous.SubscriptionMembership
+	if err := validatePrepayment(&membership, previous.CycleCount, previous.AmountExpected, req.AmountPaid); err != nil {
+		return nil, err
+	}
+	paymentRecord := &models.PaymentRecord{
+		SubscriptionMembershipID: previous.SubscriptionMembershipID,
+		PaymentCycleIdentifier:   previous.PaymentCycleIdentifier,
+		CycleCount:               previous.CycleCount,
+		AmountExpected:           previous.AmountExpected,
+		AmountPaid:               req.AmountPaid,
+		ProofImageURL:            req.ProofImageURL,
+		PaymentMethod:            req.PaymentMethod,
+		TransactionReference:     req.TransactionReference,
+		SubmittedAt:              time.Now().UTC(),
+		Status:                   models.PaymentRecordStatusProofSubmitted,
+		SupersedesRecordID:       &previous.ID,
+	}
+	s.inspectProof(ctx, paymentRecord)
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.flagReusedProof(ctx, paymentRecord, previous.ID); err != nil {
+			return err
+		}
+		if err := s.paymentRecordRepo.Create(ctx, paymentRecord); err != nil {
+			return fmt.Errorf("creating payment record: %w", err)
+		}
+		superseded, err := s.paymentRecordRepo.MarkSuperseded(ctx, previous.ID, paymentRecord.ID)
+		if err != nil {
+			return fmt.Errorf("marking payment record %d as superseded: %w", previous.ID, err)
+		}
+		if !superseded {
+			return ErrPaymentRecordNotResubmittable
+		}
+		if err := s.membershipRepo.UpdatePaymentStatus(ctx, membership.ID, models.PaymentStatusProofSubmitted); err != nil {
+			return fmt.Errorf("updating membership payment status: %w", err)
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditPaymentRecordResubmit,
+			EntityID:             paymentRecord.ID,
+			HostedSubscriptionID: &membership.HostedSubscriptionID,
+			After:                paymentRecord,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.PaymentProofSubmitted, memberUserID, membership.HostedSubscription.HostUserID, memberUserID)
+		event.HostedSubscriptionID = membership.HostedSubscriptionID
+		event.MembershipID = membership.ID
+		event.PaymentRecordID = paymentRecord.ID
+		event.Status = string(paymentRecord.Status)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paymentRecord, nil
+}
+
+// ListPaymentRecordMessages retrieves the message thread of a payment record, oldest first.
+// The thread includes the messages of the records it superseded, so a resubmission keeps the conversation.
+func (s *paymentService) ListPaymentRecordMessages(ctx context.Context, accessorUserID uint, paymentRecordID uint) ([]models.PaymentRecordMessageResponse, error) {
+	pr, err := s.getParticipantPaymentRecord(ctx, accessorUserID, paymentRecordID)
+	if err != nil {
+		return nil, err
+	}
+
+	recordIDs := []uint{pr.ID}
+	for previousID := pr.SupersedesRecordID; previousID != nil && len(recordIDs) < maxSupersededThreadDepth; {
+		previous, err := s.paymentRecordRepo.GetByID(ctx, *previousID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, fmt.Errorf("fetching superseded payment record %d: %w", *previousID, err)
+		}
+		recordIDs = append(recordIDs, previous.ID)
+		previousID = previous.SupersedesRecordID
+	}
+
+	messages, err := s.messageRepo.ListByPaymentRecordIDs(ctx, recordIDs)
+	if err != nil {
+		return nil, fmt.Errorf("listing payment record messages: %w", err)
+	}
+	hostUserID := pr.SubscriptionMembership.HostedSubscription.HostUserID
+	responses := make([]models.PaymentRecordMessageResponse, len(messages))
+	for i := range messages {
+		responses[i] = mapPaymentRecordMessage(&messages[i], hostUserID)
+	}
+	return responses, nil
+}
+
+// PostPaymentRecordMessage adds a message from the host or the member to a payment record's thread.
+func (s *paymentService) PostPaymentRecordMessage(ctx context.Context, authorUserID uint, paymentRecordID uint, req *models.CreatePaymentRecordMessageRequest) (*models.PaymentRecordMessageResponse, error) {
+	pr, err := s.getParticipantPaymentRecord(ctx, authorUserID, paymentRecordID)
+	if err != nil {
+		return nil, err
+	}
+
+	membership := pr.SubscriptionMembership
+	hostUserID := membership.HostedSubscription.HostUserID
+	message := &models.PaymentRecordMessage{
+		PaymentRecordID: pr.ID,
+		AuthorUserID:    authorUserID,
+		Author:          membership.User,
+		Kind:            models.PaymentRecordMessageComment,
+		Body:            strings.TrimSpace(req.Body),
+		AttachmentURLs:  req.AttachmentURLs,
+	}
+	recipientUserID := hostUserID
+	if authorUserID == hostUserID {
+		message.Author = membership.HostedSubscription.User
+		recipientUserID = membership.MemberUserID
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.messageRepo.Create(ctx, message); err != nil {
+			return fmt.Errorf("creating payment record message: %w", err)
+		}
+		err := s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditPaymentRecordPostMessage,
+			EntityID:             pr.ID,
+			HostedSubscriptionID: &membership.HostedSubscriptionID,
+			After:                message,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.PaymentMessagePosted, authorUserID, recipientUserID)
+		event.HostedSubscriptionID = membership.HostedSubscriptionID
+		event.MembershipID = membership.ID
+		event.PaymentRecordID = pr.ID
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := mapPaymentRecordMessage(message, hostUserID)
+	return &response, nil
+}
+
+func (s *paymentService) getMemberPaymentRecord(ctx context.Context, memberUserID uint, paymentRecordID uint) (*models.PaymentRecord, error) {
+	pr, err := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentRecordNotFound
+		}
+		return nil, fmt.Errorf("fetching payment record: %w", err)
+	}
+	if pr.SubscriptionMembership.MemberUserID != memberUserID {
+		return nil, ErrForbidden
+	}
+	return pr, nil
+}
+
+func (s *paymentService) getParticipantPaymentRecord(ctx context.Context, userID uint, paymentRecordID uint) (*models.PaymentRecord, error) {
+	pr, err := s.paymentRecordRepo.GetByID(ctx, paymentRecordID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentRecordNotFound
+		}
+		return nil, fmt.Errorf("fetching payment record: %w", err)
+	}
+	if pr.SubscriptionMembership.MemberUserID != userID && pr.SubscriptionMembership.HostedSubscription.HostUserID != userID {
+		return nil, ErrForbidden
+	}
+	return pr, nil
+}
+
+// isAwaitingReview reports whether a host can still approve or decline a record in this status.
+func isAwaitingReview(status models.PaymentRecordStatus) bool {
+	return slices.Contains(awaitingReviewStatuses, status)
+}
+
+func mapPaymentRecordMessage(message *models.PaymentRecordMessage, hostUserID uint) models.PaymentRecordMessageResponse {
+	attachments := []string(message.AttachmentURLs)
+	if attachments == nil {
+		attachments = []string{}
+	}
+	return models.PaymentRecordMessageResponse{
+		ID:                      message.ID,
+		CreatedAt:               message.CreatedAt,
+		PaymentRecordID:         message.PaymentRecordID,
+		AuthorUserID:            message.AuthorUserID,
+		AuthorName:              message.Author.FullName,
+		AuthorProfilePictureURL: message.Author.ProfilePictureURL,
+		AuthorIsHost:            message.AuthorUserID == hostUserID,
+		Kind:                    message.Kind,
+		Body:                    message.Body,
+		AttachmentURLs:          attachments,
 	}
-	return response, nil
 }
 
 // ListPaymentRecordsForMembership retrieves payment history for a member's specific subscription.
@@ -355,3 +1162,84 @@ func (s *paymentService) ListPaymentRecordsForMembership(ctx context.Context, me
 	}
 	return s.paymentRecordRepo.ListBySubscriptionMembershipID(ctx, membershipID)
 }
+
+// recordReview adds the host's review of a payment record to the audit log.
+func (s *paymentService) recordReview(ctx context.Context, action models.AuditAction, pr *models.PaymentRecord, status models.PaymentRecordStatus, hostUserID uint, declineReason string) error {
+	reviewedAt := time.Now().UTC()
+	reviewed := *pr
+	reviewed.Status = status
+	reviewed.DeclineReason = declineReason
+	reviewed.ReviewedByUserID = &hostUserID
+	reviewed.ReviewedAt = &reviewedAt
+	return s.auditSvc.Record(ctx, AuditEntry{
+		Action:               action,
+		EntityID:             pr.ID,
+		HostedSubscriptionID: &pr.SubscriptionMembership.HostedSubscriptionID,
+		Before:               pr,
+		After:                &reviewed,
+	})
+}
+
+// mapReviewedPaymentRecord maps a freshly re-fetched payment record to its response DTO.
+func mapReviewedPaymentRecord(pr *models.PaymentRecord) *models.PaymentRecordResponse {
+	var memberName string
+	var memberAvatar *string
+	var subTitle string
+	if pr.SubscriptionMembership.ID != 0 {
+		if pr.SubscriptionMembership.User.ID != 0 {
+			memberName = pr.SubscriptionMembership.User.FullName
+			memberAvatar = pr.SubscriptionMembership.User.ProfilePictureURL
+		}
+		if pr.SubscriptionMembership.HostedSubscription.ID != 0 {
+			subTitle = pr.SubscriptionMembership.HostedSubscription.SubscriptionTitle
+		}
+	}
+	return newPaymentRecordResponse(pr, memberName, memberAvatar, subTitle)
+}
+
+func newPaymentRecordResponse(pr *models.PaymentRecord, memberName string, memberAvatar *string, subTitle string) *models.PaymentRecordResponse {
+	allocations := pr.Allocations
+	if allocations == nil {
+		allocations = []models.PaymentCycleAllocation{}
+	}
+	return &models.PaymentRecordResponse{
+		ID:                       pr.ID,
+		CreatedAt:                pr.CreatedAt,
+		UpdatedAt:                pr.UpdatedAt,
+		SubscriptionMembershipID: pr.SubscriptionMembershipID,
+		PaymentCycleIdentifier:   pr.PaymentCycleIdentifier,
+		CycleCount:               pr.CycleCount,
+		AmountExpected:           pr.AmountExpected,
+		AmountPaid:               pr.AmountPaid,
+		PaymentMethod:            pr.PaymentMethod,
+		TransactionReference:     pr.TransactionReference,
+		ProofImageURL:            pr.ProofImageURL,
+		SubmittedAt:              pr.SubmittedAt,
+		Status:                   pr.Status,
+		ReviewedByUserID:         pr.ReviewedByUserID,
+		ReviewedAt:               pr.ReviewedAt,
+		DeclineReason:            pr.DeclineReason,
+		DisputedAt:               pr.DisputedAt,
+		SupersedesRecordID:       pr.SupersedesRecordID,
+		SupersededByRecordID:     pr.SupersededByRecordID,
+		SlipCheckStatus:          pr.SlipCheckStatus,
+		SlipSendingBankCode:      pr.SlipSendingBankCode,
+		SlipTransactionRef:       pr.SlipTransactionRef,
+		SlipDuplicateOfRecordID:  pr.SlipDuplicateOfRecordID,
+		ProofImageReused:         pr.SimilarProofRecordID != nil,
+		SimilarProofRecordID:     pr.SimilarProofRecordID,
+		AutoApprovalRuleID:       pr.AutoApprovalRuleID,
+		Allocations:              allocations,
+		MemberName:               memberName,
+		MemberProfilePictureURL:  memberAvatar,
+		SubscriptionTitle:        subTitle,
+	}
+}
+
+// publish records a domain event in the outbox as part of the caller's transaction.
+func (s *paymentService) publish(ctx context.Context, event events.Event) error {
+	if err := s.eventPublisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("recording %s event: %w", event.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/promptpay"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Custom errors for PromptPayService
+var (
+	ErrInvalidPromptPayID     = errors.New("invalid PromptPay ID: use a 10-digit mobile number, a 13-digit national or tax ID, or a 15-digit e-wallet ID")
+	ErrPromptPayNotConfigured = errors.New("the host has not set up PromptPay for this subscription")
+	ErrNoAmountDue            = errors.New("nothing is due on this membership")
+)
+
+// PromptPayService defines the interface for PromptPay settings and per-member payment QR codes.
+type PromptPayService interface {
+	GetSettings(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.PromptPaySettingsResponse, error)
+	UpdateSettings(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdatePromptPayRequest) (*models.PromptPaySettingsResponse, error)
+	GetMembershipPaymentQR(ctx context.Context, userID uint, membershipID uint) (*models.PaymentQRResponse, error)
+}
+
+type promptPayService struct {
+	hostedSubRepo  repositories.HostedSubscriptionRepository
+	membershipRepo repositories.SubscriptionMembershipRepository
+	transactor     repositories.Transactor
+	auditSvc       AuditService
+}
+
+// NewPromptPayService creates a new PromptPayService.
+func NewPromptPayService(
+	hostedSubRepo repositories.HostedSubscriptionRepository,
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	transactor repositories.Transactor,
+	auditSvc AuditService,
+) PromptPayService {
+	return &promptPayService{hostedSubRepo: hostedSubRepo, membershipRepo: membershipRepo, transactor: transactor, auditSvc: auditSvc}
+}
+
+// GetSettings returns the PromptPay settings of a hosted subscription owned by the host.
+func (s *promptPayService) GetSettings(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.PromptPaySettingsResponse, error) {
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return promptPaySettings(hostedSub), nil
+}
+
+// UpdateSettings sets or clears the PromptPay ID of a hosted subscription owned by the host.
+func (s *promptPayService) UpdateSettings(ctx context.Context, hostUserID uint, hostedSubscriptionID uint, req *models.UpdatePromptPayRequest) (*models.PromptPaySettingsResponse, error) {
+	hostedSub, err := s.getOwnedSubscription(ctx, hostUserID, hostedSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var promptPayID string
+	if req.PromptPayID != "" {
+		if promptPayID, err = promptpay.NormalizeID(req.PromptPayID); err != nil {
+			return nil, ErrInvalidPromptPayID
+		}
+	}
+
+	before := promptPaySettings(hostedSub)
+	hostedSub.PromptPayID = promptPayID
+	after := promptPaySettings(hostedSub)
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.hostedSubRepo.UpdatePromptPayID(ctx, hostedSub.ID, promptPayID); err != nil {
+			return fmt.Errorf("saving PromptPay ID: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditPromptPayUpdate,
+			EntityID:             hostedSub.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               before,
+			After:                after,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// GetMembershipPaymentQR builds a dynamic PromptPay payload for what the member of a membership owes on their
+// due cycle: any outstanding balance, or otherwise their share less any credit. The payload carries a reference
+// made of the membership and the due date so the host can match the transfer to the cycle.
+func (s *promptPayService) GetMembershipPaymentQR(ctx context.Context, userID uint, membershipID uint) (*models.PaymentQRResponse, error) {
+	membership, err := s.membershipRepo.GetByID(ctx, membershipID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMembershipNotFound
+		}
+		return nil, fmt.Errorf("fetching membership: %w", err)
+	}
+	hostedSub := &membership.HostedSubscription
+	if membership.MemberUserID != userID && hostedSub.HostUserID != userID {
+		return nil, ErrForbidden
+	}
+	if hostedSub.PromptPayID == "" {
+		return nil, ErrPromptPayNotConfigured
+	}
+
+	return membershipPaymentQR(membership)
+}
+
+// membershipAmountDue is what the member owes on their due cycle: any outstanding balance, or otherwise their
+// share less any credit. It is zero when the credit covers the cycle.
+func membershipAmountDue(membership *models.SubscriptionMembership) float64 {
+	if amount := membership.OutstandingAmount(); amount > 0 {
+		return amount
+	}
+	return max(models.RoundMoney(costPerSlot(&membership.HostedSubscription)-membership.CreditBalance()), 0)
+}
+
+// membershipPaymentQR builds the PromptPay payload for a membership's amount due. The membership must be loaded
+// with its hosted subscription, whose PromptPay ID is set.
+func membershipPaymentQR(membership *models.SubscriptionMembership) (*models.PaymentQRResponse, error) {
+	amount := membershipAmountDue(membership)
+	if amount <= 0 {
+		return nil, ErrNoAmountDue
+	}
+
+	reference := fmt.Sprintf("HUB%d", membership.ID)
+	if membership.NextPaymentDate != nil {
+		reference += "-" + membership.NextPaymentDate.Format("20060102")
+	}
+	payload, err := promptpay.Payload(membership.HostedSubscription.PromptPayID, amount, reference)
+	if err != nil {
+		return nil, fmt.Errorf("building PromptPay payload for membership %d: %w", membership.ID, err)
+	}
+	return &models.PaymentQRResponse{
+		SubscriptionMembershipID: membership.ID,
+		Amount:                   amount,
+		DueDate:                  membership.NextPaymentDate,
+		Reference:                reference,
+		Payload:                  payload,
+	}, nil
+}
+
+func (s *promptPayService) getOwnedSubscription(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) (*models.HostedSubscription, error) {
+	hostedSub, err := s.hostedSubRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+	return hostedSub, nil
+}
+
+func promptPaySettings(hostedSub *models.HostedSubscription) *models.PromptPaySettingsResponse {
+	return &models.PromptPaySettingsResponse{
+		HostedSubscriptionID: hostedSub.ID,
+		PromptPayID:          hostedSub.PromptPayID,
+		Enabled:              hostedSub.PromptPayID != "",
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // Registers the GIF decoder for proof images
+	_ "image/jpeg" // Registers the JPEG decoder for proof images
+	_ "image/png"  // Registers the PNG decoder for proof images
+
+	"github.com/xNatthapol/hubster/internal/phash"
+	"github.com/xNatthapol/hubster/internal/promptpay"
+	"github.com/xNatthapol/hubster/internal/qrcode"
+	"github.com/xNatthapol/hubster/internal/utils"
+)
+
+const (
+	// maxProofImageBytes bounds the size of a proof image downloaded for inspection.
+	maxProofImageBytes = 10 << 20
+	// maxProofImagePixels bounds the decoded size of a proof image, so a small file cannot expand into a huge bitmap.
+	maxProofImagePixels = 25_000_000
+)
+
+// ErrProofImageTooLarge is returned when a proof image is too large to inspect.
+var ErrProofImageTooLarge = errors.New("proof image is too large to inspect")
+
+// ProofImageInspection is what could be learned from a payment proof image.
+type ProofImageInspection struct {
+	Slip           *promptpay.Slip // Nil when the image holds no bank slip QR code
+	PerceptualHash uint64
+}
+
+// ProofImageInspector downloads a payment proof image once and inspects it: it reads the verification QR code
+// Thai banks print on transfer slips and fingerprints the picture so later re-uploads of it can be recognised.
+type ProofImageInspector interface {
+	// Inspect returns an error only when the image could not be downloaded or decoded.
+	Inspect(ctx context.Context, imageURL string) (*ProofImageInspection, error)
+}
+
+type uploadedProofImageInspector struct {
+	uploader *utils.GCSUploader
+}
+
+// NewProofImageInspector creates a ProofImageInspector for images uploaded through the upload endpoint.
+func NewProofImageInspector(uploader *utils.GCSUploader) ProofImageInspector {
+	return &uploadedProofImageInspector{uploader: uploader}
+}
+
+// Inspect downloads an uploaded proof image from storage, hashes it and decodes the slip QR code in it.
+func (i *uploadedProofImageInspector) Inspect(ctx context.Context, imageURL string) (*ProofImageInspection, error) {
+	if i.uploader == nil {
+		return nil, ErrGCSConfigMissing
+	}
+	data, err := i.uploader.ReadFile(ctx, imageURL, maxProofImageBytes)
+	if err != nil {
+		if errors.Is(err, utils.ErrObjectTooLarge) {
+			return nil, ErrProofImageTooLarge
+		}
+		return nil, fmt.Errorf("downloading proof image: %w", err)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading proof image header: %w", err)
+	}
+	if config.Width*config.Height > maxProofImagePixels {
+		return nil, ErrProofImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding proof image: %w", err)
+	}
+
+	inspection := &ProofImageInspection{PerceptualHash: phash.Compute(img)}
+	content, err := qrcode.Decode(img)
+	if err != nil {
+		if errors.Is(err, qrcode.ErrNotFound) {
+			return inspection, nil
+		}
+		return nil, fmt.Errorf("reading slip QR code: %w", err)
+	}
+	slip, err := promptpay.ParseSlip(string(content))
+	if err != nil {
+		if errors.Is(err, promptpay.ErrNotASlip) {
+			return inspection, nil
+		}
+		return nil, err
+	}
+	inspection.Slip = slip
+	return inspection, nil
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/push"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	pushMaxAttempts    = 3
+	pushInitialBackoff = 500 * time.Millisecond
+)
+
+var (
+	ErrDeviceNotFound = errors.New("device not found")
+)
+
+// PushService defines the interface for device registration and push delivery.
+type PushService interface {
+	RegisterDevice(ctx context.Context, userID uint, req *models.RegisterDeviceRequest) (*models.DeviceToken, error)
+	ListDevices(ctx context.Context, userID uint) ([]models.DeviceToken, error)
+	UnregisterDevice(ctx context.Context, userID uint, deviceID uint) error
+	SendToUser(ctx context.Context, userID uint, msg push.Message) error
+}
+
+type pushService struct {
+	deviceRepo repositories.DeviceTokenRepository
+	dispatcher push.Dispatcher
+	transactor repositories.Transactor
+	auditSvc   AuditService
+}
+
+// NewPushService creates a new PushService. A nil dispatcher disables delivery but still allows device registration.
+func NewPushService(deviceRepo repositories.DeviceTokenRepository, dispatcher push.Dispatcher, transactor repositories.Transactor, auditSvc AuditService) PushService {
+	if dispatcher == nil {
+		log.Println("WARNING: PushService created without a dispatcher. Push notifications will not be delivered.")
+	}
+	return &pushService{deviceRepo: deviceRepo, dispatcher: dispatcher, transactor: transactor, auditSvc: auditSvc}
+}
+
+// RegisterDevice registers or refreshes a device token for the user.
+func (s *pushService) RegisterDevice(ctx context.Context, userID uint, req *models.RegisterDeviceRequest) (*models.DeviceToken, error) {
+	provider := req.Provider
+	if provider == "" {
+		provider = models.PushProviderFCM
+	}
+
+	device := &models.DeviceToken{
+		UserID:     userID,
+		Token:      req.Token,
+		Platform:   req.Platform,
+		Provider:   provider,
+		LastSeenAt: time.Now().UTC(),
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.deviceRepo.Upsert(ctx, device); err != nil {
+			return fmt.Errorf("registering device token: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{Action: models.AuditDeviceRegister, EntityID: device.ID, After: device})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// ListDevices retrieves the devices registered by the user.
+func (s *pushService) ListDevices(ctx context.Context, userID uint) ([]models.DeviceToken, error) {
+	devices, err := s.deviceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing devices: %w", err)
+	}
+	return devices, nil
+}
+
+// UnregisterDevice removes one of the user's devices.
+func (s *pushService) UnregisterDevice(ctx context.Context, userID uint, deviceID uint) error {
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDeviceNotFound
+		}
+		return fmt.Errorf("fetching device: %w", err)
+	}
+	if device.UserID != userID {
+		return ErrForbidden
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.deviceRepo.Delete(ctx, deviceID); err != nil {
+			return err
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{Action: models.AuditDeviceUnregister, EntityID: device.ID, Before: device})
+	})
+}
+
+// SendToUser pushes a message to every device of the user, retrying transient failures and removing tokens the
+// provider reports as dead. Devices whose provider is not configured are skipped. It fails only if the user has
+// devices and none of them could be reached, so that retrying the delivery does not repeat it on devices that
+// already got the message.
+func (s *pushService) SendToUser(ctx context.Context, userID uint, msg push.Message) error {
+	if s.dispatcher == nil {
+		return nil
+	}
+
+	devices, err := s.deviceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("listing devices for user %d: %w", userID, err)
+	}
+
+	delivered := 0
+	var sendErrs []error
+	for _, device := range devices {
+		target := push.Target{Token: device.Token, Platform: device.Platform, Provider: device.Provider}
+		err := s.sendWithRetry(ctx, target, msg)
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, push.ErrProviderNotConfigured):
+			log.Printf("INFO: Skipping push token %d of user %d: %v", device.ID, userID, err)
+		case errors.Is(err, push.ErrInvalidToken):
+			log.Printf("INFO: Removing dead %s push token %d of user %d: %v", device.Provider, device.ID, userID, err)
+			if delErr := s.deviceRepo.DeleteByToken(ctx, device.Token); delErr != nil {
+				log.Printf("ERROR: Failed to remove dead push token %d of user %d: %v", device.ID, userID, delErr)
+			}
+		default:
+			sendErrs = append(sendErrs, fmt.Errorf("device %d: %w", device.ID, err))
+		}
+	}
+	if delivered > 0 {
+		for _, sendErr := range sendErrs {
+			log.Printf("WARNING: Push to user %d reached %d device(s), but not %v", userID, delivered, sendErr)
+		}
+		return nil
+	}
+	return errors.Join(sendErrs...)
+}
+
+func (s *pushService) sendWithRetry(ctx context.Context, target push.Target, msg push.Message) error {
+	backoff := pushInitialBackoff
+	var err error
+	for attempt := 1; attempt <= pushMaxAttempts; attempt++ {
+		err = s.dispatcher.Send(ctx, target, msg)
+		if err == nil || !push.IsRetryable(err) || attempt == pushMaxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+type pushNotificationChannel struct {
+	pushSvc PushService
+}
+
+// NewPushNotificationChannel creates a NotificationChannel that delivers notifications as push messages.
+func NewPushNotificationChannel(pushSvc PushService) NotificationChannel {
+	return &pushNotificationChannel{pushSvc: pushSvc}
+}
+
+func (c *pushNotificationChannel) Name() string {
+	return "push"
+}
+
+// Deliver pushes the notification to all of its recipient's devices.
+func (c *pushNotificationChannel) Deliver(ctx context.Context, notification *models.Notification) error {
+	data := map[string]string{
+		"notification_id": strconv.FormatUint(uint64(notification.ID), 10),
+		"type":            string(notification.Type),
+	}
+	if notification.HostedSubscriptionID != nil {
+		data["hosted_subscription_id"] = strconv.FormatUint(uint64(*notification.HostedSubscriptionID), 10)
+	}
+	if notification.JoinRequestID != nil {
+		data["join_request_id"] = strconv.FormatUint(uint64(*notification.JoinRequestID), 10)
+	}
+	if notification.PaymentRecordID != nil {
+		data["payment_record_id"] = strconv.FormatUint(uint64(*notification.PaymentRecordID), 10)
+	}
+
+	return c.pushSvc.SendToUser(ctx, notification.UserID, push.Message{
+		Title: notification.Title,
+		Body:  notification.Message,
+		Data:  data,
+	})
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/push"
+	"gorm.io/gorm"
+)
+
+// memoryDeviceTokenRepository keeps device tokens in memory for push tests.
+type memoryDeviceTokenRepository struct {
+	devices []models.DeviceToken
+}
+
+func (r *memoryDeviceTokenRepository) Upsert(_ context.Context, dt *models.DeviceToken) error {
+	dt.ID = uint(len(r.devices) + 1)
+	r.devices = append(r.devices, *dt)
+	return nil
+}
+
+func (r *memoryDeviceTokenRepository) GetByID(_ context.Context, id uint) (*models.DeviceToken, error) {
+	for i := range r.devices {
+		if r.devices[i].ID == id {
+			return &r.devices[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryDeviceTokenRepository) ListByUserID(_ context.Context, userID uint) ([]models.DeviceToken, error) {
+	var devices []models.DeviceToken
+	for _, device := range r.devices {
+		if device.UserID == userID {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+func (r *memoryDeviceTokenRepository) Delete(_ context.Context, id uint) error {
+	r.devices = slices.DeleteFunc(r.devices, func(device models.DeviceToken) bool { return device.ID == id })
+	return nil
+}
+
+func (r *memoryDeviceTokenRepository) DeleteByToken(_ context.Context, token string) error {
+	r.devices = slices.DeleteFunc(r.devices, func(device models.DeviceToken) bool { return device.Token == token })
+	return nil
+}
+
+func newTestPushService(dispatcher push.Dispatcher, tokens ...string) (PushService, *memoryDeviceTokenRepository) {
+	repo := &memoryDeviceTokenRepository{}
+	for _, token := range tokens {
+		repo.Upsert(context.Background(), &models.DeviceToken{
+			UserID:   1,
+			Token:    token,
+			Platform: models.DevicePlatformAndroid,
+			Provider: models.PushProviderFCM,
+		})
+	}
+	return &pushService{deviceRepo: repo, dispatcher: dispatcher}, repo
+}
+
+func sentTokens(dispatcher *push.FakeDispatcher) []string {
+	var tokens []string
+	for _, sent := range dispatcher.Sent() {
+		tokens = append(tokens, sent.Target.Token)
+	}
+	return tokens
+}
+
+func TestSendToUserDeliversToEveryDevice(t *testing.T) {
+	dispatcher := push.NewFakeDispatcher()
+	svc, _ := newTestPushService(dispatcher, "phone", "tablet")
+
+	msg := push.Message{Title: "Payment approved", Body: "Your payment was approved."}
+	if err := svc.SendToUser(context.Background(), 1, msg); err != nil {
+		t.Fatalf("SendToUser: %v", err)
+	}
+	if got := sentTokens(dispatcher); !slices.Equal(got, []string{"phone", "tablet"}) {
+		t.Errorf("sent to %v, want [phone tablet]", got)
+	}
+	if got := dispatcher.Sent()[0].Message; got.Title != msg.Title || got.Body != msg.Body {
+		t.Errorf("sent message %+v, want %+v", got, msg)
+	}
+}
+
+func TestSendToUserRetriesTransientFailures(t *testing.T) {
+	dispatcher := push.NewFakeDispatcher()
+	dispatcher.FailTransiently("phone", pushMaxAttempts-1)
+	svc, _ := newTestPushService(dispatcher, "phone")
+
+	if err := svc.SendToUser(context.Background(), 1, push.Message{Title: "Hi"}); err != nil {
+		t.Fatalf("SendToUser: %v", err)
+	}
+	if got := sentTokens(dispatcher); !slices.Equal(got, []string{"phone"}) {
+		t.Errorf("sent to %v, want [phone]", got)
+	}
+}
+
+func TestSendToUserGivesUpAfterMaxAttempts(t *testing.T) {
+	dispatcher := push.NewFakeDispatcher()
+	dispatcher.FailTransiently("phone", pushMaxAttempts)
+	svc, repo := newTestPushService(dispatcher, "phone")
+
+	err := svc.SendToUser(context.Background(), 1, push.Message{Title: "Hi"})
+	if !push.IsRetryable(err) {
+		t.Fatalf("SendToUser error = %v, want a retryable failure", err)
+	}
+	if len(dispatcher.Sent()) != 0 {
+		t.Errorf("sent %d messages, want none", len(dispatcher.Sent()))
+	}
+	if len(repo.devices) != 1 {
+		t.Errorf("%d devices left, want the device kept after transient failures", len(repo.devices))
+	}
+}
+
+func TestSendToUserSucceedsWhenSomeDeviceWasReached(t *testing.T) {
+	dispatcher := push.NewFakeDispatcher()
+	dispatcher.FailTransiently("tablet", pushMaxAttempts)
+	svc, _ := newTestPushService(dispatcher, "phone", "tablet")
+
+	if err := svc.SendToUser(context.Background(), 1, push.Message{Title: "Hi"}); err != nil {
+		t.Fatalf("SendToUser error = %v, want nil so the delivery is not repeated on phone", err)
+	}
+	if got := sentTokens(dispatcher); !slices.Equal(got, []string{"phone"}) {
+		t.Errorf("sent to %v, want [phone]", got)
+	}
+}
+
+func TestSendToUserSkipsUnconfiguredProviders(t *testing.T) {
+	fcm := push.NewFakeDispatcher()
+	dispatcher := push.NewMultiDispatcher(map[models.PushProvider]push.Dispatcher{models.PushProviderFCM: fcm})
+	svc, repo := newTestPushService(dispatcher)
+	repo.Upsert(context.Background(), &models.DeviceToken{
+		UserID:   1,
+		Token:    "iphone",
+		Platform: models.DevicePlatformIOS,
+		Provider: models.PushProviderAPNs,
+	})
+
+	if err := svc.SendToUser(context.Background(), 1, push.Message{Title: "Hi"}); err != nil {
+		t.Fatalf("SendToUser error = %v, want the APNs device skipped", err)
+	}
+	if len(fcm.Sent()) != 0 {
+		t.Errorf("sent %d messages, want none", len(fcm.Sent()))
+	}
+	if len(repo.devices) != 1 {
+		t.Errorf("%d devices left, want the APNs device kept", len(repo.devices))
+	}
+}
+
+func TestSendToUserRemovesDeadTokens(t *testing.T) {
+	dispatcher := push.NewFakeDispatcher("old-phone")
+	svc, repo := newTestPushService(dispatcher, "old-phone", "phone")
+
+	if err := svc.SendToUser(context.Background(), 1, push.Message{Title: "Hi"}); err != nil {
+		t.Fatalf("SendToUser: %v", err)
+	}
+	if got := sentTokens(dispatcher); !slices.Equal(got, []string{"phone"}) {
+		t.Errorf("sent to %v, want [phone]", got)
+	}
+	if len(repo.devices) != 1 || repo.devices[0].Token != "phone" {
+		t.Errorf("devices left %+v, want only phone", repo.devices)
+	}
+}
+
+func TestSendToUserWithoutDispatcherSendsNothing(t *testing.T) {
+	svc, _ := newTestPushService(nil, "phone")
+
+	if err := svc.SendToUser(context.Background(), 1, push.Message{Title: "Hi"}); err != nil {
+		t.Fatalf("SendToUser: %v", err)
+	}
+}
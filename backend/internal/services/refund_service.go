@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/events"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+// Custom errors for RefundService
+var (
+	ErrRefundNotFound            = errors.New("refund not found")
+	ErrRefundExceedsPayment      = errors.New("refund amount exceeds what is left of the payment")
+	ErrPaymentNotRefundable      = errors.New("only an approved payment of this membership can be refunded")
+	ErrRefundAlreadyAcknowledged = errors.New("refund has already been acknowledged")
+)
+
+// RefundService defines the interface for refunds from hosts to members.
+type RefundService interface {
+	SuggestRefund(ctx context.Context, userID uint, membershipID uint) (*models.RefundSuggestion, error)
+	CreateRefund(ctx context.Context, hostUserID uint, membershipID uint, req *models.CreateRefundRequest) (*models.Refund, error)
+	ListRefunds(ctx context.Context, userID uint, membershipID uint) ([]models.Refund, error)
+	AcknowledgeRefund(ctx context.Context, memberUserID uint, refundID uint) (*models.Refund, error)
+}
+
+type refundService struct {
+	refundRepo        repositories.RefundRepository
+	membershipRepo    repositories.SubscriptionMembershipRepository
+	paymentRecordRepo repositories.PaymentRecordRepository
+	ledgerRepo        repositories.MembershipLedgerRepository
+	accountingSvc     AccountingService
+	transactor        repositories.Transactor
+	eventPublisher    events.Publisher
+	auditSvc          AuditService
+}
+
+// NewRefundService creates a new RefundService.
+func NewRefundService(
+	refundRepo repositories.RefundRepository,
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	paymentRecordRepo repositories.PaymentRecordRepository,
+	ledgerRepo repositories.MembershipLedgerRepository,
+	accountingSvc AccountingService,
+	transactor repositories.Transactor,
+	eventPublisher events.Publisher,
+	auditSvc AuditService,
+) RefundService {
+	return &refundService{
+		refundRepo:        refundRepo,
+		membershipRepo:    membershipRepo,
+		paymentRecordRepo: paymentRecordRepo,
+		ledgerRepo:        ledgerRepo,
+		accountingSvc:     accountingSvc,
+		transactor:        transactor,
+		eventPublisher:    eventPublisher,
+		auditSvc:          auditSvc,
+	}
+}
+
+// SuggestRefund computes what the host should return to the member of a membership: the unused days of the
+// period the member has paid through, counted from when they left (or today), plus any credit balance.
+func (s *refundService) SuggestRefund(ctx context.Context, userID uint, membershipID uint) (*models.RefundSuggestion, error) {
+	membership, err := s.getParticipantMembership(ctx, userID, membershipID)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate := time.Now().UTC()
+	if membership.DeletedAt.Valid {
+		endDate = membership.DeletedAt.Time.UTC()
+	}
+	suggestion := &models.RefundSuggestion{
+		SubscriptionMembershipID: membership.ID,
+		EndDate:                  endDate,
+		PaidThrough:              membership.NextPaymentDate,
+		CreditBalance:            membership.CreditBalance(),
+	}
+
+	latest, err := s.paymentRecordRepo.FindLatestApprovedByMembershipID(ctx, membership.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("fetching latest approved payment: %w", err)
+	}
+	if err == nil {
+		suggestion.PaymentRecordID = &latest.ID
+	}
+
+	hs := membership.HostedSubscription
+	if membership.NextPaymentDate != nil && hs.TotalSlots > 0 && membership.NextPaymentDate.After(endDate) {
+		paidThrough := *membership.NextPaymentDate
+		cycleStart := paidThrough.AddDate(0, -1, 0)
+		if hs.BillingCycle == models.BillingAnnually {
+			cycleStart = paidThrough.AddDate(-1, 0, 0)
+		}
+		suggestion.CycleDays = wholeDays(paidThrough.Sub(cycleStart))
+		suggestion.UnusedDays = wholeDays(paidThrough.Sub(endDate))
+		if suggestion.CycleDays > 0 {
+			share := hs.CostPerCycle / float64(hs.TotalSlots)
+			suggestion.UnusedAmount = models.RoundMoney(share * float64(suggestion.UnusedDays) / float64(suggestion.CycleDays))
+		}
+	}
+
+	refunded, err := s.refundRepo.SumByMembershipID(ctx, membership.ID)
+	if err != nil {
+		return nil, fmt.Errorf("summing refunds: %w", err)
+	}
+	suggestion.AlreadyRefunded = models.RoundMoney(refunded)
+	suggestion.SuggestedAmount = models.RoundMoney(max(suggestion.UnusedAmount+suggestion.CreditBalance-suggestion.AlreadyRefunded, 0))
+	return suggestion, nil
+}
+
+// CreateRefund records a refund the host sent to the member, with proof of the transfer.
+// The refund first returns the member's credit balance; any rest is taken off the host's earnings.
+func (s *refundService) CreateRefund(ctx context.Context, hostUserID uint, membershipID uint, req *models.CreateRefundRequest) (*models.Refund, error) {
+	membership, err := s.membershipRepo.GetByIDIncludingEnded(ctx, membershipID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMembershipNotFound
+		}
+		return nil, fmt.Errorf("fetching membership: %w", err)
+	}
+	if membership.HostedSubscription.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+
+	pr, err := s.paymentRecordRepo.GetByID(ctx, req.PaymentRecordID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotRefundable
+		}
+		return nil, fmt.Errorf("fetching payment record: %w", err)
+	}
+	if pr.SubscriptionMembershipID != membership.ID || pr.Status != models.PaymentRecordStatusApproved {
+		return nil, ErrPaymentNotRefundable
+	}
+
+	suggestion, err := s.SuggestRefund(ctx, hostUserID, membershipID)
+	if err != nil {
+		return nil, err
+	}
+
+	refund := &models.Refund{
+		PaymentRecordID:          pr.ID,
+		SubscriptionMembershipID: membership.ID,
+		HostedSubscriptionID:     membership.HostedSubscriptionID,
+		HostUserID:               hostUserID,
+		MemberUserID:             membership.MemberUserID,
+		Amount:                   models.RoundMoney(req.Amount),
+		SuggestedAmount:          suggestion.SuggestedAmount,
+		Reason:                   req.Reason,
+		ProofImageURL:            req.ProofImageURL,
+		TransactionReference:     req.TransactionReference,
+		Status:                   models.RefundStatusSent,
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock the membership before summing, so concurrent refunds of the same payment are checked one after another.
+		locked, err := s.membershipRepo.GetByIDForUpdate(ctx, membership.ID)
+		if err != nil {
+			return fmt.Errorf("locking membership %d: %w", membership.ID, err)
+		}
+		refunded, err := s.refundRepo.SumByPaymentRecordID(ctx, pr.ID)
+		if err != nil {
+			return fmt.Errorf("summing refunds of payment record %d: %w", pr.ID, err)
+		}
+		if models.RoundMoney(refunded+refund.Amount) > models.RoundMoney(pr.AmountPaid) {
+			return ErrRefundExceedsPayment
+		}
+		if err := s.refundRepo.Create(ctx, refund); err != nil {
+			return fmt.Errorf("creating refund: %w", err)
+		}
+		if err := s.applyRefund(ctx, membership, locked, pr, refund); err != nil {
+			return err
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditRefundCreate,
+			EntityID:             refund.ID,
+			HostedSubscriptionID: &refund.HostedSubscriptionID,
+			After:                refund,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.RefundIssued, hostUserID, refund.MemberUserID, hostUserID)
+		event.HostedSubscriptionID = refund.HostedSubscriptionID
+		event.MembershipID = refund.SubscriptionMembershipID
+		event.PaymentRecordID = refund.PaymentRecordID
+		event.RefundID = refund.ID
+		event.Status = string(refund.Status)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// applyRefund takes a refund off the credit balance of the membership, locked by the caller, as far as it goes,
+// and journals it.
+func (s *refundService) applyRefund(ctx context.Context, party *models.SubscriptionMembership, membership *models.SubscriptionMembership, pr *models.PaymentRecord, refund *models.Refund) error {
+	fromCredit := min(refund.Amount, membership.CreditBalance())
+	fromEarnings := models.RoundMoney(refund.Amount - fromCredit)
+	description := fmt.Sprintf("Refund of payment for %s", pr.PaymentCycleIdentifier)
+	if fromCredit > 0 {
+		membership.Balance = models.RoundMoney(membership.Balance - fromCredit)
+		entry := &models.MembershipLedgerEntry{
+			SubscriptionMembershipID: membership.ID,
+			PaymentRecordID:          &pr.ID,
+			Kind:                     models.MembershipLedgerRefund,
+			Amount:                   -fromCredit,
+			BalanceAfter:             membership.Balance,
+			Description:              description,
+		}
+		if err := s.ledgerRepo.Create(ctx, entry); err != nil {
+			return fmt.Errorf("recording refund on ledger: %w", err)
+		}
+		if err := s.membershipRepo.UpdateBilling(ctx, membership); err != nil {
+			return fmt.Errorf("updating membership billing: %w", err)
+		}
+	}
+	return s.accountingSvc.PostRefund(ctx, journalPartyOfMembership(party), fromCredit, fromEarnings, time.Now().UTC(), &pr.ID, description)
+}
+
+// ListRefunds retrieves the refunds of a membership for its member or the subscription's host.
+func (s *refundService) ListRefunds(ctx context.Context, userID uint, membershipID uint) ([]models.Refund, error) {
+	membership, err := s.getParticipantMembership(ctx, userID, membershipID)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := s.refundRepo.ListByMembershipID(ctx, membership.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+// AcknowledgeRefund lets the member confirm that they received a refund.
+func (s *refundService) AcknowledgeRefund(ctx context.Context, memberUserID uint, refundID uint) (*models.Refund, error) {
+	refund, err := s.refundRepo.GetByID(ctx, refundID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefundNotFound
+		}
+		return nil, fmt.Errorf("fetching refund: %w", err)
+	}
+	if refund.MemberUserID != memberUserID {
+		return nil, ErrForbidden
+	}
+	if refund.Status != models.RefundStatusSent {
+		return nil, ErrRefundAlreadyAcknowledged
+	}
+
+	before := *refund
+	now := time.Now().UTC()
+	refund.Status = models.RefundStatusAcknowledged
+	refund.AcknowledgedAt = &now
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.refundRepo.MarkAcknowledged(ctx, refund); err != nil {
+			return fmt.Errorf("acknowledging refund: %w", err)
+		}
+		err := s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditRefundAcknowledge,
+			EntityID:             refund.ID,
+			HostedSubscriptionID: &refund.HostedSubscriptionID,
+			Before:               &before,
+			After:                refund,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.RefundAcknowledged, memberUserID, refund.HostUserID, memberUserID)
+		event.HostedSubscriptionID = refund.HostedSubscriptionID
+		event.MembershipID = refund.SubscriptionMembershipID
+		event.PaymentRecordID = refund.PaymentRecordID
+		event.RefundID = refund.ID
+		event.Status = string(refund.Status)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// getParticipantMembership fetches a membership, ended or not, for its member or the subscription's host.
+func (s *refundService) getParticipantMembership(ctx context.Context, userID uint, membershipID uint) (*models.SubscriptionMembership, error) {
+	membership, err := s.membershipRepo.GetByIDIncludingEnded(ctx, membershipID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMembershipNotFound
+		}
+		return nil, fmt.Errorf("fetching membership: %w", err)
+	}
+	if membership.MemberUserID != userID && membership.HostedSubscription.HostUserID != userID {
+		return nil, ErrForbidden
+	}
+	return membership, nil
+}
+
+// publish records a domain event in the outbox as part of the caller's transaction.
+func (s *refundService) publish(ctx context.Context, event events.Event) error {
+	if err := s.eventPublisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("recording %s event: %w", event.Type, err)
+	}
+	return nil
+}
+
+// wholeDays rounds a duration down to whole days, so a partly used day is not refunded.
+func wholeDays(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Floor(d.Hours() / 24))
+}
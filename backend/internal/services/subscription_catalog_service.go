@@ -19,12 +19,14 @@ type SubscriptionCatalogService interface {
 }
 
 type subscriptionCatalogService struct {
-	repo repositories.SubscriptionServiceRepository
+	repo       repositories.SubscriptionServiceRepository
+	transactor repositories.Transactor
+	auditSvc   AuditService
 }
 
 // NewSubscriptionCatalogService creates a new SubscriptionCatalogService instance.
-func NewSubscriptionCatalogService(repo repositories.SubscriptionServiceRepository) SubscriptionCatalogService {
-	return &subscriptionCatalogService{repo: repo}
+func NewSubscriptionCatalogService(repo repositories.SubscriptionServiceRepository, transactor repositories.Transactor, auditSvc AuditService) SubscriptionCatalogService {
+	return &subscriptionCatalogService{repo: repo, transactor: transactor, auditSvc: auditSvc}
 }
 
 // CreateSubscriptionService creates a new predefined subscription service.
@@ -42,8 +44,14 @@ func (s *subscriptionCatalogService) CreateSubscriptionService(ctx context.Conte
 		LogoURL: req.LogoURL,
 	}
 
-	if err := s.repo.CreateSubscriptionService(ctx, service); err != nil {
-		return nil, fmt.Errorf("creating subscription service: %w", err)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateSubscriptionService(ctx, service); err != nil {
+			return fmt.Errorf("creating subscription service: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{Action: models.AuditSubscriptionServiceCreate, EntityID: service.ID, After: service})
+	})
+	if err != nil {
+		return nil, err
 	}
 	return service, nil
 }
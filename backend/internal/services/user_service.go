@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+
 	"github.com/xNatthapol/hubster/internal/models"
 	"github.com/xNatthapol/hubster/internal/repositories"
 	"gorm.io/gorm"
@@ -15,12 +16,14 @@ type UserService interface {
 }
 
 type userService struct {
-	userRepo repositories.UserRepository
+	userRepo   repositories.UserRepository
+	transactor repositories.Transactor
+	auditSvc   AuditService
 }
 
 // NewUserService creates a new UserService instance.
-func NewUserService(userRepo repositories.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(userRepo repositories.UserRepository, transactor repositories.Transactor, auditSvc AuditService) UserService {
+	return &userService{userRepo: userRepo, transactor: transactor, auditSvc: auditSvc}
 }
 
 // GetUserProfile retrieves a user's profile by their ID.
@@ -46,6 +49,7 @@ func (s *userService) UpdateUserProfile(ctx context.Context, userID uint, req *m
 		return nil, err
 	}
 
+	before := *user
 	updated := false
 	if req.FullName != nil {
 		user.FullName = *req.FullName
@@ -64,7 +68,18 @@ func (s *userService) UpdateUserProfile(ctx context.Context, userID uint, req *m
 		return user, ErrNoFieldsToUpdate
 	}
 
-	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:   models.AuditUserUpdateProfile,
+			EntityID: user.ID,
+			Before:   &before,
+			After:    user,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,484 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/events"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	waitlistClaimWindow    = 24 * time.Hour
+	waitlistExpiryInterval = time.Minute
+)
+
+// Custom errors for WaitlistService
+var (
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrAlreadyOnWaitlist     = errors.New("you are already on the waitlist of this subscription")
+	ErrSubscriptionNotFull   = errors.New("subscription has a free slot: send a join request instead")
+	ErrNoSlotOffered         = errors.New("no slot is being offered to you on this waitlist entry")
+	ErrSlotOfferExpired      = errors.New("the slot offer has expired")
+)
+
+// WaitlistService defines the interface for the waitlists of full hosted subscriptions.
+type WaitlistService interface {
+	JoinWaitlist(ctx context.Context, userID uint, hostedSubscriptionID uint) (*models.WaitlistEntryResponse, error)
+	ListMyWaitlistEntries(ctx context.Context, userID uint) ([]models.WaitlistEntryResponse, error)
+	ListWaitlist(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) ([]models.WaitlistEntryResponse, error)
+	LeaveWaitlist(ctx context.Context, userID uint, entryID uint) error
+	ClaimSlot(ctx context.Context, userID uint, entryID uint, req *models.CreateJoinRequestRequest) (*models.JoinRequest, error)
+	OfferFreeSlots(ctx context.Context, hostedSubscriptionID uint) error
+	ExpireOffers(ctx context.Context, now time.Time) error
+}
+
+type waitlistService struct {
+	waitlistRepo    repositories.WaitlistRepository
+	hsRepo          repositories.HostedSubscriptionRepository
+	joinRequestRepo repositories.JoinRequestRepository
+	membershipRepo  repositories.SubscriptionMembershipRepository
+	screeningRepo   repositories.ScreeningQuestionRepository
+	transactor      repositories.Transactor
+	eventPublisher  events.Publisher
+	auditSvc        AuditService
+}
+
+// NewWaitlistService creates a new WaitlistService.
+func NewWaitlistService(
+	waitlistRepo repositories.WaitlistRepository,
+	hsRepo repositories.HostedSubscriptionRepository,
+	joinRequestRepo repositories.JoinRequestRepository,
+	membershipRepo repositories.SubscriptionMembershipRepository,
+	screeningRepo repositories.ScreeningQuestionRepository,
+	transactor repositories.Transactor,
+	eventPublisher events.Publisher,
+	auditSvc AuditService,
+) WaitlistService {
+	return &waitlistService{
+		waitlistRepo:    waitlistRepo,
+		hsRepo:          hsRepo,
+		joinRequestRepo: joinRequestRepo,
+		membershipRepo:  membershipRepo,
+		screeningRepo:   screeningRepo,
+		transactor:      transactor,
+		eventPublisher:  eventPublisher,
+		auditSvc:        auditSvc,
+	}
+}
+
+// JoinWaitlist queues the user for a full subscription. Subscriptions with a free slot take join requests instead.
+func (s *waitlistService) JoinWaitlist(ctx context.Context, userID uint, hostedSubscriptionID uint) (*models.WaitlistEntryResponse, error) {
+	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching hosted subscription: %w", err)
+	}
+	if hostedSub.HostUserID == userID {
+		return nil, ErrHostCannotJoinOwn
+	}
+	if hostedSub.Visibility == models.VisibilityPrivate {
+		return nil, ErrInviteRequired
+	}
+
+	_, err = s.membershipRepo.FindByUserAndSubscription(ctx, userID, hostedSub.ID)
+	if err == nil {
+		return nil, ErrAlreadyMember
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("checking existing membership: %w", err)
+	}
+	_, err = s.joinRequestRepo.FindPendingByRequesterAndSubscription(ctx, userID, hostedSub.ID)
+	if err == nil {
+		return nil, ErrAlreadyRequestedToJoin
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("checking existing join request: %w", err)
+	}
+
+	entry := &models.WaitlistEntry{
+		HostedSubscriptionID: hostedSub.ID,
+		UserID:               userID,
+		Status:               models.WaitlistStatusWaiting,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.waitlistRepo.LockQueue(ctx, hostedSub.ID); err != nil {
+			return fmt.Errorf("locking waitlist: %w", err)
+		}
+		_, err := s.waitlistRepo.FindActiveByUserAndSubscription(ctx, userID, hostedSub.ID)
+		if err == nil {
+			return ErrAlreadyOnWaitlist
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("checking existing waitlist entry: %w", err)
+		}
+		reserved, err := s.waitlistRepo.CountReservedSlots(ctx, hostedSub.ID)
+		if err != nil {
+			return fmt.Errorf("counting reserved slots: %w", err)
+		}
+		if len(hostedSub.Memberships)+1+int(reserved) < hostedSub.TotalSlots {
+			return ErrSubscriptionNotFull
+		}
+
+		if err := s.waitlistRepo.Create(ctx, entry); err != nil {
+			return fmt.Errorf("creating waitlist entry: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditWaitlistJoin,
+			EntityID:             entry.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			After:                entry,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.entryResponse(ctx, entry, hostedSub.SubscriptionTitle)
+}
+
+// ListMyWaitlistEntries lists the waitlists the user is waiting on or has a slot offered from, with their place in each queue.
+func (s *waitlistService) ListMyWaitlistEntries(ctx context.Context, userID uint) ([]models.WaitlistEntryResponse, error) {
+	entries, err := s.waitlistRepo.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing waitlist entries: %w", err)
+	}
+	responses := make([]models.WaitlistEntryResponse, 0, len(entries))
+	for i := range entries {
+		response, err := s.entryResponse(ctx, &entries[i], entries[i].HostedSubscription.SubscriptionTitle)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// ListWaitlist lists the queue of a subscription owned by the host, first in line first.
+func (s *waitlistService) ListWaitlist(ctx context.Context, hostUserID uint, hostedSubscriptionID uint) ([]models.WaitlistEntryResponse, error) {
+	hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("fetching subscription for ownership check: %w", err)
+	}
+	if hostedSub.HostUserID != hostUserID {
+		return nil, ErrForbidden
+	}
+
+	entries, err := s.waitlistRepo.ListActiveByHostedSubscriptionID(ctx, hostedSub.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing waitlist: %w", err)
+	}
+	responses := make([]models.WaitlistEntryResponse, 0, len(entries))
+	position := 0
+	for _, entry := range entries {
+		response := waitlistEntryResponse(&entry, hostedSub.SubscriptionTitle)
+		response.User = &models.UserResponse{
+			ID:                entry.User.ID,
+			Email:             entry.User.Email,
+			FullName:          entry.User.FullName,
+			ProfilePictureURL: entry.User.ProfilePictureURL,
+		}
+		if entry.Status == models.WaitlistStatusWaiting {
+			position++
+			response.Position = position
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// LeaveWaitlist takes the user off a waitlist. A slot they were offered goes to the next in line.
+func (s *waitlistService) LeaveWaitlist(ctx context.Context, userID uint, entryID uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		entry, err := s.getOwnEntryForUpdate(ctx, userID, entryID)
+		if err != nil {
+			return err
+		}
+		if entry.Status != models.WaitlistStatusWaiting && entry.Status != models.WaitlistStatusOffered {
+			return ErrWaitlistEntryNotFound
+		}
+
+		before := *entry
+		entry.Status = models.WaitlistStatusLeft
+		if err := s.waitlistRepo.Update(ctx, entry); err != nil {
+			return fmt.Errorf("leaving waitlist: %w", err)
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditWaitlistLeave,
+			EntityID:             entry.ID,
+			HostedSubscriptionID: &entry.HostedSubscriptionID,
+			Before:               &before,
+			After:                entry,
+		})
+		if err != nil {
+			return err
+		}
+		if before.Status == models.WaitlistStatusOffered {
+			return s.OfferFreeSlots(ctx, entry.HostedSubscriptionID)
+		}
+		return nil
+	})
+}
+
+// ClaimSlot takes the slot offered to the user and sends the host a join request for it, with an optional message
+// and the answers to the subscription's screening questions as for any other join request. The slot stays held
+// for the user until the host decides on the request.
+func (s *waitlistService) ClaimSlot(ctx context.Context, userID uint, entryID uint, req *models.CreateJoinRequestRequest) (*models.JoinRequest, error) {
+	if req == nil {
+		req = &models.CreateJoinRequestRequest{}
+	}
+
+	var joinReq *models.JoinRequest
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		entry, err := s.getOwnEntryForUpdate(ctx, userID, entryID)
+		if err != nil {
+			return err
+		}
+		if entry.Status != models.WaitlistStatusOffered {
+			return ErrNoSlotOffered
+		}
+		if entry.OfferExpiresAt != nil && !time.Now().Before(*entry.OfferExpiresAt) {
+			return ErrSlotOfferExpired
+		}
+		hostedSub, err := s.hsRepo.GetByID(ctx, entry.HostedSubscriptionID)
+		if err != nil {
+			return fmt.Errorf("fetching hosted subscription: %w", err)
+		}
+		answers, err := screeningAnswers(ctx, s.screeningRepo, hostedSub.ID, req.Answers)
+		if err != nil {
+			return err
+		}
+
+		var before *models.JoinRequest
+		joinReq, before, err = fileJoinRequest(ctx, s.joinRequestRepo, &models.JoinRequest{
+			RequesterUserID:      userID,
+			HostedSubscriptionID: entry.HostedSubscriptionID,
+			Status:               models.JoinRequestStatusPending,
+			Message:              strings.TrimSpace(req.Message),
+			Answers:              answers,
+		})
+		if err != nil {
+			return err
+		}
+
+		offered := *entry
+		entry.Status = models.WaitlistStatusClaimed
+		entry.JoinRequestID = &joinReq.ID
+		if err := s.waitlistRepo.Update(ctx, entry); err != nil {
+			return fmt.Errorf("claiming waitlist slot: %w", err)
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditWaitlistClaim,
+			EntityID:             entry.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               &offered,
+			After:                entry,
+		})
+		if err != nil {
+			return err
+		}
+		err = s.auditSvc.Record(ctx, AuditEntry{
+			Action:               models.AuditJoinRequestCreate,
+			EntityID:             joinReq.ID,
+			HostedSubscriptionID: &hostedSub.ID,
+			Before:               before,
+			After:                joinReq,
+		})
+		if err != nil {
+			return err
+		}
+
+		event := events.New(events.JoinRequestCreated, userID, hostedSub.HostUserID, userID)
+		event.HostedSubscriptionID = hostedSub.ID
+		event.JoinRequestID = joinReq.ID
+		event.Status = string(models.JoinRequestStatusPending)
+		return s.publish(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fullJoinRequest, err := s.joinRequestRepo.GetByID(ctx, joinReq.ID)
+	if err != nil {
+		log.Printf("Warning: JoinRequest %d sent from a waitlist claim, but failed to fetch its full details for response: %v", joinReq.ID, err)
+		return joinReq, nil
+	}
+	return fullJoinRequest, nil
+}
+
+// OfferFreeSlots offers each free slot of a subscription to the next user in line, who has the claim window to
+// take it. Slots already offered or claimed and awaiting the host count as taken, so calling it again is harmless.
+func (s *waitlistService) OfferFreeSlots(ctx context.Context, hostedSubscriptionID uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.waitlistRepo.LockQueue(ctx, hostedSubscriptionID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("locking waitlist: %w", err)
+		}
+		hostedSub, err := s.hsRepo.GetByID(ctx, hostedSubscriptionID)
+		if err != nil {
+			return fmt.Errorf("fetching hosted subscription: %w", err)
+		}
+		reserved, err := s.waitlistRepo.CountReservedSlots(ctx, hostedSub.ID)
+		if err != nil {
+			return fmt.Errorf("counting reserved slots: %w", err)
+		}
+		free := hostedSub.TotalSlots - (len(hostedSub.Memberships) + 1) - int(reserved)
+		if free <= 0 {
+			return nil
+		}
+
+		entries, err := s.waitlistRepo.ListNextWaiting(ctx, hostedSub.ID, free)
+		if err != nil {
+			return fmt.Errorf("listing waitlist: %w", err)
+		}
+		now := time.Now()
+		expiresAt := now.Add(waitlistClaimWindow)
+		for i := range entries {
+			entry := &entries[i]
+			entry.Status = models.WaitlistStatusOffered
+			entry.OfferedAt = &now
+			entry.OfferExpiresAt = &expiresAt
+			if err := s.waitlistRepo.Update(ctx, entry); err != nil {
+				return fmt.Errorf("offering slot to waitlist entry %d: %w", entry.ID, err)
+			}
+
+			event := events.New(events.WaitlistSlotOffered, 0, entry.UserID)
+			event.HostedSubscriptionID = hostedSub.ID
+			event.WaitlistEntryID = entry.ID
+			event.Status = string(models.WaitlistStatusOffered)
+			if err := s.publish(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ExpireOffers ends the offers that were not claimed in time and offers their slots to the next in line.
+func (s *waitlistService) ExpireOffers(ctx context.Context, now time.Time) error {
+	hostedSubscriptionIDs, err := s.waitlistRepo.ExpireOffers(ctx, now)
+	if err != nil {
+		return fmt.Errorf("expiring waitlist offers: %w", err)
+	}
+	slices.Sort(hostedSubscriptionIDs)
+
+	var errs []error
+	for _, id := range slices.Compact(hostedSubscriptionIDs) {
+		if err := s.OfferFreeSlots(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("re-offering slots of subscription %d: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *waitlistService) getOwnEntryForUpdate(ctx context.Context, userID uint, entryID uint) (*models.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetByIDForUpdate(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWaitlistEntryNotFound
+		}
+		return nil, fmt.Errorf("fetching waitlist entry: %w", err)
+	}
+	if entry.UserID != userID {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	return entry, nil
+}
+
+func (s *waitlistService) entryResponse(ctx context.Context, entry *models.WaitlistEntry, subscriptionTitle string) (*models.WaitlistEntryResponse, error) {
+	response := waitlistEntryResponse(entry, subscriptionTitle)
+	if entry.Status == models.WaitlistStatusWaiting {
+		ahead, err := s.waitlistRepo.CountWaitingBefore(ctx, entry.HostedSubscriptionID, entry.ID)
+		if err != nil {
+			return nil, fmt.Errorf("counting waitlist position: %w", err)
+		}
+		response.Position = int(ahead) + 1
+	}
+	return response, nil
+}
+
+func (s *waitlistService) publish(ctx context.Context, event events.Event) error {
+	if err := s.eventPublisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("recording %s event: %w", event.Type, err)
+	}
+	return nil
+}
+
+func waitlistEntryResponse(entry *models.WaitlistEntry, subscriptionTitle string) *models.WaitlistEntryResponse {
+	return &models.WaitlistEntryResponse{
+		ID:                   entry.ID,
+		HostedSubscriptionID: entry.HostedSubscriptionID,
+		SubscriptionTitle:    subscriptionTitle,
+		Status:               entry.Status,
+		OfferedAt:            entry.OfferedAt,
+		OfferExpiresAt:       entry.OfferExpiresAt,
+		JoinRequestID:        entry.JoinRequestID,
+		CreatedAt:            entry.CreatedAt,
+	}
+}
+
+type waitlistEventSubscriber struct {
+	waitlistSvc WaitlistService
+}
+
+// NewWaitlistEventSubscriber creates an OutboxSubscriber that offers slots to waitlisted users when they free up:
+// a member leaves or is removed, the host adds slots, or the host declines a join request sent from a waitlist claim.
+func NewWaitlistEventSubscriber(waitlistSvc WaitlistService) OutboxSubscriber {
+	return &waitlistEventSubscriber{waitlistSvc: waitlistSvc}
+}
+
+func (s *waitlistEventSubscriber) Name() string {
+	return "waitlist"
+}
+
+// Handle offers the free slots of the event's subscription. Other events are ignored.
+func (s *waitlistEventSubscriber) Handle(ctx context.Context, event events.Event) error {
+	switch event.Type {
+	case events.MemberLeft, events.MemberRemoved, events.SlotsChanged, events.JoinRequestDeclined:
+		return s.waitlistSvc.OfferFreeSlots(ctx, event.HostedSubscriptionID)
+	default:
+		return nil
+	}
+}
+
+// WaitlistJob periodically expires slot offers that were not claimed in time.
+type WaitlistJob struct {
+	waitlistSvc WaitlistService
+}
+
+// NewWaitlistJob creates a new WaitlistJob.
+func NewWaitlistJob(waitlistSvc WaitlistService) *WaitlistJob {
+	return &WaitlistJob{waitlistSvc: waitlistSvc}
+}
+
+// Start runs the job every minute in the background until ctx is cancelled.
+func (j *WaitlistJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(waitlistExpiryInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := j.waitlistSvc.ExpireOffers(ctx, time.Now().UTC()); err != nil {
+				log.Printf("ERROR: Waitlist offer expiry run failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
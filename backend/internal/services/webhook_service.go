@@ -0,0 +1,464 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xNatthapol/hubster/internal/events"
+	"github.com/xNatthapol/hubster/internal/models"
+	"github.com/xNatthapol/hubster/internal/repositories"
+	"github.com/xNatthapol/hubster/internal/webhook"
+	"gorm.io/gorm"
+)
+
+const (
+	maxWebhookEndpointsPerUser  = 10
+	webhookDispatchInterval     = 2 * time.Second
+	webhookDispatchBatchSize    = 20
+	webhookDeliveryLease        = time.Minute
+	webhookBatchDeadline        = 30 * time.Second // Well within the lease, so no other instance reclaims a batch being sent
+	webhookMaxAttempts          = 8
+	webhookInitialBackoff       = 30 * time.Second
+	webhookMaxBackoff           = 6 * time.Hour
+	defaultWebhookDeliveryLimit = 20
+	maxWebhookDeliveryLimit     = 100
+)
+
+var (
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("webhook URL must be an absolute https URL")
+	ErrWebhookURLNotAllowed    = errors.New("webhook URL must point to a public internet address")
+	ErrInvalidWebhookEventType = errors.New("unknown webhook event type")
+	ErrTooManyWebhookEndpoints = errors.New("maximum number of webhook endpoints reached")
+)
+
+// WebhookService defines the interface for managing webhook endpoints and their deliveries.
+type WebhookService interface {
+	CreateEndpoint(ctx context.Context, userID uint, req *models.CreateWebhookEndpointRequest) (*models.WebhookEndpointResponse, error)
+	ListEndpoints(ctx context.Context, userID uint) ([]models.WebhookEndpointResponse, error)
+	GetEndpoint(ctx context.Context, userID uint, endpointID uint) (*models.WebhookEndpointResponse, error)
+	UpdateEndpoint(ctx context.Context, userID uint, endpointID uint, req *models.UpdateWebhookEndpointRequest) (*models.WebhookEndpointResponse, error)
+	DeleteEndpoint(ctx context.Context, userID uint, endpointID uint) error
+	ListDeliveries(ctx context.Context, userID uint, endpointID uint, limit int, offset int) ([]models.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, userID uint, endpointID uint, deliveryID uint) (*models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, userID uint, endpointID uint, deliveryID uint) (*models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	webhookRepo repositories.WebhookRepository
+	transactor  repositories.Transactor
+	auditSvc    AuditService
+}
+
+// NewWebhookService creates a new WebhookService.
+func NewWebhookService(webhookRepo repositories.WebhookRepository, transactor repositories.Transactor, auditSvc AuditService) WebhookService {
+	return &webhookService{webhookRepo: webhookRepo, transactor: transactor, auditSvc: auditSvc}
+}
+
+// CreateEndpoint registers a webhook endpoint with a newly generated signing secret.
+// The secret is only returned by this call.
+func (s *webhookService) CreateEndpoint(ctx context.Context, userID uint, req *models.CreateWebhookEndpointRequest) (*models.WebhookEndpointResponse, error) {
+	if err := validateWebhookURL(ctx, req.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.webhookRepo.ListEndpointsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook endpoints: %w", err)
+	}
+	if len(existing) >= maxWebhookEndpointsPerUser {
+		return nil, ErrTooManyWebhookEndpoints
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := &models.WebhookEndpoint{
+		UserID:      userID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      secret,
+		EventTypes:  joinEventTypes(req.EventTypes),
+		IsActive:    true,
+	}
+	var response models.WebhookEndpointResponse
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+			return fmt.Errorf("creating webhook endpoint: %w", err)
+		}
+		response = mapWebhookEndpoint(endpoint)
+		return s.auditSvc.Record(ctx, AuditEntry{Action: models.AuditWebhookEndpointCreate, EntityID: endpoint.ID, After: response})
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.Secret = endpoint.Secret
+	return &response, nil
+}
+
+// ListEndpoints retrieves the user's webhook endpoints.
+func (s *webhookService) ListEndpoints(ctx context.Context, userID uint) ([]models.WebhookEndpointResponse, error) {
+	endpoints, err := s.webhookRepo.ListEndpointsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook endpoints: %w", err)
+	}
+	responses := make([]models.WebhookEndpointResponse, len(endpoints))
+	for i := range endpoints {
+		responses[i] = mapWebhookEndpoint(&endpoints[i])
+	}
+	return responses, nil
+}
+
+// GetEndpoint retrieves one of the user's webhook endpoints.
+func (s *webhookService) GetEndpoint(ctx context.Context, userID uint, endpointID uint) (*models.WebhookEndpointResponse, error) {
+	endpoint, err := s.getOwnedEndpoint(ctx, userID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	response := mapWebhookEndpoint(endpoint)
+	return &response, nil
+}
+
+// UpdateEndpoint applies the provided changes to one of the user's webhook endpoints.
+func (s *webhookService) UpdateEndpoint(ctx context.Context, userID uint, endpointID uint, req *models.UpdateWebhookEndpointRequest) (*models.WebhookEndpointResponse, error) {
+	endpoint, err := s.getOwnedEndpoint(ctx, userID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	before := mapWebhookEndpoint(endpoint)
+
+	if req.URL != nil {
+		if err := validateWebhookURL(ctx, *req.URL); err != nil {
+			return nil, err
+		}
+		endpoint.URL = *req.URL
+	}
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+	}
+	if len(req.EventTypes) > 0 {
+		if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+		endpoint.EventTypes = joinEventTypes(req.EventTypes)
+	}
+	if req.IsActive != nil {
+		endpoint.IsActive = *req.IsActive
+	}
+
+	response := mapWebhookEndpoint(endpoint)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+			return fmt.Errorf("updating webhook endpoint: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{Action: models.AuditWebhookEndpointUpdate, EntityID: endpoint.ID, Before: before, After: response})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteEndpoint removes one of the user's webhook endpoints and its delivery history.
+func (s *webhookService) DeleteEndpoint(ctx context.Context, userID uint, endpointID uint) error {
+	endpoint, err := s.getOwnedEndpoint(ctx, userID, endpointID)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.DeleteEndpoint(ctx, endpointID); err != nil {
+			return fmt.Errorf("deleting webhook endpoint: %w", err)
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{Action: models.AuditWebhookEndpointDelete, EntityID: endpointID, Before: mapWebhookEndpoint(endpoint)})
+	})
+}
+
+// ListDeliveries retrieves a page of deliveries made to one of the user's endpoints, newest first.
+func (s *webhookService) ListDeliveries(ctx context.Context, userID uint, endpointID uint, limit int, offset int) ([]models.WebhookDelivery, error) {
+	if _, err := s.getOwnedEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultWebhookDeliveryLimit
+	}
+	limit = min(limit, maxWebhookDeliveryLimit)
+	offset = max(offset, 0)
+
+	deliveries, err := s.webhookRepo.ListDeliveriesByEndpointID(ctx, endpointID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("listing webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetDelivery retrieves a delivery made to one of the user's endpoints, including its attempts.
+func (s *webhookService) GetDelivery(ctx context.Context, userID uint, endpointID uint, deliveryID uint) (*models.WebhookDelivery, error) {
+	if _, err := s.getOwnedEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("fetching webhook delivery: %w", err)
+	}
+	if delivery.WebhookEndpointID != endpointID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// Redeliver queues a delivery to be sent again right away with a fresh set of attempts.
+func (s *webhookService) Redeliver(ctx context.Context, userID uint, endpointID uint, deliveryID uint) (*models.WebhookDelivery, error) {
+	before, err := s.GetDelivery(ctx, userID, endpointID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	var after *models.WebhookDelivery
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.ResetDelivery(ctx, deliveryID, time.Now().UTC()); err != nil {
+			return fmt.Errorf("queueing webhook redelivery: %w", err)
+		}
+		var err error
+		if after, err = s.GetDelivery(ctx, userID, endpointID, deliveryID); err != nil {
+			return err
+		}
+		return s.auditSvc.Record(ctx, AuditEntry{Action: models.AuditWebhookDeliveryRedeliver, EntityID: deliveryID, Before: before, After: after})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+func (s *webhookService) getOwnedEndpoint(ctx context.Context, userID uint, endpointID uint) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.webhookRepo.GetEndpointByID(ctx, endpointID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookEndpointNotFound
+		}
+		return nil, fmt.Errorf("fetching webhook endpoint: %w", err)
+	}
+	if endpoint.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return endpoint, nil
+}
+
+// validateWebhookURL checks that deliveries to rawURL would be encrypted and go to a public internet address. The
+// webhook client checks the address again when it connects, as DNS can change after registration.
+func validateWebhookURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" || parsed.Scheme != "https" {
+		return ErrInvalidWebhookURL
+	}
+	if err := webhook.CheckHost(ctx, parsed.Hostname()); err != nil {
+		return ErrWebhookURLNotAllowed
+	}
+	return nil
+}
+
+// validateWebhookEventTypes checks that endpoints only subscribe to event types offered to webhooks.
+func validateWebhookEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if !events.IsWebhookType(eventType) {
+			return fmt.Errorf("%w: %q", ErrInvalidWebhookEventType, eventType)
+		}
+	}
+	return nil
+}
+
+func joinEventTypes(eventTypes []string) string {
+	unique := slices.Clone(eventTypes)
+	slices.Sort(unique)
+	return strings.Join(slices.Compact(unique), ",")
+}
+
+func splitEventTypes(eventTypes string) []string {
+	if eventTypes == "" {
+		return []string{}
+	}
+	return strings.Split(eventTypes, ",")
+}
+
+func mapWebhookEndpoint(endpoint *models.WebhookEndpoint) models.WebhookEndpointResponse {
+	return models.WebhookEndpointResponse{
+		ID:          endpoint.ID,
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		EventTypes:  splitEventTypes(endpoint.EventTypes),
+		IsActive:    endpoint.IsActive,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+}
+
+type webhookEventSubscriber struct {
+	webhookRepo repositories.WebhookRepository
+}
+
+// NewWebhookEventSubscriber creates an OutboxSubscriber that queues a webhook delivery for every active
+// endpoint of the event's recipients that subscribed to its type.
+func NewWebhookEventSubscriber(webhookRepo repositories.WebhookRepository) OutboxSubscriber {
+	return &webhookEventSubscriber{webhookRepo: webhookRepo}
+}
+
+func (s *webhookEventSubscriber) Name() string {
+	return "webhooks"
+}
+
+// Handle queues the deliveries. Sending happens in the WebhookDispatcher.
+func (s *webhookEventSubscriber) Handle(ctx context.Context, event events.Event) error {
+	endpoints, err := s.webhookRepo.ListActiveEndpointsByUserIDs(ctx, event.RecipientUserIDs)
+	if err != nil {
+		return fmt.Errorf("listing webhook endpoints: %w", err)
+	}
+
+	var payload []byte
+	for _, endpoint := range endpoints {
+		if !slices.Contains(splitEventTypes(endpoint.EventTypes), string(event.Type)) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("encoding event %s: %w", event.ID, err)
+			}
+		}
+		err := s.webhookRepo.CreateDelivery(ctx, &models.WebhookDelivery{
+			WebhookEndpointID: endpoint.ID,
+			EventID:           event.ID,
+			EventType:         string(event.Type),
+			Payload:           string(payload),
+			Status:            models.WebhookDeliveryPending,
+			NextAttemptAt:     time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("queueing delivery to endpoint %d: %w", endpoint.ID, err)
+		}
+	}
+	return nil
+}
+
+// WebhookDispatcher sends queued webhook deliveries, retrying failures with exponential backoff.
+type WebhookDispatcher struct {
+	webhookRepo repositories.WebhookRepository
+	client      *webhook.Client
+}
+
+// NewWebhookDispatcher creates a new WebhookDispatcher.
+func NewWebhookDispatcher(webhookRepo repositories.WebhookRepository, client *webhook.Client) *WebhookDispatcher {
+	return &WebhookDispatcher{webhookRepo: webhookRepo, client: client}
+}
+
+// Start sends due deliveries in the background until ctx is cancelled.
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(webhookDispatchInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := d.RunOnce(ctx, time.Now().UTC()); err != nil {
+				log.Printf("ERROR: Webhook dispatch run failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce claims a batch of due deliveries and attempts each of them once. The batch is sent in parallel and
+// cut off at webhookBatchDeadline, so every attempt is over and recorded before the deliveries' lease runs out.
+func (d *WebhookDispatcher) RunOnce(ctx context.Context, now time.Time) error {
+	deliveries, err := d.webhookRepo.ClaimDueDeliveries(ctx, now, webhookDeliveryLease, webhookDispatchBatchSize)
+	if err != nil {
+		return fmt.Errorf("claiming webhook deliveries: %w", err)
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, webhookBatchDeadline)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer wg.Done()
+			d.attempt(ctx, sendCtx, delivery)
+		}(&deliveries[i])
+	}
+	wg.Wait()
+	return nil
+}
+
+// attempt sends a delivery within sendCtx and records the outcome within ctx, so an attempt cut off by the
+// batch deadline is still recorded and retried.
+func (d *WebhookDispatcher) attempt(ctx context.Context, sendCtx context.Context, delivery *models.WebhookDelivery) {
+	endpoint := delivery.WebhookEndpoint
+	attemptedAt := time.Now().UTC()
+	attempt := &models.WebhookDeliveryAttempt{}
+
+	var failure string
+	if !endpoint.IsActive {
+		failure = "endpoint is disabled"
+	} else {
+		resp, err := d.client.Send(sendCtx, webhook.Request{
+			URL:        endpoint.URL,
+			Secret:     endpoint.Secret,
+			EventID:    delivery.EventID,
+			EventType:  delivery.EventType,
+			DeliveryID: delivery.ID,
+			Payload:    []byte(delivery.Payload),
+		})
+		attempt.StatusCode = resp.StatusCode
+		attempt.ResponseBody = resp.Body
+		attempt.DurationMs = resp.Duration.Milliseconds()
+		switch {
+		case err != nil:
+			failure = err.Error()
+		case !resp.Succeeded():
+			failure = fmt.Sprintf("endpoint responded with status %d", resp.StatusCode)
+		}
+	}
+	attempt.Error = failure
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &attemptedAt
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = failure
+	switch {
+	case failure == "":
+		delivery.Status = models.WebhookDeliverySucceeded
+	case !endpoint.IsActive || delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+		log.Printf("Warning: Webhook delivery %d to endpoint %d failed permanently after %d attempts: %s", delivery.ID, endpoint.ID, delivery.Attempts, failure)
+	default:
+		delivery.NextAttemptAt = attemptedAt.Add(webhookBackoff(delivery.Attempts))
+	}
+
+	if err := d.webhookRepo.RecordAttempt(ctx, delivery, attempt); err != nil {
+		log.Printf("ERROR: Failed to record attempt for webhook delivery %d: %v", delivery.ID, err)
+	}
+}
+
+func webhookBackoff(attempt int) time.Duration {
+	backoff := webhookInitialBackoff
+	for i := 1; i < attempt && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookMaxBackoff)
+}
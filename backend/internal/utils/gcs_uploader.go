@@ -2,15 +2,24 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"google.golang.org/api/option"
 )
 
+// ErrForeignObjectURL is returned when a URL does not point to an object in the uploader's bucket.
+var ErrForeignObjectURL = errors.New("URL does not point to an uploaded object")
+
+// ErrObjectTooLarge is returned when an object exceeds the size a caller is willing to read.
+var ErrObjectTooLarge = errors.New("object is too large")
+
 type GCSUploader struct {
 	Client     *storage.Client
 	BucketName string
@@ -75,6 +84,65 @@ func (g *GCSUploader) UploadFile(ctx context.Context, objectName string, fileRea
 	return url, nil
 }
 
+// ReadFile downloads an object of the bucket given the URL returned by UploadFile, even once the signed URL
+// has expired. URLs outside the bucket are refused so callers never fetch arbitrary addresses.
+func (g *GCSUploader) ReadFile(ctx context.Context, fileURL string, maxBytes int64) ([]byte, error) {
+	if g.Client == nil {
+		return nil, fmt.Errorf("GCS client is not initialized")
+	}
+	objectName, err := g.objectNameFromURL(fileURL)
+	if err != nil {
+		return nil, err
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	reader, err := g.Client.Bucket(g.BucketName).Object(objectName).NewReader(readCtx)
+	if err != nil {
+		return nil, fmt.Errorf("opening GCS object '%s': %w", objectName, err)
+	}
+	defer reader.Close()
+	if reader.Attrs.Size > maxBytes {
+		return nil, ErrObjectTooLarge
+	}
+	data, err := io.ReadAll(io.LimitReader(reader, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading GCS object '%s': %w", objectName, err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrObjectTooLarge
+	}
+	return data, nil
+}
+
+// objectNameFromURL extracts the object name from a path-style (storage.googleapis.com/<bucket>/<object>)
+// or virtual-hosted-style (<bucket>.storage.googleapis.com/<object>) URL of the bucket.
+func (g *GCSUploader) objectNameFromURL(fileURL string) (string, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil || u.Scheme != "https" {
+		return "", ErrForeignObjectURL
+	}
+	path := strings.TrimPrefix(u.Path, "/")
+	var objectName string
+	switch u.Host {
+	case "storage.googleapis.com":
+		bucketPrefix := g.BucketName + "/"
+		if !strings.HasPrefix(path, bucketPrefix) {
+			return "", ErrForeignObjectURL
+		}
+		objectName = strings.TrimPrefix(path, bucketPrefix)
+	case g.BucketName + ".storage.googleapis.com":
+		objectName = path
+	default:
+		return "", ErrForeignObjectURL
+	}
+	if objectName == "" {
+		return "", ErrForeignObjectURL
+	}
+	return objectName, nil
+}
+
 // Close releases resources associated with the GCS client.
 func (g *GCSUploader) Close() error {
 	if g.Client != nil {
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrDisallowedAddress is returned for webhook hosts that are, or resolve to, an address that is not on the
+// public internet, so endpoints cannot be used to reach the server's own network.
+var ErrDisallowedAddress = errors.New("webhook address is not a public internet address")
+
+// nonPublicPrefixes are the ranges not caught by the netip.Addr predicates used in IsPublicAddr.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),         // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),     // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),      // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),     // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),       // Reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),      // NAT64, which can map onto any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"),    // Local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),     // Documentation
+	netip.MustParsePrefix("fec0::/10"),         // Deprecated site-local
+	netip.MustParsePrefix("2002::/16"),         // 6to4, which embeds an IPv4 address
+	netip.MustParsePrefix("2001::/32"),         // Teredo, which embeds an IPv4 address
+	netip.MustParsePrefix("100::/64"),          // Discard-only
+	netip.MustParsePrefix("fd00:ec2::254/128"), // Cloud metadata over IPv6 (also within the ULA range)
+}
+
+// IsPublicAddr reports whether addr is a public unicast address. Loopback, private (RFC 1918 and IPv6 ULA),
+// link-local, including the 169.254.169.254 cloud metadata address, multicast and reserved addresses are not.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost resolves host and returns ErrDisallowedAddress unless every address it resolves to is public.
+func CheckHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublicAddr(addr) {
+			return ErrDisallowedAddress
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: resolving %s: %v", ErrDisallowedAddress, host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("%w: %s has no addresses", ErrDisallowedAddress, host)
+	}
+	for _, addr := range addrs {
+		if !IsPublicAddr(addr) {
+			return ErrDisallowedAddress
+		}
+	}
+	return nil
+}
+
+// dialControl refuses connections to non-public addresses. It runs after name resolution, on the address
+// actually dialled, so a host that resolves differently at delivery time than at registration is still caught.
+func dialControl(_ string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrDisallowedAddress, address)
+	}
+	if !IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrDisallowedAddress, addrPort.Addr())
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the HMAC signature of a delivery, formatted as "t=<unix seconds>,v1=<hex digest>".
+	SignatureHeader = "X-Hubster-Signature"
+	// EventTypeHeader carries the type of the delivered event.
+	EventTypeHeader = "X-Hubster-Event"
+	// EventIDHeader carries the event ID. Receivers should use it as an idempotency key, as retried deliveries repeat it.
+	EventIDHeader = "X-Hubster-Event-Id"
+	// DeliveryIDHeader carries the ID of the delivery being attempted.
+	DeliveryIDHeader = "X-Hubster-Delivery"
+
+	maxResponseBodyBytes = 4 << 10
+	requestTimeout       = 10 * time.Second
+)
+
+// GenerateSecret returns a new random signing secret.
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign computes the signature header value for body sent at timestamp.
+// The digest is HMAC-SHA256 over "<unix seconds>.<body>" keyed with the endpoint secret.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Request is a single webhook delivery attempt.
+type Request struct {
+	URL        string
+	Secret     string
+	EventID    string
+	EventType  string
+	DeliveryID uint
+	Payload    []byte
+}
+
+// Response is what the receiving endpoint answered.
+type Response struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+// Succeeded reports whether the endpoint acknowledged the delivery with a 2xx status.
+func (r Response) Succeeded() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Client sends signed webhook deliveries.
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient creates a new Client. Redirects are not followed so deliveries only reach the registered URL, and
+// connections to non-public addresses are refused, as is going through a proxy that would dial on our behalf.
+func NewClient() *Client {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: dialControl,
+	}
+	return &Client{httpClient: &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: requestTimeout,
+			MaxIdleConns:        20,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Send POSTs the signed payload. A non-nil error means no response was received.
+func (c *Client) Send(ctx context.Context, req Request) (Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Payload))
+	if err != nil {
+		return Response{}, fmt.Errorf("creating webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Hubster-Webhooks/1.0")
+	httpReq.Header.Set(EventTypeHeader, req.EventType)
+	httpReq.Header.Set(EventIDHeader, req.EventID)
+	httpReq.Header.Set(DeliveryIDHeader, strconv.FormatUint(uint64(req.DeliveryID), 10))
+	httpReq.Header.Set(SignatureHeader, Sign(req.Secret, time.Now(), req.Payload))
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return Response{Duration: time.Since(start)}, fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyBytes))
+	return Response{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		Duration:   time.Since(start),
+	}, nil
+}